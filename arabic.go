@@ -0,0 +1,278 @@
+package escpos
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+// Contextual forms of an Arabic letter, in the order of the presentation forms
+const (
+	formIsolated = iota
+	formFinal
+	formInitial
+	formMedial
+)
+
+// arabicLetters holds the isolated presentation form of each Arabic letter
+// and its number of forms: 4 for the letters joining on both sides, 2 for
+// the letters joining only to the preceding one, 1 for hamza
+var arabicLetters = map[rune]struct {
+	isolated rune
+	forms    int
+}{
+	0x0621: {0xFE80, 1}, 0x0622: {0xFE81, 2}, 0x0623: {0xFE83, 2}, 0x0624: {0xFE85, 2},
+	0x0625: {0xFE87, 2}, 0x0626: {0xFE89, 4}, 0x0627: {0xFE8D, 2}, 0x0628: {0xFE8F, 4},
+	0x0629: {0xFE93, 2}, 0x062A: {0xFE95, 4}, 0x062B: {0xFE99, 4}, 0x062C: {0xFE9D, 4},
+	0x062D: {0xFEA1, 4}, 0x062E: {0xFEA5, 4}, 0x062F: {0xFEA9, 2}, 0x0630: {0xFEAB, 2},
+	0x0631: {0xFEAD, 2}, 0x0632: {0xFEAF, 2}, 0x0633: {0xFEB1, 4}, 0x0634: {0xFEB5, 4},
+	0x0635: {0xFEB9, 4}, 0x0636: {0xFEBD, 4}, 0x0637: {0xFEC1, 4}, 0x0638: {0xFEC5, 4},
+	0x0639: {0xFEC9, 4}, 0x063A: {0xFECD, 4}, 0x0641: {0xFED1, 4}, 0x0642: {0xFED5, 4},
+	0x0643: {0xFED9, 4}, 0x0644: {0xFEDD, 4}, 0x0645: {0xFEE1, 4}, 0x0646: {0xFEE5, 4},
+	0x0647: {0xFEE9, 4}, 0x0648: {0xFEED, 2}, 0x0649: {0xFEEF, 2}, 0x064A: {0xFEF1, 4},
+}
+
+// lamAlef holds the isolated form of the ligature of lam with each alef,
+// the final form following it
+var lamAlef = map[rune]rune{0x0622: 0xFEF5, 0x0623: 0xFEF7, 0x0625: 0xFEF9, 0x0627: 0xFEFB}
+
+const (
+	arabicLam     = 0x0644
+	arabicTatweel = 0x0640
+)
+
+// joinsBefore reports whether r connects to the letter following it
+func joinsBefore(r rune) bool {
+	return r == arabicTatweel || arabicLetters[r].forms == 4
+}
+
+// joinsAfter reports whether r connects to the letter preceding it
+func joinsAfter(r rune) bool {
+	return r == arabicTatweel || arabicLetters[r].forms >= 2
+}
+
+// isArabicMark reports whether r is a vowel mark, left out of the shaped text
+func isArabicMark(r rune) bool {
+	return r >= 0x064B && r <= 0x0652
+}
+
+// ShapeArabic prepares Arabic text for printers, which neither join letters
+// nor order right-to-left text: each letter is replaced by its contextual
+// presentation form, lam-alef pairs by their ligature, and the text is
+// returned in visual order, left to right. Runs of digits and Latin text
+// keep their order. Vowel marks are left out.
+func ShapeArabic(text string) string {
+	return string(visualOrder(shapeLetters(text)))
+}
+
+// shapeLetters replaces the Arabic letters of text by their contextual
+// presentation forms, in logical order, leaving out the vowel marks
+func shapeLetters(text string) []rune {
+	var letters []rune
+	for _, r := range text {
+		if !isArabicMark(r) {
+			letters = append(letters, r)
+		}
+	}
+
+	shaped := make([]rune, 0, len(letters))
+	for i := 0; i < len(letters); i++ {
+		r := letters[i]
+		letter, ok := arabicLetters[r]
+		if !ok {
+			shaped = append(shaped, r)
+			continue
+		}
+		prev := i > 0 && joinsBefore(letters[i-1])
+
+		if r == arabicLam && i+1 < len(letters) {
+			if lig, ok := lamAlef[letters[i+1]]; ok {
+				if prev {
+					lig++
+				}
+				shaped = append(shaped, lig)
+				i++
+				continue
+			}
+		}
+
+		next := letter.forms == 4 && i+1 < len(letters) && joinsAfter(letters[i+1])
+		form := formIsolated
+		switch {
+		case prev && letter.forms >= 2 && next:
+			form = formMedial
+		case prev && letter.forms >= 2:
+			form = formFinal
+		case next:
+			form = formInitial
+		}
+		shaped = append(shaped, letter.isolated+rune(form))
+	}
+	return shaped
+}
+
+// isLeftToRight reports whether r belongs to the runs kept in reading order
+func isLeftToRight(r rune) bool {
+	return r < 0x0590 && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '%') || r >= 0x0660 && r <= 0x0669
+}
+
+// mirrored holds the brackets swapped in right-to-left text
+var mirrored = map[rune]rune{'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{', '<': '>', '>': '<'}
+
+// visualOrder reverses right-to-left text for printing left to right,
+// keeping the order of its left-to-right runs such as numbers. Separators
+// between two left-to-right characters, as in "12.50" or "10:30", belong
+// to the run.
+func visualOrder(text []rune) []rune {
+	out := make([]rune, 0, len(text))
+	for end := len(text); end > 0; {
+		start := end - 1
+		if isLeftToRight(text[start]) {
+			for start > 0 && (isLeftToRight(text[start-1]) ||
+				start > 1 && strings.ContainsRune(".,:/-", text[start-1]) && isLeftToRight(text[start-2])) {
+				start--
+			}
+			out = append(out, text[start:end]...)
+		} else if m, ok := mirrored[text[start]]; ok {
+			out = append(out, m)
+		} else {
+			out = append(out, text[start])
+		}
+		end = start
+	}
+	return out
+}
+
+// CodePage864 is the IBM 864 Arabic encoding (CodePageCP864), holding the
+// presentation forms of the Arabic letters. Text is meant to be shaped with
+// ShapeArabic first; the forms missing from the code page are printed with
+// another form of the same letter.
+var CodePage864 encoding.Encoding = cp864{}
+
+// cp864High holds the characters from 0x80 to 0xFF of code page 864, 0 where undefined
+var cp864High = [128]rune{
+	0x00B0, 0x00B7, 0x2219, 0x221A, 0x2592, 0x2500, 0x2502, 0x253C, 0x2524, 0x252C, 0x251C, 0x2534, 0x2510, 0x250C, 0x2514, 0x2518,
+	0x03B2, 0x221E, 0x03C6, 0x00B1, 0x00BD, 0x00BC, 0x2248, 0x00AB, 0x00BB, 0xFEF7, 0xFEF8, 0, 0, 0xFEFB, 0xFEFC, 0,
+	0x00A0, 0x00AD, 0xFE82, 0x00A3, 0x00A4, 0xFE84, 0, 0, 0xFE8E, 0xFE8F, 0xFE95, 0xFE99, 0x060C, 0xFE9D, 0xFEA1, 0xFEA5,
+	0x0660, 0x0661, 0x0662, 0x0663, 0x0664, 0x0665, 0x0666, 0x0667, 0x0668, 0x0669, 0xFED1, 0x061B, 0xFEB1, 0xFEB5, 0xFEB9, 0x061F,
+	0x00A2, 0xFE80, 0xFE81, 0xFE83, 0xFE85, 0xFECA, 0xFE8B, 0xFE8D, 0xFE91, 0xFE93, 0xFE97, 0xFE9B, 0xFE9F, 0xFEA3, 0xFEA7, 0xFEA9,
+	0xFEAB, 0xFEAD, 0xFEAF, 0xFEB3, 0xFEB7, 0xFEBB, 0xFEBF, 0xFEC1, 0xFEC5, 0xFECB, 0xFECF, 0x00A6, 0x00AC, 0x00F7, 0x00D7, 0xFEC9,
+	0x0640, 0xFED3, 0xFED7, 0xFEDB, 0xFEDF, 0xFEE3, 0xFEE7, 0xFEEB, 0xFEED, 0xFEEF, 0xFEF3, 0xFEBD, 0xFECC, 0xFECE, 0xFECD, 0xFEE1,
+	0xFE7D, 0x0651, 0xFEE5, 0xFEE9, 0xFEEC, 0xFEF0, 0xFEF2, 0xFED0, 0xFED5, 0xFEF5, 0xFEF6, 0xFEDD, 0xFED9, 0xFEF1, 0x25A0, 0,
+}
+
+// cp864Bytes maps the characters to their byte in code page 864, including
+// the presentation forms substituted by another form of their letter
+var cp864Bytes = func() map[rune]byte {
+	m := make(map[rune]byte, 256)
+	for i := rune(0); i < 0x80; i++ {
+		m[i] = byte(i)
+	}
+	m[0x066A] = '%' // Arabic percent sign, printed for 0x25
+	for i, r := range cp864High {
+		if r != 0 {
+			m[r] = byte(0x80 + i)
+		}
+	}
+
+	// Preferred substitutes of each missing form, by form
+	substitutes := [4][]int{
+		formIsolated: {formFinal, formInitial, formMedial},
+		formFinal:    {formIsolated, formMedial, formInitial},
+		formInitial:  {formIsolated, formMedial, formFinal},
+		formMedial:   {formInitial, formFinal, formIsolated},
+	}
+	for base, letter := range arabicLetters {
+		for form := 0; form < letter.forms; form++ {
+			r := letter.isolated + rune(form)
+			if _, ok := m[r]; ok {
+				continue
+			}
+			for _, c := range substitutes[form] {
+				if b, ok := m[letter.isolated+rune(c)]; ok && c < letter.forms {
+					m[r] = b
+					break
+				}
+			}
+		}
+		if b, ok := m[letter.isolated]; ok {
+			m[base] = b
+		}
+	}
+
+	// Alef with hamza below is missing, printed as alef
+	m[0x0625], m[0xFE87], m[0xFE88] = m[0x0627], m[0xFE8D], m[0xFE8E]
+	m[0xFEF9], m[0xFEFA] = m[0xFEFB], m[0xFEFC]
+	return m
+}()
+
+// cp864 implements encoding.Encoding for code page 864
+type cp864 struct{}
+
+func (cp864) NewDecoder() *encoding.Decoder {
+	return &encoding.Decoder{Transformer: cp864Decoder{}}
+}
+
+func (cp864) NewEncoder() *encoding.Encoder {
+	return &encoding.Encoder{Transformer: cp864Encoder{}}
+}
+
+func (cp864) String() string {
+	return "IBM Code Page 864"
+}
+
+// cp864Unsupported is returned for the characters missing from code page 864,
+// letting encoding.ReplaceUnsupported print the SUB character instead
+type cp864Unsupported struct{}
+
+func (cp864Unsupported) Error() string {
+	return "character not supported by code page 864"
+}
+
+func (cp864Unsupported) Replacement() byte {
+	return 0x1A
+}
+
+type cp864Encoder struct{ transform.NopResetter }
+
+func (cp864Encoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		r, size := utf8.DecodeRune(src[nSrc:])
+		if r == utf8.RuneError && size == 1 && !atEOF && !utf8.FullRune(src[nSrc:]) {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		b, ok := cp864Bytes[r]
+		if !ok {
+			return nDst, nSrc, cp864Unsupported{}
+		}
+		if nDst >= len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		dst[nDst] = b
+		nDst++
+		nSrc += size
+	}
+	return nDst, nSrc, nil
+}
+
+type cp864Decoder struct{ transform.NopResetter }
+
+func (cp864Decoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for _, b := range src {
+		r := rune(b)
+		if b >= 0x80 {
+			if r = cp864High[b-0x80]; r == 0 {
+				r = utf8.RuneError
+			}
+		}
+		if nDst+utf8.RuneLen(r) > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += utf8.EncodeRune(dst[nDst:], r)
+		nSrc++
+	}
+	return nDst, nSrc, nil
+}
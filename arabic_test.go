@@ -0,0 +1,51 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestShapeArabic tests the contextual forms and the visual order
+func TestShapeArabic(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []rune
+	}{
+		// seen initial, lam-alef final, meem isolated
+		{"ligature", "سلام", []rune{0xFEE1, 0xFEFC, 0xFEB3}},
+		// meem initial, reh final, hah initial, beh medial, alef final
+		{"joining", "مرحبا", []rune{0xFE8E, 0xFE92, 0xFEA3, 0xFEAE, 0xFEE3}},
+		{"isolated lam-alef", "لا", []rune{0xFEFB}},
+		{"vowel marks", "مَ", []rune{0xFEE1}},
+		{"number", "ا 12.50", []rune{'1', '2', '.', '5', '0', ' ', 0xFE8D}},
+		{"latin", "ا VAT", []rune{'V', 'A', 'T', ' ', 0xFE8D}},
+		{"brackets", "(ا)", []rune{'(', 0xFE8D, ')'}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, string(tt.expected), ShapeArabic(tt.text), tt.name)
+	}
+}
+
+// TestCodePage864 tests encoding the presentation forms with code page 864
+func TestCodePage864(t *testing.T) {
+	encoded, err := CodePage864.NewEncoder().String(ShapeArabic("مرحبا 10%"))
+	require.NoError(t, err)
+	// The final beh and reh are missing, printed with their initial and isolated forms
+	assert.Equal(t, "10% \xa8\xc8\xcd\xd1\xe5", encoded)
+
+	// Unshaped letters print as their isolated form
+	encoded, err = CodePage864.NewEncoder().String("إ")
+	require.NoError(t, err)
+	assert.Equal(t, "\xc7", encoded)
+
+	_, err = CodePage864.NewEncoder().String("€")
+	assert.Error(t, err)
+
+	decoded, err := CodePage864.NewDecoder().String("a\xef\x9e\xd3")
+	require.NoError(t, err)
+	assert.Equal(t, "a"+ShapeArabic("سلام"), decoded)
+}
@@ -0,0 +1,127 @@
+package escpos
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/codabar"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/code39"
+	"github.com/boombuler/barcode/code93"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/twooffive"
+)
+
+// BarcodeAsImage renders a barcode locally and prints it through the raster
+// image pipeline, for printers that do not support the symbology natively.
+// symbology: one of the Barcode* or BarcodeB* constants (UPC-E and the GS1
+// symbologies cannot be rendered)
+// widthDots: width of the image in dots, 0 to use the module width set with SetBarcodeWidth
+// heightDots: height of the image in dots, 0 to use the height set with SetBarcodeHeight
+//
+// The human readable text is printed as regular text when an HRI position
+// was selected with SetHRIPosition.
+func (e *Escpos) BarcodeAsImage(symbology uint8, code string, widthDots, heightDots int) (int, error) {
+	bc, err := encodeBarcode(symbology, code)
+	if err != nil {
+		return 0, err
+	}
+
+	modules := bc.Bounds().Dx()
+	if widthDots == 0 {
+		widthDots = modules * int(e.barcodeWidth)
+	}
+	if heightDots == 0 {
+		heightDots = int(e.barcodeHeight)
+	}
+	if widthDots < modules {
+		return 0, fmt.Errorf("barcode needs a width of at least %d dots", modules)
+	}
+	if heightDots < 1 {
+		return 0, fmt.Errorf("barcode height must be at least 1 dot")
+	}
+
+	scaled, err := barcode.Scale(bc, widthDots, heightDots)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scale barcode: %w", err)
+	}
+
+	raster, err := ditherImage(scaled, true, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to rasterize barcode: %w", err)
+	}
+
+	written := 0
+	if e.hriPosition == HRIPositionAbove || e.hriPosition == HRIPositionBoth {
+		n, err := e.Write(code + "\n")
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+
+	n, err := e.printRaster(raster)
+	written += n
+	if err != nil {
+		return written, err
+	}
+
+	if e.hriPosition == HRIPositionBelow || e.hriPosition == HRIPositionBoth {
+		n, err := e.Write(code + "\n")
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// encodeBarcode encodes a barcode with the software encoders
+func encodeBarcode(symbology uint8, code string) (barcode.Barcode, error) {
+	var bc barcode.Barcode
+	var err error
+
+	switch functionA(symbology) {
+	case BarcodeUPCA:
+		// UPC-A is an EAN-13 code starting with 0
+		bc, err = ean.Encode("0" + code)
+	case BarcodeEAN13, BarcodeEAN8:
+		bc, err = ean.Encode(code)
+	case BarcodeCode39:
+		bc, err = code39.Encode(strings.Trim(code, "*"), false, false)
+	case BarcodeITF:
+		bc, err = twooffive.Encode(code, true)
+	case BarcodeCodabar:
+		bc, err = codabar.Encode(strings.ToUpper(code))
+	case BarcodeBCode93:
+		bc, err = code93.Encode(code, true, false)
+	case BarcodeBCode128, BarcodeBCode128Auto:
+		bc, err = code128.Encode(code128Data(code))
+	default:
+		return nil, invalidBarcode(symbology, "barcode type %d cannot be rendered as an image", symbology)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode barcode: %w", err)
+	}
+	return bc, nil
+}
+
+// code128Data drops the ESC/POS code set selection ("{A", "{B" or "{C") of
+// CODE128 data, for the encoders picking the code sets themselves
+func code128Data(code string) string {
+	if len(code) >= 2 && code[0] == '{' && code[1] >= 'A' && code[1] <= 'C' {
+		return code[2:]
+	}
+	return code
+}
+
+// functionA maps the function B barcode types having a function A equivalent to that type
+func functionA(symbology uint8) uint8 {
+	if symbology >= BarcodeBUPCA && symbology <= BarcodeBCodabar {
+		return symbology - BarcodeBUPCA
+	}
+	return symbology
+}
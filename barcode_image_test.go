@@ -0,0 +1,76 @@
+package escpos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBarcodeAsImage tests rendering a barcode as a raster image
+func TestBarcodeAsImage(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	// EAN-13 is 95 modules wide, 3 dots per module by default
+	_, err := p.BarcodeAsImage(BarcodeEAN13, "1234567890128", 0, 0)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	output := mock.Bytes()
+	widthBytes := (95*3 + 7) / 8
+	assert.Equal(t, []byte{gs, 'v', '0', 0, byte(widthBytes), 0, 162, 0}, output[:8])
+	assert.Len(t, output, 8+widthBytes*162)
+
+	// Explicit size and HRI text below
+	mock = NewMockPrinter()
+	p = New(mock)
+
+	_, err = p.SetHRIPosition(HRIPositionBelow)
+	assert.NoError(t, err)
+	_, err = p.BarcodeAsImage(BarcodeBCode128, "{BABC-123", 400, 80)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	output = mock.Bytes()
+	assert.Equal(t, []byte{gs, 'H', HRIPositionBelow, gs, 'v', '0', 0, 50, 0, 80, 0}, output[:11])
+	assert.True(t, bytes.HasSuffix(output, []byte("{BABC-123\n")))
+
+	// Too narrow
+	_, err = p.BarcodeAsImage(BarcodeEAN13, "1234567890128", 50, 80)
+	assert.Error(t, err)
+
+	// Not renderable
+	_, err = p.BarcodeAsImage(BarcodeUPCE, "01234565", 0, 0)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be rendered as an image")
+}
+
+// TestBarcodeFallback tests the automatic fallback for barcodes unsupported by the profile
+func TestBarcodeFallback(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{UnsupportedBarcodes: []uint8{BarcodeCode39}})
+
+	_, err := p.CODE39("ABC123")
+	assert.NoError(t, err)
+	_, err = p.BarcodeB(BarcodeBCode39, []byte("ABC123"))
+	assert.NoError(t, err)
+	_, err = p.EAN13("1234567890128")
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	output := mock.Bytes()
+	assert.Equal(t, 2, bytes.Count(output, []byte{gs, 'v', '0'}))
+	assert.NotContains(t, string(output), string([]byte{gs, 'k', BarcodeCode39}))
+	assert.Contains(t, string(output), string([]byte{gs, 'k', BarcodeEAN13}))
+
+	assert.False(t, p.Profile().SupportsBarcode(BarcodeBCode39))
+	assert.True(t, p.Profile().SupportsBarcode(BarcodeBCode128))
+}
@@ -0,0 +1,102 @@
+package escpos
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+)
+
+// Width in dots of the Font A characters
+const fontAWidth = 12
+
+// WriteBilingual prints two texts side by side, such as an English label and
+// its Arabic translation: left in the left column, and right, a
+// right-to-left text, aligned to the right margin in the right column. Each
+// text is wrapped in its column; the right one is shaped with ShapeArabic
+// and printed with code page 864 (CodePageCP864). Lines are laid out for the
+// characters per line of the current size with left justification.
+func (e *Escpos) WriteBilingual(left, right string) (int, error) {
+	cols := e.CharsPerLine()
+	leftWidth := (cols - 1) / 2
+	rightWidth := cols - 1 - leftWidth
+	if leftWidth < 1 {
+		return 0, fmt.Errorf("a line of %d characters is too narrow for two columns", cols)
+	}
+
+	leftLines := wrapText(left, leftWidth)
+	rightLines := wrapText(right, rightWidth)
+	written := 0
+	for i := range max(len(leftLines), len(rightLines)) {
+		var l, r string
+		if i < len(leftLines) {
+			l = leftLines[i]
+		}
+		if i < len(rightLines) {
+			r = ShapeArabic(rightLines[i])
+		}
+
+		if r != "" {
+			l += strings.Repeat(" ", leftWidth-utf8.RuneCountInString(l)+1+rightWidth-utf8.RuneCountInString(r))
+		}
+		n, err := e.Write(l)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		n, err = e.writeInCodePage(r, CodePage864, CodePageCP864)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		n, err = e.Write("\n")
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// writeInCodePage writes text with another code page, such as right-to-left
+// text in visual order with an Arabic one, then switches back to the code
+// page of the default encoding
+func (e *Escpos) writeInCodePage(text string, enc encoding.Encoding, codepage uint8) (int, error) {
+	if isASCII(text) {
+		return e.Write(text)
+	}
+	n, err := e.WriteWithEncoding(text, enc, codepage)
+	if err != nil || e.enc == nil {
+		return n, err
+	}
+	m, err := e.SetCodePage(e.codepage)
+	return n + m, err
+}
+
+// wrapText splits text in lines of at most width characters, breaking
+// between words, and inside the words longer than a line
+func wrapText(text string, width int) []string {
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(text) {
+		w := []rune(word)
+		if len(line) > 0 && len(line)+1+len(w) > width {
+			lines = append(lines, string(line))
+			line = line[:0]
+		}
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+		for len(line)+len(w) > width {
+			cut := width - len(line)
+			lines = append(lines, string(append(line, w[:cut]...)))
+			line, w = line[:0], w[cut:]
+		}
+		line = append(line, w...)
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
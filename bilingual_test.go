@@ -0,0 +1,45 @@
+package escpos
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteBilingual tests the columns of a bilingual line
+func TestWriteBilingual(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{PrintWidth: 384}) // 32 characters: 15 + 1 + 16
+
+	_, err := p.WriteBilingual("Total", "سلام")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := "Total" + strings.Repeat(" ", 24) + "\x1bt\x28\xef\x9e\xd3\x1bt\x02\n"
+	assert.Equal(t, expected, string(mock.Bytes()))
+}
+
+// TestWriteBilingualWrap tests wrapping the columns
+func TestWriteBilingualWrap(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{PrintWidth: 384})
+
+	_, err := p.WriteBilingual("Chicken shawarma sandwich", "12.50")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := "Chicken" + strings.Repeat(" ", 20) + "12.50\n" +
+		"shawarma\n" +
+		"sandwich\n"
+	assert.Equal(t, expected, string(mock.Bytes()))
+}
+
+// TestWrapText tests breaking text in lines
+func TestWrapText(t *testing.T) {
+	assert.Equal(t, []string{"a bb", "ccc", "dddd", "dd"}, wrapText("a bb ccc dddddd", 4))
+	assert.Empty(t, wrapText("  ", 4))
+}
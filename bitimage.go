@@ -13,11 +13,13 @@ func closestNDivisibleBy8(n int) int {
 	return n1
 }
 
-func printImage(img image.Image) (xL byte, xH byte, yL byte, yH byte, data []byte) {
+// printImage converts an image to raster data, the pixels of a luminance
+// under threshold being printed black
+func printImage(img image.Image, threshold int) (xL byte, xH byte, yL byte, yH byte, data []byte) {
 	width, height, pixels := getPixels(img)
 
 	removeTransparency(&pixels)
-	makeGrayscale(&pixels)
+	makeGrayscale(&pixels, threshold)
 
 	printWidth := closestNDivisibleBy8(width)
 	printHeight := closestNDivisibleBy8(height)
@@ -26,7 +28,7 @@ func printImage(img image.Image) (xL byte, xH byte, yL byte, yH byte, data []byt
 	return byte((printWidth >> 3) & 0xff), byte(((printWidth >> 3) >> 8) & 0xff), byte(printHeight & 0xff), byte((printHeight >> 8) & 0xff), bytes
 }
 
-func makeGrayscale(pixels *[][]pixel) {
+func makeGrayscale(pixels *[][]pixel, threshold int) {
 	height := len(*pixels)
 	width := len((*pixels)[0])
 
@@ -37,7 +39,7 @@ func makeGrayscale(pixels *[][]pixel) {
 
 			luminance := (float64(pixel.R) * 0.299) + (float64(pixel.G) * 0.587) + (float64(pixel.B) * 0.114)
 			var value int
-			if luminance < 128 {
+			if luminance < float64(threshold) {
 				value = 0
 			} else {
 				value = 255
@@ -0,0 +1,74 @@
+package escpos
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// BoxStyle is the border drawn by WriteBoxed
+type BoxStyle uint8
+
+const (
+	// BoxSingle draws a single line border
+	BoxSingle BoxStyle = iota
+	// BoxDouble draws a double line border
+	BoxDouble
+	// BoxASCII draws a border of '+', '-' and '|'
+	BoxASCII
+)
+
+// boxChars holds the corners (top left, top right, bottom left, bottom
+// right), then the horizontal and vertical lines of each box style
+var boxChars = [...][6]rune{
+	BoxSingle: {'┌', '┐', '└', '┘', '─', '│'},
+	BoxDouble: {'╔', '╗', '╚', '╝', '═', '║'},
+	BoxASCII:  {'+', '+', '+', '+', '-', '|'},
+}
+
+// boxCharsFor returns the characters of the box style, or the ASCII ones
+// when the encoding lacks the box-drawing characters
+func (e *Escpos) boxCharsFor(style BoxStyle) [6]rune {
+	if int(style) >= len(boxChars) || e.enc == nil {
+		return boxChars[BoxASCII]
+	}
+	chars := boxChars[style]
+	if _, err := e.enc.NewEncoder().String(string(chars[:])); err != nil {
+		return boxChars[BoxASCII]
+	}
+	return chars
+}
+
+// WriteBoxed prints text framed by a border, such as an order number on a
+// kitchen ticket. The text can hold several lines; lines wider than the
+// paper are wrapped. The border is drawn with the box-drawing characters of
+// the code page, or with ASCII characters when the encoding lacks them.
+func (e *Escpos) WriteBoxed(text string, style BoxStyle) (int, error) {
+	cols := e.CharsPerLine()
+	if cols < 5 {
+		return 0, fmt.Errorf("a line of %d characters is too narrow for a box", cols)
+	}
+
+	var lines []string
+	width := 0
+	for _, line := range strings.Split(text, "\n") {
+		if utf8.RuneCountInString(line) > cols-4 {
+			lines = append(lines, wrapText(line, cols-4)...)
+		} else {
+			lines = append(lines, line)
+		}
+	}
+	for _, line := range lines {
+		width = max(width, utf8.RuneCountInString(line))
+	}
+
+	c := e.boxCharsFor(style)
+	horizontal := strings.Repeat(string(c[4]), width+2)
+	var b strings.Builder
+	b.WriteString(string(c[0]) + horizontal + string(c[1]) + "\n")
+	for _, line := range lines {
+		b.WriteString(string(c[5]) + " " + pad(line, width, JustifyLeft) + " " + string(c[5]) + "\n")
+	}
+	b.WriteString(string(c[2]) + horizontal + string(c[3]) + "\n")
+	return e.Write(b.String())
+}
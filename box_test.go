@@ -0,0 +1,46 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TestWriteBoxed tests the border drawn with the characters of the code page
+func TestWriteBoxed(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	_, err := p.SetEncoding(charmap.CodePage437, CodePagePC437)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	mock.Reset()
+
+	_, err = p.WriteBoxed("#42\nTable 7", BoxDouble)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	expected, err := charmap.CodePage437.NewEncoder().String("╔═════════╗\n║ #42     ║\n║ Table 7 ║\n╚═════════╝\n")
+	require.NoError(t, err)
+	assert.Equal(t, "\x1bt\x00"+expected, mock.String())
+}
+
+// TestWriteBoxedASCII tests the fallback border and the wrapped lines
+func TestWriteBoxedASCII(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	_, err := p.SetEncoding(nil, 0)
+	require.NoError(t, err)
+	require.NoError(t, p.SetCharsPerLine(10))
+	require.NoError(t, p.Print())
+	mock.Reset()
+
+	_, err = p.WriteBoxed("order 1234", BoxSingle)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, "+-------+\n| order |\n| 1234  |\n+-------+\n", mock.String())
+
+	require.NoError(t, p.SetCharsPerLine(4))
+	_, err = p.WriteBoxed("x", BoxSingle)
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+package escpos
+
+import "bufio"
+
+// WithBufferSize sets the size in bytes of the buffer holding the commands
+// until they are sent to the printer (default: 4096). The buffer is sent
+// whenever it is full, and by Print.
+func WithBufferSize(n int) Option {
+	return func(e *Escpos) {
+		if n > 0 {
+			e.dst = bufio.NewWriterSize(e.out, n)
+		}
+	}
+}
+
+// WithAutoFlush sends the buffered commands as soon as they reach threshold
+// bytes, so very long jobs stream to the printer instead of waiting for
+// Print. A threshold of 1 sends every command immediately, for small jobs
+// sensitive to the printer status.
+func WithAutoFlush(threshold int) Option {
+	return func(e *Escpos) {
+		e.autoFlush = max(threshold, 0)
+	}
+}
+
+// autoFlushed sends the buffer once it holds the auto flush threshold
+func (e *Escpos) autoFlushed() error {
+	if e.autoFlush > 0 && e.dst.Buffered() >= e.autoFlush {
+		return e.flush()
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithBufferSize tests sending the buffer whenever it is full
+func TestWithBufferSize(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock, WithBufferSize(16))
+
+	assert.Equal(t, 16, p.Diagnostics().BufferSize)
+	p.Write("0123456789")
+	assert.Empty(t, mock.Bytes())
+	p.Write("0123456789")
+	assert.Equal(t, "0123456789012345", mock.String())
+
+	require.NoError(t, p.Print())
+	assert.Equal(t, "01234567890123456789", mock.String())
+}
+
+// TestWithAutoFlush tests sending the buffer once it reaches the threshold
+func TestWithAutoFlush(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock, WithAutoFlush(8))
+
+	p.Write("0123")
+	assert.Empty(t, mock.Bytes())
+	p.Write("4567")
+	assert.Equal(t, "01234567", mock.String())
+
+	mock.Reset()
+	p = New(mock, WithAutoFlush(1))
+	p.SetBold(true)
+	assert.Equal(t, "\x1bE\x01", mock.String())
+}
@@ -0,0 +1,156 @@
+package escpos
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// capabilityEncodings maps the code page names of the capabilities database
+// to their character encodings
+var capabilityEncodings = map[string]encoding.Encoding{
+	"CP437":       charmap.CodePage437,
+	"CP850":       charmap.CodePage850,
+	"CP852":       charmap.CodePage852,
+	"CP855":       charmap.CodePage855,
+	"CP858":       charmap.CodePage858,
+	"CP860":       charmap.CodePage860,
+	"CP862":       charmap.CodePage862,
+	"CP863":       charmap.CodePage863,
+	"CP864":       CodePage864,
+	"CP865":       charmap.CodePage865,
+	"CP866":       charmap.CodePage866,
+	"CP874":       charmap.Windows874,
+	"CP1250":      charmap.Windows1250,
+	"CP1251":      charmap.Windows1251,
+	"CP1252":      charmap.Windows1252,
+	"CP1253":      charmap.Windows1253,
+	"CP1254":      charmap.Windows1254,
+	"CP1255":      charmap.Windows1255,
+	"CP1256":      charmap.Windows1256,
+	"CP1257":      charmap.Windows1257,
+	"CP1258":      charmap.Windows1258,
+	"ISO_8859-1":  charmap.ISO8859_1,
+	"ISO_8859-2":  charmap.ISO8859_2,
+	"ISO_8859-3":  charmap.ISO8859_3,
+	"ISO_8859-4":  charmap.ISO8859_4,
+	"ISO_8859-5":  charmap.ISO8859_5,
+	"ISO_8859-6":  charmap.ISO8859_6,
+	"ISO_8859-7":  charmap.ISO8859_7,
+	"ISO_8859-8":  charmap.ISO8859_8,
+	"ISO_8859-15": charmap.ISO8859_15,
+	"KOI8-R":      charmap.KOI8R,
+	"KOI8-U":      charmap.KOI8U,
+}
+
+// capabilityProfile is a printer model of the capabilities database
+type capabilityProfile struct {
+	Name      string            `json:"name"`
+	Vendor    string            `json:"vendor"`
+	CodePages map[string]string `json:"codePages"`
+	Features  map[string]bool   `json:"features"`
+	Fonts     map[string]struct {
+		Columns int `json:"columns"`
+	} `json:"fonts"`
+	Media struct {
+		DPI   any `json:"dpi"`
+		Width struct {
+			Pixels any `json:"pixels"`
+		} `json:"width"`
+	} `json:"media"`
+}
+
+// LoadCapabilities reads the printer models of the community maintained
+// ESC/POS capabilities database (capabilities.json, as used by python-escpos
+// and escpos-php) and returns their profiles by model key. The print width,
+// resolution, fonts, characters per line, code pages and the QR code,
+// cutter and raster image features are taken over; the other capabilities
+// keep their defaults.
+func LoadCapabilities(r io.Reader) (map[string]Profile, error) {
+	var db struct {
+		Profiles map[string]capabilityProfile `json:"profiles"`
+	}
+	if err := json.NewDecoder(r).Decode(&db); err != nil {
+		return nil, fmt.Errorf("failed to parse capabilities: %w", err)
+	}
+
+	profiles := make(map[string]Profile, len(db.Profiles))
+	for key, c := range db.Profiles {
+		p, err := c.profile()
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", key, err)
+		}
+		profiles[key] = p
+	}
+	return profiles, nil
+}
+
+// profile converts a model of the capabilities database to a Profile
+func (c capabilityProfile) profile() (Profile, error) {
+	p := Profile{
+		Name:       c.Name,
+		PrintWidth: capabilityNumber(c.Media.Width.Pixels),
+		DPI:        capabilityNumber(c.Media.DPI),
+	}
+	if c.Vendor != "" && c.Vendor != "Generic" {
+		p.Name = c.Vendor + " " + c.Name
+	}
+
+	for key, font := range c.Fonts {
+		n, err := strconv.ParseUint(key, 10, 8)
+		if err != nil {
+			return Profile{}, fmt.Errorf("invalid font %q", key)
+		}
+		p.Fonts = append(p.Fonts, uint8(n))
+		if n == 0 {
+			p.CharsPerLine = font.Columns
+		}
+	}
+	sort.Slice(p.Fonts, func(i, j int) bool { return p.Fonts[i] < p.Fonts[j] })
+
+	for key, name := range c.CodePages {
+		n, err := strconv.ParseUint(key, 10, 8)
+		if err != nil {
+			return Profile{}, fmt.Errorf("invalid code page %q", key)
+		}
+		enc, ok := capabilityEncodings[name]
+		if !ok {
+			continue
+		}
+		if p.CodePageEncodings == nil {
+			p.CodePageEncodings = make(map[uint8]encoding.Encoding)
+		}
+		p.CodePages = append(p.CodePages, uint8(n))
+		p.CodePageEncodings[uint8(n)] = enc
+		if name == "CP874" {
+			p.ThaiCodePage = uint8(n)
+		}
+	}
+	sort.Slice(p.CodePages, func(i, j int) bool { return p.CodePages[i] < p.CodePages[j] })
+
+	if qr, ok := c.Features["qrCode"]; ok && !qr {
+		p.NoQRCode = true
+	}
+	full, fullOK := c.Features["paperFullCut"]
+	part, partOK := c.Features["paperPartCut"]
+	if fullOK && partOK && !full && !part {
+		p.NoCutter = true
+	}
+	if raster, ok := c.Features["bitImageRaster"]; ok && !raster && c.Features["graphics"] {
+		p.RasterCommand = RasterGraphics
+	}
+	return p, nil
+}
+
+// capabilityNumber returns a number of the capabilities database, 0 when it is "Unknown"
+func capabilityNumber(v any) int {
+	if f, ok := v.(float64); ok && f > 0 {
+		return int(f)
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package escpos
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// capabilitiesJSON is an extract of the capabilities database
+const capabilitiesJSON = `{
+	"encodings": {"CP437": {"name": "CP437"}},
+	"profiles": {
+		"TM-T88V": {
+			"name": "TM-T88V",
+			"vendor": "Epson",
+			"codePages": {"0": "CP437", "1": "CP932", "16": "CP1252", "21": "CP874", "46": "CP1251"},
+			"features": {"qrCode": true, "paperFullCut": true, "paperPartCut": true, "bitImageRaster": true},
+			"fonts": {"0": {"name": "Font A", "columns": 42}, "1": {"name": "Font B", "columns": 56}},
+			"media": {"dpi": 180, "width": {"mm": 80, "pixels": 512}}
+		},
+		"simple": {
+			"name": "Simple",
+			"vendor": "Generic",
+			"codePages": {"0": "CP437"},
+			"features": {"qrCode": false, "paperFullCut": false, "paperPartCut": false, "bitImageRaster": false, "graphics": true},
+			"fonts": {"0": {"name": "Font A", "columns": 32}},
+			"media": {"dpi": "Unknown", "width": {"mm": "Unknown", "pixels": "Unknown"}}
+		}
+	}
+}`
+
+// TestLoadCapabilities tests the conversion of the models to profiles
+func TestLoadCapabilities(t *testing.T) {
+	profiles, err := LoadCapabilities(strings.NewReader(capabilitiesJSON))
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+
+	p := profiles["TM-T88V"]
+	assert.Equal(t, "Epson TM-T88V", p.Name)
+	assert.Equal(t, 512, p.PrintWidth)
+	assert.Equal(t, 180, p.DPI)
+	assert.Equal(t, 42, p.CharsPerLine)
+	assert.Equal(t, []uint8{FontA, FontB}, p.Fonts)
+	assert.Equal(t, []uint8{0, 16, 21, 46}, p.CodePages)
+	assert.Equal(t, charmap.Windows1251, p.CodePageEncodings[46])
+	assert.Equal(t, uint8(21), p.ThaiCodePage)
+	assert.False(t, p.NoQRCode)
+	assert.False(t, p.NoCutter)
+	assert.Equal(t, RasterGSv0, p.RasterCommand)
+
+	s := profiles["simple"]
+	assert.Equal(t, "Simple", s.Name)
+	assert.Equal(t, 0, s.PrintWidth)
+	assert.True(t, s.NoQRCode)
+	assert.True(t, s.NoCutter)
+	assert.Equal(t, RasterGraphics, s.RasterCommand)
+
+	_, err = LoadCapabilities(strings.NewReader("{"))
+	assert.Error(t, err)
+}
+
+// TestLoadCapabilitiesCodePages tests WriteUTF8 with the code page numbers of a loaded profile
+func TestLoadCapabilitiesCodePages(t *testing.T) {
+	profiles, err := LoadCapabilities(strings.NewReader(capabilitiesJSON))
+	require.NoError(t, err)
+
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(profiles["TM-T88V"])
+	_, err = p.WriteUTF8("Ж")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, "\x1bt\x2e\xc6\x1bt\x02", mock.String())
+}
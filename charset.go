@@ -0,0 +1,52 @@
+package escpos
+
+import "fmt"
+
+// International character sets (ESC R), replacing a few ASCII characters
+// such as '#' or '@' with national characters
+const (
+	CharsetUSA          uint8 = 0
+	CharsetFrance       uint8 = 1
+	CharsetGermany      uint8 = 2
+	CharsetUK           uint8 = 3
+	CharsetDenmarkI     uint8 = 4
+	CharsetSweden       uint8 = 5
+	CharsetItaly        uint8 = 6
+	CharsetSpainI       uint8 = 7
+	CharsetJapan        uint8 = 8
+	CharsetNorway       uint8 = 9
+	CharsetDenmarkII    uint8 = 10
+	CharsetSpainII      uint8 = 11
+	CharsetLatinAmerica uint8 = 12
+	CharsetKorea        uint8 = 13
+)
+
+// SetInternationalCharset selects the international character set (ESC R)
+func (e *Escpos) SetInternationalCharset(charset uint8) (int, error) {
+	if charset > CharsetKorea {
+		return 0, fmt.Errorf("invalid international character set %d", charset)
+	}
+	return e.WriteRaw([]byte{esc, 'R', charset})
+}
+
+// applyProfileCharset makes the encoding of the profile the default
+// encoding, and selects its code page and international character set
+func (e *Escpos) applyProfileCharset() (int, error) {
+	p := e.profile
+	written := 0
+	if p.Encoding != nil {
+		n, err := e.SetEncoding(p.Encoding, p.CodePage)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	if p.Encoding != nil || p.Charset != CharsetUSA {
+		n, err := e.SetInternationalCharset(p.Charset)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,68 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TestProfileCharset tests selecting the code page of the profile
+func TestProfileCharset(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Encoding: charmap.Windows1252, CodePage: CodePageWPC1252, Charset: CharsetFrance})
+
+	_, err := p.Write("é")
+	require.NoError(t, err)
+	_, err = p.Initialize()
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := []byte{
+		esc, 't', CodePageWPC1252, esc, 'R', CharsetFrance,
+		esc, 't', CodePageWPC1252, 0xE9,
+		esc, '@', esc, 't', CodePageWPC1252, esc, 'R', CharsetFrance,
+	}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestProfileWithoutCharset tests that the generic profile sends nothing
+func TestProfileWithoutCharset(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(ProfileEpsonTMT20II)
+	_, err := p.Initialize()
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, []byte{esc, '@'}, mock.Bytes())
+
+	// A character set alone
+	mock = NewMockPrinter()
+	p = New(mock)
+	p.SetProfile(Profile{Charset: CharsetGermany})
+	require.NoError(t, p.Print())
+	assert.Equal(t, []byte{esc, 'R', CharsetGermany}, mock.Bytes())
+}
+
+// TestSetInternationalCharset tests the character set range
+func TestSetInternationalCharset(t *testing.T) {
+	p := New(NewMockPrinter())
+	_, err := p.SetInternationalCharset(CharsetKorea)
+	assert.NoError(t, err)
+	_, err = p.SetInternationalCharset(14)
+	assert.Error(t, err)
+}
+
+// TestProfileInvalidCharset tests reporting the invalid character set of a profile
+func TestProfileInvalidCharset(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	assert.ErrorContains(t, p.SetProfile(Profile{Charset: CharsetKorea + 1}), "invalid international character set")
+
+	p.SetStickyErrors(true)
+	assert.Error(t, p.SetProfile(Profile{Charset: CharsetKorea + 1}))
+	assert.Error(t, p.Print())
+	assert.Empty(t, mock.Bytes())
+}
@@ -0,0 +1,124 @@
+package escpos
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// chunking splits the writes to slow printers, see WithChunking
+type chunking struct {
+	size        int
+	delay       time.Duration
+	poll        bool
+	pollTimeout time.Duration
+
+	// status check before each chunk, see WithFlowControl
+	flow        bool
+	flowTimeout time.Duration
+}
+
+// WithChunking splits the data sent to the printer in chunks of at most
+// chunkSize bytes, waiting delay between two chunks, for the cheap printers
+// whose receive buffer overflows on large images and corrupts the output
+func WithChunking(chunkSize int, delay time.Duration) Option {
+	return func(e *Escpos) {
+		if chunkSize <= 0 {
+			return
+		}
+		if e.out.chunks == nil {
+			e.out.chunks = &chunking{}
+		}
+		e.out.chunks.size, e.out.chunks.delay = chunkSize, delay
+	}
+}
+
+// WithBusyPolling makes the writes split by WithChunking wait for the answer
+// to a real-time status request (DLE EOT 1) after each chunk, so the next
+// chunk is only sent once the printer is responsive again. The write fails
+// when the printer does not answer within timeout (0: 5 seconds). It has no
+// effect without WithChunking.
+func WithBusyPolling(timeout time.Duration) Option {
+	return func(e *Escpos) {
+		if e.out.chunks == nil {
+			e.out.chunks = &chunking{}
+		}
+		if timeout <= 0 {
+			timeout = defaultPollTimeout
+		}
+		e.out.chunks.poll, e.out.chunks.pollTimeout = true, timeout
+	}
+}
+
+// writeChunks writes p in chunks, pausing between them
+func (c *countingWriter) writeChunks(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if c.chunks.flow {
+			if err := c.waitReady(); err != nil {
+				return written, err
+			}
+		}
+		chunk := p[:min(len(p), c.chunks.size)]
+		n, err := c.writeCounted(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[len(chunk):]
+		if len(p) == 0 {
+			break
+		}
+		if err := c.pause(); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// pause waits between two chunks
+func (c *countingWriter) pause() error {
+	if c.chunks.delay > 0 {
+		time.Sleep(c.chunks.delay)
+	}
+	if !c.chunks.poll {
+		return nil
+	}
+
+	_, err := c.pollStatus(time.Now().Add(c.chunks.pollTimeout))
+	return err
+}
+
+// pollStatus sends a real-time status request (DLE EOT 1) and returns the
+// status byte, waiting for it until deadline. Printers not readable are
+// reported online.
+func (c *countingWriter) pollStatus(deadline time.Time) (byte, error) {
+	r, ok := c.w.(io.Reader)
+	if !ok {
+		return 0, nil
+	}
+	if _, err := WriteFull(c.w, []byte{dle, 0x04, RT_STATUS_ONLINE}); err != nil {
+		return 0, fmt.Errorf("failed to poll the printer: %w", err)
+	}
+	reply := make([]byte, 1)
+	for {
+		n, err := r.Read(reply)
+		if err != nil {
+			return 0, fmt.Errorf("failed to poll the printer: %w", err)
+		}
+		if n > 0 {
+			return reply[0], nil
+		}
+		if time.Now().After(deadline) {
+			return 0, fmt.Errorf("%w: no answer to the poll before the deadline", ErrTimeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Delay between two reads waiting for the answer to a busy poll, and default
+// time after which the printer is considered gone
+const (
+	pollInterval       = 10 * time.Millisecond
+	defaultPollTimeout = 5 * time.Second
+)
@@ -0,0 +1,45 @@
+package escpos
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithChunking tests splitting the writes in chunks
+func TestWithChunking(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock, WithChunking(4, time.Millisecond))
+
+	p.WriteRaw([]byte("0123456789"))
+	require.NoError(t, p.Print())
+
+	assert.Equal(t, [][]byte{[]byte("0123"), []byte("4567"), []byte("89")}, mock.Writes())
+	assert.Equal(t, int64(10), p.Diagnostics().BytesSent)
+}
+
+// TestWithBusyPolling tests polling the printer between the chunks
+func TestWithBusyPolling(t *testing.T) {
+	mock := NewMockPrinter()
+	mock.SetStatus([]byte{0x16})
+	p := New(mock, WithChunking(4, 0), WithBusyPolling(0))
+
+	p.WriteRaw([]byte("0123456789"))
+	require.NoError(t, p.Print())
+
+	poll := "\x10\x04\x01"
+	assert.Equal(t, "0123"+poll+"4567"+poll+"89", mock.String())
+}
+
+// TestWithBusyPollingTimeout tests failing when the printer does not answer the poll
+func TestWithBusyPollingTimeout(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock, WithChunking(4, 0), WithBusyPolling(50*time.Millisecond))
+
+	p.WriteRaw(bytes.Repeat([]byte{'a'}, 8))
+	assert.ErrorIs(t, p.Print(), ErrTimeout)
+	assert.Equal(t, "aaaa\x10\x04\x01", mock.String())
+}
@@ -0,0 +1,48 @@
+package escpos
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock is the vendor specific protocol setting and reading the real-time
+// clock of a printer. Set it on the Clock field of the profile.
+type Clock interface {
+	// SetCommand returns the command setting the clock to t
+	SetCommand(t time.Time) ([]byte, error)
+	// ReadCommand returns the command requesting the time of the clock
+	ReadCommand() []byte
+	// Terminator returns the byte ending the response to the read command
+	Terminator() byte
+	// ParseTime parses the response to the read command, without its terminator
+	ParseTime(resp []byte) (time.Time, error)
+}
+
+// SetClock sets the real-time clock of the printer to t, e.g. to sync the
+// printer clock when provisioning it
+func (e *Escpos) SetClock(t time.Time) (int, error) {
+	if !e.profile.SupportsClock() {
+		return 0, fmt.Errorf("%s has no real-time clock", e.profile.name())
+	}
+	cmd, err := e.profile.Clock.SetCommand(t)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build clock command: %w", err)
+	}
+	return e.WriteRaw(cmd)
+}
+
+// ReadClock reads the real-time clock of the printer
+func (e *Escpos) ReadClock() (time.Time, error) {
+	if !e.profile.SupportsClock() {
+		return time.Time{}, fmt.Errorf("%s has no real-time clock", e.profile.name())
+	}
+	resp, err := e.requestUntil(e.profile.Clock.ReadCommand(), e.profile.Clock.Terminator())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read clock: %w", err)
+	}
+	t, err := e.profile.Clock.ParseTime(resp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid clock response: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,104 @@
+package escpos
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// Framing bytes of the Datecs fiscal protocol
+const (
+	datecsPreamble   = 0x01
+	datecsSeparator  = 0x04
+	datecsPostamble  = 0x05
+	datecsTerminator = 0x03
+)
+
+// Datecs fiscal protocol commands
+const (
+	datecsSetDateTime  = 0x3D // command 61
+	datecsReadDateTime = 0x3E // command 62
+)
+
+// Layout of the dates exchanged with the Datecs fiscal printers
+const datecsTimeLayout = "02-01-06 15:04:05"
+
+// DatecsClock is the clock protocol of the Datecs fiscal printers (FP and DP
+// series): commands 61 and 62 of their fiscal protocol, exchanging the local
+// time. Use a pointer, the protocol numbering the frames it sends:
+//
+//	p.SetProfile(escpos.Profile{Name: "Datecs FP-700", Clock: &escpos.DatecsClock{}})
+type DatecsClock struct {
+	seq byte
+}
+
+// SetCommand returns the frame of command 61, setting the date and time
+func (c *DatecsClock) SetCommand(t time.Time) ([]byte, error) {
+	return c.frame(datecsSetDateTime, []byte(t.Local().Format(datecsTimeLayout))), nil
+}
+
+// ReadCommand returns the frame of command 62, reading the date and time
+func (c *DatecsClock) ReadCommand() []byte {
+	return c.frame(datecsReadDateTime, nil)
+}
+
+// Terminator returns the byte ending the Datecs frames
+func (c *DatecsClock) Terminator() byte {
+	return datecsTerminator
+}
+
+// ParseTime parses the reply to command 62:
+// 01 LEN SEQ CMD DATA 04 STATUS(6 bytes) 05 BCC(4 bytes)
+func (c *DatecsClock) ParseTime(resp []byte) (time.Time, error) {
+	// The printer sends SYN bytes while it processes the command
+	i := bytes.LastIndexByte(resp, datecsPreamble)
+	if i < 0 {
+		return time.Time{}, fmt.Errorf("missing frame preamble")
+	}
+	frame := resp[i+1:]
+	if len(frame) < 15 || frame[len(frame)-5] != datecsPostamble || frame[len(frame)-12] != datecsSeparator {
+		return time.Time{}, fmt.Errorf("malformed frame: % X", resp)
+	}
+	body := frame[:len(frame)-4]
+	if int(frame[0]) != 0x20+len(body) {
+		return time.Time{}, fmt.Errorf("invalid frame length %#x", frame[0])
+	}
+	if !bytes.Equal(frame[len(body):], datecsChecksum(body)) {
+		return time.Time{}, fmt.Errorf("invalid frame checksum")
+	}
+	if frame[1] != c.seq || frame[2] != datecsReadDateTime {
+		return time.Time{}, fmt.Errorf("unexpected reply to command %d, sequence %#x", frame[2], frame[1])
+	}
+	return time.ParseInLocation(datecsTimeLayout, string(frame[3:len(frame)-12]), time.Local)
+}
+
+// frame wraps a command and its data in a frame with the next sequence number:
+// 01 LEN SEQ CMD DATA 05 BCC(4 bytes) 03
+func (c *DatecsClock) frame(cmd byte, data []byte) []byte {
+	if c.seq < 0x20 || c.seq >= 0x7F {
+		c.seq = 0x20
+	} else {
+		c.seq++
+	}
+	body := append([]byte{byte(0x20 + 4 + len(data)), c.seq, cmd}, data...)
+	body = append(body, datecsPostamble)
+
+	frame := append([]byte{datecsPreamble}, body...)
+	frame = append(frame, datecsChecksum(body)...)
+	return append(frame, datecsTerminator)
+}
+
+// datecsChecksum returns the sum of the bytes from LEN to the postamble, as
+// four nibbles offset by 0x30
+func datecsChecksum(body []byte) []byte {
+	var sum uint16
+	for _, b := range body {
+		sum += uint16(b)
+	}
+	return []byte{
+		0x30 + byte(sum>>12&0x0F),
+		0x30 + byte(sum>>8&0x0F),
+		0x30 + byte(sum>>4&0x0F),
+		0x30 + byte(sum&0x0F),
+	}
+}
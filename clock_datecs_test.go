@@ -0,0 +1,63 @@
+package escpos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDatecsClock tests the Datecs fiscal protocol frames of the clock commands
+func TestDatecsClock(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Clock: &DatecsClock{}})
+
+	_, err := p.SetClock(time.Date(2024, 3, 15, 9, 30, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	set := append([]byte{0x01, 0x35, 0x20, 0x3D}, "15-03-24 09:30:00"...)
+	set = append(set, 0x05, '0', '3', '>', '0', 0x03)
+	assert.Equal(t, set, mock.Bytes())
+
+	// Reply preceded by SYN bytes, status bytes all clear
+	reply := append([]byte{0x16, 0x16, 0x01, 0x3C, 0x21, 0x3E}, "15-03-24 09:30:00"...)
+	reply = append(reply, 0x04, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x05, '0', '6', '>', '=', 0x03)
+	mock.SetStatus(reply)
+	now, err := p.ReadClock()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 15, 9, 30, 0, 0, time.Local), now)
+	assert.Equal(t, []byte{0x01, 0x24, 0x21, 0x3E, 0x05, '0', '0', '8', '8', 0x03}, mock.Bytes()[len(set):])
+}
+
+// TestDatecsClockInvalidReplies tests rejecting the corrupted and stale replies
+func TestDatecsClockInvalidReplies(t *testing.T) {
+	reply := append([]byte{0x01, 0x3C, 0x21, 0x3E}, "15-03-24 09:30:00"...)
+	reply = append(reply, 0x04, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x05, '0', '6', '>', '=')
+
+	clock := &DatecsClock{seq: 0x21}
+	_, err := clock.ParseTime(reply)
+	require.NoError(t, err)
+
+	corrupted := append([]byte{}, reply...)
+	corrupted[5] = '2'
+	_, err = clock.ParseTime(corrupted)
+	assert.ErrorContains(t, err, "checksum")
+
+	_, err = (&DatecsClock{seq: 0x22}).ParseTime(reply)
+	assert.ErrorContains(t, err, "sequence")
+
+	_, err = clock.ParseTime(reply[:10])
+	assert.ErrorContains(t, err, "malformed")
+
+	_, err = clock.ParseTime([]byte{0x16})
+	assert.ErrorContains(t, err, "preamble")
+}
+
+// TestDatecsClockSequence tests the sequence numbers wrapping around
+func TestDatecsClockSequence(t *testing.T) {
+	clock := &DatecsClock{seq: 0x7E}
+	assert.Equal(t, byte(0x7F), clock.ReadCommand()[2])
+	assert.Equal(t, byte(0x20), clock.ReadCommand()[2])
+}
@@ -0,0 +1,62 @@
+package escpos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClock is a clock protocol exchanging the time as "YYMMDDhhmmss"
+type testClock struct{}
+
+func (testClock) SetCommand(t time.Time) ([]byte, error) {
+	return append([]byte{esc, 'T'}, t.Format("060102150405")...), nil
+}
+
+func (testClock) ReadCommand() []byte {
+	return []byte{esc, 't', 'T'}
+}
+
+func (testClock) Terminator() byte {
+	return 0
+}
+
+func (testClock) ParseTime(resp []byte) (time.Time, error) {
+	return time.Parse("060102150405", string(resp))
+}
+
+// TestClock tests setting and reading the printer clock
+func TestClock(t *testing.T) {
+	mock := NewMockPrinter()
+	mock.SetStatus([]byte("240315093000\x00"))
+	p := New(mock)
+	p.SetProfile(Profile{Clock: testClock{}})
+
+	_, err := p.SetClock(time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	now, err := p.ReadClock()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC), now)
+
+	expected := append([]byte{esc, 'T'}, "240315093000"...)
+	expected = append(expected, esc, 't', 'T')
+	assert.Equal(t, expected, mock.Bytes())
+
+	mock.SetStatus([]byte("garbage\x00"))
+	_, err = p.ReadClock()
+	assert.Error(t, err)
+}
+
+// TestClockUnsupported tests the printers without a clock
+func TestClockUnsupported(t *testing.T) {
+	p := New(NewMockPrinter())
+	p.SetProfile(Profile{Name: "Test printer"})
+
+	_, err := p.SetClock(time.Now())
+	assert.ErrorContains(t, err, "Test printer has no real-time clock")
+	_, err = p.ReadClock()
+	assert.Error(t, err)
+}
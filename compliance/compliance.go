@@ -0,0 +1,153 @@
+// Package compliance adds the legal requirements of fiscal receipts to
+// documents: mandatory fields, header and footer blocks and numbering rules.
+// Each jurisdiction is a Module, so the core package stays generic and the
+// modules needed by a shop are composed with Build.
+package compliance
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/schawnndev/escpos"
+)
+
+// Receipt holds the data of a receipt that the modules check and print
+type Receipt struct {
+	// Register identifies the cash register, each one numbering its own series
+	Register string
+	// Time is the time of the sale
+	Time time.Time
+	// Number is assigned by the modules numbering the receipts
+	Number string
+	// Fields holds the merchant and transaction data, by the names defined
+	// by the modules (e.g. FieldSIRET)
+	Fields map[string]string
+}
+
+// Module implements the rules of a jurisdiction
+type Module interface {
+	// Name of the jurisdiction, used in error messages
+	Name() string
+	// Validate checks the mandatory fields of a receipt
+	Validate(r Receipt) error
+	// Numbers reports whether the module numbers the receipts
+	Numbers() bool
+	// Number assigns the number of the receipt, called only when Numbers
+	// returns true
+	Number(r *Receipt) error
+	// Header writes the block printed before the body of the receipt
+	Header(d *escpos.Document, r Receipt) error
+	// Footer writes the block printed after the body of the receipt
+	Footer(d *escpos.Document, r Receipt) error
+}
+
+// UnusedNumberError is returned by Build when the receipt failed after being
+// numbered. The number is consumed, so the application must record it, for
+// instance as a voided receipt, to keep the series without gaps.
+type UnusedNumberError struct {
+	// Number is the number assigned to the receipt
+	Number string
+	// Err is the failure that followed the numbering
+	Err error
+}
+
+func (e *UnusedNumberError) Error() string {
+	return fmt.Sprintf("%v (receipt number %s unused)", e.Err, e.Number)
+}
+
+func (e *UnusedNumberError) Unwrap() error {
+	return e.Err
+}
+
+// Build checks the receipt against the modules, numbers it, then returns a
+// document made of the module headers, the body and the module footers. The
+// modules are applied in order; at most one of them may number the receipt,
+// and only once all of them accept it, so no number is lost to an invalid
+// receipt. A failure after the numbering returns an UnusedNumberError.
+func Build(body *escpos.Document, r *Receipt, modules ...Module) (*escpos.Document, error) {
+	var numbering Module
+	for _, m := range modules {
+		if m.Numbers() {
+			if numbering != nil {
+				return nil, fmt.Errorf("%s and %s both number the receipt", numbering.Name(), m.Name())
+			}
+			numbering = m
+		}
+	}
+	for _, m := range modules {
+		if err := m.Validate(*r); err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Name(), err)
+		}
+	}
+	if numbering == nil {
+		return compose(body, *r, modules)
+	}
+	if err := numbering.Number(r); err != nil {
+		return nil, fmt.Errorf("%s: failed to number the receipt: %w", numbering.Name(), err)
+	}
+	d, err := compose(body, *r, modules)
+	if err != nil {
+		return nil, &UnusedNumberError{Number: r.Number, Err: err}
+	}
+	return d, nil
+}
+
+// compose writes the module headers, the body and the module footers
+func compose(body *escpos.Document, r Receipt, modules []Module) (*escpos.Document, error) {
+	d := escpos.NewDocument()
+	for _, m := range modules {
+		if err := m.Header(d, r); err != nil {
+			return nil, fmt.Errorf("%s: failed to write the header: %w", m.Name(), err)
+		}
+	}
+	if err := d.Embed(body); err != nil {
+		return nil, err
+	}
+	for i := len(modules) - 1; i >= 0; i-- {
+		if err := modules[i].Footer(d, r); err != nil {
+			return nil, fmt.Errorf("%s: failed to write the footer: %w", modules[i].Name(), err)
+		}
+	}
+	return d, nil
+}
+
+// requireFields checks that the receipt has a value for each field
+func requireFields(r Receipt, names ...string) error {
+	for _, name := range names {
+		if r.Fields[name] == "" {
+			return fmt.Errorf("missing mandatory field %q", name)
+		}
+	}
+	return nil
+}
+
+// Counter hands out the receipt numbers of each series, without gaps
+type Counter interface {
+	// Next returns the next number of a series, starting at 1
+	Next(series string) (uint64, error)
+}
+
+// MemoryCounter is a Counter kept in memory, for tests and for applications
+// persisting the last numbers themselves
+type MemoryCounter struct {
+	mu   sync.Mutex
+	last map[string]uint64
+}
+
+// NewMemoryCounter creates a counter starting each series after its number in last
+func NewMemoryCounter(last map[string]uint64) *MemoryCounter {
+	c := &MemoryCounter{last: make(map[string]uint64, len(last))}
+	for series, n := range last {
+		c.last[series] = n
+	}
+	return c
+}
+
+// Next returns the next number of a series
+func (c *MemoryCounter) Next(series string) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.last[series]++
+	return c.last[series], nil
+}
@@ -0,0 +1,118 @@
+package compliance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/schawnndev/escpos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingModule records the calls made by Build
+type recordingModule struct {
+	name       string
+	calls      *[]string
+	invalid    bool
+	numbers    bool
+	failHeader bool
+}
+
+func (m recordingModule) Name() string { return m.name }
+
+func (m recordingModule) Validate(r Receipt) error {
+	*m.calls = append(*m.calls, m.name+" validate")
+	if m.invalid {
+		return assert.AnError
+	}
+	return nil
+}
+
+func (m recordingModule) Numbers() bool { return m.numbers }
+
+func (m recordingModule) Number(r *Receipt) error {
+	*m.calls = append(*m.calls, m.name+" number")
+	r.Number = m.name
+	return nil
+}
+
+func (m recordingModule) Header(d *escpos.Document, r Receipt) error {
+	if m.failHeader {
+		return assert.AnError
+	}
+	_, err := d.Write("[" + m.name + "]")
+	return err
+}
+
+func (m recordingModule) Footer(d *escpos.Document, r Receipt) error {
+	_, err := d.Write("[/" + m.name + "]")
+	return err
+}
+
+// TestBuild tests the order in which the modules are applied
+func TestBuild(t *testing.T) {
+	var calls []string
+	body := escpos.NewDocument()
+	_, err := body.Write("body")
+	require.NoError(t, err)
+
+	r := &Receipt{}
+	d, err := Build(body, r, recordingModule{name: "a", calls: &calls}, recordingModule{name: "b", calls: &calls, numbers: true})
+	require.NoError(t, err)
+
+	data, err := d.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, "[a][b]body[/b][/a]", string(data))
+	assert.Equal(t, []string{"a validate", "b validate", "b number"}, calls)
+	assert.Equal(t, "b", r.Number)
+}
+
+// TestBuildNumberedTwice tests rejecting two modules numbering the receipt
+func TestBuildNumberedTwice(t *testing.T) {
+	counter := NewMemoryCounter(nil)
+	_, err := Build(escpos.NewDocument(), &Receipt{Time: testTime}, France{Counter: counter}, Germany{})
+	assert.ErrorContains(t, err, "France and Germany both number the receipt")
+
+	// No number is consumed
+	n, err := counter.Next("")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), n)
+}
+
+// TestBuildUnusedNumber tests reporting the number of a receipt failing after its numbering
+func TestBuildUnusedNumber(t *testing.T) {
+	var calls []string
+	counter := NewMemoryCounter(map[string]uint64{"R1": 41})
+	_, err := Build(escpos.NewDocument(), frenchReceipt(), France{Counter: counter}, recordingModule{name: "b", calls: &calls, failHeader: true})
+	assert.ErrorIs(t, err, assert.AnError)
+
+	var unused *UnusedNumberError
+	require.ErrorAs(t, err, &unused)
+	assert.Equal(t, "R1-00000042", unused.Number)
+	assert.ErrorContains(t, err, "receipt number R1-00000042 unused")
+}
+
+// TestBuildInvalid tests that an invalid receipt is not numbered
+func TestBuildInvalid(t *testing.T) {
+	var calls []string
+	_, err := Build(escpos.NewDocument(), &Receipt{}, recordingModule{name: "a", calls: &calls}, recordingModule{name: "b", calls: &calls, invalid: true})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ErrorContains(t, err, "b: ")
+	assert.Equal(t, []string{"a validate", "b validate"}, calls)
+}
+
+// TestMemoryCounter tests numbering several series
+func TestMemoryCounter(t *testing.T) {
+	c := NewMemoryCounter(map[string]uint64{"R1": 41})
+	for _, tt := range []struct {
+		series   string
+		expected uint64
+	}{{"R1", 42}, {"R2", 1}, {"R1", 43}} {
+		n, err := c.Next(tt.series)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, n)
+	}
+}
+
+// testTime is the time of the test receipts
+var testTime = time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
@@ -0,0 +1,75 @@
+package compliance
+
+import (
+	"fmt"
+
+	"github.com/schawnndev/escpos"
+)
+
+// Fields of the French receipts
+const (
+	FieldMerchantName = "merchant_name"
+	FieldAddress      = "address"
+	FieldSIRET        = "siret"  // company registration number
+	FieldVATID        = "vat_id" // intra-community VAT number
+)
+
+// France implements the French rules: the merchant identification is
+// mandatory and receipts are numbered continuously per register
+type France struct {
+	// Counter numbers the receipts, by register
+	Counter Counter
+}
+
+// Name returns the name of the jurisdiction
+func (f France) Name() string {
+	return "France"
+}
+
+// Validate checks the merchant identification
+func (f France) Validate(r Receipt) error {
+	if f.Counter == nil {
+		return fmt.Errorf("no receipt counter")
+	}
+	if r.Time.IsZero() {
+		return fmt.Errorf("missing receipt time")
+	}
+	return requireFields(r, FieldMerchantName, FieldAddress, FieldSIRET, FieldVATID)
+}
+
+// Numbers returns true, France numbering the receipts
+func (f France) Numbers() bool {
+	return true
+}
+
+// Number assigns the next number of the register, such as "R1-00000042"
+func (f France) Number(r *Receipt) error {
+	n, err := f.Counter.Next(r.Register)
+	if err != nil {
+		return err
+	}
+	r.Number = fmt.Sprintf("%08d", n)
+	if r.Register != "" {
+		r.Number = r.Register + "-" + r.Number
+	}
+	return nil
+}
+
+// Header prints the merchant identification
+func (f France) Header(d *escpos.Document, r Receipt) error {
+	if _, err := d.SetJustify(escpos.JustifyCenter); err != nil {
+		return err
+	}
+	_, err := d.Write(fmt.Sprintf("%s\n%s\nSIRET %s\n", r.Fields[FieldMerchantName], r.Fields[FieldAddress], r.Fields[FieldSIRET]))
+	if err != nil {
+		return err
+	}
+	_, err = d.SetJustify(escpos.JustifyLeft)
+	return err
+}
+
+// Footer prints the receipt number, the time and the VAT number
+func (f France) Footer(d *escpos.Document, r Receipt) error {
+	_, err := d.Write(fmt.Sprintf("Ticket n° %s\n%s\nTVA %s\n", r.Number, r.Time.Format("02/01/2006 15:04:05"), r.Fields[FieldVATID]))
+	return err
+}
@@ -0,0 +1,58 @@
+package compliance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frenchReceipt returns a receipt with the French mandatory fields
+func frenchReceipt() *Receipt {
+	return &Receipt{
+		Register: "R1",
+		Time:     testTime,
+		Fields: map[string]string{
+			FieldMerchantName: "Boulangerie Martin",
+			FieldAddress:      "1 rue de la Paix, Paris",
+			FieldSIRET:        "12345678900011",
+			FieldVATID:        "FR12345678901",
+		},
+	}
+}
+
+// TestFrance tests the French header, footer and numbering
+func TestFrance(t *testing.T) {
+	f := France{Counter: NewMemoryCounter(map[string]uint64{"R1": 41})}
+	r := frenchReceipt()
+
+	d, err := Build(escpos.NewDocument(), r, f)
+	require.NoError(t, err)
+	assert.Equal(t, "R1-00000042", r.Number)
+
+	data, err := d.Bytes()
+	require.NoError(t, err)
+	text := string(data)
+	assert.Contains(t, text, "Boulangerie Martin\n1 rue de la Paix, Paris\nSIRET 12345678900011\n")
+	assert.Contains(t, text, "R1-00000042\n01/03/2024 12:30:00\nTVA FR12345678901\n")
+	// "°" in the default code page
+	assert.Contains(t, text, "Ticket n\xf8 ")
+	assert.True(t, strings.HasPrefix(text, "\x1ba\x01"))
+}
+
+// TestFranceMissingField tests the mandatory fields
+func TestFranceMissingField(t *testing.T) {
+	counter := NewMemoryCounter(nil)
+	r := frenchReceipt()
+	delete(r.Fields, FieldSIRET)
+
+	_, err := Build(escpos.NewDocument(), r, France{Counter: counter})
+	assert.ErrorContains(t, err, `France: missing mandatory field "siret"`)
+
+	// No number was used
+	n, err := counter.Next("R1")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), n)
+}
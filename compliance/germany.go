@@ -0,0 +1,66 @@
+package compliance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/schawnndev/escpos"
+)
+
+// Fields of the German receipts, read from the technical security system (TSE)
+const (
+	FieldTSESerial      = "tse_serial"
+	FieldTSETransaction = "tse_transaction"
+	FieldTSESignature   = "tse_signature"
+	FieldTSECounter     = "tse_signature_counter"
+	FieldTSEStart       = "tse_start" // RFC 3339 start time of the transaction
+)
+
+// Germany implements the German rules (KassenSichV): every receipt prints
+// the data of its TSE transaction, the transaction number numbering it
+type Germany struct{}
+
+// Name returns the name of the jurisdiction
+func (Germany) Name() string {
+	return "Germany"
+}
+
+// Validate checks the TSE data
+func (Germany) Validate(r Receipt) error {
+	if r.Time.IsZero() {
+		return fmt.Errorf("missing receipt time")
+	}
+	if err := requireFields(r, FieldTSESerial, FieldTSETransaction, FieldTSESignature, FieldTSECounter, FieldTSEStart); err != nil {
+		return err
+	}
+	if _, err := time.Parse(time.RFC3339, r.Fields[FieldTSEStart]); err != nil {
+		return fmt.Errorf("invalid TSE start time: %w", err)
+	}
+	return nil
+}
+
+// Numbers returns true, the TSE numbering the receipts
+func (Germany) Numbers() bool {
+	return true
+}
+
+// Number uses the TSE transaction number
+func (Germany) Number(r *Receipt) error {
+	r.Number = r.Fields[FieldTSETransaction]
+	return nil
+}
+
+// Header prints nothing, the merchant identification not being regulated
+func (Germany) Header(d *escpos.Document, r Receipt) error {
+	return nil
+}
+
+// Footer prints the TSE data
+func (Germany) Footer(d *escpos.Document, r Receipt) error {
+	start, _ := time.Parse(time.RFC3339, r.Fields[FieldTSEStart])
+	const layout = "2006-01-02T15:04:05"
+	_, err := d.Write(fmt.Sprintf("TSE-Seriennummer: %s\nTransaktion: %s\nSignaturzaehler: %s\nStart: %s\nEnde: %s\nSignatur: %s\n",
+		r.Fields[FieldTSESerial], r.Fields[FieldTSETransaction], r.Fields[FieldTSECounter],
+		start.Format(layout), r.Time.Format(layout), r.Fields[FieldTSESignature]))
+	return err
+}
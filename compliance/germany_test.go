@@ -0,0 +1,50 @@
+package compliance
+
+import (
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// germanReceipt returns a receipt with the TSE data
+func germanReceipt() *Receipt {
+	return &Receipt{
+		Time: testTime,
+		Fields: map[string]string{
+			FieldTSESerial:      "a1b2c3",
+			FieldTSETransaction: "1234",
+			FieldTSESignature:   "c2lnbmF0dXJl",
+			FieldTSECounter:     "5678",
+			FieldTSEStart:       "2024-03-01T12:29:40Z",
+		},
+	}
+}
+
+// TestGermany tests the TSE footer and numbering
+func TestGermany(t *testing.T) {
+	r := germanReceipt()
+	d, err := Build(escpos.NewDocument(), r, Germany{})
+	require.NoError(t, err)
+	assert.Equal(t, "1234", r.Number)
+
+	data, err := d.Bytes()
+	require.NoError(t, err)
+	expected := "TSE-Seriennummer: a1b2c3\nTransaktion: 1234\nSignaturzaehler: 5678\n" +
+		"Start: 2024-03-01T12:29:40\nEnde: 2024-03-01T12:30:00\nSignatur: c2lnbmF0dXJl\n"
+	assert.Equal(t, expected, string(data))
+}
+
+// TestGermanyInvalid tests the TSE data checks
+func TestGermanyInvalid(t *testing.T) {
+	r := germanReceipt()
+	delete(r.Fields, FieldTSESignature)
+	_, err := Build(escpos.NewDocument(), r, Germany{})
+	assert.ErrorContains(t, err, "tse_signature")
+
+	r = germanReceipt()
+	r.Fields[FieldTSEStart] = "yesterday"
+	_, err = Build(escpos.NewDocument(), r, Germany{})
+	assert.ErrorContains(t, err, "invalid TSE start time")
+}
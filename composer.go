@@ -0,0 +1,37 @@
+package escpos
+
+// Composer builds commands in memory with the methods of Escpos, without a
+// printer connection: a receipt can be composed once, then stored, sent to
+// several printers or compared in tests. Document adds embedded fragments,
+// savepoints and reverse output on top of the same idea.
+type Composer struct {
+	*Escpos
+	buf *documentBuffer
+}
+
+// NewComposer creates an empty composer, configured by the options of New
+func NewComposer(opts ...Option) *Composer {
+	buf := &documentBuffer{}
+	return &Composer{Escpos: New(buf, opts...), buf: buf}
+}
+
+// Bytes returns a copy of the commands composed so far. Flushing them to
+// the memory buffer fails when an option needs the printer, such as
+// WithFlowControl.
+func (c *Composer) Bytes() ([]byte, error) {
+	if err := c.dst.Flush(); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), c.buf.Bytes()...), nil
+}
+
+// Len returns the size of the commands composed so far in bytes
+func (c *Composer) Len() int {
+	return c.buf.Len() + c.dst.Buffered()
+}
+
+// Reset discards the commands composed so far, keeping the style and settings
+func (c *Composer) Reset() {
+	c.dst.Reset(c.out)
+	c.buf.Reset()
+}
@@ -0,0 +1,49 @@
+package escpos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestComposer tests composing commands without a printer
+func TestComposer(t *testing.T) {
+	c := NewComposer()
+	c.SetBold(true)
+	c.Write("Total")
+	c.Cut()
+
+	expected := "\x1bE\x01Total\x1dVA\x00"
+	data, err := c.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte(expected), data)
+	assert.Equal(t, len(expected), c.Len())
+
+	// The composed commands are sent as is
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.WriteRaw(data)
+	require.NoError(t, p.Print())
+	assert.Equal(t, expected, mock.String())
+
+	c.Reset()
+	data, err = c.Bytes()
+	require.NoError(t, err)
+	assert.Empty(t, data)
+	assert.True(t, c.Style.Bold)
+}
+
+// TestComposerOptions tests configuring a composer with the options of New
+func TestComposerOptions(t *testing.T) {
+	c := NewComposer(WithAutoFlush(1))
+	c.Write("a")
+	assert.Equal(t, 1, c.buf.Len())
+
+	// Flow control needs the status of a printer
+	c = NewComposer(WithFlowControl(4, time.Millisecond))
+	c.Write("Total")
+	_, err := c.Bytes()
+	assert.Error(t, err)
+}
@@ -0,0 +1,210 @@
+package escpos
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Compression methods for links between two instances of this package
+const (
+	CompressionNone uint8 = 0 // no compression
+	CompressionZlib uint8 = 1 // zlib stream (RFC 1950)
+	CompressionGzip uint8 = 2 // gzip stream (RFC 1952)
+)
+
+// compressionMagic starts the handshake sent by NewCompressedPrinter.
+// It begins with a NUL byte, which rarely starts a regular ESC/POS job, so
+// AcceptCompressed can tell compressing and plain clients apart.
+var compressionMagic = []byte{0x00, 'E', 'S', 'C', 'Z'}
+
+// flushWriteCloser is implemented by the zlib and gzip writers
+type flushWriteCloser interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// compressedPrinter compresses everything written to the remote gateway
+type compressedPrinter struct {
+	inner Printer
+	w     flushWriteCloser // nil when the gateway refused compression
+}
+
+// NewCompressedPrinter negotiates stream compression with a remote gateway
+// running AcceptCompressed, and returns a Printer compressing all the data
+// written to it. methods lists the accepted compression methods by order of
+// preference (default: zlib, then gzip).
+//
+// Only use it when the remote end is another instance of this package: a raw
+// printer would print the handshake. Data read from the gateway (status
+// replies) is not compressed.
+func NewCompressedPrinter(inner Printer, methods ...uint8) (Printer, error) {
+	if len(methods) == 0 {
+		methods = []uint8{CompressionZlib, CompressionGzip}
+	}
+	if len(methods) > 255 {
+		return nil, fmt.Errorf("too many compression methods")
+	}
+
+	offer := append(bytes.Clone(compressionMagic), byte(len(methods)))
+	offer = append(offer, methods...)
+	if _, err := WriteFull(inner, offer); err != nil {
+		return nil, fmt.Errorf("failed to send compression offer: %w", err)
+	}
+
+	reply := make([]byte, 1)
+	if _, err := io.ReadFull(inner, reply); err != nil {
+		return nil, fmt.Errorf("failed to read compression reply: %w", err)
+	}
+
+	if reply[0] != CompressionNone && !slices.Contains(methods, reply[0]) {
+		return nil, fmt.Errorf("gateway selected a compression method that was not offered: %d", reply[0])
+	}
+
+	cp := &compressedPrinter{inner: inner}
+	switch reply[0] {
+	case CompressionNone:
+	case CompressionZlib:
+		cp.w = zlib.NewWriter(fullWriter{inner})
+	case CompressionGzip:
+		cp.w = gzip.NewWriter(fullWriter{inner})
+	default:
+		return nil, fmt.Errorf("gateway selected an unknown compression method: %d", reply[0])
+	}
+
+	return cp, nil
+}
+
+// Write compresses p and flushes the compressor so the data is sent right away
+func (cp *compressedPrinter) Write(p []byte) (int, error) {
+	if cp.w == nil {
+		return WriteFull(cp.inner, p)
+	}
+	n, err := cp.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, cp.w.Flush()
+}
+
+func (cp *compressedPrinter) Read(p []byte) (int, error) {
+	return cp.inner.Read(p)
+}
+
+// Transport describes the connection for Diagnostics
+func (cp *compressedPrinter) Transport() string {
+	method := "uncompressed"
+	switch cp.w.(type) {
+	case *zlib.Writer:
+		method = "zlib"
+	case *gzip.Writer:
+		method = "gzip"
+	}
+	return transportName(cp.inner) + " (" + method + ")"
+}
+
+// Close terminates the compressed stream and closes the underlying connection
+func (cp *compressedPrinter) Close() error {
+	if cp.w != nil {
+		if err := cp.w.Close(); err != nil {
+			cp.inner.Close()
+			return err
+		}
+	}
+	return cp.inner.Close()
+}
+
+// acceptedConn is the gateway side of a connection accepted by AcceptCompressed
+type acceptedConn struct {
+	conn   io.ReadWriteCloser
+	src    io.Reader // connection data, including the bytes read while detecting the handshake
+	method uint8
+	r      io.Reader // decompressor, created on first Read
+}
+
+// AcceptCompressed is the gateway side of NewCompressedPrinter. It inspects
+// the start of a client connection and, when the client offers compression,
+// selects the first offered method listed in methods (default: zlib and gzip)
+// and returns a connection whose Read yields the decompressed job stream.
+//
+// Clients whose stream does not start with the handshake (plain ESC/POS
+// senders, including the jobs padded with NUL bytes) are passed through
+// unchanged. Data written back to the client is never compressed.
+func AcceptCompressed(conn io.ReadWriteCloser, methods ...uint8) (io.ReadWriteCloser, error) {
+	if len(methods) == 0 {
+		methods = []uint8{CompressionZlib, CompressionGzip}
+	}
+
+	br := bufio.NewReader(conn)
+	ac := &acceptedConn{conn: conn, src: br}
+
+	// Bytes are only awaited while they match the handshake, so a plain
+	// client waiting for a status reply is not blocked
+	for n := 1; n <= len(compressionMagic); n++ {
+		head, err := br.Peek(n)
+		if len(head) < n {
+			if n == 1 {
+				return nil, fmt.Errorf("failed to read from client: %w", err)
+			}
+			return ac, nil // plain client ending its stream
+		}
+		if head[n-1] != compressionMagic[n-1] {
+			return ac, nil // plain client
+		}
+	}
+	br.Discard(len(compressionMagic))
+
+	count, err := br.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compression offer: %w", err)
+	}
+	offered := make([]byte, count)
+	if _, err := io.ReadFull(br, offered); err != nil {
+		return nil, fmt.Errorf("failed to read compression offer: %w", err)
+	}
+
+	ac.method = CompressionNone
+	for _, m := range offered {
+		if m != CompressionNone && slices.Contains(methods, m) {
+			ac.method = m
+			break
+		}
+	}
+
+	if _, err := conn.Write([]byte{ac.method}); err != nil {
+		return nil, fmt.Errorf("failed to send compression reply: %w", err)
+	}
+
+	return ac, nil
+}
+
+// Read returns the decompressed data sent by the client
+func (ac *acceptedConn) Read(p []byte) (int, error) {
+	if ac.r == nil {
+		var err error
+		switch ac.method {
+		case CompressionZlib:
+			ac.r, err = zlib.NewReader(ac.src)
+		case CompressionGzip:
+			ac.r, err = gzip.NewReader(ac.src)
+		default:
+			ac.r = ac.src
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to start decompression: %w", err)
+		}
+	}
+	return ac.r.Read(p)
+}
+
+func (ac *acceptedConn) Write(p []byte) (int, error) {
+	return ac.conn.Write(p)
+}
+
+func (ac *acceptedConn) Close() error {
+	return ac.conn.Close()
+}
@@ -0,0 +1,107 @@
+package escpos
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingConn counts the bytes written on the wire
+type countingConn struct {
+	net.Conn
+	written int
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.written += n
+	return n, err
+}
+
+// acceptAndRead runs the gateway side of a link and returns everything received
+func acceptAndRead(conn net.Conn, methods ...uint8) <-chan []byte {
+	received := make(chan []byte, 1)
+	go func() {
+		defer conn.Close()
+		ac, err := AcceptCompressed(conn, methods...)
+		if err != nil {
+			received <- nil
+			return
+		}
+		data, _ := io.ReadAll(ac)
+		received <- data
+	}()
+	return received
+}
+
+// TestCompressedLink tests sending a job through a compressed link
+func TestCompressedLink(t *testing.T) {
+	for _, method := range []uint8{CompressionZlib, CompressionGzip} {
+		client, server := net.Pipe()
+		received := acceptAndRead(server)
+
+		wire := &countingConn{Conn: client}
+		cp, err := NewCompressedPrinter(wire, method)
+		require.NoError(t, err)
+
+		job := bytes.Repeat([]byte{0xFF, 0x00, 0xAA}, 10000)
+		p := New(cp)
+		_, err = p.WriteRaw(job)
+		require.NoError(t, err)
+		require.NoError(t, p.Print())
+		require.NoError(t, cp.Close())
+
+		assert.Equal(t, job, <-received)
+		assert.Less(t, wire.written, len(job)/10, "the job should be compressed on the wire")
+	}
+}
+
+// TestCompressedLinkRefused tests a gateway that does not accept any offered method
+func TestCompressedLinkRefused(t *testing.T) {
+	client, server := net.Pipe()
+	received := acceptAndRead(server, CompressionGzip)
+
+	cp, err := NewCompressedPrinter(client, CompressionZlib)
+	require.NoError(t, err)
+
+	_, err = cp.Write([]byte("plain"))
+	require.NoError(t, err)
+	require.NoError(t, cp.Close())
+
+	assert.Equal(t, []byte("plain"), <-received)
+}
+
+// TestAcceptCompressedPlainClient tests that clients not offering compression are passed through
+func TestAcceptCompressedPlainClient(t *testing.T) {
+	client, server := net.Pipe()
+	received := acceptAndRead(server)
+
+	job := []byte{esc, '@', 'H', 'i', '\n'}
+	_, err := client.Write(job)
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	assert.Equal(t, job, <-received)
+}
+
+// TestAcceptCompressedNULPadding tests that plain jobs starting with NUL bytes are passed through
+func TestAcceptCompressedNULPadding(t *testing.T) {
+	for _, job := range [][]byte{
+		{0, 0, 0, esc, '@', 'H', 'i', '\n'},
+		{0, 'E', 'S', 'C', '!', 0},
+		{0, 'E'},
+	} {
+		client, server := net.Pipe()
+		received := acceptAndRead(server)
+
+		_, err := client.Write(job)
+		require.NoError(t, err)
+		require.NoError(t, client.Close())
+
+		assert.Equal(t, job, <-received)
+	}
+}
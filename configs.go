@@ -5,3 +5,8 @@ var (
 	ConfigEpsonTMT88II = PrinterConfig{DisableUpsideDown: true}
 	ConfigSOL802       = PrinterConfig{DisableUpsideDown: true}
 )
+
+var (
+	ProfileEpsonTMT20II = Profile{Name: "Epson TM-T20II", MaxImageHeight: 2303, Fonts: []uint8{FontA, FontB}}
+	ProfileStarTSP650II = Profile{Name: "Star TSP650II", Dialect: DialectStarLine, NoQRCode: true}
+)
@@ -0,0 +1,48 @@
+package escpos
+
+import "fmt"
+
+// Alignments of the printer counter
+const (
+	CounterRightSpaces uint8 = 0 // right aligned, padded with spaces
+	CounterRightZeros  uint8 = 1 // right aligned, padded with zeros
+	CounterLeft        uint8 = 2 // left aligned, padded with spaces
+)
+
+// SetCounterFormat sets the print format of the printer counter (GS C 0):
+// the number of digits (1-5, 0: as many as the value has) and the alignment
+// (one of the Counter* constants). Only the lower digits of longer values
+// are printed.
+func (e *Escpos) SetCounterFormat(digits, alignment uint8) (int, error) {
+	if digits > 5 {
+		return 0, fmt.Errorf("invalid counter digits %d, must be 0-5", digits)
+	}
+	if alignment > CounterLeft {
+		return 0, fmt.Errorf("invalid counter alignment %d", alignment)
+	}
+	return e.WriteRaw([]byte{gs, 'C', '0', digits, alignment})
+}
+
+// SetCounterRange sets the counting of the printer counter (GS C 1): it
+// counts from from to to by step, printing each value repeat times, then
+// starts again at from. It counts down when from is greater than to. A step
+// or repeat of 0 freezes the counter.
+func (e *Escpos) SetCounterRange(from, to uint16, step, repeat uint8) (int, error) {
+	if from == to {
+		return 0, fmt.Errorf("counter range %d-%d is empty", from, to)
+	}
+	return e.WriteRaw([]byte{gs, 'C', '1', byte(from), byte(from >> 8), byte(to), byte(to >> 8), step, repeat})
+}
+
+// SetCounterValue sets the value printed next by the printer counter (GS C 2)
+func (e *Escpos) SetCounterValue(value uint16) (int, error) {
+	return e.WriteRaw([]byte{gs, 'C', '2', byte(value), byte(value >> 8)})
+}
+
+// PrintCounter prints the value of the printer counter inline, then counts
+// (GS c). Numbered tickets, such as queue tickets or raffle stubs, can be
+// printed from the same job data without keeping the number in the
+// application.
+func (e *Escpos) PrintCounter() (int, error) {
+	return e.WriteRaw([]byte{gs, 'c'})
+}
@@ -0,0 +1,43 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCounter tests the printer counter commands
+func TestCounter(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.SetCounterFormat(3, CounterRightZeros)
+	require.NoError(t, err)
+	_, err = p.SetCounterRange(1, 999, 1, 2)
+	require.NoError(t, err)
+	_, err = p.SetCounterValue(300)
+	require.NoError(t, err)
+	_, err = p.PrintCounter()
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := []byte{
+		gs, 'C', '0', 3, 1,
+		gs, 'C', '1', 1, 0, 0xE7, 0x03, 1, 2,
+		gs, 'C', '2', 0x2C, 0x01,
+		gs, 'c',
+	}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestCounterInvalid tests the counter parameter checks
+func TestCounterInvalid(t *testing.T) {
+	p := New(NewMockPrinter())
+	_, err := p.SetCounterFormat(6, CounterLeft)
+	assert.Error(t, err)
+	_, err = p.SetCounterFormat(2, 3)
+	assert.Error(t, err)
+	_, err = p.SetCounterRange(5, 5, 1, 1)
+	assert.Error(t, err)
+}
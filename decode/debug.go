@@ -0,0 +1,136 @@
+package decode
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/schawnndev/escpos"
+)
+
+// Number of bytes shown on a line of the dump, longer commands such as
+// images are shortened
+const dumpBytes = 16
+
+// debugPrinter forwards to a printer and dumps the commands written to it
+type debugPrinter struct {
+	inner escpos.Printer
+	mu    sync.Mutex
+	w     io.Writer
+	dec   *Decoder
+	off   int64 // offset of the next command in the stream
+}
+
+// NewDebugPrinter returns a Printer forwarding everything to inner and
+// writing to w an annotated hex dump of the bytes written, one command per
+// line with its offset, bytes and name, and of the bytes read back:
+//
+//	000000  1B 45 01                                        ESC E  bold 1
+//	000003  48 65 6C 6C 6F                                  text "Hello"
+//	000008  0A                                              LF
+//	000009  10 04 04                                        DLE EOT  real-time status
+//	<<      12                                              read
+//
+// Commands split across writes are dumped once complete.
+func NewDebugPrinter(inner escpos.Printer, w io.Writer) escpos.Printer {
+	return &debugPrinter{inner: inner, w: w, dec: NewDecoder()}
+}
+
+// Write forwards p to the printer and dumps the bytes it accepted
+func (d *debugPrinter) Write(p []byte) (int, error) {
+	n, err := d.inner.Write(p)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, cmd := range d.dec.Feed(p[:n]) {
+		d.dump(cmd)
+	}
+	if err != nil {
+		fmt.Fprintf(d.w, "!!      write error after %d of %d bytes: %v\n", n, len(p), err)
+	}
+	return n, err
+}
+
+// Read reads from the printer and dumps the bytes read
+func (d *debugPrinter) Read(p []byte) (int, error) {
+	n, err := d.inner.Read(p)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if n > 0 {
+		fmt.Fprintf(d.w, "<<      %-47s read\n", hexBytes(p[:n]))
+	}
+	if err != nil && err != io.EOF {
+		fmt.Fprintf(d.w, "!!      read error: %v\n", err)
+	}
+	return n, err
+}
+
+// Close dumps the incomplete command left, if any, and closes the printer
+func (d *debugPrinter) Close() error {
+	d.mu.Lock()
+	if pending := d.dec.Pending(); len(pending) > 0 {
+		fmt.Fprintf(d.w, "%06X  %-47s incomplete command\n", d.off, hexBytes(pending))
+	}
+	d.mu.Unlock()
+	return d.inner.Close()
+}
+
+// Transport describes the connection for Diagnostics
+func (d *debugPrinter) Transport() string {
+	if t, ok := d.inner.(escpos.TransportDescriber); ok {
+		return t.Transport() + " (debug)"
+	}
+	return fmt.Sprintf("%T (debug)", d.inner)
+}
+
+// dump writes the line of a command
+func (d *debugPrinter) dump(cmd Command) {
+	fmt.Fprintf(d.w, "%06X  %-47s %s\n", d.off, hexBytes(cmd.Raw), annotation(cmd))
+	d.off += int64(len(cmd.Raw))
+}
+
+// hexBytes formats the bytes of a command, shortening long ones
+func hexBytes(b []byte) string {
+	if len(b) <= dumpBytes {
+		return fmt.Sprintf("% X", b)
+	}
+	return fmt.Sprintf("% X …", b[:dumpBytes-2])
+}
+
+// annotation describes a command for the dump
+func annotation(cmd Command) string {
+	switch cmd.Kind {
+	case KindText:
+		return fmt.Sprintf("text %q", cmd.Text)
+	case KindLineFeed, KindControl:
+		return cmd.Name
+	}
+
+	parts := []string{cmd.Name + " "}
+	if cmd.Description != "" {
+		parts = append(parts, cmd.Description)
+	}
+	switch cmd.Kind {
+	case KindStyle, KindCodePage, KindFeed, KindDrawer:
+		parts = append(parts, fmt.Sprint(cmd.Value))
+	case KindBarcode:
+		parts = append(parts, fmt.Sprintf("type %d %q", cmd.Symbology, cmd.Text))
+	case KindSymbol:
+		if cmd.Text != "" {
+			parts = append(parts, fmt.Sprintf("%q", cmd.Text))
+		}
+	case KindCut:
+		if cmd.Partial {
+			parts = append(parts, "partial")
+		}
+	}
+	if cmd.Image != nil {
+		parts = append(parts, fmt.Sprintf("%dx%d", cmd.Image.Width, cmd.Image.Height))
+	}
+	if len(cmd.Raw) > dumpBytes {
+		parts = append(parts, fmt.Sprintf("(%d bytes)", len(cmd.Raw)))
+	}
+	return strings.TrimSpace(strings.Join(parts, " "))
+}
@@ -0,0 +1,67 @@
+package decode
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/escpostest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDebugPrinter tests dumping the commands sent to a printer
+func TestDebugPrinter(t *testing.T) {
+	m := escpostest.NewMockPrinter()
+	m.SetStatus([]byte{0x12})
+	var dump strings.Builder
+	p := escpos.New(NewDebugPrinter(m, &dump))
+
+	p.SetBold(true)
+	p.Write("Hello\n")
+	p.Cut()
+	require.NoError(t, p.Print())
+	_, err := p.PaperStatus()
+	require.NoError(t, err)
+
+	expected := "000000  1B 45 01                                        ESC E  bold 1\n" +
+		"000003  48 65 6C 6C 6F                                  text \"Hello\"\n" +
+		"000008  0A                                              LF\n" +
+		"000009  1D 56 41 00                                     GS V  cut\n" +
+		"00000D  10 04 04                                        DLE EOT  real-time status\n" +
+		"<<      12                                              read\n"
+	assert.Equal(t, expected, dump.String())
+	assert.Contains(t, m.String(), "Hello")
+}
+
+// TestDebugPrinterSplitWrites tests dumping commands split across writes and long commands
+func TestDebugPrinterSplitWrites(t *testing.T) {
+	m := escpostest.NewMockPrinter()
+	var dump strings.Builder
+	d := NewDebugPrinter(m, &dump)
+
+	d.Write([]byte{0x1D, 'v', '0', 0, 3, 0})
+	assert.Empty(t, dump.String())
+	d.Write([]byte{8, 0})
+	d.Write(make([]byte, 24))
+
+	assert.Equal(t, "000000  1D 76 30 00 03 00 08 00 00 00 00 00 00 00 …     GS v 0  raster image 24x8 (32 bytes)\n", dump.String())
+}
+
+// TestDebugPrinterErrors tests dumping write errors and incomplete commands
+func TestDebugPrinterErrors(t *testing.T) {
+	m := escpostest.NewMockPrinter()
+	var dump strings.Builder
+	d := NewDebugPrinter(m, &dump)
+
+	m.FailAfter(2, errors.New("offline"))
+	n, err := d.Write([]byte{0x1B, 'E', 1})
+	assert.Error(t, err)
+	assert.Equal(t, 2, n)
+	require.NoError(t, d.Close())
+
+	assert.Equal(t, "!!      write error after 2 of 3 bytes: offline\n"+
+		"000000  1B 45                                           incomplete command\n", dump.String())
+	assert.True(t, m.Closed())
+}
@@ -0,0 +1,702 @@
+// Package decode parses a raw ESC/POS byte stream back into commands: text
+// runs, style changes, barcodes, raster images, cuts and the other commands,
+// for debugging captured jobs, building emulators and converting legacy
+// print jobs.
+package decode
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/schawnndev/escpos"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// Control bytes
+const (
+	ht  byte = 0x09
+	lf  byte = 0x0A
+	ff  byte = 0x0C
+	cr  byte = 0x0D
+	esc byte = 0x1B
+	gs  byte = 0x1D
+	fs  byte = 0x1C
+	dle byte = 0x10
+)
+
+// Kind is the kind of a decoded command
+type Kind uint8
+
+const (
+	// KindText is a run of printable text
+	KindText Kind = iota
+	// KindLineFeed is LF, printing the current line
+	KindLineFeed
+	// KindControl is another control byte, such as CR or HT
+	KindControl
+	// KindInitialize is ESC @
+	KindInitialize
+	// KindStyle changes a text style attribute, see Command.Style
+	KindStyle
+	// KindCodePage selects the code page of the text (ESC t)
+	KindCodePage
+	// KindFeed feeds the paper by lines or dots
+	KindFeed
+	// KindBarcode prints a barcode (GS k)
+	KindBarcode
+	// KindSymbol stores or prints a two-dimensional code (GS ( k)
+	KindSymbol
+	// KindImage prints or stores a raster image (GS v 0, GS ( L, GS 8 L)
+	KindImage
+	// KindCut cuts the paper (GS V)
+	KindCut
+	// KindDrawer sends a pulse to the cash drawer (ESC p)
+	KindDrawer
+	// KindRealtime is a real-time command or status request (DLE)
+	KindRealtime
+	// KindOther is any other command
+	KindOther
+)
+
+var kindNames = [...]string{
+	KindText:       "text",
+	KindLineFeed:   "line feed",
+	KindControl:    "control",
+	KindInitialize: "initialize",
+	KindStyle:      "style",
+	KindCodePage:   "code page",
+	KindFeed:       "feed",
+	KindBarcode:    "barcode",
+	KindSymbol:     "symbol",
+	KindImage:      "image",
+	KindCut:        "cut",
+	KindDrawer:     "drawer",
+	KindRealtime:   "real-time",
+	KindOther:      "other",
+}
+
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", k)
+}
+
+// Image is a raster image of a command
+type Image struct {
+	Width, Height int // in dots
+	Data          []byte
+}
+
+// Command is a decoded command
+type Command struct {
+	Kind Kind
+	// Name is the mnemonic of the command, such as "ESC E" or "GS v 0", or
+	// the name of a control byte
+	Name string
+	// Description is a short description of the command, such as "bold"
+	Description string
+	// Raw holds the bytes of the command
+	Raw []byte
+
+	// Text is the decoded text of a text run, or the data of a barcode or symbol
+	Text string
+	// Style is the attribute changed by a style command: "bold", "underline",
+	// "size", "justify", "reverse", "upside down", "rotate", "font",
+	// "double strike" or "print mode"
+	Style string
+	// Value is the parameter of style, code page and feed commands, such as
+	// 1 for bold on, the GS ! size byte or a number of lines
+	Value int
+	// Dots is set for the feeds in motion units (1/180 inch by default)
+	// rather than lines
+	Dots bool
+	// Partial is set for partial cuts
+	Partial bool
+	// Symbology is the GS k type of a barcode
+	Symbology uint8
+	// Image is the image of a raster image command
+	Image *Image
+}
+
+// Decoder decodes a stream of commands, possibly split across writes, and
+// tracks the code page and Kanji mode to decode the text
+type Decoder struct {
+	pending   []byte
+	codepage  uint8
+	kanji     bool
+	kanjiSJIS bool
+}
+
+// NewDecoder creates a decoder in the power-on state of a printer, with code page PC437
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// Decode decodes a complete stream. A truncated command at the end of the
+// stream is an error.
+func Decode(data []byte) ([]Command, error) {
+	d := NewDecoder()
+	cmds := d.Feed(data)
+	if len(d.pending) > 0 {
+		return cmds, fmt.Errorf("truncated command at the end of the stream: % x", d.pending)
+	}
+	return cmds, nil
+}
+
+// Feed decodes the complete commands of the data written so far; a
+// trailing incomplete command is kept until the next call
+func (d *Decoder) Feed(data []byte) []Command {
+	buf := append(d.pending, data...)
+	var cmds []Command
+	for len(buf) > 0 {
+		cmd, n := d.next(buf)
+		if n == 0 {
+			break
+		}
+		cmd.Raw = bytes.Clone(buf[:n])
+		cmds = append(cmds, cmd)
+		buf = buf[n:]
+	}
+	d.pending = bytes.Clone(buf)
+	return cmds
+}
+
+// Pending returns the bytes of the incomplete command waiting for more data
+func (d *Decoder) Pending() []byte {
+	return d.pending
+}
+
+// next decodes the command at the start of buf and returns its length, or
+// 0 when more bytes are needed
+func (d *Decoder) next(buf []byte) (Command, int) {
+	switch buf[0] {
+	case lf:
+		return Command{Kind: KindLineFeed, Name: "LF", Description: "line feed"}, 1
+	case esc:
+		return d.escCommand(buf)
+	case gs:
+		return d.gsCommand(buf)
+	case fs:
+		return d.fsCommand(buf)
+	case dle:
+		return dleCommand(buf)
+	}
+
+	if buf[0] < 0x20 {
+		return Command{Kind: KindControl, Name: controlName(buf[0])}, 1
+	}
+
+	n := 0
+	for n < len(buf) && buf[n] >= 0x20 {
+		n++
+	}
+	return Command{Kind: KindText, Name: "text", Text: d.text(buf[:n])}, n
+}
+
+// controlName returns the name of a control byte
+func controlName(b byte) string {
+	switch b {
+	case ht:
+		return "HT"
+	case ff:
+		return "FF"
+	case cr:
+		return "CR"
+	}
+	return fmt.Sprintf("0x%02X", b)
+}
+
+// text decodes printable bytes with the active code page
+func (d *Decoder) text(b []byte) string {
+	if d.kanji && d.kanjiSJIS {
+		if decoded, err := japanese.ShiftJIS.NewDecoder().Bytes(b); err == nil {
+			return string(decoded)
+		}
+	} else if enc, ok := escpos.CodePageEncoding(d.codepage); ok {
+		if decoded, err := enc.NewDecoder().Bytes(b); err == nil {
+			return string(decoded)
+		}
+	}
+	return string(b)
+}
+
+// name returns the mnemonic of a command, such as "ESC E", from its prefix
+func name(prefix string, b ...byte) string {
+	s := prefix
+	for _, c := range b {
+		if c > 0x20 && c < 0x7F {
+			s += " " + string(c)
+		} else {
+			s += fmt.Sprintf(" %d", c)
+		}
+	}
+	return s
+}
+
+// Total lengths of the fixed length commands, by command byte
+var (
+	escLengths = map[byte]int{
+		'@': 2, '2': 2, 'L': 2, 'S': 2, 'i': 2, 'm': 2, '<': 2,
+		'E': 3, '-': 3, '{': 3, 'V': 3, 'a': 3, 'M': 3, 't': 3, 'd': 3, '3': 3,
+		'J': 3, 'G': 3, ' ': 3, '!': 3, 'R': 3, 'K': 3, 'e': 3, '%': 3, 'r': 3,
+		'=': 3, 'U': 3, 'T': 3, '?': 3,
+		'$': 4, '\\': 4, 'c': 4, 'B': 4,
+		'p': 5, '7': 5,
+		'W': 10,
+	}
+	gsLengths = map[byte]int{
+		':': 2, 'c': 2,
+		'!': 3, 'B': 3, 'H': 3, 'f': 3, 'h': 3, 'w': 3, 'b': 3, 'I': 3, 'r': 3,
+		'a': 3, '/': 3, 'E': 3, 'T': 3,
+		'P': 4, 'L': 4, 'W': 4, '$': 4, '\\': 4,
+	}
+	fsLengths = map[byte]int{
+		'&': 2, '.': 2,
+		'C': 3, '-': 3, 'W': 3, '!': 3,
+		'S': 4, 'p': 4, 'd': 4,
+		'2': 76,
+	}
+)
+
+// Descriptions of the commands, by mnemonic
+var descriptions = map[string]string{
+	"ESC @": "initialize", "ESC E": "bold", "ESC -": "underline", "ESC a": "justification",
+	"ESC {": "upside down", "ESC V": "rotation", "ESC M": "font", "ESC G": "double strike",
+	"ESC !": "print mode", "ESC t": "code page", "ESC R": "international character set",
+	"ESC d": "feed lines", "ESC J": "feed dots", "ESC K": "reverse feed dots", "ESC e": "reverse feed lines",
+	"ESC 2": "default line spacing", "ESC 3": "line spacing", "ESC $": "absolute position",
+	"ESC \\": "relative position", "ESC p": "drawer pulse", "ESC D": "tab stops",
+	"ESC *": "bit image", "ESC &": "user-defined characters", "ESC %": "user-defined characters on/off",
+	"ESC ?": "delete user-defined character", "ESC c": "panel buttons and paper sensors",
+	"ESC 7": "heating settings", "ESC SP": "character spacing",
+	"GS !": "character size", "GS B": "reverse", "GS V": "cut", "GS k": "barcode",
+	"GS h": "barcode height", "GS w": "barcode width", "GS H": "HRI position", "GS f": "HRI font",
+	"GS v 0": "raster image", "GS ( k": "2D code", "GS ( L": "graphics", "GS 8 L": "graphics",
+	"GS ( K": "print density and speed", "GS L": "left margin", "GS W": "print area width",
+	"GS C": "counter", "GS c": "print counter", "GS I": "printer ID", "GS r": "status",
+	"GS a": "automatic status back", "GS P": "motion units", "GS *": "define downloaded bit image",
+	"GS /": "print downloaded bit image",
+	"FS &": "Kanji mode on", "FS .": "Kanji mode off", "FS C": "Kanji code system",
+	"FS q": "define NV bit images", "FS p": "print NV bit image",
+	"DLE EOT": "real-time status", "DLE ENQ": "real-time request", "DLE DC4": "real-time command",
+}
+
+// describe sets the description of a command from its name
+func describe(cmd Command) Command {
+	if cmd.Description == "" {
+		cmd.Description = descriptions[cmd.Name]
+	}
+	return cmd
+}
+
+// escCommand decodes an ESC command
+func (d *Decoder) escCommand(buf []byte) (Command, int) {
+	if len(buf) < 2 {
+		return Command{}, 0
+	}
+	cmd := Command{Kind: KindOther, Name: name("ESC", buf[1])}
+	if buf[1] == ' ' {
+		cmd.Name = "ESC SP"
+	}
+
+	if n, ok := escLengths[buf[1]]; ok {
+		if len(buf) < n {
+			return Command{}, 0
+		}
+		return describe(d.escFixed(cmd, buf[:n])), n
+	}
+
+	n := 2
+	switch buf[1] {
+	case 'D':
+		// ESC D n1...nk NUL
+		i := bytes.IndexByte(buf[2:], 0)
+		if i < 0 {
+			return Command{}, 0
+		}
+		n = i + 3
+	case '&':
+		n = userCharactersLength(buf)
+	case '*':
+		// ESC * m nL nH d1...dk
+		if len(buf) < 5 {
+			return Command{}, 0
+		}
+		k := int(buf[3]) + int(buf[4])*256
+		if buf[2] == 32 || buf[2] == 33 {
+			k *= 3
+		}
+		n = available(buf, 5+k)
+	case '(':
+		n = parenLength(buf)
+		if n > 0 {
+			cmd.Name = name("ESC", '(', buf[2])
+		}
+	}
+	if n == 0 {
+		return Command{}, 0
+	}
+	return describe(cmd), n
+}
+
+// escFixed decodes a fixed length ESC command
+func (d *Decoder) escFixed(cmd Command, raw []byte) Command {
+	style := func(s string, v byte) Command {
+		cmd.Kind, cmd.Style, cmd.Value = KindStyle, s, int(v)
+		return cmd
+	}
+	switch raw[1] {
+	case '@':
+		d.codepage, d.kanji, d.kanjiSJIS = 0, false, false
+		cmd.Kind = KindInitialize
+	case 'E':
+		return style("bold", raw[2]&1)
+	case 'G':
+		return style("double strike", raw[2]&1)
+	case '-':
+		return style("underline", asciiDigit(raw[2])%3)
+	case '{':
+		return style("upside down", raw[2]&1)
+	case 'V':
+		return style("rotate", asciiDigit(raw[2]))
+	case 'a':
+		return style("justify", asciiDigit(raw[2])%3)
+	case 'M':
+		return style("font", asciiDigit(raw[2]))
+	case '!':
+		return style("print mode", raw[2])
+	case 't':
+		d.codepage = raw[2]
+		cmd.Kind, cmd.Value = KindCodePage, int(raw[2])
+	case 'd':
+		cmd.Kind, cmd.Value = KindFeed, int(raw[2])
+	case 'J':
+		cmd.Kind, cmd.Value, cmd.Dots = KindFeed, int(raw[2]), true
+	case 'K':
+		cmd.Kind, cmd.Value, cmd.Dots = KindFeed, -int(raw[2]), true
+	case 'e':
+		cmd.Kind, cmd.Value = KindFeed, -int(raw[2])
+	case 'p':
+		cmd.Kind, cmd.Value = KindDrawer, int(asciiDigit(raw[2]))
+	}
+	return cmd
+}
+
+// gsCommand decodes a GS command
+func (d *Decoder) gsCommand(buf []byte) (Command, int) {
+	if len(buf) < 2 {
+		return Command{}, 0
+	}
+	cmd := Command{Kind: KindOther, Name: name("GS", buf[1])}
+
+	if n, ok := gsLengths[buf[1]]; ok {
+		if len(buf) < n {
+			return Command{}, 0
+		}
+		switch buf[1] {
+		case '!':
+			cmd.Kind, cmd.Style, cmd.Value = KindStyle, "size", int(buf[2])
+		case 'B':
+			cmd.Kind, cmd.Style, cmd.Value = KindStyle, "reverse", int(buf[2]&1)
+		}
+		return describe(cmd), n
+	}
+
+	n := 2
+	switch buf[1] {
+	case 'V':
+		// GS V m [n]
+		if len(buf) < 3 {
+			return Command{}, 0
+		}
+		n = 3
+		if buf[2] >= 65 {
+			n = available(buf, 4)
+		}
+		cmd.Kind = KindCut
+		cmd.Partial = buf[2] == 1 || buf[2] == 49 || buf[2] == 66 || buf[2] == 98 || buf[2] == 104
+	case 'k':
+		return barcode(buf)
+	case '(':
+		n = parenLength(buf)
+		if n > 0 {
+			cmd.Name = name("GS", '(', buf[2])
+			switch buf[2] {
+			case 'k':
+				cmd = symbol(cmd, buf[5:n])
+			case 'L':
+				cmd = graphics(cmd, buf[5:n])
+			}
+		}
+	case '8':
+		// GS 8 L p1 p2 p3 p4 m fn ...
+		if len(buf) < 7 {
+			return Command{}, 0
+		}
+		k := int(buf[3]) | int(buf[4])<<8 | int(buf[5])<<16 | int(buf[6])<<24
+		n = available(buf, 7+k)
+		if n > 0 {
+			cmd.Name = name("GS", '8', buf[2])
+			if buf[2] == 'L' {
+				cmd = graphics(cmd, buf[7:n])
+			}
+		}
+	case 'v':
+		return rasterImage(buf)
+	case '*':
+		// GS * x y d1...d(x*y*8)
+		if len(buf) < 4 {
+			return Command{}, 0
+		}
+		n = available(buf, 4+int(buf[2])*int(buf[3])*8)
+	case 'C':
+		n = counterLength(buf)
+	}
+	if n == 0 {
+		return Command{}, 0
+	}
+	return describe(cmd), n
+}
+
+// fsCommand decodes an FS command
+func (d *Decoder) fsCommand(buf []byte) (Command, int) {
+	if len(buf) < 2 {
+		return Command{}, 0
+	}
+	cmd := Command{Kind: KindOther, Name: name("FS", buf[1])}
+
+	if n, ok := fsLengths[buf[1]]; ok {
+		if len(buf) < n {
+			return Command{}, 0
+		}
+		switch buf[1] {
+		case '&':
+			d.kanji = true
+		case '.':
+			d.kanji = false
+		case 'C':
+			d.kanjiSJIS = asciiDigit(buf[2]) != 0
+		}
+		return describe(cmd), n
+	}
+
+	n := 2
+	switch buf[1] {
+	case 'q':
+		n = nvImagesLength(buf)
+	case '(':
+		n = parenLength(buf)
+		if n > 0 {
+			cmd.Name = name("FS", '(', buf[2])
+		}
+	}
+	if n == 0 {
+		return Command{}, 0
+	}
+	return describe(cmd), n
+}
+
+// dleCommand decodes a real-time DLE command
+func dleCommand(buf []byte) (Command, int) {
+	if len(buf) < 3 {
+		return Command{}, 0
+	}
+	cmd := Command{Kind: KindRealtime, Name: name("DLE", buf[1]), Value: int(buf[2])}
+
+	n := 2
+	switch buf[1] {
+	case 0x04:
+		cmd.Name, n = "DLE EOT", 3
+	case 0x05:
+		cmd.Name, n = "DLE ENQ", 3
+	case 0x14:
+		cmd.Name, n = "DLE DC4", 3
+		switch buf[2] {
+		case 1, 2:
+			n = available(buf, 5)
+		case 7:
+			n = available(buf, 4)
+		case 8:
+			n = available(buf, 10)
+		}
+	}
+	if n == 0 {
+		return Command{}, 0
+	}
+	return describe(cmd), n
+}
+
+// barcode decodes GS k in both the NUL terminated and the length prefixed forms
+func barcode(buf []byte) (Command, int) {
+	if len(buf) < 3 {
+		return Command{}, 0
+	}
+	m := buf[2]
+
+	var data []byte
+	var n int
+	if m <= 6 {
+		i := bytes.IndexByte(buf[3:], 0)
+		if i < 0 {
+			return Command{}, 0
+		}
+		data, n = buf[3:3+i], i+4
+	} else {
+		if len(buf) < 4 {
+			return Command{}, 0
+		}
+		n = 4 + int(buf[3])
+		if len(buf) < n {
+			return Command{}, 0
+		}
+		data = buf[4:n]
+	}
+	return describe(Command{Kind: KindBarcode, Name: "GS k", Symbology: m, Text: string(data)}), n
+}
+
+// symbol decodes the body of a GS ( k command, from the cn parameter on:
+// the QR code data stored by function 80 and the print of function 81
+func symbol(cmd Command, body []byte) Command {
+	if len(body) < 2 || body[0] != 49 {
+		return cmd
+	}
+	switch body[1] {
+	case 80:
+		if len(body) >= 3 {
+			cmd.Kind, cmd.Text = KindSymbol, string(body[3:])
+			cmd.Description = "store QR code data"
+		}
+	case 81:
+		cmd.Kind, cmd.Description = KindSymbol, "print QR code"
+	}
+	return cmd
+}
+
+// graphics decodes the GS ( L and GS 8 L functions storing raster data in
+// the print buffer (112), from the m parameter on
+func graphics(cmd Command, params []byte) Command {
+	if len(params) < 2 || params[0] != 48 {
+		return cmd
+	}
+	switch params[1] {
+	case 112:
+		// m fn a bx by c xL xH yL yH d1...dk
+		if len(params) < 10 {
+			return cmd
+		}
+		cmd.Kind, cmd.Description = KindImage, "store graphics"
+		cmd.Image = &Image{
+			Width:  int(params[6]) + int(params[7])*256,
+			Height: int(params[8]) + int(params[9])*256,
+			Data:   bytes.Clone(params[10:]),
+		}
+	case 50:
+		cmd.Description = "print graphics"
+	}
+	return cmd
+}
+
+// rasterImage decodes GS v 0
+func rasterImage(buf []byte) (Command, int) {
+	if len(buf) < 8 {
+		return Command{}, 0
+	}
+	widthBytes := int(buf[4]) + int(buf[5])*256
+	height := int(buf[6]) + int(buf[7])*256
+	n := 8 + widthBytes*height
+	if len(buf) < n {
+		return Command{}, 0
+	}
+	return describe(Command{
+		Kind:  KindImage,
+		Name:  "GS v 0",
+		Value: int(buf[3]),
+		Image: &Image{Width: widthBytes * 8, Height: height, Data: bytes.Clone(buf[8:n])},
+	}), n
+}
+
+// available returns n if buf holds at least n bytes, 0 otherwise
+func available(buf []byte, n int) int {
+	if len(buf) < n {
+		return 0
+	}
+	return n
+}
+
+// parenLength returns the length of an "ESC|GS|FS ( X pL pH ..." command
+func parenLength(buf []byte) int {
+	if len(buf) < 5 {
+		return 0
+	}
+	return available(buf, 5+int(buf[3])+int(buf[4])*256)
+}
+
+// userCharactersLength returns the length of "ESC & y c1 c2 [x d1...d(y*x)]..."
+func userCharactersLength(buf []byte) int {
+	if len(buf) < 5 {
+		return 0
+	}
+	y := int(buf[2])
+	n := 5
+	for c := int(buf[3]); c <= int(buf[4]); c++ {
+		if len(buf) <= n {
+			return 0
+		}
+		n += 1 + y*int(buf[n])
+	}
+	return available(buf, n)
+}
+
+// nvImagesLength returns the length of "FS q n [xL xH yL yH d1...dk]1...[...]n"
+func nvImagesLength(buf []byte) int {
+	if len(buf) < 3 {
+		return 0
+	}
+	n := 3
+	for i := 0; i < int(buf[2]); i++ {
+		if len(buf) < n+4 {
+			return 0
+		}
+		x := int(buf[n]) + int(buf[n+1])*256
+		y := int(buf[n+2]) + int(buf[n+3])*256
+		n += 4 + x*y*8
+	}
+	return available(buf, n)
+}
+
+// counterLength returns the length of the GS C counter commands
+func counterLength(buf []byte) int {
+	if len(buf) < 3 {
+		return 0
+	}
+	switch buf[2] {
+	case '0', 0:
+		return available(buf, 5)
+	case '1', 1:
+		return available(buf, 9)
+	case '2', 2:
+		return available(buf, 5)
+	case ';':
+		// GS C ; sa ; sb ; sn ; sr ; sc ;
+		count := 0
+		for i := 2; i < len(buf); i++ {
+			if buf[i] == ';' {
+				count++
+				if count == 6 {
+					return i + 1
+				}
+			}
+		}
+		return 0
+	}
+	return 3
+}
+
+// asciiDigit maps the ASCII digit form of a parameter ('0', '1', ...) to its numeric form
+func asciiDigit(n byte) byte {
+	if n >= '0' && n <= '9' {
+		return n - '0'
+	}
+	return n
+}
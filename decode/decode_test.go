@@ -0,0 +1,203 @@
+package decode
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bufferPrinter is a printer recording the stream
+type bufferPrinter struct {
+	bytes.Buffer
+}
+
+func (b *bufferPrinter) Close() error { return nil }
+
+// TestDecodeReceipt tests decoding the stream of a small receipt
+func TestDecodeReceipt(t *testing.T) {
+	buf := &bufferPrinter{}
+	p := escpos.New(buf)
+
+	p.SetBold(true)
+	p.Write("Crème\n")
+	p.SetBold(false)
+	p.EAN13("1234567890128")
+	p.Cut()
+	require.NoError(t, p.Print())
+
+	cmds, err := Decode(buf.Bytes())
+	require.NoError(t, err)
+
+	var text string
+	var bold []int
+	var barcode, cut *Command
+	for i, cmd := range cmds {
+		switch {
+		case cmd.Kind == KindText:
+			text += cmd.Text
+		case cmd.Kind == KindStyle && cmd.Style == "bold":
+			bold = append(bold, cmd.Value)
+		case cmd.Kind == KindBarcode:
+			barcode = &cmds[i]
+		case cmd.Kind == KindCut:
+			cut = &cmds[i]
+		}
+	}
+	assert.Equal(t, "Crème", text)
+	assert.Contains(t, bold, 1)
+	assert.Equal(t, 0, bold[len(bold)-1])
+	require.NotNil(t, barcode)
+	assert.Equal(t, "GS k", barcode.Name)
+	assert.Equal(t, "1234567890128", barcode.Text)
+	require.NotNil(t, cut)
+	assert.False(t, cut.Partial)
+}
+
+// TestDecodeRaw tests decoding the raw bytes and names of commands
+func TestDecodeRaw(t *testing.T) {
+	cmds, err := Decode([]byte("\x1b@\x1b-\x02AB\n\x1dVB\x00"))
+	require.NoError(t, err)
+	require.Len(t, cmds, 5)
+
+	assert.Equal(t, KindInitialize, cmds[0].Kind)
+	assert.Equal(t, "ESC @", cmds[0].Name)
+	assert.Equal(t, "initialize", cmds[0].Description)
+	assert.Equal(t, []byte{0x1B, '-', 2}, cmds[1].Raw)
+	assert.Equal(t, "underline", cmds[1].Style)
+	assert.Equal(t, 2, cmds[1].Value)
+	assert.Equal(t, "AB", cmds[2].Text)
+	assert.Equal(t, KindLineFeed, cmds[3].Kind)
+	assert.Equal(t, KindCut, cmds[4].Kind)
+	assert.True(t, cmds[4].Partial)
+}
+
+// TestDecodeImage tests decoding a raster image
+func TestDecodeImage(t *testing.T) {
+	cmds, err := Decode([]byte{0x1D, 'v', '0', 0, 1, 0, 2, 0, 0xFF, 0x80})
+	require.NoError(t, err)
+	require.Len(t, cmds, 1)
+
+	assert.Equal(t, KindImage, cmds[0].Kind)
+	assert.Equal(t, "GS v 0", cmds[0].Name)
+	assert.Equal(t, &Image{Width: 8, Height: 2, Data: []byte{0xFF, 0x80}}, cmds[0].Image)
+}
+
+// TestDecodeQRCode tests decoding the stored data of a QR code
+func TestDecodeQRCode(t *testing.T) {
+	buf := &bufferPrinter{}
+	p := escpos.New(buf)
+	p.QRCode("https://example.com", escpos.QRCodeModel2, 4, escpos.QRCodeErrorCorrectionLevelM)
+	require.NoError(t, p.Print())
+
+	cmds, err := Decode(buf.Bytes())
+	require.NoError(t, err)
+
+	var data string
+	printed := false
+	for _, cmd := range cmds {
+		if cmd.Kind == KindSymbol && cmd.Description == "store QR code data" {
+			data = cmd.Text
+		}
+		printed = printed || cmd.Description == "print QR code"
+	}
+	assert.Equal(t, "https://example.com", data)
+	assert.True(t, printed)
+}
+
+// TestDecoderFeed tests decoding commands split across writes
+func TestDecoderFeed(t *testing.T) {
+	d := NewDecoder()
+
+	assert.Empty(t, d.Feed([]byte{0x1D, 'k', 2, '1', '2'}))
+	assert.Equal(t, []byte{0x1D, 'k', 2, '1', '2'}, d.Pending())
+
+	cmds := d.Feed([]byte{'3', 0, 0x1B, 'E'})
+	require.Len(t, cmds, 1)
+	assert.Equal(t, "123", cmds[0].Text)
+	assert.Equal(t, []byte{0x1B, 'E'}, d.Pending())
+}
+
+// TestDecodeTruncated tests that a truncated command is an error
+func TestDecodeTruncated(t *testing.T) {
+	cmds, err := Decode([]byte("AB\x1b!"))
+	assert.Error(t, err)
+	assert.Len(t, cmds, 1)
+}
+
+// variableCommands are complete commands whose length depends on their parameters
+var variableCommands = []string{
+	"\x1dVA\x05",
+	"\x1dVB\x00",
+	"\x1bD\x08\x10\x00",
+	"\x1b&\x03\x20\x21\x01\xaa\xbb\xcc\x01\xaa\xbb\xcc",
+	"\x1b*\x00\x02\x00\xff\x00",
+	"\x1b*\x21\x01\x00\xff\x00\xff",
+	"\x1b(A\x02\x00\x30\x31",
+	"\x1dk\x02123\x00",
+	"\x1dk\x49\x03abc",
+	"\x1d(k\x03\x00\x31\x51\x30",
+	"\x1d(L\x02\x00\x30\x32",
+	"\x1d8L\x02\x00\x00\x00\x30\x32",
+	"\x1dv0\x00\x01\x00\x02\x00\xff\x80",
+	"\x1d*\x01\x01\x01\x02\x03\x04\x05\x06\x07\x08",
+	"\x1dC0\x01\x02",
+	"\x1dC1\x01\x00\x09\x00\x01\x01",
+	"\x1dC;1;2;3;4;5;",
+	"\x1cq\x01\x01\x00\x01\x00\x01\x02\x03\x04\x05\x06\x07\x08",
+	"\x1c(A\x02\x00\x30\x31",
+	"\x10\x04\x01",
+	"\x10\x14\x01\x00\x01",
+	"\x10\x14\x08\x01\x03\x14\x01\x06\x02\x08",
+}
+
+// TestDecodeTruncatedCommands tests that every truncation of the variable
+// length commands waits for more bytes, as when a command is split across writes
+func TestDecodeTruncatedCommands(t *testing.T) {
+	for _, command := range variableCommands {
+		data := []byte(command)
+		cmds, err := Decode(data)
+		require.NoError(t, err, "% x", data)
+		require.Len(t, cmds, 1, "% x", data)
+
+		for i := 1; i < len(data); i++ {
+			cmds, err := Decode(data[:i])
+			assert.Error(t, err, "% x", data[:i])
+			assert.Empty(t, cmds, "% x", data[:i])
+
+			d := NewDecoder()
+			cmds = append(d.Feed(data[:i]), d.Feed(data[i:])...)
+			require.Len(t, cmds, 1, "% x split at %d", data, i)
+			assert.Equal(t, data, cmds[0].Raw)
+		}
+	}
+}
+
+// FuzzDecode tests that no stream makes the decoder panic
+func FuzzDecode(f *testing.F) {
+	for _, command := range variableCommands {
+		f.Add([]byte(command))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		cmds, _ := Decode(data)
+		var raw []byte
+		for _, cmd := range cmds {
+			raw = append(raw, cmd.Raw...)
+		}
+		if !bytes.HasPrefix(data, raw) {
+			t.Fatalf("commands % x are not a prefix of % x", raw, data)
+		}
+	})
+}
+
+// TestDecodeCodePage tests decoding text with the selected code page
+func TestDecodeCodePage(t *testing.T) {
+	cmds, err := Decode([]byte{0x1B, 't', escpos.CodePageWPC1252, 0x80})
+	require.NoError(t, err)
+	require.Len(t, cmds, 2)
+
+	assert.Equal(t, KindCodePage, cmds[0].Kind)
+	assert.Equal(t, "€", cmds[1].Text)
+}
@@ -0,0 +1,244 @@
+package decode
+
+import (
+	"fmt"
+
+	"github.com/boombuler/barcode/qr"
+	"github.com/schawnndev/escpos"
+)
+
+// Defaults of the printers, as assumed by escpos
+const (
+	defaultDPI      = 203
+	defaultCutter   = 15.0 // mm from the print head to the cutter
+	defaultSpacing  = 30   // line spacing in 1/180 inch
+	defaultBarcode  = 162  // barcode height in dots
+	defaultQRModule = 3    // QR code module size in dots
+	fontAHeight     = 24
+	fontBHeight     = 17
+)
+
+// Length is the length of paper a job consumes
+type Length struct {
+	Dots        int
+	Millimeters float64
+}
+
+func (l Length) String() string {
+	return fmt.Sprintf("%d dots (%.1fmm)", l.Dots, l.Millimeters)
+}
+
+// Measure returns the length of paper the commands of data consume on a
+// printer of the given profile, accounting for the line spacing, the text
+// size, the feeds, the images, the barcodes, the QR codes and the feeds of
+// the cuts. Lines wrapped by the printer and NV images are not counted.
+func Measure(data []byte, profile escpos.Profile) (Length, error) {
+	cmds, err := Decode(data)
+	if err != nil {
+		return Length{}, err
+	}
+
+	m := newMeter(profile)
+	for _, cmd := range cmds {
+		m.command(cmd)
+	}
+	m.endLine()
+
+	dots := max(m.dots, 0)
+	return Length{Dots: dots, Millimeters: float64(dots) * 25.4 / float64(m.dpi)}, nil
+}
+
+// DryRun composes a job with fn in memory, without a printer, and measures
+// the paper it would consume on a printer of the given profile, e.g. for a
+// kiosk to warn that the paper is nearly out before starting a long receipt
+func DryRun(profile escpos.Profile, fn func(p *escpos.Escpos) error) (Length, error) {
+	c := escpos.NewComposer()
+	if err := c.SetProfile(profile); err != nil {
+		return Length{}, err
+	}
+	if err := fn(c.Escpos); err != nil {
+		return Length{}, err
+	}
+	data, err := c.Bytes()
+	if err != nil {
+		return Length{}, err
+	}
+	return Measure(data, profile)
+}
+
+// meter follows the paper motion of a stream of commands
+type meter struct {
+	profile escpos.Profile
+	dpi     int
+	dots    int
+
+	spacing    int // line spacing in dots
+	font       int // character height in dots
+	height     int // character height multiplier
+	doubleMode bool
+	line       int // height of the text of the current line, 0 if empty
+
+	barcodeHeight int
+	hri           byte
+	qrModule      int
+	qrLevel       qr.ErrorCorrectionLevel
+	qrData        string
+	graphics      int // height of the graphics stored in the print buffer
+}
+
+// newMeter creates a meter in the power-on state of a printer
+func newMeter(profile escpos.Profile) *meter {
+	m := &meter{profile: profile, dpi: profile.DPI}
+	if m.dpi <= 0 {
+		m.dpi = defaultDPI
+	}
+	m.initialize()
+	return m
+}
+
+// initialize resets the settings as ESC @ does
+func (m *meter) initialize() {
+	m.spacing = m.units(defaultSpacing)
+	m.font, m.height, m.doubleMode = fontAHeight, 1, false
+	m.barcodeHeight, m.hri = defaultBarcode, 0
+	m.qrModule, m.qrLevel = defaultQRModule, qr.L
+}
+
+// units converts a distance in motion units, 1/180 inch, to dots. It is
+// the unit of the line spacing, of the ESC J and ESC K feeds and of the feed
+// of the GS V cuts.
+func (m *meter) units(n int) int {
+	if n < 0 {
+		return -m.units(-n)
+	}
+	return (n*m.dpi + 90) / 180
+}
+
+// endLine feeds the current line, at least as high as its text
+func (m *meter) endLine() {
+	if m.line > 0 {
+		m.dots += max(m.spacing, m.line)
+		m.line = 0
+	}
+}
+
+// charHeight returns the height of the characters of the current size
+func (m *meter) charHeight() int {
+	h := m.font * m.height
+	if m.doubleMode && m.height == 1 {
+		h *= 2
+	}
+	return h
+}
+
+// command follows a command
+func (m *meter) command(cmd Command) {
+	raw := cmd.Raw
+	switch cmd.Kind {
+	case KindText:
+		m.line = max(m.line, m.charHeight())
+	case KindLineFeed:
+		m.line = max(m.line, 1)
+		m.endLine()
+	case KindInitialize:
+		m.initialize()
+	case KindStyle:
+		switch cmd.Style {
+		case "size":
+			m.height = cmd.Value&0x0f + 1
+		case "print mode":
+			m.doubleMode = cmd.Value&0x10 != 0
+			m.font = fontAHeight
+			if cmd.Value&1 != 0 {
+				m.font = fontBHeight
+			}
+		case "font":
+			m.font = fontAHeight
+			if cmd.Value == 1 {
+				m.font = fontBHeight
+			}
+		}
+	case KindFeed:
+		switch {
+		case cmd.Dots:
+			// The feed replaces the line spacing of the printed line
+			m.line = 0
+			m.dots += m.units(cmd.Value)
+		case cmd.Value > 0:
+			m.line = max(m.line, 1)
+			m.endLine()
+			m.dots += (cmd.Value - 1) * m.spacing
+		case cmd.Value == 0:
+			// ESC d 0 prints the pending line, with its height
+			m.endLine()
+		default:
+			m.line = 0
+			m.dots += cmd.Value * m.spacing
+		}
+	case KindBarcode:
+		m.endLine()
+		m.dots += m.barcodeHeight
+		switch m.hri {
+		case 1, 2, '1', '2':
+			m.dots += fontAHeight
+		case 3, '3':
+			m.dots += 2 * fontAHeight
+		}
+	case KindSymbol:
+		if cmd.Description == "store QR code data" {
+			m.qrData = cmd.Text
+		} else if cmd.Description == "print QR code" && m.qrData != "" {
+			m.endLine()
+			if bc, err := qr.Encode(m.qrData, m.qrLevel, qr.Auto); err == nil {
+				m.dots += bc.Bounds().Dy() * m.qrModule
+			}
+		}
+	case KindImage:
+		m.endLine()
+		if cmd.Name == "GS v 0" {
+			rows := cmd.Image.Height
+			if cmd.Value&2 != 0 {
+				rows *= 2
+			}
+			m.dots += rows
+		} else if cmd.Image != nil {
+			m.graphics = cmd.Image.Height
+		}
+	case KindCut:
+		m.endLine()
+		if len(raw) == 4 {
+			cutter := m.profile.CutterDistance
+			if cutter <= 0 {
+				cutter = int(defaultCutter*float64(m.dpi)/25.4 + 0.5)
+			}
+			m.dots += cutter + m.units(int(raw[3]))
+		}
+	case KindOther:
+		m.other(cmd)
+	}
+}
+
+// other follows the settings of the commands of no specific kind
+func (m *meter) other(cmd Command) {
+	raw := cmd.Raw
+	switch {
+	case cmd.Description == "print graphics":
+		m.endLine()
+		m.dots += m.graphics
+	case cmd.Name == "ESC 2":
+		m.spacing = m.units(defaultSpacing)
+	case cmd.Name == "ESC 3" && len(raw) == 3:
+		m.spacing = m.units(int(raw[2]))
+	case cmd.Name == "GS h" && len(raw) == 3:
+		m.barcodeHeight = int(raw[2])
+	case cmd.Name == "GS H" && len(raw) == 3:
+		m.hri = raw[2]
+	case cmd.Name == "GS ( k" && len(raw) == 8 && raw[5] == 49:
+		switch raw[6] {
+		case 67:
+			m.qrModule = int(raw[7])
+		case 69:
+			m.qrLevel = [...]qr.ErrorCorrectionLevel{qr.L, qr.M, qr.Q, qr.H}[(raw[7]-48)&3]
+		}
+	}
+}
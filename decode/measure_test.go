@@ -0,0 +1,69 @@
+package decode
+
+import (
+	"image"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMeasure tests the length of the paper motions of raw commands
+func TestMeasure(t *testing.T) {
+	for name, tc := range map[string]struct {
+		data []byte
+		dots int
+	}{
+		"lines":            {[]byte("A\nB\n"), 68},
+		"pending line":     {[]byte("A\nB"), 68},
+		"double height":    {[]byte("\x1d!\x11A\n"), 48},
+		"print mode":       {[]byte("\x1b!\x10A\n"), 48},
+		"line spacing":     {[]byte("\x1b3\x3cA\n\x1b2\n"), 68 + 34},
+		"feed units":       {[]byte("\x1bJ\xb4"), 203},
+		"feed units text":  {[]byte("A\x1bJ\x64"), 113},
+		"feed lines":       {[]byte("A\x1bd\x03"), 102},
+		"feed no line":     {[]byte("A\x1bd\x00"), 34},
+		"feed nothing":     {[]byte("\x1bd\x00"), 0},
+		"reverse feed":     {[]byte("\x1bJ\x64\x1bK\x14"), 113 - 23},
+		"reverse lines":    {[]byte("\x1bd\x03\x1be\x01"), 68},
+		"raster":           {[]byte("\x1dv0\x00\x01\x00\x0a\x00" + string(make([]byte, 10))), 10},
+		"raster doubled":   {[]byte("\x1dv0\x03\x01\x00\x0a\x00" + string(make([]byte, 10))), 20},
+		"barcode":          {[]byte("\x1dh\x32\x1dH\x02\x1dk\x02012345678905\x00"), 50 + 24},
+		"cut":              {[]byte("\x1dVA\x05"), 120 + 6},
+		"partial cut":      {[]byte("\x1dVB\xb4"), 120 + 203},
+		"cut without feed": {[]byte("\x1dV\x00"), 0},
+		"initialize":       {[]byte("\x1b3\x3c\x1b@A\n"), 34},
+	} {
+		l, err := Measure(tc.data, escpos.Profile{})
+		require.NoError(t, err, name)
+		assert.Equal(t, tc.dots, l.Dots, name)
+	}
+
+	_, err := Measure([]byte("\x1dv0\x00\x01"), escpos.Profile{})
+	assert.Error(t, err)
+}
+
+// TestDryRun tests measuring a job composed with the methods of Escpos
+func TestDryRun(t *testing.T) {
+	profile := escpos.Profile{DPI: 180, CutterDistance: 100}
+	l, err := DryRun(profile, func(p *escpos.Escpos) error {
+		if _, err := p.Write("Hello\n"); err != nil {
+			return err
+		}
+		if _, err := p.PrintImageWithProcessing(image.NewGray(image.Rect(0, 0, 8, 40)), escpos.ImageProcessDither, true, true); err != nil {
+			return err
+		}
+		if _, err := p.QRCode("https://example.com", escpos.QRCodeModel2, 4, escpos.QRCodeErrorCorrectionLevelL); err != nil {
+			return err
+		}
+		_, err := p.CutWithFeed(0)
+		return err
+	})
+	require.NoError(t, err)
+
+	// 30 dots for the line, 40 for the image, 25 modules of 4 dots, and 100 to the cutter
+	assert.Equal(t, 30+40+100+100, l.Dots)
+	assert.InDelta(t, 270*25.4/180, l.Millimeters, 0.001)
+	assert.Equal(t, "270 dots (38.1mm)", l.String())
+}
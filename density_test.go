@@ -0,0 +1,56 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPrintDensity tests the GS ( K density and speed commands
+func TestPrintDensity(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.SetPrintDensity(3)
+	assert.NoError(t, err)
+	_, err = p.SetPrintDensity(-2)
+	assert.NoError(t, err)
+	_, err = p.SetPrintSpeed(1)
+	assert.NoError(t, err)
+
+	_, err = p.SetPrintDensity(7)
+	assert.Error(t, err)
+	_, err = p.SetPrintSpeed(10)
+	assert.Error(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{
+		gs, '(', 'K', 2, 0, 49, 3,
+		gs, '(', 'K', 2, 0, 49, 254,
+		gs, '(', 'K', 2, 0, 50, 1,
+	}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestPrintDensityESC7 tests the ESC 7 heating parameters of clone firmware
+func TestPrintDensityESC7(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{DensityCommand: DensityESC7})
+
+	_, err := p.SetPrintDensity(2)
+	assert.NoError(t, err)
+	_, err = p.SetPrintSpeed(4)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{
+		esc, '7', 7, 100, 2,
+		esc, '7', 7, 100, 12,
+	}
+	assert.Equal(t, expected, mock.Bytes())
+}
@@ -0,0 +1,136 @@
+package escpos
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// modulePath is the import path of this package, used to find its version in the build information
+const modulePath = "github.com/schawnndev/escpos"
+
+// TransportDescriber is implemented by printers able to describe their
+// transport (e.g. "tcp 192.168.1.20:9100") for Diagnostics
+type TransportDescriber interface {
+	Transport() string
+}
+
+// Diagnostics describes the library and the printer connection, so bug
+// reports from the field contain consistent environment data
+type Diagnostics struct {
+	Version   string // version of the library, "(devel)" when unknown
+	GoVersion string // version of the Go runtime
+	Profile   string // name of the active profile, empty for the generic profile
+	Transport string // description of the printer transport
+	CodePage  uint8  // active code page
+
+	Buffered   int   // bytes waiting in the buffer
+	BufferSize int   // size of the buffer
+	BytesSent  int64 // bytes sent to the printer since New
+
+	LastStatusType byte      // DLE EOT type of the last status query, 0 if none
+	LastStatus     []byte    // response to the last status query
+	LastStatusTime time.Time // time of the last status query
+}
+
+// String renders the diagnostics, one "key: value" line per field
+func (d Diagnostics) String() string {
+	profile := d.Profile
+	if profile == "" {
+		profile = "generic"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "escpos: %s\n", d.Version)
+	fmt.Fprintf(&sb, "go: %s\n", d.GoVersion)
+	fmt.Fprintf(&sb, "profile: %s\n", profile)
+	fmt.Fprintf(&sb, "transport: %s\n", d.Transport)
+	fmt.Fprintf(&sb, "code page: %d\n", d.CodePage)
+	fmt.Fprintf(&sb, "buffer: %d/%d bytes\n", d.Buffered, d.BufferSize)
+	fmt.Fprintf(&sb, "sent: %d bytes\n", d.BytesSent)
+	if d.LastStatusType == 0 {
+		sb.WriteString("last status: none\n")
+	} else {
+		fmt.Fprintf(&sb, "last status: type %d = % X at %s\n", d.LastStatusType, d.LastStatus, d.LastStatusTime.Format(time.RFC3339))
+	}
+	return sb.String()
+}
+
+// Diagnostics returns the diagnostics of the library and the printer connection
+func (e *Escpos) Diagnostics() Diagnostics {
+	return Diagnostics{
+		Version:        libraryVersion(),
+		GoVersion:      runtime.Version(),
+		Profile:        e.profile.Name,
+		Transport:      transportName(e.reader),
+		CodePage:       e.codepage,
+		Buffered:       e.dst.Buffered(),
+		BufferSize:     e.dst.Size(),
+		BytesSent:      e.out.n,
+		LastStatusType: e.lastStatusType,
+		LastStatus:     append([]byte(nil), e.lastStatus...),
+		LastStatusTime: e.lastStatusTime,
+	}
+}
+
+// PrintDiagnostics prints the diagnostics as a slip, the buffer statistics
+// being taken before the slip is written
+func (e *Escpos) PrintDiagnostics() (int, error) {
+	return e.Write("DIAGNOSTICS\n" + e.Diagnostics().String())
+}
+
+// libraryVersion returns the version of this module from the build information
+func libraryVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}
+
+// transportName describes the transport of a printer
+func transportName(p io.Reader) string {
+	if d, ok := p.(TransportDescriber); ok {
+		return d.Transport()
+	}
+	return fmt.Sprintf("%T", p)
+}
+
+// countingWriter counts the bytes written to the printer, retrying partial writes
+type countingWriter struct {
+	w       io.Writer
+	n       int64
+	onWrite func(bytes int) // see WithMetrics
+	chunks  *chunking       // see WithChunking
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.chunks != nil && c.chunks.size > 0 {
+		return c.writeChunks(p)
+	}
+	return c.writeCounted(p)
+}
+
+// writeCounted writes p, counting the bytes written
+func (c *countingWriter) writeCounted(p []byte) (int, error) {
+	n, err := WriteFull(c.w, p)
+	c.n += int64(n)
+	if c.onWrite != nil {
+		c.onWrite(n)
+	}
+	return n, err
+}
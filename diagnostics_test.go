@@ -0,0 +1,53 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiagnostics tests collecting the diagnostics
+func TestDiagnostics(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(ProfileEpsonTMT20II)
+
+	d := p.Diagnostics()
+	assert.NotEmpty(t, d.Version)
+	assert.NotEmpty(t, d.GoVersion)
+	assert.Equal(t, "Epson TM-T20II", d.Profile)
+	assert.Equal(t, "*escpostest.MockPrinter", d.Transport)
+	assert.Equal(t, CodePagePC850, d.CodePage)
+	assert.Equal(t, 4096, d.BufferSize)
+	assert.Equal(t, byte(0), d.LastStatusType)
+
+	_, err := p.WriteRaw([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, p.Diagnostics().Buffered)
+
+	mock.SetStatus([]byte{0x12})
+	_, err = p.IsOnline()
+	assert.NoError(t, err)
+
+	d = p.Diagnostics()
+	assert.Equal(t, 0, d.Buffered)
+	assert.Equal(t, int64(6), d.BytesSent)
+	assert.Equal(t, RT_STATUS_ONLINE, d.LastStatusType)
+	assert.Equal(t, []byte{0x12}, d.LastStatus)
+	assert.Contains(t, d.String(), "last status: type 1 = 12")
+}
+
+// TestPrintDiagnostics tests printing the diagnostics slip
+func TestPrintDiagnostics(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.PrintDiagnostics()
+	assert.NoError(t, err)
+	assert.NoError(t, p.Print())
+
+	output := string(mock.Bytes())
+	assert.Contains(t, output, "DIAGNOSTICS\n")
+	assert.Contains(t, output, "profile: generic\n")
+	assert.Contains(t, output, "last status: none\n")
+}
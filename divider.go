@@ -0,0 +1,26 @@
+package escpos
+
+import "strings"
+
+// Divider prints a line of c across the paper, for the characters per line
+// of the current size
+func (e *Escpos) Divider(c rune) (int, error) {
+	return e.Write(strings.Repeat(string(c), e.CharsPerLine()) + "\n")
+}
+
+// DoubleDivider prints a line of '=' across the paper, such as above a total
+func (e *Escpos) DoubleDivider() (int, error) {
+	return e.Divider('=')
+}
+
+// TearHere prints a dotted line across the paper with "tear here" in its
+// middle, marking where to tear a stub off a ticket
+func (e *Escpos) TearHere() (int, error) {
+	const label = " tear here "
+	cols := e.CharsPerLine()
+	if cols < len(label)+2 {
+		return e.Divider('.')
+	}
+	left := (cols - len(label)) / 2
+	return e.Write(strings.Repeat(".", left) + label + strings.Repeat(".", cols-len(label)-left) + "\n")
+}
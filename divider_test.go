@@ -0,0 +1,31 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDividers tests the separator lines
+func TestDividers(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	require.NoError(t, p.SetCharsPerLine(20))
+
+	_, err := p.Divider('-')
+	require.NoError(t, err)
+	_, err = p.DoubleDivider()
+	require.NoError(t, err)
+	_, err = p.TearHere()
+	require.NoError(t, err)
+	p.Style.Width = 2
+	_, err = p.TearHere()
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	assert.Equal(t, "--------------------\n"+
+		"====================\n"+
+		".... tear here .....\n"+
+		"..........\n", mock.String())
+}
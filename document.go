@@ -0,0 +1,202 @@
+package escpos
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/text/encoding"
+)
+
+// Document is an ESC/POS command stream built in memory with the methods of
+// Escpos, to be printed later with PrintDocument, possibly several times.
+// Sections can be built speculatively with Savepoint and RollbackTo, and
+// common fragments built once and embedded in other documents with Embed.
+type Document struct {
+	*Escpos
+	buf *documentBuffer
+
+	// embedded documents, in order, by offset in buf
+	embeds []embed
+	// blocks of a document with reverse output, see SetReverseOutput
+	blocks []blockMark
+}
+
+// embed is a document embedded at an offset of the buffer of another document
+type embed struct {
+	offset int
+	doc    *Document
+}
+
+// NewDocument creates an empty document
+func NewDocument() *Document {
+	buf := &documentBuffer{}
+	return &Document{Escpos: New(buf), buf: buf}
+}
+
+// Bytes returns the commands written to the document, including the
+// current content of the embedded documents
+func (d *Document) Bytes() ([]byte, error) {
+	return d.appendTo(nil)
+}
+
+// appendTo appends the commands of the document to dst
+func (d *Document) appendTo(dst []byte) ([]byte, error) {
+	if d.blocks != nil {
+		return d.appendReversed(dst)
+	}
+	if err := d.dst.Flush(); err != nil {
+		return nil, err
+	}
+	data := d.buf.Bytes()
+	last := 0
+	for _, em := range d.embeds {
+		dst = append(dst, data[last:em.offset]...)
+		var err error
+		if dst, err = em.doc.appendTo(dst); err != nil {
+			return nil, err
+		}
+		last = em.offset
+	}
+	return append(dst, data[last:]...), nil
+}
+
+// Len returns the size of the document in bytes, including the embedded documents
+func (d *Document) Len() int {
+	n := d.length()
+	for _, em := range d.embeds {
+		n += em.doc.Len()
+	}
+	return n
+}
+
+// length returns the size of the commands written to the document itself
+func (d *Document) length() int {
+	return d.buf.Len() + d.dst.Buffered()
+}
+
+// Embed inserts a document, such as a header block or a legal footer, at the
+// current position. The fragment is kept by reference and its bytes are
+// copied only when the document is printed, so a fragment built once can be
+// shared by every job; changes made to it later show in every document
+// embedding it. Fragments are printed with the style in effect where they
+// are embedded: they should reset the styles they change.
+func (d *Document) Embed(fragment *Document) error {
+	if d.blocks != nil {
+		return fmt.Errorf("cannot embed a document in a document with reverse output")
+	}
+	if fragment.contains(d) {
+		return fmt.Errorf("cannot embed a document in itself")
+	}
+	if err := d.dst.Flush(); err != nil {
+		return err
+	}
+	d.embeds = append(d.embeds, embed{offset: d.buf.Len(), doc: fragment})
+	return nil
+}
+
+// contains reports whether the document is other or embeds it
+func (d *Document) contains(other *Document) bool {
+	if d == other {
+		return true
+	}
+	for _, em := range d.embeds {
+		if em.doc.contains(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteTo writes the commands of the document to w, implementing io.WriterTo
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	data, err := d.Bytes()
+	if err != nil {
+		return 0, err
+	}
+	n, err := WriteFull(w, data)
+	return int64(n), err
+}
+
+// Savepoint marks a position in a document, with the text style, encoding,
+// warnings and sticky error in effect there
+type Savepoint struct {
+	doc       *Document
+	length    int
+	embeds    int
+	blocks    int
+	style     Style
+	enc       encoding.Encoding
+	codepage  uint8
+	kanjiMode bool
+	kanjiCode uint8
+	warnings  int
+	err       error
+}
+
+// Savepoint returns the current position of the document, to return to with RollbackTo
+func (d *Document) Savepoint() Savepoint {
+	return Savepoint{
+		doc:       d,
+		length:    d.length(),
+		embeds:    len(d.embeds),
+		blocks:    len(d.blocks),
+		style:     d.Style,
+		enc:       d.enc,
+		codepage:  d.codepage,
+		kanjiMode: d.kanjiMode,
+		kanjiCode: d.kanjiCode,
+		warnings:  len(d.warnings),
+		err:       d.err,
+	}
+}
+
+// RollbackTo removes everything written to the document after sp, with the
+// warnings and the sticky error raised since, and restores the style and
+// encoding in effect at sp. Savepoints taken after sp are no longer valid.
+func (d *Document) RollbackTo(sp Savepoint) error {
+	if sp.doc != d {
+		return fmt.Errorf("savepoint belongs to another document")
+	}
+	if err := d.dst.Flush(); err != nil {
+		return err
+	}
+	if sp.length > d.buf.Len() {
+		return fmt.Errorf("savepoint at byte %d is past the end of the document (%d bytes)", sp.length, d.buf.Len())
+	}
+
+	d.buf.Truncate(sp.length)
+	d.embeds = d.embeds[:min(sp.embeds, len(d.embeds))]
+	if d.blocks != nil {
+		d.blocks = d.blocks[:min(sp.blocks, len(d.blocks))]
+	}
+	d.Style = sp.style
+	d.enc, d.codepage = sp.enc, sp.codepage
+	d.kanjiMode, d.kanjiCode = sp.kanjiMode, sp.kanjiCode
+	d.warnings = d.warnings[:min(sp.warnings, len(d.warnings))]
+	d.err = sp.err
+	return nil
+}
+
+// PrintDocument sends the commands of a document to the printer
+func (e *Escpos) PrintDocument(d *Document) (int, error) {
+	data, err := d.Bytes()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build document: %w", err)
+	}
+	return e.WriteRaw(data)
+}
+
+// documentBuffer is the printer of a document, keeping the data written to it
+type documentBuffer struct {
+	bytes.Buffer
+}
+
+func (b *documentBuffer) Read(p []byte) (int, error) {
+	return 0, errors.New("cannot read the printer status while building a document")
+}
+
+func (b *documentBuffer) Close() error {
+	return nil
+}
@@ -0,0 +1,171 @@
+package escpos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TestDocument tests building a document and printing it twice
+func TestDocument(t *testing.T) {
+	doc := NewDocument()
+	_, err := doc.SetBold(true)
+	require.NoError(t, err)
+	_, err = doc.Write("Hello\n")
+	require.NoError(t, err)
+	assert.Equal(t, 9, doc.Len())
+
+	mock := NewMockPrinter()
+	p := New(mock)
+	_, err = p.PrintDocument(doc)
+	require.NoError(t, err)
+	_, err = p.PrintDocument(doc)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := []byte{esc, 'E', 1, 'H', 'e', 'l', 'l', 'o', '\n'}
+	assert.Equal(t, append(expected, expected...), mock.Bytes())
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), n)
+	assert.Equal(t, expected, buf.Bytes())
+
+	_, err = doc.QueryStatus(RT_STATUS_ONLINE)
+	assert.Error(t, err)
+}
+
+// TestDocumentRollback tests rolling back a speculative section
+func TestDocumentRollback(t *testing.T) {
+	doc := NewDocument()
+	doc.Write("Header\n")
+
+	sp := doc.Savepoint()
+	doc.SetBold(true)
+	doc.SetEncoding(charmap.CodePage866, CodePagePC866)
+	doc.Write("Wide table\n")
+	assert.True(t, doc.Style.Bold)
+
+	require.NoError(t, doc.RollbackTo(sp))
+	assert.False(t, doc.Style.Bold)
+	assert.Equal(t, CodePagePC850, doc.codepage)
+	assert.Equal(t, charmap.CodePage850, doc.enc)
+
+	doc.Write("Narrow\n")
+	data, err := doc.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("Header\nNarrow\n"), data)
+
+	// Rolling back to the same savepoint again is allowed
+	require.NoError(t, doc.RollbackTo(sp))
+	data, err = doc.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("Header\n"), data)
+}
+
+// TestDocumentRollbackWarnings tests that the warnings and the sticky error
+// of a rolled back section are discarded with it
+func TestDocumentRollbackWarnings(t *testing.T) {
+	doc := NewDocument()
+	doc.SetStickyErrors(true)
+	doc.OpenDrawer(5, 1)
+	require.Len(t, doc.Warnings(), 1)
+
+	sp := doc.Savepoint()
+	doc.OpenDrawer(1, 20)
+	assert.Error(t, doc.SetProfile(Profile{Charset: CharsetKorea + 1}))
+	assert.Len(t, doc.Warnings(), 2)
+	assert.Error(t, doc.Err())
+
+	require.NoError(t, doc.RollbackTo(sp))
+	assert.Len(t, doc.Warnings(), 1)
+	assert.NoError(t, doc.Err())
+	_, err := doc.Write("Kept\n")
+	require.NoError(t, err)
+	data, err := doc.Bytes()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Kept\n")
+}
+
+// TestDocumentRollbackInvalid tests the savepoints that cannot be rolled back to
+func TestDocumentRollbackInvalid(t *testing.T) {
+	doc := NewDocument()
+	other := NewDocument()
+	assert.Error(t, doc.RollbackTo(other.Savepoint()))
+
+	start := doc.Savepoint()
+	doc.Write("abc")
+	later := doc.Savepoint()
+	require.NoError(t, doc.RollbackTo(start))
+	assert.Error(t, doc.RollbackTo(later))
+}
+
+// TestDocumentEmbed tests embedding shared fragments in documents
+func TestDocumentEmbed(t *testing.T) {
+	header := NewDocument()
+	header.SetJustify(JustifyCenter)
+	header.Write("SHOP\n")
+	header.SetJustify(JustifyLeft)
+
+	footer := NewDocument()
+	footer.Write("Thank you\n")
+
+	receipt := func(item string) *Document {
+		doc := NewDocument()
+		require.NoError(t, doc.Embed(header))
+		doc.Write(item + "\n")
+		require.NoError(t, doc.Embed(footer))
+		return doc
+	}
+
+	doc := receipt("Coffee")
+	data, err := doc.Bytes()
+	require.NoError(t, err)
+	expected := []byte{esc, 'a', 1, 'S', 'H', 'O', 'P', '\n', esc, 'a', 0}
+	expected = append(expected, "Coffee\nThank you\n"...)
+	assert.Equal(t, expected, data)
+	assert.Equal(t, len(expected), doc.Len())
+
+	// Fragments are embedded by reference
+	footer.Write("See you soon\n")
+	data, err = receipt("Tea").Bytes()
+	require.NoError(t, err)
+	assert.True(t, bytes.HasSuffix(data, []byte("Tea\nThank you\nSee you soon\n")))
+
+	// Nested fragments
+	outer := NewDocument()
+	require.NoError(t, outer.Embed(doc))
+	data, err = outer.Bytes()
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, expected[:len(expected)-len("Thank you\n")]))
+}
+
+// TestDocumentEmbedCycle tests that documents cannot embed themselves
+func TestDocumentEmbedCycle(t *testing.T) {
+	a := NewDocument()
+	b := NewDocument()
+	require.NoError(t, a.Embed(b))
+	assert.Error(t, a.Embed(a))
+	assert.Error(t, b.Embed(a))
+}
+
+// TestDocumentEmbedRollback tests that rolling back removes the fragments embedded after the savepoint
+func TestDocumentEmbedRollback(t *testing.T) {
+	footer := NewDocument()
+	footer.Write("Footer\n")
+
+	doc := NewDocument()
+	doc.Write("Body\n")
+	sp := doc.Savepoint()
+	require.NoError(t, doc.Embed(footer))
+	require.NoError(t, doc.RollbackTo(sp))
+	doc.Write("End\n")
+
+	data, err := doc.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("Body\nEnd\n"), data)
+}
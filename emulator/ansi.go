@@ -0,0 +1,163 @@
+package emulator
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Width of a Font A character in dots, used to map images onto terminal cells
+const dotsPerColumn = 12
+
+// ANSI escape sequences
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiDim       = "\x1b[2m"
+	ansiUnderline = "\x1b[4m"
+	ansiDouble    = "\x1b[21m"
+	ansiReverse   = "\x1b[7m"
+)
+
+// quadrants maps 2x2 dot patterns (bit 0: upper left, 1: upper right,
+// 2: lower left, 3: lower right) to Unicode quadrant block characters
+var quadrants = []rune(" ▘▝▀▖▌▞▛▗▚▐▜▄▙▟█")
+
+// RenderANSI writes an approximation of the paper to w using ANSI styling
+// (bold, underline, reverse; double-strike is shown as bold) and Unicode block characters for raster images,
+// so receipts can be checked in a terminal, an SSH session or a CI log.
+// Each terminal cell stands for one Font A character; the paper is framed by
+// its edges.
+func (em *Emulator) RenderANSI(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	columns := em.columns
+
+	bw.WriteString("┌" + strings.Repeat("─", columns) + "┐\n")
+	for _, el := range em.Paper() {
+		switch el.Kind {
+		case KindText:
+			writePaperLine(bw, el.padding(columns), ansiText(el), el.width(), columns)
+		case KindImage:
+			for _, row := range ansiRaster(el.Raster, columns) {
+				width := utf8.RuneCountInString(row)
+				writePaperLine(bw, justifyOffset(el.Justify, width, columns), row, width, columns)
+			}
+		case KindBarcode, KindQRCode:
+			label := el.text(columns)
+			width := utf8.RuneCountInString(label)
+			if width > columns {
+				label = string([]rune(label)[:columns])
+				width = columns
+			}
+			writePaperLine(bw, justifyOffset(el.Justify, width, columns), ansiDim+label+ansiReset, width, columns)
+		case KindCut:
+			if el.Partial {
+				bw.WriteString("├" + strings.Repeat("╌", columns) + "┤\n")
+			} else {
+				bw.WriteString("└" + strings.Repeat("─", columns) + "┘\n")
+				bw.WriteString("┌" + strings.Repeat("─", columns) + "┐\n")
+			}
+		}
+	}
+	bw.WriteString("└" + strings.Repeat("─", columns) + "┘\n")
+
+	return bw.Flush()
+}
+
+// writePaperLine writes one framed line, content being width cells wide once displayed
+func writePaperLine(w *bufio.Writer, padding int, content string, width int, columns int) {
+	fill := columns - padding - width
+	if fill < 0 {
+		fill = 0
+	}
+	w.WriteString("│")
+	w.WriteString(strings.Repeat(" ", padding))
+	w.WriteString(content)
+	w.WriteString(strings.Repeat(" ", fill))
+	w.WriteString("│\n")
+}
+
+// ansiText renders the spans of a text line with ANSI styling.
+// Wide characters are followed by spaces to keep the layout of the paper.
+func ansiText(el Element) string {
+	var sb strings.Builder
+	for _, s := range el.Spans {
+		var codes string
+		if s.Style.Bold || s.Style.DoubleStrike {
+			codes += ansiBold
+		}
+		switch s.Style.Underline {
+		case 1:
+			codes += ansiUnderline
+		case 2:
+			codes += ansiDouble
+		}
+		if s.Style.Reverse {
+			codes += ansiReverse
+		}
+
+		sb.WriteString(codes)
+		for _, r := range s.Text {
+			sb.WriteRune(r)
+			if s.Style.Width > 1 {
+				sb.WriteString(strings.Repeat(" ", int(s.Style.Width)-1))
+			}
+		}
+		if codes != "" {
+			sb.WriteString(ansiReset)
+		}
+	}
+	return sb.String()
+}
+
+// ansiRaster converts a raster image to rows of quadrant block characters.
+// A cell covers dotsPerColumn dots horizontally and twice as many vertically,
+// split in four quadrants lit when at least half of their dots are black.
+func ansiRaster(r Raster, columns int) []string {
+	qw := dotsPerColumn / 2 // quadrant width in dots
+	qh := dotsPerColumn     // quadrant height in dots
+
+	cells := (r.Width + dotsPerColumn - 1) / dotsPerColumn
+	if cells > columns {
+		cells = columns
+	}
+
+	var rows []string
+	for y := 0; y < r.Height; y += 2 * qh {
+		var sb strings.Builder
+		for c := 0; c < cells; c++ {
+			x := c * dotsPerColumn
+			idx := 0
+			if quadrantLit(r, x, y, qw, qh) {
+				idx |= 1
+			}
+			if quadrantLit(r, x+qw, y, qw, qh) {
+				idx |= 2
+			}
+			if quadrantLit(r, x, y+qh, qw, qh) {
+				idx |= 4
+			}
+			if quadrantLit(r, x+qw, y+qh, qw, qh) {
+				idx |= 8
+			}
+			sb.WriteRune(quadrants[idx])
+		}
+		rows = append(rows, sb.String())
+	}
+	return rows
+}
+
+// quadrantLit reports whether at least half of the dots of a w*h area of the raster are black
+func quadrantLit(r Raster, x0, y0, w, h int) bool {
+	black, total := 0, 0
+	for y := y0; y < y0+h && y < r.Height; y++ {
+		for x := x0; x < x0+w && x < r.Width; x++ {
+			total++
+			if r.At(x, y) {
+				black++
+			}
+		}
+	}
+	return total > 0 && black*2 >= total
+}
@@ -0,0 +1,59 @@
+package emulator
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRenderANSI tests the terminal rendering of styled text and cuts
+func TestRenderANSI(t *testing.T) {
+	em := New(WithColumns(10))
+	p := escpos.New(em)
+
+	p.SetBold(true)
+	p.Write("Hi")
+	p.SetBold(false)
+	p.SetUnderline(escpos.UnderlineSingle)
+	p.Write("there\n")
+	require.NoError(t, p.PrintAndCut())
+
+	var buf bytes.Buffer
+	require.NoError(t, em.RenderANSI(&buf))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Equal(t, "┌──────────┐", lines[0])
+	assert.Equal(t, "│"+ansiBold+"Hi"+ansiReset+ansiUnderline+"there"+ansiReset+"   │", lines[1])
+	assert.Equal(t, "└──────────┘", lines[2])
+}
+
+// TestRenderANSIImage tests rendering a raster image with quadrant blocks
+func TestRenderANSIImage(t *testing.T) {
+	em := New(WithColumns(10))
+	p := escpos.New(em)
+
+	// Left half black, right half white: 24x24 dots = 2 cells
+	img := image.NewGray(image.Rect(0, 0, 24, 24))
+	for y := 0; y < 24; y++ {
+		for x := 0; x < 24; x++ {
+			if x < 12 {
+				img.Set(x, y, color.Black)
+			} else {
+				img.Set(x, y, color.White)
+			}
+		}
+	}
+	_, err := p.PrintImageWithProcessing(img, escpos.ImageProcessThreshold, false, false)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	var buf bytes.Buffer
+	require.NoError(t, em.RenderANSI(&buf))
+	assert.Contains(t, buf.String(), "│█ ")
+}
@@ -0,0 +1,159 @@
+// Package emulator provides a virtual ESC/POS printer.
+//
+// An Emulator implements the escpos.Printer interface: it interprets the
+// command stream it receives, keeps a model of the printed paper and answers
+// real-time status requests, so receipts can be previewed and tested without
+// any hardware.
+package emulator
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	"github.com/schawnndev/escpos"
+)
+
+// Default status bytes returned for DLE EOT 1-4 (online, no error, paper adequate)
+var defaultStatus = [5]byte{0, 0x16, 0x12, 0x12, 0x12}
+
+// Emulator is a virtual printer implementing escpos.Printer
+type Emulator struct {
+	mu       sync.Mutex
+	raw      bytes.Buffer // every byte ever received
+	pending  []byte       // bytes of an incomplete command waiting for more data
+	replies  bytes.Buffer // pending answers to status requests
+	status   [5]byte
+	columns  int
+	state    state
+	line     []Span
+	elements []Element
+	stored   *Raster // graphics data in the print buffer, see GS ( L
+}
+
+// Option configures an Emulator
+type Option func(*Emulator)
+
+// WithColumns sets the number of Font A characters per line (default: 48, an 80mm printer)
+func WithColumns(n int) Option {
+	return func(em *Emulator) {
+		if n > 0 {
+			em.columns = n
+		}
+	}
+}
+
+// New creates a new Emulator
+func New(opts ...Option) *Emulator {
+	em := &Emulator{
+		status:  defaultStatus,
+		columns: 48,
+		state:   defaultState(),
+	}
+	for _, opt := range opts {
+		opt(em)
+	}
+	return em
+}
+
+// Write interprets the received bytes. Commands split across several writes are supported.
+func (em *Emulator) Write(p []byte) (int, error) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	em.raw.Write(p)
+	em.pending = append(em.pending, p...)
+	em.pending = em.interpret(em.pending)
+	return len(p), nil
+}
+
+// Read returns the answers to the status requests received so far.
+// It returns 0 bytes when no answer is pending.
+func (em *Emulator) Read(p []byte) (int, error) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	if em.replies.Len() == 0 {
+		return 0, nil
+	}
+	return em.replies.Read(p)
+}
+
+// Close implements escpos.Printer. It does nothing.
+func (em *Emulator) Close() error {
+	return nil
+}
+
+// SetStatus sets the byte returned for the real-time status request DLE EOT n (1-4)
+func (em *Emulator) SetStatus(n byte, status byte) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	if n >= 1 && n <= 4 {
+		em.status[n] = status
+	}
+}
+
+// Bytes returns a copy of every byte received by the emulator
+func (em *Emulator) Bytes() []byte {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	return bytes.Clone(em.raw.Bytes())
+}
+
+// Columns returns the number of Font A characters per line
+func (em *Emulator) Columns() int {
+	return em.columns
+}
+
+// Paper returns the elements printed so far, including the text line
+// currently held in the print buffer.
+func (em *Emulator) Paper() []Element {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	elements := make([]Element, len(em.elements), len(em.elements)+1)
+	copy(elements, em.elements)
+	if len(em.line) > 0 {
+		elements = append(elements, em.textElement())
+	}
+	return elements
+}
+
+// Reset clears the paper and the received bytes and restores the power-on state
+func (em *Emulator) Reset() {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	em.raw.Reset()
+	em.replies.Reset()
+	em.pending = nil
+	em.line = nil
+	em.elements = nil
+	em.state = defaultState()
+}
+
+// Text renders the paper as plain text, one printed line per text line.
+// Barcodes, QR codes and images are replaced by a short description and
+// cuts by a dashed line.
+func (em *Emulator) Text() string {
+	var sb strings.Builder
+	for _, el := range em.Paper() {
+		sb.WriteString(el.text(em.columns))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// String implements fmt.Stringer and returns Text()
+func (em *Emulator) String() string {
+	return em.Text()
+}
+
+// Transport describes the emulator for escpos.Diagnostics
+func (em *Emulator) Transport() string {
+	return "emulator"
+}
+
+var _ escpos.Printer = (*Emulator)(nil)
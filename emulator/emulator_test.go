@@ -0,0 +1,195 @@
+package emulator
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEmulatorText tests rendering a small receipt as plain text
+func TestEmulatorText(t *testing.T) {
+	em := New(WithColumns(20))
+	p := escpos.New(em)
+
+	p.SetJustify(escpos.JustifyCenter)
+	p.SetBold(true)
+	p.Write("SHOP\n")
+	p.SetBold(false)
+	p.SetJustify(escpos.JustifyRight)
+	p.Write("12.50\n")
+	p.SetJustify(escpos.JustifyLeft)
+	p.EAN13("1234567890128")
+	p.QRCode("https://example.com", escpos.QRCodeModel2, 4, escpos.QRCodeErrorCorrectionLevelM)
+	require.NoError(t, p.PrintAndCut())
+
+	expected := "        SHOP\n" +
+		"               12.50\n" +
+		"[barcode EAN13: 1234567890128]\n" +
+		"[qr: https://example.com]\n" +
+		"--------------------\n"
+	assert.Equal(t, expected, em.Text())
+
+	paper := em.Paper()
+	require.Len(t, paper, 5)
+	assert.True(t, paper[0].Spans[0].Style.Bold)
+	assert.False(t, paper[1].Spans[0].Style.Bold)
+	assert.Equal(t, KindCut, paper[4].Kind)
+}
+
+// TestEmulatorCodePage tests that text is decoded with the active code page
+func TestEmulatorCodePage(t *testing.T) {
+	em := New()
+	p := escpos.New(em)
+
+	p.Write("Crème brûlée\n")
+	require.NoError(t, p.Print())
+
+	assert.Equal(t, "Crème brûlée\n", em.Text())
+}
+
+// TestEmulatorSplitWrites tests that commands split across writes are interpreted once complete
+func TestEmulatorSplitWrites(t *testing.T) {
+	em := New()
+
+	em.Write([]byte{0x1D, 'v', '0', 0, 1})
+	assert.Empty(t, em.Paper())
+
+	em.Write([]byte{0, 2, 0, 0xFF, 0x80})
+	paper := em.Paper()
+	require.Len(t, paper, 1)
+	assert.Equal(t, KindImage, paper[0].Kind)
+	assert.Equal(t, 8, paper[0].Raster.Width)
+	assert.Equal(t, 2, paper[0].Raster.Height)
+	assert.True(t, paper[0].Raster.At(7, 0))
+	assert.True(t, paper[0].Raster.At(0, 1))
+	assert.False(t, paper[0].Raster.At(1, 1))
+}
+
+// TestEmulatorStatus tests answering real-time status requests
+func TestEmulatorStatus(t *testing.T) {
+	em := New()
+	p := escpos.New(em)
+
+	online, err := p.IsOnline()
+	assert.NoError(t, err)
+	assert.True(t, online)
+
+	em.SetStatus(escpos.RT_STATUS_PAPER, 0x60)
+	status, err := p.PaperStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, status)
+}
+
+// TestEmulatorReset tests clearing the emulator
+func TestEmulatorReset(t *testing.T) {
+	em := New()
+	em.Write([]byte("hello\n"))
+	assert.NotEmpty(t, em.Bytes())
+
+	em.Reset()
+	assert.Empty(t, em.Bytes())
+	assert.Empty(t, em.Paper())
+}
+
+// TestEmulatorPositioning tests tab stops and absolute positions
+func TestEmulatorPositioning(t *testing.T) {
+	em := New()
+	p := escpos.New(em)
+
+	p.SetTabPositions([]uint8{6, 12})
+	p.Write("Qty")
+	p.Tab()
+	p.Write("Item")
+	p.Tab()
+	p.Write("Price")
+	p.Tab() // no stop left: ignored
+	p.Write("\n")
+	p.SetAbsolutePosition(10 * 12)
+	p.Write("x")
+	p.SetRelativePosition(2 * 12)
+	p.Write("y\n")
+	require.NoError(t, p.Print())
+
+	assert.Equal(t, "Qty   Item  Price\n          x  y\n", em.Text())
+}
+
+// TestEmulatorDoubleStrike tests tracking the double-strike mode
+func TestEmulatorDoubleStrike(t *testing.T) {
+	em := New()
+	p := escpos.New(em)
+
+	p.SetDoubleStrike(true)
+	p.SetCharacterSpacing(2)
+	p.Write("dark\n")
+	require.NoError(t, p.Print())
+
+	paper := em.Paper()
+	require.Len(t, paper, 1)
+	assert.Equal(t, "dark", paper[0].PlainText())
+	assert.True(t, paper[0].Spans[0].Style.DoubleStrike)
+}
+
+// TestEmulatorKanji tests that Shift JIS text is decoded in Kanji mode
+func TestEmulatorKanji(t *testing.T) {
+	em := New()
+	p := escpos.New(em)
+
+	p.WriteSJIS("日本語")
+	p.SetKanjiMode(false)
+	p.Write(" ok\n")
+	require.NoError(t, p.Print())
+
+	assert.Equal(t, "日本語 ok\n", em.Text())
+}
+
+// TestEmulatorGraphics tests images stored in the print buffer with GS ( L then printed
+func TestEmulatorGraphics(t *testing.T) {
+	em := New()
+	p := escpos.New(em)
+	p.SetProfile(escpos.Profile{RasterCommand: escpos.RasterGraphics})
+
+	img := image.NewGray(image.Rect(0, 0, 16, 4))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	img.Set(3, 2, color.Black)
+	_, err := p.PrintImageWithProcessing(img, escpos.ImageProcessDither, true, true)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	paper := em.Paper()
+	require.Len(t, paper, 1)
+	assert.Equal(t, KindImage, paper[0].Kind)
+	assert.Equal(t, 16, paper[0].Raster.Width)
+	assert.Equal(t, 4, paper[0].Raster.Height)
+	assert.True(t, paper[0].Raster.At(3, 2))
+	assert.False(t, paper[0].Raster.At(2, 2))
+}
+
+// TestEmulatorCounter tests printing the printer counter
+func TestEmulatorCounter(t *testing.T) {
+	em := New(WithColumns(20))
+	p := escpos.New(em)
+
+	p.SetCounterFormat(3, escpos.CounterRightZeros)
+	p.SetCounterRange(8, 10, 1, 2)
+	for range 5 {
+		p.Write("No ")
+		p.PrintCounter()
+		p.Write("\n")
+	}
+	p.SetCounterFormat(4, escpos.CounterLeft)
+	p.SetCounterRange(3, 1, 2, 1)
+	for range 3 {
+		p.PrintCounter()
+		p.Write("|\n")
+	}
+	require.NoError(t, p.Print())
+
+	expected := "No 008\nNo 008\nNo 009\nNo 009\nNo 010\n" +
+		"3   |\n1   |\n3   |\n"
+	assert.Equal(t, expected, em.Text())
+}
@@ -0,0 +1,681 @@
+package emulator
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/schawnndev/escpos"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// Control bytes
+const (
+	ht  byte = 0x09
+	lf  byte = 0x0A
+	ff  byte = 0x0C
+	esc byte = 0x1B
+	gs  byte = 0x1D
+	fs  byte = 0x1C
+	dle byte = 0x10
+	eot byte = 0x04
+	enq byte = 0x05
+	dc4 byte = 0x14
+)
+
+// Approximate height of a text line in dots, used to convert dot feeds into lines
+const lineHeightDots = 30
+
+// state is the printer state that survives between commands
+type state struct {
+	style     Style
+	justify   escpos.Justify
+	codepage  uint8
+	qrData    string
+	tabs      []int // tab stops in columns, nil for the default stops every 8 columns
+	kanji     bool  // Kanji mode (FS &)
+	kanjiSJIS bool  // Shift JIS code system (FS C 1 or 2)
+	counter   counter
+}
+
+// counter is the printer counter (GS C)
+type counter struct {
+	digits, alignment byte
+	from, to, value   int
+	step, repeat      int
+	printed           int // times the value was printed
+}
+
+// defaultState returns the power-on state of the printer
+func defaultState() state {
+	return state{
+		style:   Style{Width: 1, Height: 1},
+		counter: counter{from: 1, to: 65535, value: 1, step: 1, repeat: 1},
+	}
+}
+
+// interpret executes every complete command in buf and returns the bytes of
+// a trailing incomplete command, if any.
+func (em *Emulator) interpret(buf []byte) []byte {
+	for len(buf) > 0 {
+		n := em.command(buf)
+		if n == 0 {
+			return buf
+		}
+		buf = buf[n:]
+	}
+	return nil
+}
+
+// command executes the command at the start of buf and returns its length,
+// or 0 when more bytes are needed.
+func (em *Emulator) command(buf []byte) int {
+	switch buf[0] {
+	case lf, ff:
+		em.newline()
+		return 1
+	case ht:
+		em.tab()
+		return 1
+	case esc:
+		return em.escCommand(buf)
+	case gs:
+		return em.gsCommand(buf)
+	case fs:
+		return em.fsCommand(buf)
+	case dle:
+		return em.dleCommand(buf)
+	}
+
+	if buf[0] < 0x20 {
+		// CR and other control bytes do not print anything
+		return 1
+	}
+
+	n := 0
+	for n < len(buf) && buf[n] >= 0x20 {
+		n++
+	}
+	em.text(buf[:n])
+	return n
+}
+
+// escCommand executes an ESC command
+func (em *Emulator) escCommand(buf []byte) int {
+	if len(buf) < 2 {
+		return 0
+	}
+
+	if n, ok := escLengths[buf[1]]; ok {
+		if len(buf) < n {
+			return 0
+		}
+		em.escFixed(buf[:n])
+		return n
+	}
+
+	switch buf[1] {
+	case 'D':
+		// ESC D n1...nk NUL
+		i := bytes.IndexByte(buf[2:], 0)
+		if i < 0 {
+			return 0
+		}
+		em.state.tabs = []int{}
+		for _, n := range buf[2 : 2+i] {
+			em.state.tabs = append(em.state.tabs, int(n))
+		}
+		return i + 3
+	case '&':
+		return userCharactersLength(buf)
+	case '*':
+		// ESC * m nL nH d1...dk
+		if len(buf) < 5 {
+			return 0
+		}
+		k := int(buf[3]) + int(buf[4])*256
+		if buf[2] == 32 || buf[2] == 33 {
+			k *= 3
+		}
+		return available(buf, 5+k)
+	case '(':
+		return parenLength(buf)
+	}
+	return 2
+}
+
+// escFixed executes a fixed length ESC command
+func (em *Emulator) escFixed(cmd []byte) {
+	s := &em.state
+	switch cmd[1] {
+	case '@':
+		em.line = nil
+		em.state = defaultState()
+	case 'E':
+		s.style.Bold = cmd[2]&1 == 1
+	case 'G':
+		s.style.DoubleStrike = cmd[2]&1 == 1
+	case '-':
+		s.style.Underline = asciiDigit(cmd[2]) % 3
+	case '{':
+		s.style.UpsideDown = cmd[2]&1 == 1
+	case 'V':
+		s.style.Rotate = asciiDigit(cmd[2]) != 0
+	case 'a':
+		s.justify = escpos.Justify(asciiDigit(cmd[2]) % 3)
+	case 'M':
+		s.style.Font = asciiDigit(cmd[2])
+	case 't':
+		s.codepage = cmd[2]
+	case '!':
+		n := cmd[2]
+		s.style.Font = n & 1
+		s.style.Bold = n&0x08 != 0
+		s.style.Height = 1 + (n>>4)&1
+		s.style.Width = 1 + (n>>5)&1
+		s.style.Underline = (n >> 7) & 1
+	case '$':
+		// ESC $ nL nH: absolute position in dots
+		em.moveTo(int(cmd[2]) | int(cmd[3])<<8)
+	case '\\':
+		// ESC \ nL nH: relative position in dots, negative moves are ignored
+		dots := int(int16(uint16(cmd[2]) | uint16(cmd[3])<<8))
+		if dots > 0 {
+			em.moveTo(Element{Spans: em.line}.width()*dotsPerColumn + dots)
+		}
+	case 'd':
+		em.feedLines(int(cmd[2]))
+	case 'J':
+		em.feedLines(int(cmd[2]) / lineHeightDots)
+	}
+}
+
+// gsCommand executes a GS command
+func (em *Emulator) gsCommand(buf []byte) int {
+	if len(buf) < 2 {
+		return 0
+	}
+
+	if n, ok := gsLengths[buf[1]]; ok {
+		if len(buf) < n {
+			return 0
+		}
+		em.gsFixed(buf[:n])
+		return n
+	}
+
+	switch buf[1] {
+	case 'V':
+		// GS V m [n]
+		if len(buf) < 3 {
+			return 0
+		}
+		n := 3
+		if buf[2] >= 65 {
+			n = 4
+		}
+		if len(buf) < n {
+			return 0
+		}
+		em.cut(buf[2] == 1 || buf[2] == 49 || buf[2] == 66 || buf[2] == 98 || buf[2] == 104)
+		return n
+	case 'k':
+		return em.barcode(buf)
+	case '(':
+		n := parenLength(buf)
+		if n > 0 && buf[2] == 'k' {
+			em.symbol(buf[5:n])
+		}
+		if n > 0 && buf[2] == 'L' {
+			em.graphics(buf[5:n])
+		}
+		return n
+	case '8':
+		// GS 8 L p1 p2 p3 p4 m fn ...
+		if len(buf) < 7 {
+			return 0
+		}
+		k := int(buf[3]) | int(buf[4])<<8 | int(buf[5])<<16 | int(buf[6])<<24
+		n := available(buf, 7+k)
+		if n > 0 && buf[2] == 'L' {
+			em.graphics(buf[7:n])
+		}
+		return n
+	case 'v':
+		return em.rasterImage(buf)
+	case '*':
+		// GS * x y d1...d(x*y*8)
+		if len(buf) < 4 {
+			return 0
+		}
+		return available(buf, 4+int(buf[2])*int(buf[3])*8)
+	case 'C':
+		n := counterLength(buf)
+		if n > 0 {
+			em.state.counter.set(buf[:n])
+		}
+		return n
+	case 'c':
+		em.text([]byte(em.state.counter.next()))
+		return 2
+	}
+	return 2
+}
+
+// gsFixed executes a fixed length GS command
+func (em *Emulator) gsFixed(cmd []byte) {
+	s := &em.state
+	switch cmd[1] {
+	case '!':
+		s.style.Width = (cmd[2]>>4)&0x07 + 1
+		s.style.Height = cmd[2]&0x07 + 1
+	case 'B':
+		s.style.Reverse = cmd[2]&1 == 1
+	}
+}
+
+// fsCommand executes an FS command
+func (em *Emulator) fsCommand(buf []byte) int {
+	if len(buf) < 2 {
+		return 0
+	}
+
+	if n, ok := fsLengths[buf[1]]; ok {
+		if len(buf) < n {
+			return 0
+		}
+		switch buf[1] {
+		case '&':
+			em.state.kanji = true
+		case '.':
+			em.state.kanji = false
+		case 'C':
+			em.state.kanjiSJIS = asciiDigit(buf[2]) != 0
+		}
+		return n
+	}
+
+	switch buf[1] {
+	case 'q':
+		return nvImagesLength(buf)
+	case '(':
+		return parenLength(buf)
+	}
+	return 2
+}
+
+// dleCommand executes a real-time DLE command
+func (em *Emulator) dleCommand(buf []byte) int {
+	if len(buf) < 3 {
+		return 0
+	}
+
+	switch buf[1] {
+	case eot:
+		if n := buf[2]; n >= 1 && n <= 4 {
+			em.replies.WriteByte(em.status[n])
+		}
+		return 3
+	case enq:
+		return 3
+	case dc4:
+		switch buf[2] {
+		case 1, 2:
+			return available(buf, 5)
+		case 7:
+			return available(buf, 4)
+		case 8:
+			return available(buf, 10)
+		}
+		return 3
+	}
+	return 2
+}
+
+// barcode interprets GS k in both the NUL terminated and the length prefixed forms
+func (em *Emulator) barcode(buf []byte) int {
+	if len(buf) < 3 {
+		return 0
+	}
+	m := buf[2]
+
+	var data []byte
+	var n int
+	if m <= 6 {
+		i := bytes.IndexByte(buf[3:], 0)
+		if i < 0 {
+			return 0
+		}
+		data, n = buf[3:3+i], i+4
+	} else {
+		if len(buf) < 4 {
+			return 0
+		}
+		n = 4 + int(buf[3])
+		if len(buf) < n {
+			return 0
+		}
+		data = buf[4:n]
+	}
+
+	em.flushLine()
+	em.elements = append(em.elements, Element{
+		Kind:      KindBarcode,
+		Justify:   em.state.justify,
+		Symbology: m,
+		Data:      string(data),
+	})
+	return n
+}
+
+// symbol interprets the body of a GS ( k two-dimensional code command
+func (em *Emulator) symbol(body []byte) {
+	if len(body) < 2 || body[0] != 49 {
+		return
+	}
+	switch body[1] {
+	case 80: // store the data
+		if len(body) >= 3 {
+			em.state.qrData = string(body[3:])
+		}
+	case 81: // print the stored data
+		em.flushLine()
+		em.elements = append(em.elements, Element{
+			Kind:    KindQRCode,
+			Justify: em.state.justify,
+			Data:    em.state.qrData,
+		})
+	}
+}
+
+// rasterImage interprets GS v 0
+func (em *Emulator) rasterImage(buf []byte) int {
+	if len(buf) < 8 {
+		return 0
+	}
+	widthBytes := int(buf[4]) + int(buf[5])*256
+	height := int(buf[6]) + int(buf[7])*256
+	n := 8 + widthBytes*height
+	if len(buf) < n {
+		return 0
+	}
+
+	em.flushLine()
+	em.elements = append(em.elements, Element{
+		Kind:    KindImage,
+		Justify: em.state.justify,
+		Raster: Raster{
+			Width:  widthBytes * 8,
+			Height: height,
+			Data:   bytes.Clone(buf[8:n]),
+		},
+	})
+	return n
+}
+
+// graphics executes the GS ( L and GS 8 L graphics functions storing raster
+// data in the print buffer (112) and printing it (50), from the m parameter on
+func (em *Emulator) graphics(params []byte) {
+	if len(params) < 2 || params[0] != 48 {
+		return
+	}
+	switch params[1] {
+	case 112:
+		// m fn a bx by c xL xH yL yH d1...dk
+		if len(params) < 10 {
+			return
+		}
+		width := int(params[6]) + int(params[7])*256
+		height := int(params[8]) + int(params[9])*256
+		em.stored = &Raster{Width: width, Height: height, Data: bytes.Clone(params[10:])}
+	case 50:
+		if em.stored == nil {
+			return
+		}
+		em.flushLine()
+		em.elements = append(em.elements, Element{
+			Kind:    KindImage,
+			Justify: em.state.justify,
+			Raster:  *em.stored,
+		})
+		em.stored = nil
+	}
+}
+
+// text adds printable bytes to the current line, decoding them with the active code page
+func (em *Emulator) text(b []byte) {
+	s := string(b)
+	if em.state.kanji && em.state.kanjiSJIS {
+		if decoded, err := japanese.ShiftJIS.NewDecoder().Bytes(b); err == nil {
+			s = string(decoded)
+		}
+	} else if enc, ok := escpos.CodePageEncoding(em.state.codepage); ok {
+		if decoded, err := enc.NewDecoder().Bytes(b); err == nil {
+			s = string(decoded)
+		}
+	}
+
+	style := em.state.style
+	if n := len(em.line); n > 0 && em.line[n-1].Style == style {
+		em.line[n-1].Text += s
+		return
+	}
+	em.line = append(em.line, Span{Text: s, Style: style})
+}
+
+// tab moves to the next default tab stop (every 8 characters)
+func (em *Emulator) tab() {
+	w := Element{Spans: em.line}.width()
+	if em.state.tabs == nil {
+		em.text(bytes.Repeat([]byte{' '}, 8-w%8))
+		return
+	}
+	for _, stop := range em.state.tabs {
+		if stop > w {
+			em.text(bytes.Repeat([]byte{' '}, stop-w))
+			return
+		}
+	}
+	// no tab stop past the current position: HT is ignored
+}
+
+// moveTo pads the current line with spaces up to the column containing the
+// given dot position. Moving backwards is not supported.
+func (em *Emulator) moveTo(dots int) {
+	w := Element{Spans: em.line}.width()
+	if col := dots / dotsPerColumn; col > w {
+		em.text(bytes.Repeat([]byte{' '}, col-w))
+	}
+}
+
+// newline prints the current line, or an empty line when nothing is buffered
+func (em *Emulator) newline() {
+	em.elements = append(em.elements, em.textElement())
+	em.line = nil
+}
+
+// flushLine prints the current line if it contains text
+func (em *Emulator) flushLine() {
+	if len(em.line) > 0 {
+		em.newline()
+	}
+}
+
+// feedLines prints the current line and feeds the paper by n lines in total
+func (em *Emulator) feedLines(n int) {
+	if len(em.line) > 0 {
+		em.newline()
+		n--
+	}
+	for ; n > 0; n-- {
+		em.newline()
+	}
+}
+
+// cut prints the current line and adds a cut to the paper
+func (em *Emulator) cut(partial bool) {
+	em.flushLine()
+	em.elements = append(em.elements, Element{Kind: KindCut, Partial: partial})
+}
+
+// textElement returns the current line as an element
+func (em *Emulator) textElement() Element {
+	return Element{Kind: KindText, Justify: em.state.justify, Spans: em.line}
+}
+
+// Total lengths of the fixed length commands, by command byte
+var (
+	escLengths = map[byte]int{
+		'@': 2, '2': 2, 'L': 2, 'S': 2, 'i': 2, 'm': 2, '<': 2,
+		'E': 3, '-': 3, '{': 3, 'V': 3, 'a': 3, 'M': 3, 't': 3, 'd': 3, '3': 3,
+		'J': 3, 'G': 3, ' ': 3, '!': 3, 'R': 3, 'K': 3, 'e': 3, '%': 3, 'r': 3,
+		'=': 3, 'U': 3, 'T': 3, '?': 3,
+		'$': 4, '\\': 4, 'c': 4, 'B': 4,
+		'p': 5, '7': 5,
+		'W': 10,
+	}
+	gsLengths = map[byte]int{
+		':': 2,
+		'!': 3, 'B': 3, 'H': 3, 'f': 3, 'h': 3, 'w': 3, 'b': 3, 'I': 3, 'r': 3,
+		'a': 3, '/': 3, 'E': 3, 'T': 3,
+		'P': 4, 'L': 4, 'W': 4, '$': 4, '\\': 4,
+	}
+	fsLengths = map[byte]int{
+		'&': 2, '.': 2,
+		'C': 3, '-': 3, 'W': 3, '!': 3,
+		'S': 4, 'p': 4, 'd': 4,
+		'2': 76,
+	}
+)
+
+// available returns n if buf holds at least n bytes, 0 otherwise
+func available(buf []byte, n int) int {
+	if len(buf) < n {
+		return 0
+	}
+	return n
+}
+
+// parenLength returns the length of an "ESC|GS|FS ( X pL pH ..." command
+func parenLength(buf []byte) int {
+	if len(buf) < 5 {
+		return 0
+	}
+	return available(buf, 5+int(buf[3])+int(buf[4])*256)
+}
+
+// userCharactersLength returns the length of "ESC & y c1 c2 [x d1...d(y*x)]..."
+func userCharactersLength(buf []byte) int {
+	if len(buf) < 5 {
+		return 0
+	}
+	y := int(buf[2])
+	n := 5
+	for c := int(buf[3]); c <= int(buf[4]); c++ {
+		if len(buf) <= n {
+			return 0
+		}
+		n += 1 + y*int(buf[n])
+	}
+	return available(buf, n)
+}
+
+// nvImagesLength returns the length of "FS q n [xL xH yL yH d1...dk]1...[...]n"
+func nvImagesLength(buf []byte) int {
+	if len(buf) < 3 {
+		return 0
+	}
+	n := 3
+	for i := 0; i < int(buf[2]); i++ {
+		if len(buf) < n+4 {
+			return 0
+		}
+		x := int(buf[n]) + int(buf[n+1])*256
+		y := int(buf[n+2]) + int(buf[n+3])*256
+		n += 4 + x*y*8
+	}
+	return available(buf, n)
+}
+
+// counterLength returns the length of the GS C counter commands
+func counterLength(buf []byte) int {
+	if len(buf) < 3 {
+		return 0
+	}
+	switch buf[2] {
+	case '0', 0:
+		return available(buf, 5)
+	case '1', 1:
+		return available(buf, 9)
+	case '2', 2:
+		return available(buf, 5)
+	case ';':
+		// GS C ; sa ; sb ; sn ; sr ; sc ;
+		count := 0
+		for i := 2; i < len(buf); i++ {
+			if buf[i] == ';' {
+				count++
+				if count == 6 {
+					return i + 1
+				}
+			}
+		}
+		return 0
+	}
+	return 3
+}
+
+// asciiDigit maps the ASCII digit form of a parameter ('0', '1', ...) to its numeric form
+func asciiDigit(n byte) byte {
+	if n >= '0' && n <= '9' {
+		return n - '0'
+	}
+	return n
+}
+
+// set executes a GS C counter command
+func (c *counter) set(cmd []byte) {
+	switch cmd[2] {
+	case '0', 0:
+		c.digits, c.alignment = cmd[3], cmd[4]
+	case '1', 1:
+		c.from, c.to = int(cmd[3])|int(cmd[4])<<8, int(cmd[5])|int(cmd[6])<<8
+		c.step, c.repeat = int(cmd[7]), int(cmd[8])
+		c.value, c.printed = c.from, 0
+	case '2', 2:
+		c.value, c.printed = int(cmd[3])|int(cmd[4])<<8, 0
+	}
+}
+
+// next returns the formatted value of the counter, then counts
+func (c *counter) next() string {
+	s := strconv.Itoa(c.value)
+	if d := int(c.digits); d > 0 {
+		if len(s) > d {
+			s = s[len(s)-d:]
+		}
+		pad := d - len(s)
+		switch c.alignment {
+		case escpos.CounterRightZeros:
+			s = strings.Repeat("0", pad) + s
+		case escpos.CounterLeft:
+			s += strings.Repeat(" ", pad)
+		default:
+			s = strings.Repeat(" ", pad) + s
+		}
+	}
+
+	c.printed++
+	if c.step == 0 || c.repeat == 0 || c.printed < c.repeat {
+		return s
+	}
+	c.printed = 0
+	if c.from <= c.to {
+		if c.value += c.step; c.value > c.to {
+			c.value = c.from
+		}
+	} else if c.value -= c.step; c.value < c.to {
+		c.value = c.from
+	}
+	return s
+}
@@ -0,0 +1,171 @@
+package emulator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/schawnndev/escpos"
+)
+
+// Kind identifies the type of a printed element
+type Kind uint8
+
+// Element kinds
+const (
+	KindText    Kind = iota // a line of text
+	KindImage               // a raster image
+	KindBarcode             // a 1D barcode
+	KindQRCode              // a QR code
+	KindCut                 // a full or partial cut
+)
+
+// Style is the character formatting active while a span was printed
+type Style struct {
+	Bold         bool
+	DoubleStrike bool
+	Underline    uint8
+	Reverse      bool
+	UpsideDown   bool
+	Rotate       bool
+	Font         uint8
+	Width        uint8 // width multiplier (1-8)
+	Height       uint8 // height multiplier (1-8)
+}
+
+// Span is a run of text printed with a single style
+type Span struct {
+	Text  string
+	Style Style
+}
+
+// Raster is a monochrome image, one bit per dot, MSB first, rows padded to whole bytes
+type Raster struct {
+	Width  int // width in dots
+	Height int // height in dots
+	Data   []byte
+}
+
+// At reports whether the dot at (x, y) is black
+func (r Raster) At(x, y int) bool {
+	if x < 0 || y < 0 || x >= r.Width || y >= r.Height {
+		return false
+	}
+	i := y*((r.Width+7)/8) + x/8
+	if i >= len(r.Data) {
+		return false
+	}
+	return r.Data[i]&(0x80>>(x%8)) != 0
+}
+
+// Element is one item printed on the paper
+type Element struct {
+	Kind    Kind
+	Justify escpos.Justify
+
+	Spans []Span // KindText
+
+	Raster Raster // KindImage
+
+	Symbology uint8  // KindBarcode: the GS k barcode type
+	Data      string // KindBarcode and KindQRCode: the encoded data
+
+	Partial bool // KindCut: true for a partial cut
+}
+
+// PlainText returns the text of a KindText element without styling
+func (el Element) PlainText() string {
+	var sb strings.Builder
+	for _, s := range el.Spans {
+		sb.WriteString(s.Text)
+	}
+	return sb.String()
+}
+
+// width returns the number of Font A columns used by a text element
+func (el Element) width() int {
+	w := 0
+	for _, s := range el.Spans {
+		mult := int(s.Style.Width)
+		if mult < 1 {
+			mult = 1
+		}
+		w += utf8.RuneCountInString(s.Text) * mult
+	}
+	return w
+}
+
+// padding returns the number of spaces needed before a text element to honour its justification
+func (el Element) padding(columns int) int {
+	return justifyOffset(el.Justify, el.width(), columns)
+}
+
+// justifyOffset returns the offset, in columns, of a content of the given width
+func justifyOffset(j escpos.Justify, width int, columns int) int {
+	free := columns - width
+	if free <= 0 {
+		return 0
+	}
+	switch j {
+	case escpos.JustifyCenter:
+		return free / 2
+	case escpos.JustifyRight:
+		return free
+	default:
+		return 0
+	}
+}
+
+// text renders the element as a single plain-text line
+func (el Element) text(columns int) string {
+	switch el.Kind {
+	case KindText:
+		text := el.PlainText()
+		if text == "" {
+			return ""
+		}
+		return strings.Repeat(" ", el.padding(columns)) + text
+	case KindImage:
+		return fmt.Sprintf("[image %dx%d]", el.Raster.Width, el.Raster.Height)
+	case KindBarcode:
+		return fmt.Sprintf("[barcode %s: %s]", symbologyName(el.Symbology), el.Data)
+	case KindQRCode:
+		return fmt.Sprintf("[qr: %s]", el.Data)
+	case KindCut:
+		if el.Partial {
+			return strings.Repeat("- ", columns/2)
+		}
+		return strings.Repeat("-", columns)
+	}
+	return ""
+}
+
+// symbologyName returns a readable name for a GS k barcode type
+func symbologyName(m uint8) string {
+	switch m {
+	case escpos.BarcodeUPCA, escpos.BarcodeBUPCA:
+		return "UPC-A"
+	case escpos.BarcodeUPCE, escpos.BarcodeBUPCE:
+		return "UPC-E"
+	case escpos.BarcodeEAN13, escpos.BarcodeBEAN13:
+		return "EAN13"
+	case escpos.BarcodeEAN8, escpos.BarcodeBEAN8:
+		return "EAN8"
+	case escpos.BarcodeCode39, escpos.BarcodeBCode39:
+		return "CODE39"
+	case escpos.BarcodeITF, escpos.BarcodeBITF:
+		return "ITF"
+	case escpos.BarcodeCodabar, escpos.BarcodeBCodabar:
+		return "CODABAR"
+	case escpos.BarcodeBCode93:
+		return "CODE93"
+	case escpos.BarcodeBCode128, escpos.BarcodeBCode128Auto:
+		return "CODE128"
+	case escpos.BarcodeBGS1128:
+		return "GS1-128"
+	case escpos.BarcodeBGS1DataBarOmni, escpos.BarcodeBGS1DataBarTruncated,
+		escpos.BarcodeBGS1DataBarLimited, escpos.BarcodeBGS1DataBarExpanded:
+		return "GS1 DataBar"
+	}
+	return fmt.Sprintf("type %d", m)
+}
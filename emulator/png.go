@@ -0,0 +1,180 @@
+package emulator
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Size of a Font A character cell in dots
+const (
+	cellWidth  = dotsPerColumn
+	cellHeight = 24
+)
+
+// Height in dots of the mark drawn for a cut
+const cutHeight = 12
+
+// fonts holds the faces used to draw text, loaded on first use
+var fonts struct {
+	once          sync.Once
+	regular, bold font.Face
+	err           error
+}
+
+// loadFonts parses the monospace Go fonts, sized to fill a Font A cell
+func loadFonts() (font.Face, font.Face, error) {
+	fonts.once.Do(func() {
+		newFace := func(ttf []byte) (font.Face, error) {
+			f, err := opentype.Parse(ttf)
+			if err != nil {
+				return nil, err
+			}
+			return opentype.NewFace(f, &opentype.FaceOptions{Size: 20, DPI: 72, Hinting: font.HintingFull})
+		}
+		fonts.regular, fonts.err = newFace(gomono.TTF)
+		if fonts.err == nil {
+			fonts.bold, fonts.err = newFace(gomonobold.TTF)
+		}
+	})
+	return fonts.regular, fonts.bold, fonts.err
+}
+
+// Image renders the paper as a grayscale image, one pixel per printer dot.
+// Text is drawn with the Go Mono font in Font A sized cells, barcodes and QR
+// codes as their text description, and cuts as a solid or dashed line.
+func (em *Emulator) Image() (*image.Gray, error) {
+	regular, bold, err := loadFonts()
+	if err != nil {
+		return nil, err
+	}
+
+	paper := em.Paper()
+	width := em.columns * cellWidth
+
+	height := 0
+	for _, el := range paper {
+		height += elementHeight(el)
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	y := 0
+	for _, el := range paper {
+		switch el.Kind {
+		case KindText:
+			drawSpans(img, el.Spans, el.padding(em.columns)*cellWidth, y, elementHeight(el), regular, bold)
+		case KindImage:
+			x := justifyOffset(el.Justify, el.Raster.Width, width)
+			for ry := 0; ry < el.Raster.Height; ry++ {
+				for rx := 0; rx < el.Raster.Width && x+rx < width; rx++ {
+					if el.Raster.At(rx, ry) {
+						img.SetGray(x+rx, y+ry, color.Gray{})
+					}
+				}
+			}
+		case KindBarcode, KindQRCode:
+			label := el.text(em.columns)
+			spans := []Span{{Text: label, Style: Style{Width: 1, Height: 1}}}
+			x := justifyOffset(el.Justify, len([]rune(label)), em.columns) * cellWidth
+			drawSpans(img, spans, x, y, elementHeight(el), regular, bold)
+		case KindCut:
+			for x := 0; x < width; x++ {
+				if !el.Partial || (x/8)%2 == 0 {
+					img.SetGray(x, y+cutHeight/2, color.Gray{})
+				}
+			}
+		}
+		y += elementHeight(el)
+	}
+
+	return img, nil
+}
+
+// RenderPNG writes the paper rendered by Image to w as a PNG image
+func (em *Emulator) RenderPNG(w io.Writer) error {
+	img, err := em.Image()
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// elementHeight returns the height of an element on the paper in dots
+func elementHeight(el Element) int {
+	switch el.Kind {
+	case KindImage:
+		return el.Raster.Height
+	case KindCut:
+		return cutHeight
+	case KindText:
+		h := 1
+		for _, s := range el.Spans {
+			h = max(h, int(s.Style.Height))
+		}
+		return h * lineHeightDots
+	}
+	return lineHeightDots
+}
+
+// drawSpans draws a line of text starting at (x, y), the text sitting on
+// the bottom of a line height dots high
+func drawSpans(img *image.Gray, spans []Span, x, y, height int, regular, bold font.Face) {
+	for _, s := range spans {
+		face := regular
+		if s.Style.Bold || s.Style.DoubleStrike {
+			face = bold
+		}
+		w, h := max(int(s.Style.Width), 1), max(int(s.Style.Height), 1)
+		top := y + height - h*lineHeightDots + (lineHeightDots-cellHeight)*h/2
+
+		for _, r := range s.Text {
+			cell := glyphCell(face, r, s.Style)
+			for cy := 0; cy < cellHeight*h; cy++ {
+				for cx := 0; cx < cellWidth*w; cx++ {
+					if cell.GrayAt(cx/w, cy/h).Y < 128 {
+						img.SetGray(x+cx, top+cy, color.Gray{})
+					}
+				}
+			}
+			x += cellWidth * w
+		}
+	}
+}
+
+// glyphCell draws a character in a black on white Font A cell, with its underline and reverse styling
+func glyphCell(face font.Face, r rune, style Style) *image.Gray {
+	cell := image.NewGray(image.Rect(0, 0, cellWidth, cellHeight))
+	draw.Draw(cell, cell.Bounds(), image.White, image.Point{}, draw.Src)
+
+	d := font.Drawer{
+		Dst:  cell,
+		Src:  image.Black,
+		Face: face,
+		Dot:  fixed.P(0, cellHeight-5),
+	}
+	d.DrawString(string(r))
+
+	for u := 0; u < int(style.Underline); u++ {
+		for x := 0; x < cellWidth; x++ {
+			cell.SetGray(x, cellHeight-1-u, color.Gray{})
+		}
+	}
+
+	if style.Reverse {
+		for i, v := range cell.Pix {
+			cell.Pix[i] = 255 - v
+		}
+	}
+	return cell
+}
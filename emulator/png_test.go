@@ -0,0 +1,54 @@
+package emulator
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEmulatorImage tests rendering the paper as an image
+func TestEmulatorImage(t *testing.T) {
+	em := New(WithColumns(10))
+	p := escpos.New(em)
+
+	p.Write("Hi\n")
+	p.SetSize(2, 2)
+	p.Write("XL\n")
+	p.SetSize(1, 1)
+	p.WriteRaw([]byte{0x1D, 'v', '0', 0, 1, 0, 2, 0, 0xFF, 0xFF})
+	require.NoError(t, p.PrintAndCut())
+
+	img, err := em.Image()
+	require.NoError(t, err)
+
+	// 10 columns of 12 dots; a line, a double height line, 2 image rows and a cut
+	assert.Equal(t, 120, img.Bounds().Dx())
+	assert.Equal(t, 30+60+2+cutHeight, img.Bounds().Dy())
+
+	// The image rows are black on their 8 first dots
+	assert.Equal(t, uint8(0), img.GrayAt(0, 90).Y)
+	assert.Equal(t, uint8(0), img.GrayAt(7, 91).Y)
+	assert.Equal(t, uint8(255), img.GrayAt(8, 90).Y)
+
+	// Some text was drawn on the first line, the right side stays blank
+	dark := 0
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 120; x++ {
+			if img.GrayAt(x, y).Y < 128 {
+				dark++
+				assert.Less(t, x, 24)
+			}
+		}
+	}
+	assert.Positive(t, dark)
+
+	var buf bytes.Buffer
+	require.NoError(t, em.RenderPNG(&buf))
+	decoded, err := png.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, img.Bounds(), decoded.Bounds())
+}
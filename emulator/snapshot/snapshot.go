@@ -0,0 +1,140 @@
+// Package snapshot compares receipts rendered by the emulator against golden
+// PNG images, catching encoding and layout regressions in tests.
+//
+// Goldens are stored under testdata/snapshots by default. Run the tests with
+// ESCPOS_UPDATE_SNAPSHOTS=1 to create or update them.
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/schawnndev/escpos/emulator"
+)
+
+// UpdateEnv is the environment variable making Match write the goldens instead of comparing them
+const UpdateEnv = "ESCPOS_UPDATE_SNAPSHOTS"
+
+// config holds the options of Match
+type config struct {
+	dir       string
+	tolerance float64
+}
+
+// Option configures Match
+type Option func(*config)
+
+// WithDir sets the directory holding the goldens (default: testdata/snapshots)
+func WithDir(dir string) Option {
+	return func(c *config) {
+		c.dir = dir
+	}
+}
+
+// WithTolerance sets the ratio of pixels allowed to differ from the golden (default: 0.0001)
+func WithTolerance(ratio float64) Option {
+	return func(c *config) {
+		c.tolerance = ratio
+	}
+}
+
+// Match renders the paper of em and compares it with the golden image
+// <dir>/<name>.png, failing t when they differ by more than the tolerance.
+// On failure, the rendered image is written next to the golden as
+// <name>.actual.png.
+func Match(t testing.TB, name string, em *emulator.Emulator, opts ...Option) {
+	t.Helper()
+
+	cfg := config{dir: filepath.Join("testdata", "snapshots"), tolerance: 0.0001}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var buf bytes.Buffer
+	if err := em.RenderPNG(&buf); err != nil {
+		t.Fatalf("failed to render snapshot %s: %v", name, err)
+	}
+
+	golden := filepath.Join(cfg.dir, name+".png")
+	actual := filepath.Join(cfg.dir, name+".actual.png")
+
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(cfg.dir, 0o755); err != nil {
+			t.Fatalf("failed to create snapshot directory: %v", err)
+		}
+		if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+			t.Fatalf("failed to write snapshot %s: %v", name, err)
+		}
+		os.Remove(actual)
+		return
+	}
+
+	want, err := readPNG(golden)
+	if err != nil {
+		t.Fatalf("failed to read snapshot %s (run with %s=1 to create it): %v", name, UpdateEnv, err)
+	}
+	got, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("failed to decode rendered snapshot %s: %v", name, err)
+	}
+
+	if ratio := Diff(got, want); ratio > cfg.tolerance {
+		os.WriteFile(actual, buf.Bytes(), 0o644)
+		t.Errorf("snapshot %s differs from the golden: %.4f%% of the pixels differ (tolerance %.4f%%), rendered image written to %s",
+			name, ratio*100, cfg.tolerance*100, actual)
+		return
+	}
+	os.Remove(actual)
+}
+
+// Diff returns the ratio of pixels differing between two images, comparing
+// their luminance. Images of different sizes differ by the area they do not share.
+func Diff(a, b image.Image) float64 {
+	ab, bb := a.Bounds(), b.Bounds()
+	w, h := max(ab.Dx(), bb.Dx()), max(ab.Dy(), bb.Dy())
+	if w == 0 || h == 0 {
+		return 0
+	}
+
+	differ := 0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			pa := image.Pt(ab.Min.X+x, ab.Min.Y+y)
+			pb := image.Pt(bb.Min.X+x, bb.Min.Y+y)
+			if !pa.In(ab) || !pb.In(bb) {
+				differ++
+				continue
+			}
+			if dark(a, pa) != dark(b, pb) {
+				differ++
+			}
+		}
+	}
+	return float64(differ) / float64(w*h)
+}
+
+// dark reports whether the pixel at p is closer to black than to white
+func dark(img image.Image, p image.Point) bool {
+	r, g, b, _ := img.At(p.X, p.Y).RGBA()
+	return (299*r+587*g+114*b)/1000 < 0x8000
+}
+
+// readPNG decodes a PNG file
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PNG: %w", err)
+	}
+	return img, nil
+}
@@ -0,0 +1,112 @@
+package snapshot
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// failureRecorder records the failures reported by Match instead of failing the test
+type failureRecorder struct {
+	testing.TB
+	failed bool
+}
+
+func (f *failureRecorder) Errorf(format string, args ...any) {
+	f.failed = true
+}
+
+func (f *failureRecorder) Fatalf(format string, args ...any) {
+	f.failed = true
+}
+
+// localizedReceipt prints a short receipt in the given language
+func localizedReceipt(t *testing.T, enc encoding.Encoding, codepage uint8, lines []string) *emulator.Emulator {
+	em := emulator.New(emulator.WithColumns(32))
+	p := escpos.New(em)
+
+	_, err := p.SetEncoding(enc, codepage)
+	require.NoError(t, err)
+
+	p.SetJustify(escpos.JustifyCenter)
+	p.SetBold(true)
+	p.Write(lines[0] + "\n")
+	p.SetBold(false)
+	p.SetJustify(escpos.JustifyLeft)
+	for _, line := range lines[1:] {
+		p.Write(line + "\n")
+	}
+	require.NoError(t, p.PrintAndCut())
+	return em
+}
+
+// TestLocalizedReceipts renders receipts in several code pages and scripts
+func TestLocalizedReceipts(t *testing.T) {
+	receipts := []struct {
+		name     string
+		enc      encoding.Encoding
+		codepage uint8
+		lines    []string
+	}{
+		{"french", charmap.CodePage850, escpos.CodePagePC850, []string{"Crêperie Éloïse", "Crème brûlée      6,50", "Café noisette     2,20"}},
+		{"german", charmap.CodePage858, escpos.CodePagePC858, []string{"Bäckerei Größe", "Brötchen x4    € 1,80", "Süßes Teilchen  € 2,40"}},
+		{"greek", charmap.Windows1253, escpos.CodePageCP1253, []string{"Καφενείο", "Ελληνικός καφές   2,50", "Γλυκό του κουταλιού"}},
+		{"russian", charmap.CodePage866, escpos.CodePagePC866, []string{"Кафе Ёлка", "Борщ             350,00", "Чай с лимоном     90,00"}},
+		{"turkish", charmap.Windows1254, escpos.CodePageCP1254, []string{"Çay Ocağı", "Şekerli çay       15,00", "Lokum (İzmir)     40,00"}},
+	}
+
+	for _, r := range receipts {
+		t.Run(r.name, func(t *testing.T) {
+			em := localizedReceipt(t, r.enc, r.codepage, r.lines)
+			Match(t, r.name, em)
+		})
+	}
+}
+
+// TestMatchDetectsChanges tests that a different receipt fails the comparison
+func TestMatchDetectsChanges(t *testing.T) {
+	dir := t.TempDir()
+	em := localizedReceipt(t, charmap.CodePage850, escpos.CodePagePC850, []string{"Crêperie", "Crème brûlée 6,50"})
+
+	t.Setenv(UpdateEnv, "1")
+	Match(t, "receipt", em, WithDir(dir))
+	t.Setenv(UpdateEnv, "")
+
+	Match(t, "receipt", em, WithDir(dir))
+
+	// A lost accent must be detected with the default tolerance
+	changed := localizedReceipt(t, charmap.CodePage850, escpos.CodePagePC850, []string{"Crêperie", "Crème brulée 6,50"})
+	mock := &failureRecorder{TB: t}
+	Match(mock, "receipt", changed, WithDir(dir))
+	assert.True(t, mock.failed)
+
+	_, err := os.Stat(filepath.Join(dir, "receipt.actual.png"))
+	assert.NoError(t, err)
+
+	// A large tolerance accepts the change
+	mock = &failureRecorder{TB: t}
+	Match(mock, "receipt", changed, WithDir(dir), WithTolerance(0.5))
+	assert.False(t, mock.failed)
+}
+
+// TestDiff tests the pixel difference ratio
+func TestDiff(t *testing.T) {
+	a := image.NewGray(image.Rect(0, 0, 10, 10))
+	b := image.NewGray(image.Rect(0, 0, 10, 10))
+	assert.Equal(t, 0.0, Diff(a, b))
+
+	b.SetGray(0, 0, color.Gray{Y: 255})
+	assert.InDelta(t, 0.01, Diff(a, b), 1e-9)
+
+	c := image.NewGray(image.Rect(0, 0, 10, 20))
+	assert.InDelta(t, 0.5, Diff(a, c), 1e-9)
+}
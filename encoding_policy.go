@@ -0,0 +1,147 @@
+package escpos
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/unicode/norm"
+)
+
+// EncodingPolicy is the handling of the characters missing from the encoding of the printer
+type EncodingPolicy uint8
+
+const (
+	// EncodingReplace prints the replacement character instead (default)
+	EncodingReplace EncodingPolicy = iota
+	// EncodingSkip leaves the characters out
+	EncodingSkip
+	// EncodingTransliterate prints an approximation, such as "e" for "ē" or
+	// "EUR" for "€", and the replacement character when there is none
+	EncodingTransliterate
+	// EncodingFail makes the write fail with an *EncodingError
+	EncodingFail
+)
+
+// EncodingError is returned by the writes of text holding a character
+// missing from the encoding, with the EncodingFail policy
+type EncodingError struct {
+	Rune   rune // the character
+	Offset int  // byte offset of the character in the text
+}
+
+func (e *EncodingError) Error() string {
+	return fmt.Sprintf("character %q at offset %d is not supported by the encoding", e.Rune, e.Offset)
+}
+
+// transliterations holds the approximations of the characters that do not
+// decompose to a base letter
+var transliterations = map[rune]string{
+	'€': "EUR", '£': "GBP", '¥': "JPY", '©': "(C)", '®': "(R)", '™': "TM",
+	'‘': "'", '’': "'", '‚': "'", '“': "\"", '”': "\"", '„': "\"", '«': "<<", '»': ">>",
+	'–': "-", '—': "-", '…': "...", '•': "*", '×': "x", '÷': "/",
+	'ß': "ss", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE", 'ø': "o", 'Ø': "O",
+	'ł': "l", 'Ł': "L", 'đ': "d", 'Đ': "D", 'ı': "i", '\u00a0': " ",
+}
+
+// SetEncodingPolicy sets the handling of the characters missing from the encoding
+func (e *Escpos) SetEncodingPolicy(p EncodingPolicy) {
+	e.encPolicy = p
+}
+
+// SetReplacementCharacter sets the character printed instead of the
+// characters missing from the encoding (0: the replacement byte of the
+// encoding, usually SUB)
+func (e *Escpos) SetReplacementCharacter(r rune) {
+	e.replacement = r
+}
+
+// SetEncodingErrorHandler sets a function called with each character missing
+// from the encoding and the text printed instead ("" when it is skipped,
+// U+FFFD for the replacement byte of the encoding), so data quality issues
+// can be logged
+func (e *Escpos) SetEncodingErrorHandler(fn func(r rune, printed string)) {
+	e.onEncodingError = fn
+}
+
+// encode converts UTF-8 text to enc, applying the encoding policy to the
+// characters missing from it
+func (e *Escpos) encode(data []byte, enc encoding.Encoding) ([]byte, error) {
+	encoder := enc.NewEncoder()
+	if out, err := encoder.Bytes(data); err == nil {
+		return out, nil
+	}
+
+	var out []byte
+	for offset := 0; offset < len(data); {
+		r, size := utf8.DecodeRune(data[offset:])
+		chunk := data[offset : offset+size]
+		offset += size
+
+		if b, err := encoder.Bytes(chunk); err == nil {
+			out = append(out, b...)
+			continue
+		}
+
+		var printed string
+		switch e.encPolicy {
+		case EncodingFail:
+			return nil, &EncodingError{Rune: r, Offset: offset - size}
+		case EncodingSkip:
+		case EncodingTransliterate:
+			if t, ok := transliterate(r); ok {
+				if b, err := encoder.Bytes([]byte(t)); err == nil {
+					printed = t
+					out = append(out, b...)
+					break
+				}
+			}
+			fallthrough
+		default:
+			b, s, err := e.replacementBytes(encoder, chunk)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode data: %w", err)
+			}
+			printed = s
+			out = append(out, b...)
+		}
+
+		e.warn(WarningReplaced, "character %q at offset %d printed as %q", r, offset-size, printed)
+		if e.onEncodingError != nil {
+			e.onEncodingError(r, printed)
+		}
+	}
+	return out, nil
+}
+
+// replacementBytes returns the encoded replacement of a character and its text
+func (e *Escpos) replacementBytes(encoder *encoding.Encoder, chunk []byte) ([]byte, string, error) {
+	if e.replacement != 0 {
+		if b, err := encoder.Bytes([]byte(string(e.replacement))); err == nil {
+			return b, string(e.replacement), nil
+		}
+	}
+	b, err := encoding.ReplaceUnsupported(encoder).Bytes(chunk)
+	return b, string(utf8.RuneError), err
+}
+
+// transliterate returns an approximation of r made of other characters
+func transliterate(r rune) (string, bool) {
+	if t, ok := transliterations[r]; ok {
+		return t, true
+	}
+
+	// Strip the accents of the decomposed character
+	var b strings.Builder
+	for _, c := range norm.NFD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, c) {
+			b.WriteRune(c)
+		}
+	}
+	if t := b.String(); t != "" && t != string(r) {
+		return t, true
+	}
+	return "", false
+}
@@ -0,0 +1,72 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TestEncodingPolicyReplace tests the default policy and the replacement character
+func TestEncodingPolicyReplace(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.WriteRawWithEncoding([]byte("a→b"), charmap.CodePage850)
+	require.NoError(t, err)
+
+	p.SetReplacementCharacter('?')
+	_, err = p.WriteRawWithEncoding([]byte("a→b"), charmap.CodePage850)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	assert.Equal(t, []byte{'a', 0x1A, 'b', 'a', '?', 'b'}, mock.Bytes())
+}
+
+// TestEncodingPolicies tests skipping, transliterating and failing on unsupported characters
+func TestEncodingPolicies(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetReplacementCharacter('?')
+
+	type replaced struct {
+		r       rune
+		printed string
+	}
+	var report []replaced
+	p.SetEncodingErrorHandler(func(r rune, printed string) {
+		report = append(report, replaced{r, printed})
+	})
+
+	p.SetEncodingPolicy(EncodingSkip)
+	_, err := p.WriteRawWithEncoding([]byte("a→b"), charmap.CodePage437)
+	require.NoError(t, err)
+
+	// PC437 has no €, ł, ő or arrow
+	p.SetEncodingPolicy(EncodingTransliterate)
+	_, err = p.WriteRawWithEncoding([]byte("5€ łő→"), charmap.CodePage437)
+	require.NoError(t, err)
+
+	p.SetEncodingPolicy(EncodingFail)
+	_, err = p.WriteRawWithEncoding([]byte("ok→"), charmap.CodePage437)
+	var encErr *EncodingError
+	require.ErrorAs(t, err, &encErr)
+	assert.Equal(t, '→', encErr.Rune)
+	assert.Equal(t, 2, encErr.Offset)
+
+	require.NoError(t, p.Print())
+	assert.Equal(t, []byte("ab5EUR lo?"), mock.Bytes())
+	assert.Equal(t, []replaced{{'→', ""}, {'€', "EUR"}, {'ł', "l"}, {'ő', "o"}, {'→', "?"}}, report)
+}
+
+// TestTransliterate tests the approximations of characters
+func TestTransliterate(t *testing.T) {
+	for r, expected := range map[rune]string{'é': "e", 'Ñ': "N", 'ß': "ss", '…': "..."} {
+		got, ok := transliterate(r)
+		assert.True(t, ok, string(r))
+		assert.Equal(t, expected, got)
+	}
+	_, ok := transliterate('→')
+	assert.False(t, ok)
+}
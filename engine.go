@@ -0,0 +1,181 @@
+package escpos
+
+import (
+	"context"
+	"image"
+	"io"
+	"time"
+
+	"golang.org/x/text/encoding"
+)
+
+// Engine is the set of printing operations implemented by Escpos.
+//
+// Applications can depend on Engine instead of *Escpos so the printing layer
+// can be replaced by a mock in their own unit tests, without asserting on the
+// raw ESC/POS bytes. Escpos is the default implementation.
+type Engine interface {
+	// Sending data
+	Print() error
+	PrintAndCut() error
+	WriteRaw(data []byte) (int, error)
+	PrintDocument(d *Document) (int, error)
+	BeginJob() *Job
+	PrintJSON(doc []byte) (int, error)
+	PrintMarkdown(src string) (int, error)
+	PrintReceiptLine(src string) (int, error)
+
+	// Text
+	Write(data string) (int, error)
+	WriteSafe(data string) (int, error)
+	WriteStyled(text string, s Style) (int, error)
+	WriteGBK(data string) (int, error)
+	WriteWEU(data string) (int, error)
+	WriteWithEncoding(data string, enc encoding.Encoding, codepage uint8) (int, error)
+	WriteRawWithEncoding(data []byte, enc encoding.Encoding) (int, error)
+	WriteSJIS(data string) (int, error)
+	WriteEUCKR(data string) (int, error)
+	WriteBig5(data string) (int, error)
+	WriteBilingual(left, right string) (int, error)
+	WriteArabic(text string) (int, error)
+	WriteThai(text string) (int, error)
+	WriteUTF8(text string) (int, error)
+	SetPaperWidth(mm int) error
+	SetCharsPerLine(n int) error
+	PrintWidth() int
+	CharsPerLine() int
+	WriteTable(t *Table) (int, error)
+	WriteKV(left, right string) (int, error)
+	Divider(c rune) (int, error)
+	DoubleDivider() (int, error)
+	TearHere() (int, error)
+	WriteBoxed(text string, style BoxStyle) (int, error)
+	SetEncoding(enc encoding.Encoding, codepage uint8) (int, error)
+	SetCodePage(codepage uint8) (int, error)
+	SetInternationalCharset(charset uint8) (int, error)
+	SetCounterFormat(digits, alignment uint8) (int, error)
+	SetCounterRange(from, to uint16, step, repeat uint8) (int, error)
+	SetCounterValue(value uint16) (int, error)
+	PrintCounter() (int, error)
+	SetEncodingPolicy(p EncodingPolicy)
+	SetReplacementCharacter(r rune)
+	SetEncodingErrorHandler(fn func(r rune, printed string))
+	SetNewlinePolicy(p NewlinePolicy)
+	SetKanjiMode(on bool) (int, error)
+	SetKanjiCodeSystem(code uint8) (int, error)
+	SetKanjiUnderline(u uint8) (int, error)
+	SetKanjiSpacing(left, right uint8) (int, error)
+	DefineUserCharacters(from, to byte, glyphs [][]byte) (int, error)
+	EnableUserCharacters(b bool) (int, error)
+
+	// Styling
+	ApplyStyle(s Style) (int, error)
+	ResetStyle() (int, error)
+	WithStyle(s Style, fn func() error) error
+	SetSize(height, width uint8) (int, error)
+	SetJustify(j Justify) (int, error)
+	SetBold(b bool) (int, error)
+	SetUnderline(u uint8) (int, error)
+	SetUpsideDown(u bool) (int, error)
+	SetRotate(r bool) (int, error)
+	SetReverse(r bool) (int, error)
+	SetFont(f uint8) (int, error)
+	SetSmoothing(b bool) (int, error)
+	SetCharacterSpacing(n uint8) (int, error)
+	SetDoubleStrike(b bool) (int, error)
+	SetLargePrint(on bool) (int, error)
+
+	// Positioning
+	SetAbsolutePosition(dots uint16) (int, error)
+	SetRelativePosition(dots int16) (int, error)
+	SetTabPositions(positions []uint8) (int, error)
+	Tab() (int, error)
+
+	// Barcodes and QR codes
+	SetHRIPosition(p uint8) (int, error)
+	SetHRIFont(p bool) (int, error)
+	SetBarcodeHeight(p uint8) (int, error)
+	SetBarcodeWidth(p uint8) (int, error)
+	UPCA(code string) (int, error)
+	UPCE(code string) (int, error)
+	EAN13(code string) (int, error)
+	EAN8(code string) (int, error)
+	CODE39(code string) (int, error)
+	ITF(code string) (int, error)
+	CODABAR(code string) (int, error)
+	Barcode(barcodeType uint8, code string) (int, error)
+	BarcodeB(symbology uint8, data []byte) (int, error)
+	BarcodeAsImage(symbology uint8, code string, widthDots, heightDots int) (int, error)
+	QRCode(code string, model uint8, size uint8, correctionLevel uint8) (int, error)
+	QRCodeBytes(data []byte, model uint8, size uint8, correctionLevel uint8) (int, error)
+	QRCodeAsImage(data string, sizeDots int, ecLevel uint8) (int, error)
+	QRCodeAdvanced(data []byte, opts QRCodeOptions) (int, error)
+	QRCodeSymbolSize() (QRCodeSymbol, error)
+
+	// Images
+	PrintImageWithProcessing(image image.Image, processMethod uint8, highDensityVertical bool, highDensityHorizontal bool) (int, error)
+	PrintImageWithOptions(img image.Image, opts ImageOptions) (int, error)
+	PrintImageGrayscale(img image.Image, mode GrayscaleMode) (int, error)
+	PrintImageStream(img image.Image) (int, error)
+	PrintImageFile(path string, opts ImageOptions) (int, error)
+	PrintImageReader(r io.Reader, opts ImageOptions) (int, error)
+	PrintImageURL(ctx context.Context, url string, opts ImageOptions) (int, error)
+	PrintNVBitImage(p uint8, mode uint8) (int, error)
+	StoreNVImage(index uint8, img image.Image) (int, error)
+	PrintNVImage(index uint8) (int, error)
+	DeleteNVImage(index uint8) (int, error)
+	NVImageCapacity() (int, error)
+
+	// Paper handling
+	LineFeed() (int, error)
+	LineFeedN(p uint8) (int, error)
+	FeedDots(n uint8) (int, error)
+	ReverseFeedDots(n uint8) (int, error)
+	ReverseFeedLines(n uint8) (int, error)
+	SetDefaultLineSpacing() (int, error)
+	SetLineSpacing(p uint8) (int, error)
+	SetMotionUnits(x, y uint8) (int, error)
+	SetPrintDensity(level int) (int, error)
+	SetPrintSpeed(level int) (int, error)
+	Cut() (int, error)
+	PartialCut() (int, error)
+	CutWithFeed(feedDots uint8) (int, error)
+	PartialCutWithFeed(feedDots uint8) (int, error)
+	FeedToCutPosition() (int, error)
+	TearLine() (int, error)
+
+	// Device control and status
+	Initialize() (int, error)
+	OpenDrawer(pin uint8, time uint8) (int, error)
+	OpenDrawerRealtime(pin uint8) (int, error)
+	RecoverAndRestart() (int, error)
+	RecoverAndCancel() (int, error)
+	ClearBufferRealtime() (int, error)
+	EnablePanelButtons(b bool) (int, error)
+	SetPanelLight(state LightState) (int, error)
+	SelectPaperSensorsToStop(mask uint8) (int, error)
+	SelectPaperEndSensors(mask uint8) (int, error)
+	QueryStatus(statusType byte) ([]byte, error)
+	IsOnline() (bool, error)
+	PaperStatus() (int, error)
+	FullStatus() (PrinterStatus, error)
+	GetPrinterID() (PrinterInfo, error)
+	SetClock(t time.Time) (int, error)
+	ReadClock() (time.Time, error)
+	Diagnostics() Diagnostics
+	PrintDiagnostics() (int, error)
+	PrintHeadTestPattern() (int, error)
+
+	// Warnings
+	Warnings() []Warning
+	ClearWarnings()
+
+	// Sticky errors
+	SetStickyErrors(on bool)
+	Err() error
+
+	// High-level helpers
+	PrintItemLabels(orderNumber string, items []LineItem) error
+}
+
+var _ Engine = (*Escpos)(nil)
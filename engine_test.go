@@ -0,0 +1,46 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingEngine is an Engine mock recording the text written, as an application would write it
+type recordingEngine struct {
+	Engine
+	written []string
+	cuts    int
+}
+
+func (r *recordingEngine) Write(data string) (int, error) {
+	r.written = append(r.written, data)
+	return len(data), nil
+}
+
+func (r *recordingEngine) PrintAndCut() error {
+	r.cuts++
+	return nil
+}
+
+// printGreeting is application code depending on Engine only
+func printGreeting(e Engine, name string) error {
+	if _, err := e.Write("Hello " + name + "\n"); err != nil {
+		return err
+	}
+	return e.PrintAndCut()
+}
+
+// TestEngineMock tests that application code can run against both a mock and Escpos
+func TestEngineMock(t *testing.T) {
+	rec := &recordingEngine{}
+	err := printGreeting(rec, "Gopher")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Hello Gopher\n"}, rec.written)
+	assert.Equal(t, 1, rec.cuts)
+
+	mock := NewMockPrinter()
+	err = printGreeting(New(mock), "Gopher")
+	assert.NoError(t, err)
+	assert.Equal(t, append([]byte("Hello Gopher\n"), gs, 'V', 'A', 0x00), mock.Bytes())
+}
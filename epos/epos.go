@@ -0,0 +1,234 @@
+// Package epos renders print jobs to Epson ePOS-Print XML, the format of the
+// ePOS web service of the TM-Intelligent and network Epson printers, so a
+// receipt built with the receipt package can be sent either as ESC/POS to
+// port 9100 or as XML to the printer's HTTP endpoint.
+package epos
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/schawnndev/escpos"
+)
+
+// Namespace of the ePOS-Print XML schema
+const Namespace = "http://www.epson-pos.com/schemas/2011/03/epos-print"
+
+// Default layout, of an 80mm printer
+const (
+	defaultCharsPerLine = 48
+	defaultPrintWidth   = 576
+)
+
+// barcodeTypes maps the GS k barcode types to their ePOS names
+var barcodeTypes = map[uint8]string{
+	escpos.BarcodeUPCA:    "upc_a",
+	escpos.BarcodeUPCE:    "upc_e",
+	escpos.BarcodeEAN13:   "ean13",
+	escpos.BarcodeEAN8:    "ean8",
+	escpos.BarcodeCode39:  "code39",
+	escpos.BarcodeITF:     "itf",
+	escpos.BarcodeCodabar: "codabar",
+}
+
+// qrLevels maps the QR code error correction levels to their ePOS names
+var qrLevels = map[uint8]string{
+	escpos.QRCodeErrorCorrectionLevelL: "level_l",
+	escpos.QRCodeErrorCorrectionLevelM: "level_m",
+	escpos.QRCodeErrorCorrectionLevelQ: "level_q",
+	escpos.QRCodeErrorCorrectionLevelH: "level_h",
+}
+
+// Document is an ePOS-Print XML print job. It has the methods of Escpos
+// used by the receipt package, so a receipt renders to it unchanged.
+type Document struct {
+	// Style is the current text style
+	Style escpos.Style
+
+	buf          bytes.Buffer
+	charsPerLine int
+	printWidth   int
+}
+
+// NewDocument creates an empty print job for an 80mm printer
+func NewDocument() *Document {
+	return &Document{charsPerLine: defaultCharsPerLine, printWidth: defaultPrintWidth}
+}
+
+// SetCharsPerLine sets the number of characters of normal size on a line, for the layouts
+func (d *Document) SetCharsPerLine(n int) {
+	d.charsPerLine = n
+}
+
+// SetPrintWidth sets the printable width in dots, for the image scaling
+func (d *Document) SetPrintWidth(dots int) {
+	d.printWidth = dots
+}
+
+// CharsPerLine returns the number of characters of the current size on a line
+func (d *Document) CharsPerLine() int {
+	return d.charsPerLine / int(max(d.Style.Width, 1))
+}
+
+// PrintWidth returns the printable width in dots
+func (d *Document) PrintWidth() int {
+	return d.printWidth
+}
+
+// element appends an element with its attributes and text content, an
+// empty element when content is ""
+func (d *Document) element(name string, attrs []string, content string) (int, error) {
+	start := d.buf.Len()
+	d.buf.WriteString("<" + name)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		d.buf.WriteString(" " + attrs[i] + `="`)
+		if err := xml.EscapeText(&d.buf, []byte(attrs[i+1])); err != nil {
+			return d.buf.Len() - start, err
+		}
+		d.buf.WriteString(`"`)
+	}
+	if content == "" {
+		d.buf.WriteString("/>")
+		return d.buf.Len() - start, nil
+	}
+	d.buf.WriteString(">")
+	if err := xml.EscapeText(&d.buf, []byte(content)); err != nil {
+		return d.buf.Len() - start, err
+	}
+	d.buf.WriteString("</" + name + ">")
+	return d.buf.Len() - start, nil
+}
+
+// ApplyStyle sets the text style of the following text
+func (d *Document) ApplyStyle(s escpos.Style) (int, error) {
+	d.Style = s
+	align := [...]string{"left", "center", "right"}[min(s.Justify, escpos.JustifyRight)]
+	return d.element("text", []string{
+		"align", align,
+		"width", fmt.Sprint(min(max(s.Width, 1), 8)),
+		"height", fmt.Sprint(min(max(s.Height, 1), 8)),
+		"em", fmt.Sprint(s.Bold),
+		"ul", fmt.Sprint(s.Underline > 0),
+		"reverse", fmt.Sprint(s.Reverse),
+		"rotate", fmt.Sprint(s.Rotate),
+	}, "")
+}
+
+// WithStyle applies the style s, calls fn, then restores the current style
+func (d *Document) WithStyle(s escpos.Style, fn func() error) error {
+	prev := d.Style
+	if _, err := d.ApplyStyle(s); err != nil {
+		return err
+	}
+	err := fn()
+	if _, rerr := d.ApplyStyle(prev); rerr != nil && err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// Write prints text
+func (d *Document) Write(text string) (int, error) {
+	if text == "" {
+		return 0, nil
+	}
+	return d.element("text", nil, text)
+}
+
+// WriteKV prints left flush left and right flush right on one line
+func (d *Document) WriteKV(left, right string) (int, error) {
+	return d.Write(escpos.LayoutKV(left, right, d.CharsPerLine()))
+}
+
+// WriteTable prints the rows of a table
+func (d *Document) WriteTable(t *escpos.Table) (int, error) {
+	lines, err := t.Lines(d.CharsPerLine())
+	if err != nil {
+		return 0, err
+	}
+	return d.Write(strings.Join(lines, ""))
+}
+
+// Divider prints a line of c across the paper
+func (d *Document) Divider(c rune) (int, error) {
+	return d.Write(strings.Repeat(string(c), d.CharsPerLine()) + "\n")
+}
+
+// Barcode prints a barcode with its human readable text below
+func (d *Document) Barcode(barcodeType uint8, code string) (int, error) {
+	name, ok := barcodeTypes[barcodeType]
+	if !ok {
+		return 0, fmt.Errorf("barcode type %d is not supported by ePOS-Print", barcodeType)
+	}
+	return d.element("barcode", []string{"type", name, "hri", "below", "width", "3", "height", "162"}, code)
+}
+
+// QRCode prints a QR code
+func (d *Document) QRCode(code string, model uint8, size uint8, correctionLevel uint8) (int, error) {
+	level, ok := qrLevels[correctionLevel]
+	if !ok {
+		level = "level_m"
+	}
+	symbol := "qrcode_model_2"
+	if model == escpos.QRCodeModel1 {
+		symbol = "qrcode_model_1"
+	}
+	return d.element("symbol", []string{"type", symbol, "level", level, "width", fmt.Sprint(min(max(size, 1), 16))}, code)
+}
+
+// PrintImageWithProcessing prints an image, dithered to black and white
+// whatever the processing method
+func (d *Document) PrintImageWithProcessing(img image.Image, processMethod uint8, highDensityVertical bool, highDensityHorizontal bool) (int, error) {
+	cmd, err := escpos.PrepareImageForPrinting(img, true, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert image: %w", err)
+	}
+	// GS v 0 m xL xH yL yH, then the rows
+	width := (int(cmd[4]) | int(cmd[5])<<8) * 8
+	height := int(cmd[6]) | int(cmd[7])<<8
+	return d.element("image", []string{
+		"width", fmt.Sprint(width),
+		"height", fmt.Sprint(height),
+		"color", "color_1",
+		"mode", "mono",
+	}, base64.StdEncoding.EncodeToString(cmd[8:]))
+}
+
+// Feed feeds the paper by n lines
+func (d *Document) Feed(n int) (int, error) {
+	return d.element("feed", []string{"line", fmt.Sprint(n)}, "")
+}
+
+// Cut feeds the paper to the cutter and cuts it
+func (d *Document) Cut() (int, error) {
+	return d.element("cut", []string{"type", "feed"}, "")
+}
+
+// OpenDrawer sends a pulse to the drawer connected to pin 0 or 1
+func (d *Document) OpenDrawer(pin uint8) (int, error) {
+	drawer := "drawer_1"
+	if pin == 1 {
+		drawer = "drawer_2"
+	}
+	return d.element("pulse", []string{"drawer", drawer, "time", "pulse_100"}, "")
+}
+
+// Bytes returns the epos-print element of the job
+func (d *Document) Bytes() []byte {
+	out := []byte(`<epos-print xmlns="` + Namespace + `">`)
+	out = append(out, d.buf.Bytes()...)
+	return append(out, "</epos-print>"...)
+}
+
+// Envelope returns the job in the SOAP envelope posted to the ePOS web
+// service, http://<printer>/cgi-bin/epos/service.cgi?devid=local_printer
+func (d *Document) Envelope() []byte {
+	out := []byte(`<?xml version="1.0" encoding="utf-8"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`)
+	out = append(out, d.Bytes()...)
+	return append(out, "</s:Body></s:Envelope>"...)
+}
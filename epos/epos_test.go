@@ -0,0 +1,66 @@
+package epos
+
+import (
+	"encoding/xml"
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/receipt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ receipt.Target = (*Document)(nil)
+
+// TestReceipt tests a receipt rendered to ePOS-Print XML
+func TestReceipt(t *testing.T) {
+	d := NewDocument()
+	d.SetCharsPerLine(20)
+
+	r := receipt.New(
+		receipt.Line{Text: "Café & Co", Style: escpos.Style{Bold: true}},
+		receipt.KV{Key: "TOTAL", Value: "9.90"},
+		receipt.QR{Data: "https://example.com"},
+	)
+	require.NoError(t, r.Render(d))
+	_, err := d.Cut()
+	require.NoError(t, err)
+
+	style := func(em bool, align string) string {
+		return `<text align="` + align + `" width="1" height="1" em="` + map[bool]string{true: "true", false: "false"}[em] +
+			`" ul="false" reverse="false" rotate="false"/>`
+	}
+	expected := `<epos-print xmlns="http://www.epson-pos.com/schemas/2011/03/epos-print">` +
+		style(true, "left") + `<text>Café &amp; Co&#xA;</text>` + style(false, "left") +
+		style(false, "left") + `<text>TOTAL           9.90&#xA;</text>` + style(false, "left") +
+		style(false, "center") + `<symbol type="qrcode_model_2" level="level_m" width="6">https://example.com</symbol>` + style(false, "left") +
+		`<cut type="feed"/>` +
+		`</epos-print>`
+	assert.Equal(t, expected, string(d.Bytes()))
+
+	var doc struct{}
+	assert.NoError(t, xml.Unmarshal(d.Envelope(), &doc))
+}
+
+// TestCodes tests the barcodes, images and drawer pulses
+func TestCodes(t *testing.T) {
+	d := NewDocument()
+	_, err := d.Barcode(escpos.BarcodeEAN13, "4006381333931")
+	require.NoError(t, err)
+	_, err = d.Barcode(escpos.BarcodeBCode128, "x")
+	assert.Error(t, err)
+	_, err = d.PrintImageWithProcessing(image.NewGray(image.Rect(0, 0, 16, 1)), escpos.ImageProcessDither, true, true)
+	require.NoError(t, err)
+	_, err = d.OpenDrawer(1)
+	require.NoError(t, err)
+	_, err = d.Feed(2)
+	require.NoError(t, err)
+
+	body := strings.TrimSuffix(strings.TrimPrefix(string(d.Bytes()), `<epos-print xmlns="`+Namespace+`">`), "</epos-print>")
+	assert.Equal(t, `<barcode type="ean13" hri="below" width="3" height="162">4006381333931</barcode>`+
+		`<image width="16" height="1" color="color_1" mode="mono">//8=</image>`+
+		`<pulse drawer="drawer_2" time="pulse_100"/>`+
+		`<feed line="2"/>`, body)
+}
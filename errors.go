@@ -0,0 +1,47 @@
+package escpos
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by the printer operations, to be tested with errors.Is
+var (
+	// ErrFeatureDisabled is returned by the commands of a feature disabled
+	// with SetConfig, such as SetBold with DisableBold
+	ErrFeatureDisabled = errors.New("disabled in the printer configuration")
+	// ErrOffline is returned when the printer reports itself offline
+	ErrOffline = errors.New("printer is offline")
+	// ErrPaperOut is returned when the printer reports that it has no paper
+	ErrPaperOut = errors.New("printer is out of paper")
+	// ErrTimeout is returned when the printer does not answer in time
+	ErrTimeout = errors.New("printer did not respond in time")
+)
+
+// ErrInvalidBarcode is returned for the barcodes that cannot be printed, to be
+// tested with errors.As
+type ErrInvalidBarcode struct {
+	Type   uint8  // barcode type, one of the Barcode* constants
+	Reason string // such as "EAN-13 code can only contain digits"
+}
+
+func (e *ErrInvalidBarcode) Error() string {
+	return e.Reason
+}
+
+// invalidBarcode returns an ErrInvalidBarcode with a formatted reason
+func invalidBarcode(barcodeType uint8, format string, args ...any) error {
+	return &ErrInvalidBarcode{Type: barcodeType, Reason: fmt.Sprintf(format, args...)}
+}
+
+// Err returns ErrOffline or ErrPaperOut, with the details of the status, when
+// the printer cannot print, nil otherwise
+func (s PrinterStatus) Err() error {
+	switch {
+	case s.PaperOut || s.PaperEndStop:
+		return fmt.Errorf("%w (%s)", ErrPaperOut, s)
+	case !s.Online:
+		return fmt.Errorf("%w (%s)", ErrOffline, s)
+	}
+	return nil
+}
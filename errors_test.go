@@ -0,0 +1,66 @@
+package escpos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestErrFeatureDisabled tests the errors of the disabled features
+func TestErrFeatureDisabled(t *testing.T) {
+	p := New(NewMockPrinter())
+	p.SetConfig(PrinterConfig{DisableBold: true, DisableJustify: true})
+
+	_, err := p.SetBold(true)
+	assert.ErrorIs(t, err, ErrFeatureDisabled)
+	assert.EqualError(t, err, "bold mode is disabled in the printer configuration")
+
+	_, err = p.WriteStyled("text", Style{Justify: JustifyCenter})
+	assert.ErrorIs(t, err, ErrFeatureDisabled)
+}
+
+// TestErrInvalidBarcode tests the errors of the invalid barcodes
+func TestErrInvalidBarcode(t *testing.T) {
+	p := New(NewMockPrinter())
+
+	tests := []struct {
+		barcodeType uint8
+		code        string
+		reason      string
+	}{
+		{BarcodeEAN13, "12345678901X", "EAN-13 code can only contain digits"},
+		{BarcodeITF, "123", "ITF code must have an even number of digits (at least 2)"},
+		{BarcodeCode39, "abc", "CODE39 code contains an invalid character 'a' at position 0 (allowed: A-Z, 0-9, space and -.$/+%)"},
+		{BarcodeCode39, "*AB#*", "CODE39 code contains an invalid character '#' at position 3 (allowed: A-Z, 0-9, space and -.$/+%)"},
+		{BarcodeCodabar, "A12", "CODABAR code must start and end with a start/stop character (A-D)"},
+		{42, "123", "invalid barcode type: 42"},
+	}
+
+	for _, tt := range tests {
+		_, err := p.Barcode(tt.barcodeType, tt.code)
+		var invalid *ErrInvalidBarcode
+		require.ErrorAs(t, err, &invalid, "code %q", tt.code)
+		assert.Equal(t, tt.barcodeType, invalid.Type)
+		assert.Equal(t, tt.reason, invalid.Reason)
+	}
+
+	_, err := p.BarcodeB(BarcodeBCode128, nil)
+	var invalid *ErrInvalidBarcode
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, BarcodeBCode128, invalid.Type)
+}
+
+// TestPrinterStatusErr tests the errors of the printer states
+func TestPrinterStatusErr(t *testing.T) {
+	assert.NoError(t, PrinterStatus{Online: true, PaperNearEnd: true}.Err())
+
+	err := PrinterStatus{Online: false, CoverOpen: true}.Err()
+	assert.ErrorIs(t, err, ErrOffline)
+	assert.EqualError(t, err, "printer is offline (offline, cover open)")
+
+	err = PrinterStatus{Online: false, PaperEndStop: true, PaperOut: true}.Err()
+	assert.ErrorIs(t, err, ErrPaperOut)
+	assert.False(t, errors.Is(err, ErrOffline))
+}
@@ -0,0 +1,212 @@
+// Package escpostest provides a mock printer for testing code printing with
+// escpos: it records the stream, answers status requests with scripted
+// replies and fails on demand, and helpers assert the commands it received.
+package escpostest
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+// MockPrinter implements the escpos Printer interface, recording every write.
+// It is safe for concurrent use.
+type MockPrinter struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	writes [][]byte
+	closed bool
+
+	status  []byte
+	replies [][]byte
+
+	writeErr   error
+	writeLimit int // bytes accepted before writeErr
+	readErr    error
+	closeErr   error
+}
+
+// NewMockPrinter creates a mock printer accepting every write and answering
+// reads with no data
+func NewMockPrinter() *MockPrinter {
+	return &MockPrinter{}
+}
+
+// Write records p, or fails with the error set with FailWrites or FailAfter
+func (m *MockPrinter) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.writeErr != nil {
+		n := min(len(p), m.writeLimit)
+		m.record(p[:n])
+		m.writeLimit -= n
+		if n < len(p) {
+			return n, m.writeErr
+		}
+		return n, nil
+	}
+	m.record(p)
+	return len(p), nil
+}
+
+// record appends data to the stream
+func (m *MockPrinter) record(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	m.buf.Write(p)
+	m.writes = append(m.writes, bytes.Clone(p))
+}
+
+// Read answers with the next reply queued with QueueStatus, then with the
+// status set with SetStatus
+func (m *MockPrinter) Read(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.readErr != nil {
+		return 0, m.readErr
+	}
+	if len(m.replies) > 0 {
+		n := copy(p, m.replies[0])
+		m.replies = m.replies[1:]
+		return n, nil
+	}
+	return copy(p, m.status), nil
+}
+
+// Close marks the printer closed and returns the error set with FailClose
+func (m *MockPrinter) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return m.closeErr
+}
+
+// Closed reports whether Close was called
+func (m *MockPrinter) Closed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// SetStatus sets the bytes answered by every read once the queued replies are
+// consumed (empty: no data)
+func (m *MockPrinter) SetStatus(status []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status = bytes.Clone(status)
+}
+
+// QueueStatus queues replies answered by the next reads, one per read, such
+// as the successive status bytes of a printer running out of paper
+func (m *MockPrinter) QueueStatus(replies ...[]byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range replies {
+		m.replies = append(m.replies, bytes.Clone(r))
+	}
+}
+
+// FailWrites makes every later write fail with err (nil: accept writes again)
+func (m *MockPrinter) FailWrites(err error) {
+	m.FailAfter(0, err)
+}
+
+// FailAfter accepts n more bytes, then fails the writes with err (nil: no
+// failure). The write crossing the limit is recorded up to it and returns
+// the bytes accepted.
+func (m *MockPrinter) FailAfter(n int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writeErr, m.writeLimit = err, n
+}
+
+// FailReads makes every later read fail with err (nil: answer reads again)
+func (m *MockPrinter) FailReads(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.readErr = err
+}
+
+// FailClose makes Close return err
+func (m *MockPrinter) FailClose(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closeErr = err
+}
+
+// Bytes returns a copy of the bytes written so far
+func (m *MockPrinter) Bytes() []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return bytes.Clone(m.buf.Bytes())
+}
+
+// String returns the bytes written so far as a string
+func (m *MockPrinter) String() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.buf.String()
+}
+
+// Writes returns the data of each write, in order
+func (m *MockPrinter) Writes() [][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([][]byte(nil), m.writes...)
+}
+
+// Reset discards the bytes written so far
+func (m *MockPrinter) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.buf.Reset()
+	m.writes = nil
+}
+
+// ContainsCommand reports whether the stream holds the command bytes
+func (m *MockPrinter) ContainsCommand(cmd []byte) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return bytes.Contains(m.buf.Bytes(), cmd)
+}
+
+// CountCommand returns the number of occurrences of the command bytes in the stream
+func (m *MockPrinter) CountCommand(cmd []byte) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return bytes.Count(m.buf.Bytes(), cmd)
+}
+
+// AssertContainsCommand fails the test if the printer did not receive the
+// command bytes, such as []byte{0x1B, 'E', 1} for bold on
+func AssertContainsCommand(t testing.TB, m *MockPrinter, cmd []byte) bool {
+	t.Helper()
+	if !m.ContainsCommand(cmd) {
+		t.Errorf("command % X not found in the stream:\n% X", cmd, m.Bytes())
+		return false
+	}
+	return true
+}
+
+// AssertNotContainsCommand fails the test if the printer received the command bytes
+func AssertNotContainsCommand(t testing.TB, m *MockPrinter, cmd []byte) bool {
+	t.Helper()
+	if m.ContainsCommand(cmd) {
+		t.Errorf("unexpected command % X found in the stream:\n% X", cmd, m.Bytes())
+		return false
+	}
+	return true
+}
+
+// AssertWritten fails the test if the stream is not exactly data
+func AssertWritten(t testing.TB, m *MockPrinter, data []byte) bool {
+	t.Helper()
+	if got := m.Bytes(); !bytes.Equal(got, data) {
+		t.Errorf("stream differs:\nexpected: % X\nactual:   % X", data, got)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package escpostest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMockPrinterRecords tests recording the stream of a printer
+func TestMockPrinterRecords(t *testing.T) {
+	m := NewMockPrinter()
+	p := escpos.New(m)
+
+	p.SetBold(true)
+	p.Write("Hello")
+	require.NoError(t, p.Print())
+
+	AssertContainsCommand(t, m, []byte{0x1B, 'E', 1})
+	AssertNotContainsCommand(t, m, []byte{0x1D, 'V'})
+	assert.Contains(t, m.String(), "Hello")
+	assert.Equal(t, 1, m.CountCommand([]byte("Hello")))
+	assert.NotEmpty(t, m.Writes())
+
+	m.Reset()
+	AssertWritten(t, m, nil)
+	assert.Empty(t, m.Writes())
+}
+
+// TestMockPrinterStatus tests answering queued replies, then the status
+func TestMockPrinterStatus(t *testing.T) {
+	m := NewMockPrinter()
+	m.SetStatus([]byte{0x12})
+	m.QueueStatus([]byte{0x1E}, []byte{0x7E})
+
+	buf := make([]byte, 1)
+	for _, expected := range []byte{0x1E, 0x7E, 0x12, 0x12} {
+		n, err := m.Read(buf)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, expected, buf[0])
+	}
+}
+
+// TestMockPrinterStatusQuery tests a status query answered by the queued replies
+func TestMockPrinterStatusQuery(t *testing.T) {
+	m := NewMockPrinter()
+	m.QueueStatus([]byte{0x12 | escpos.RT_MASK_NOPAPER})
+	p := escpos.New(m)
+
+	paper, err := p.PaperStatus()
+	require.NoError(t, err)
+	assert.Equal(t, 0, paper)
+	AssertContainsCommand(t, m, []byte{0x10, 0x04, escpos.RT_STATUS_PAPER})
+}
+
+// TestMockPrinterFailures tests injecting errors
+func TestMockPrinterFailures(t *testing.T) {
+	m := NewMockPrinter()
+	errJam := errors.New("paper jam")
+
+	m.FailAfter(3, errJam)
+	n, err := m.Write([]byte("ab"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	n, err = m.Write([]byte("cde"))
+	assert.ErrorIs(t, err, errJam)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "abc", m.String())
+
+	m.FailWrites(nil)
+	_, err = m.Write([]byte("f"))
+	assert.NoError(t, err)
+
+	m.FailReads(errJam)
+	_, err = m.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, errJam)
+
+	m.FailClose(errJam)
+	assert.ErrorIs(t, m.Close(), errJam)
+	assert.True(t, m.Closed())
+}
+
+// TestAssertContainsCommand tests reporting a missing command
+func TestAssertContainsCommand(t *testing.T) {
+	m := NewMockPrinter()
+	m.Write([]byte("abc"))
+
+	inner := &testing.T{}
+	assert.False(t, AssertContainsCommand(inner, m, []byte("x")))
+	assert.True(t, inner.Failed())
+	assert.True(t, AssertContainsCommand(t, m, []byte("bc")))
+}
@@ -0,0 +1,29 @@
+// Package target selects the printer used by the example programs.
+package target
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/emulator"
+)
+
+// Open connects to the network printer at addr, or creates an emulator when addr is empty.
+// The returned function closes the connection, or writes the emulated paper to out.
+func Open(addr string, out io.Writer) (escpos.Printer, func() error, error) {
+	if addr != "" {
+		printer, err := escpos.NewNetworkPrinter(addr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+		}
+		return printer, printer.Close, nil
+	}
+
+	em := emulator.New()
+	done := func() error {
+		_, err := io.WriteString(out, em.Text())
+		return err
+	}
+	return em, done, nil
+}
@@ -0,0 +1,46 @@
+// Command labelstation prints one drink label per ordered item, as used at
+// the pick-up counter of a coffee shop.
+//
+// By default the output goes to the emulator and is printed on stdout; use
+// -addr to send it to a network printer instead.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/examples/internal/target"
+)
+
+var items = []escpos.LineItem{
+	{Name: "Flat White", Quantity: 2, Modifiers: []string{"Oat milk", "Extra shot"}, Note: "Sam"},
+	{Name: "Iced Latte", Quantity: 1, Modifiers: []string{"Vanilla"}, Note: "Alex"},
+}
+
+func main() {
+	addr := flag.String("addr", "", "network printer address (host:port), the emulator is used when empty")
+	order := flag.String("order", "118", "order number printed on the labels")
+	flag.Parse()
+
+	printer, done, err := target.Open(*addr, os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := run(escpos.New(printer), *order, items); err != nil {
+		log.Fatal(err)
+	}
+	if err := done(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run prints the labels of an order
+func run(p *escpos.Escpos, order string, items []escpos.LineItem) error {
+	if err := p.PrintItemLabels(order, items); err != nil {
+		return err
+	}
+	return p.Print()
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRun prints the example labels on the emulator
+func TestRun(t *testing.T) {
+	em := emulator.New()
+
+	err := run(escpos.New(em), "118", items)
+	require.NoError(t, err)
+
+	cuts := 0
+	for _, el := range em.Paper() {
+		if el.Kind == emulator.KindCut {
+			cuts++
+		}
+	}
+	assert.Equal(t, 3, cuts)
+
+	text := em.Text()
+	assert.Equal(t, 3, strings.Count(text, "#118"))
+	assert.Contains(t, text, " + Oat milk")
+	assert.Contains(t, text, "3/3")
+}
@@ -0,0 +1,71 @@
+// Command queueticket prints numbered queue tickets, as issued by a
+// self-service kiosk at a counter or pharmacy.
+//
+// By default the output goes to the emulator and is printed on stdout; use
+// -addr to send it to a network printer instead.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/examples/internal/target"
+)
+
+// ticket is a queue ticket
+type ticket struct {
+	Number  int
+	Service string
+	Waiting int // number of people waiting ahead
+	Issued  time.Time
+}
+
+func main() {
+	addr := flag.String("addr", "", "network printer address (host:port), the emulator is used when empty")
+	first := flag.Int("first", 1, "number of the first ticket")
+	count := flag.Int("n", 1, "number of tickets to print")
+	flag.Parse()
+
+	printer, done, err := target.Open(*addr, os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p := escpos.New(printer)
+	for i := 0; i < *count; i++ {
+		t := ticket{Number: *first + i, Service: "Pharmacy", Waiting: i, Issued: time.Now()}
+		if err := run(p, t); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := done(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run prints a single ticket
+func run(p *escpos.Escpos, t ticket) error {
+	number := fmt.Sprintf("A%03d", t.Number)
+
+	p.SetJustify(escpos.JustifyCenter)
+	p.Write(t.Service + "\n")
+	p.SetBold(true)
+	p.SetSize(4, 4)
+	p.Write(number + "\n")
+	p.SetSize(1, 1)
+	p.SetBold(false)
+	p.Write(fmt.Sprintf("%d person(s) ahead of you\n", t.Waiting))
+	p.Write(t.Issued.Format("02/01/2006 15:04") + "\n")
+
+	if _, err := p.QRCode("https://example.com/queue/"+number, escpos.QRCodeModel2, 6, escpos.QRCodeErrorCorrectionLevelM); err != nil {
+		return fmt.Errorf("failed to print ticket QR code: %w", err)
+	}
+	p.Write("Scan to follow the queue\n")
+	p.SetJustify(escpos.JustifyLeft)
+
+	return p.PrintAndCut()
+}
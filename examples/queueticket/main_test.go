@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRun prints a queue ticket on the emulator
+func TestRun(t *testing.T) {
+	em := emulator.New()
+
+	issued := time.Date(2024, 5, 17, 9, 30, 0, 0, time.UTC)
+	err := run(escpos.New(em), ticket{Number: 7, Service: "Pharmacy", Waiting: 3, Issued: issued})
+	require.NoError(t, err)
+
+	text := em.Text()
+	assert.Contains(t, text, "A007")
+	assert.Contains(t, text, "3 person(s) ahead of you")
+	assert.Contains(t, text, "17/05/2024 09:30")
+	assert.Contains(t, text, "[qr: https://example.com/queue/A007]")
+
+	paper := em.Paper()
+	assert.Equal(t, emulator.KindCut, paper[len(paper)-1].Kind)
+}
@@ -0,0 +1,110 @@
+// Command restaurant prints a kitchen ticket and the customer bill of a table order.
+//
+// By default the output goes to the emulator and is printed on stdout; use
+// -addr to send it to a network printer instead.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/examples/internal/target"
+)
+
+// orderLine is an ordered item with its unit price in cents
+type orderLine struct {
+	escpos.LineItem
+	UnitPrice int
+}
+
+var order = []orderLine{
+	{LineItem: escpos.LineItem{Name: "Burger", Quantity: 2, Modifiers: []string{"No onions"}}, UnitPrice: 1250},
+	{LineItem: escpos.LineItem{Name: "Fries", Quantity: 2}, UnitPrice: 400},
+	{LineItem: escpos.LineItem{Name: "Lemonade", Quantity: 1, Note: "No ice"}, UnitPrice: 350},
+}
+
+func main() {
+	addr := flag.String("addr", "", "network printer address (host:port), the emulator is used when empty")
+	flag.Parse()
+
+	printer, done, err := target.Open(*addr, os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := run(escpos.New(printer), "12", order); err != nil {
+		log.Fatal(err)
+	}
+	if err := done(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run prints the kitchen ticket and the bill for a table
+func run(p *escpos.Escpos, table string, lines []orderLine) error {
+	if err := printKitchenTicket(p, table, lines); err != nil {
+		return err
+	}
+	if err := printBill(p, table, lines); err != nil {
+		return err
+	}
+	return p.Print()
+}
+
+// printKitchenTicket prints the items to prepare, without prices
+func printKitchenTicket(p *escpos.Escpos, table string, lines []orderLine) error {
+	p.SetJustify(escpos.JustifyCenter)
+	p.SetSize(2, 2)
+	p.Write("TABLE " + table + "\n")
+	p.SetSize(1, 1)
+	p.SetJustify(escpos.JustifyLeft)
+
+	for _, line := range lines {
+		p.SetBold(true)
+		p.Write(fmt.Sprintf("%dx %s\n", line.Quantity, line.Name))
+		p.SetBold(false)
+		for _, modifier := range line.Modifiers {
+			p.Write("   " + modifier + "\n")
+		}
+		if line.Note != "" {
+			p.Write("   " + line.Note + "\n")
+		}
+	}
+
+	_, err := p.Cut()
+	return err
+}
+
+// printBill prints the customer bill
+func printBill(p *escpos.Escpos, table string, lines []orderLine) error {
+	p.SetJustify(escpos.JustifyCenter)
+	p.SetBold(true)
+	p.Write("CHEZ GOPHER\n")
+	p.SetBold(false)
+	p.Write("Table " + table + "\n\n")
+	p.SetJustify(escpos.JustifyLeft)
+
+	total := 0
+	for _, line := range lines {
+		amount := line.Quantity * line.UnitPrice
+		total += amount
+		p.Write(fmt.Sprintf("%-2d %-32s %10s\n", line.Quantity, line.Name, money(amount)))
+	}
+
+	p.Write("\n")
+	p.SetBold(true)
+	p.Write(fmt.Sprintf("%-35s %10s\n", "TOTAL", money(total)))
+	p.SetBold(false)
+	p.LineFeedN(2)
+
+	_, err := p.Cut()
+	return err
+}
+
+// money formats an amount in cents
+func money(cents int) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
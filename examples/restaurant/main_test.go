@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRun prints the example order on the emulator
+func TestRun(t *testing.T) {
+	em := emulator.New()
+
+	err := run(escpos.New(em), "12", order)
+	require.NoError(t, err)
+
+	text := em.Text()
+	assert.Contains(t, text, "TABLE 12")
+	assert.Contains(t, text, "2x Burger")
+	assert.Contains(t, text, "No onions")
+	assert.Contains(t, text, "36.50")
+
+	cuts := 0
+	for _, el := range em.Paper() {
+		if el.Kind == emulator.KindCut {
+			cuts++
+		}
+	}
+	assert.Equal(t, 2, cuts)
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(text), "-"))
+}
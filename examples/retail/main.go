@@ -0,0 +1,97 @@
+// Command retail prints a retail receipt with a VAT breakdown and a barcode
+// identifying the transaction.
+//
+// By default the output goes to the emulator and is printed on stdout; use
+// -addr to send it to a network printer instead.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/examples/internal/target"
+)
+
+// article is a sold article, the price is in cents and includes VAT
+type article struct {
+	Name     string
+	Quantity int
+	Price    int
+	VATRate  int // VAT rate in percent
+}
+
+var basket = []article{
+	{Name: "Baguette", Quantity: 2, Price: 120, VATRate: 5},
+	{Name: "Café moulu 250g", Quantity: 1, Price: 459, VATRate: 5},
+	{Name: "Piles AA x4", Quantity: 1, Price: 699, VATRate: 20},
+}
+
+func main() {
+	addr := flag.String("addr", "", "network printer address (host:port), the emulator is used when empty")
+	flag.Parse()
+
+	printer, done, err := target.Open(*addr, os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := run(escpos.New(printer), "2000123456789", basket); err != nil {
+		log.Fatal(err)
+	}
+	if err := done(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run prints the receipt for the basket
+func run(p *escpos.Escpos, transaction string, articles []article) error {
+	p.SetJustify(escpos.JustifyCenter)
+	p.SetSize(2, 2)
+	p.Write("GOPHER MARKET\n")
+	p.SetSize(1, 1)
+	p.Write("1 rue du Port, 75000 Paris\n\n")
+	p.SetJustify(escpos.JustifyLeft)
+
+	total := 0
+	vat := map[int]int{} // VAT amount by rate
+	for _, a := range articles {
+		amount := a.Quantity * a.Price
+		total += amount
+		vat[a.VATRate] += amount - amount*100/(100+a.VATRate)
+		p.Write(fmt.Sprintf("%-3d %-30s %6s %2d%%\n", a.Quantity, a.Name, money(amount), a.VATRate))
+	}
+
+	p.Write("\n")
+	p.SetBold(true)
+	p.Write(fmt.Sprintf("%-34s %10s\n", "TOTAL TTC", money(total)))
+	p.SetBold(false)
+
+	rates := make([]int, 0, len(vat))
+	for rate := range vat {
+		rates = append(rates, rate)
+	}
+	sort.Ints(rates)
+	for _, rate := range rates {
+		p.Write(fmt.Sprintf("%-34s %10s\n", fmt.Sprintf("dont TVA %d%%", rate), money(vat[rate])))
+	}
+
+	p.Write("\n")
+	p.SetJustify(escpos.JustifyCenter)
+	p.SetHRIPosition(escpos.HRIPositionBelow)
+	if _, err := p.EAN13(transaction); err != nil {
+		return fmt.Errorf("failed to print transaction barcode: %w", err)
+	}
+	p.Write("Merci de votre visite !\n")
+	p.LineFeedN(2)
+
+	return p.PrintAndCut()
+}
+
+// money formats an amount in cents
+func money(cents int) string {
+	return fmt.Sprintf("%d,%02d", cents/100, cents%100)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRun prints the example basket on the emulator
+func TestRun(t *testing.T) {
+	em := emulator.New()
+
+	err := run(escpos.New(em), "2000123456789", basket)
+	require.NoError(t, err)
+
+	text := em.Text()
+	assert.Contains(t, text, "Café moulu 250g")
+	assert.Contains(t, text, "TOTAL TTC")
+	assert.Contains(t, text, "13,98")
+	assert.Contains(t, text, "dont TVA 20%")
+	assert.Contains(t, text, "[barcode EAN13: 2000123456789]")
+
+	paper := em.Paper()
+	assert.Equal(t, emulator.KindCut, paper[len(paper)-1].Kind)
+}
+
+// TestRunInvalidTransaction tests that an invalid transaction number is reported
+func TestRunInvalidTransaction(t *testing.T) {
+	err := run(escpos.New(emulator.New()), "ABC", basket)
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package escpos
+
+import "fmt"
+
+// CommandBuilder builds the bytes of a vendor specific command from its arguments
+type CommandBuilder func(args ...any) ([]byte, error)
+
+// ExtensionSet is a set of vendor specific commands, by command name
+type ExtensionSet map[string]CommandBuilder
+
+// RegisterExtension registers a vendor specific command on the profile, so
+// proprietary features (e.g. Star raster mode, Bixolon MSR) can be used
+// without patching the package. Commands are namespaced by vendor; registering
+// an existing vendor and name replaces the previous builder.
+//
+// Register the extensions before passing the profile to SetProfile.
+func (p *Profile) RegisterExtension(vendor, name string, build CommandBuilder) {
+	if p.Extensions == nil {
+		p.Extensions = map[string]ExtensionSet{}
+	}
+	if p.Extensions[vendor] == nil {
+		p.Extensions[vendor] = ExtensionSet{}
+	}
+	p.Extensions[vendor][name] = build
+}
+
+// RegisterExtensions registers a set of vendor specific commands on the profile
+func (p *Profile) RegisterExtensions(vendor string, set ExtensionSet) {
+	for name, build := range set {
+		p.RegisterExtension(vendor, name, build)
+	}
+}
+
+// Extension returns the builder of a vendor specific command registered on the profile
+func (p Profile) Extension(vendor, name string) (CommandBuilder, bool) {
+	build, ok := p.Extensions[vendor][name]
+	return build, ok && build != nil
+}
+
+// Extension builds a vendor specific command registered on the printer
+// profile and writes it like any other command, so it goes through the same
+// write path as the built-in commands.
+func (e *Escpos) Extension(vendor, name string, args ...any) (int, error) {
+	build, ok := e.profile.Extension(vendor, name)
+	if !ok {
+		return 0, fmt.Errorf("extension %s/%s is not registered for %s", vendor, name, e.profile.name())
+	}
+
+	cmd, err := build(args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build extension %s/%s: %w", vendor, name, err)
+	}
+	return e.WriteRaw(cmd)
+}
+
+// byteArgs converts the arguments of an extension to bytes, n arguments being expected
+func byteArgs(args []any, n int) ([]byte, error) {
+	if len(args) != n {
+		return nil, fmt.Errorf("expected %d arguments, got %d", n, len(args))
+	}
+
+	b := make([]byte, n)
+	for i, arg := range args {
+		var v int
+		switch a := arg.(type) {
+		case byte:
+			v = int(a)
+		case int:
+			v = a
+		default:
+			return nil, fmt.Errorf("argument %d must be an int or a byte, got %T", i+1, arg)
+		}
+		if v < 0 || v > 255 {
+			return nil, fmt.Errorf("argument %d must be between 0-255, got %d", i+1, v)
+		}
+		b[i] = byte(v)
+	}
+	return b, nil
+}
@@ -0,0 +1,53 @@
+package escpos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExtension tests registering and running vendor specific commands
+func TestExtension(t *testing.T) {
+	profile := Profile{Name: "Star TSP143"}
+	profile.RegisterExtension("star", "cut", func(args ...any) ([]byte, error) {
+		return []byte{esc, 'd', 3}, nil
+	})
+	profile.RegisterExtensions("bixolon", ExtensionSet{
+		"msr-read": func(args ...any) ([]byte, error) {
+			if len(args) != 1 {
+				return nil, fmt.Errorf("expected the track number")
+			}
+			track, ok := args[0].(int)
+			if !ok {
+				return nil, fmt.Errorf("track number must be an int")
+			}
+			return []byte{esc, 'M', 'S', byte(track)}, nil
+		},
+	})
+
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(profile)
+
+	_, err := p.Extension("star", "cut")
+	assert.NoError(t, err)
+	_, err = p.Extension("bixolon", "msr-read", 2)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{esc, 'd', 3, esc, 'M', 'S', 2}, mock.Bytes())
+
+	// Builder errors are reported
+	_, err = p.Extension("bixolon", "msr-read")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to build extension bixolon/msr-read")
+
+	// Unknown commands and vendors are reported
+	_, err = p.Extension("star", "raster-mode")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "extension star/raster-mode is not registered for Star TSP143")
+	_, err = p.Extension("epson", "cut")
+	assert.Error(t, err)
+}
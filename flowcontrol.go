@@ -0,0 +1,55 @@
+package escpos
+
+import (
+	"fmt"
+	"time"
+)
+
+// Delay between two status checks while the printer is not ready
+const flowInterval = 100 * time.Millisecond
+
+// WithFlowControl checks the printer status (DLE EOT 1) before sending each
+// block of at most blockSize bytes, and waits while the printer reports
+// itself offline, such as when its receive buffer is full, a cover is open
+// or the paper ran out. This prevents the silent truncation of long jobs on
+// serial links without hardware flow control. The write fails when the
+// printer is not ready within timeout (0: 30 seconds).
+func WithFlowControl(blockSize int, timeout time.Duration) Option {
+	return func(e *Escpos) {
+		if blockSize <= 0 {
+			return
+		}
+		if e.out.chunks == nil {
+			e.out.chunks = &chunking{}
+		}
+		if timeout <= 0 {
+			timeout = defaultFlowTimeout
+		}
+		chunks := e.out.chunks
+		if chunks.size == 0 || blockSize < chunks.size {
+			chunks.size = blockSize
+		}
+		chunks.flow, chunks.flowTimeout = true, timeout
+	}
+}
+
+// Default time waited for the printer to be ready, see WithFlowControl
+const defaultFlowTimeout = 30 * time.Second
+
+// waitReady polls the printer status until it reports itself online
+func (c *countingWriter) waitReady() error {
+	deadline := time.Now().Add(c.chunks.flowTimeout)
+	for {
+		status, err := c.pollStatus(deadline)
+		if err != nil {
+			return err
+		}
+		if status&RT_MASK_OFFLINE == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: %w for %s", ErrTimeout, ErrOffline, c.chunks.flowTimeout)
+		}
+		time.Sleep(flowInterval)
+	}
+}
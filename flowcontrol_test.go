@@ -0,0 +1,37 @@
+package escpos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithFlowControl tests waiting for the printer to be ready before each block
+func TestWithFlowControl(t *testing.T) {
+	mock := NewMockPrinter()
+	mock.SetStatus([]byte{0x16})
+	// The printer is busy when the second block is checked
+	mock.QueueStatus([]byte{0x16}, []byte{0x16 | RT_MASK_OFFLINE}, []byte{0x16})
+	p := New(mock, WithFlowControl(4, time.Second))
+
+	p.WriteRaw([]byte("0123456789"))
+	require.NoError(t, p.Print())
+
+	poll := "\x10\x04\x01"
+	assert.Equal(t, poll+"0123"+poll+poll+"4567"+poll+"89", mock.String())
+}
+
+// TestWithFlowControlTimeout tests failing when the printer stays offline
+func TestWithFlowControlTimeout(t *testing.T) {
+	mock := NewMockPrinter()
+	mock.SetStatus([]byte{0x16 | RT_MASK_OFFLINE})
+	p := New(mock, WithFlowControl(4, 50*time.Millisecond))
+
+	p.WriteRaw([]byte("0123456789"))
+	err := p.Print()
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.ErrorIs(t, err, ErrOffline)
+	assert.NotContains(t, mock.String(), "0123")
+}
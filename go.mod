@@ -3,8 +3,10 @@ module github.com/schawnndev/escpos
 go 1.25.0
 
 require (
+	github.com/boombuler/barcode v1.1.0
 	github.com/kovidgoyal/imaging v1.8.21
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/image v0.38.0
 	golang.org/x/text v0.35.0
 )
 
@@ -14,7 +16,6 @@ require (
 	github.com/kovidgoyal/go-shm v1.0.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rwcarlsen/goexif v0.0.0-20190401172101-9e8deecbddbd // indirect
-	golang.org/x/image v0.38.0 // indirect
 	golang.org/x/sys v0.42.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
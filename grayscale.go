@@ -0,0 +1,166 @@
+package escpos
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/kovidgoyal/imaging"
+)
+
+// GrayscaleMode selects how PrintImageGrayscale renders the gray levels
+type GrayscaleMode uint8
+
+const (
+	// GrayscaleMultiTone sends a 16 level image with the multiple tone
+	// graphics of GS ( L, supported by Epson models such as the TM-T88VI.
+	// The profile must use RasterGraphics.
+	GrayscaleMultiTone GrayscaleMode = iota
+	// GrayscaleMultiPass prints each band of the image several times,
+	// feeding the paper back in between: the first pass prints the light
+	// and dark dots at a low density, the next ones only the darker dots at
+	// higher densities. The printer must feed the paper back (ESC K).
+	GrayscaleMultiPass
+)
+
+// Height in dots of the bands printed by GrayscaleMultiPass, small enough to
+// be fed back by every printer supporting ESC K
+const multiPassBand = 24
+
+// Print densities of the passes of GrayscaleMultiPass, see SetPrintDensity
+var multiPassDensities = []int{-6, -3, 0}
+
+// PrintImageGrayscale prints an image with gray levels instead of dithering
+// it, for photos on marketing receipts. Experimental: the result depends on
+// the paper and on the firmware.
+func (e *Escpos) PrintImageGrayscale(img image.Image, mode GrayscaleMode) (int, error) {
+	if err := e.checkUpright("grayscale images"); err != nil {
+		return 0, err
+	}
+	switch mode {
+	case GrayscaleMultiTone:
+		if e.star() || e.profile.RasterCommand != RasterGraphics {
+			return 0, fmt.Errorf("%s does not support multiple tone graphics", e.profile.name())
+		}
+		return e.printMultiTone(grayLevels(img))
+	case GrayscaleMultiPass:
+		if e.star() || e.profile.NoReverseFeed {
+			return 0, fmt.Errorf("%s cannot feed the paper back for multiple passes", e.profile.name())
+		}
+		return e.printMultiPass(grayLevels(img))
+	}
+	return 0, fmt.Errorf("unknown grayscale mode: %d", mode)
+}
+
+// grayLevels returns the darkness of each pixel of an image flattened on
+// white paper, 0 (white) to 255 (black)
+func grayLevels(img image.Image) *image.Gray {
+	bounds := img.Bounds()
+	white := imaging.New(bounds.Dx(), bounds.Dy(), color.White)
+	flat := imaging.Overlay(white, img, image.Point{}, 1.0)
+
+	gray := image.NewGray(flat.Bounds())
+	for i := range gray.Pix {
+		p := flat.Pix[i*4 : i*4+3]
+		luminance := (299*int(p[0]) + 587*int(p[1]) + 114*int(p[2])) / 1000
+		gray.Pix[i] = byte(255 - luminance)
+	}
+	return gray
+}
+
+// layer returns the raster image of the pixels of a darkness selected by dark
+func layer(gray *image.Gray, dark func(level byte) bool) rasterImage {
+	width, height := gray.Rect.Dx(), gray.Rect.Dy()
+	r := rasterImage{widthBytes: (width + 7) / 8, width: width, height: height}
+	r.data = make([]byte, r.widthBytes*height)
+	for y := range height {
+		for x := range width {
+			if dark(gray.Pix[y*gray.Stride+x]) {
+				r.data[y*r.widthBytes+x/8] |= 0x80 >> (x % 8)
+			}
+		}
+	}
+	return r
+}
+
+// printMultiTone prints the 16 levels of gray as four bit planes of a GS ( L
+// multiple tone image, the first plane holding the most significant bit
+func (e *Escpos) printMultiTone(gray *image.Gray) (int, error) {
+	planes := make([][]rasterImage, 4)
+	for bit := range planes {
+		shift := 3 - bit
+		planes[bit] = layer(gray, func(level byte) bool {
+			return (level>>4)>>shift&1 != 0
+		}).split(e.profile.MaxImageHeight)
+	}
+
+	written := 0
+	for band := range planes[0] {
+		for bit, plane := range planes {
+			r := plane[band]
+			width, err := LowHigh16(r.widthBytes * 8)
+			if err != nil {
+				return written, err
+			}
+			height, err := LowHigh16(r.height)
+			if err != nil {
+				return written, err
+			}
+			params := []byte{48, 112, 52, 1, 1, byte(49 + bit), width[0], width[1], height[0], height[1]}
+			cmd, err := graphicsCommand(params, r.data)
+			if err != nil {
+				return written, fmt.Errorf("failed to encode image: %w", err)
+			}
+			n, err := e.WriteRaw(cmd)
+			written += n
+			if err != nil {
+				return written, err
+			}
+		}
+		n, err := e.WriteRaw([]byte{gs, '(', 'L', 2, 0, 48, 50})
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// printMultiPass prints the bands of the image in passes of increasing
+// darkness and density, then restores the print density
+func (e *Escpos) printMultiPass(gray *image.Gray) (int, error) {
+	passes := make([][]rasterImage, len(multiPassDensities))
+	for i := range passes {
+		threshold := byte(256 * (i + 1) / (len(passes) + 1))
+		passes[i] = layer(gray, func(level byte) bool {
+			return level >= threshold
+		}).split(multiPassBand)
+	}
+
+	written := 0
+	density := e.density
+	for band := range passes[0] {
+		for i, pass := range passes {
+			n, err := e.SetPrintDensity(multiPassDensities[i])
+			written += n
+			if err != nil {
+				return written, err
+			}
+			n, err = e.printRaster(pass[band])
+			written += n
+			if err != nil {
+				return written, err
+			}
+			if i == len(passes)-1 {
+				break
+			}
+			n, err = e.ReverseFeedDots(byte(pass[band].height))
+			written += n
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+	n, err := e.SetPrintDensity(density)
+	return written + n, err
+}
@@ -0,0 +1,84 @@
+package escpos
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// grayTestImage returns an image of a black row above a mid gray row
+func grayTestImage() image.Image {
+	img := image.NewGray(image.Rect(0, 0, 8, 2))
+	for x := range 8 {
+		img.Set(x, 0, color.Black)
+		img.Set(x, 1, color.Gray{Y: 136})
+	}
+	return img
+}
+
+// TestPrintImageGrayscaleMultiTone tests the bit planes of the multiple tone image
+func TestPrintImageGrayscaleMultiTone(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{RasterCommand: RasterGraphics})
+
+	_, err := p.PrintImageGrayscale(grayTestImage(), GrayscaleMultiTone)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	plane := func(c byte, rows ...byte) []byte {
+		return append([]byte{gs, '(', 'L', 12, 0, 48, 112, 52, 1, 1, c, 8, 0, 2, 0}, rows...)
+	}
+	var expected []byte
+	expected = append(expected, plane(49, 0xff, 0x00)...)
+	expected = append(expected, plane(50, 0xff, 0xff)...)
+	expected = append(expected, plane(51, 0xff, 0xff)...)
+	expected = append(expected, plane(52, 0xff, 0xff)...)
+	expected = append(expected, gs, '(', 'L', 2, 0, 48, 50)
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestPrintImageGrayscaleMultiPass tests the passes printed at increasing densities
+func TestPrintImageGrayscaleMultiPass(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.PrintImageGrayscale(grayTestImage(), GrayscaleMultiPass)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	density := func(level int) []byte {
+		return []byte{gs, '(', 'K', 2, 0, 49, byte(level)}
+	}
+	raster := func(rows ...byte) []byte {
+		return append([]byte{gs, 'v', '0', 0, 1, 0, 2, 0}, rows...)
+	}
+	var expected []byte
+	expected = append(expected, density(-6)...)
+	expected = append(expected, raster(0xff, 0xff)...)
+	expected = append(expected, esc, 'K', 2)
+	expected = append(expected, density(-3)...)
+	expected = append(expected, raster(0xff, 0x00)...)
+	expected = append(expected, esc, 'K', 2)
+	expected = append(expected, density(0)...)
+	expected = append(expected, raster(0xff, 0x00)...)
+	expected = append(expected, density(0)...)
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestPrintImageGrayscaleUnsupported tests the printers without grayscale support
+func TestPrintImageGrayscaleUnsupported(t *testing.T) {
+	p := New(NewMockPrinter())
+	_, err := p.PrintImageGrayscale(grayTestImage(), GrayscaleMultiTone)
+	assert.Error(t, err)
+
+	p.SetProfile(Profile{NoReverseFeed: true})
+	_, err = p.PrintImageGrayscale(grayTestImage(), GrayscaleMultiPass)
+	assert.Error(t, err)
+
+	_, err = p.PrintImageGrayscale(grayTestImage(), 9)
+	assert.Error(t, err)
+}
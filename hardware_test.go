@@ -0,0 +1,194 @@
+//go:build hardware
+
+package escpos
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// The hardware conformance suite exercises the features of the library on a
+// connected printer:
+//
+//	ESCPOS_PRINTER=192.168.1.20:9100 go test -tags hardware -run TestHardware .
+//
+// It prints a numbered sample of each feature and writes a capability report
+// (ESCPOS_REPORT, default hardware-report.json) holding the answers of the
+// printer to the queries, the samples to check against the printout and the
+// suggested profile fields. Fill in the "printed" field of each sample and
+// contribute the report to add the printer to the profiles.
+
+// hardwareReport is the capability report of a printer
+type hardwareReport struct {
+	Date     time.Time         `json:"date"`
+	Address  string            `json:"address"`
+	Identity *PrinterInfo      `json:"identity,omitempty"`
+	Queries  map[string]string `json:"queries"`
+	Samples  []hardwareSample  `json:"samples"`
+	Profile  hardwareProfile   `json:"profile"`
+}
+
+// hardwareSample is a printed sample, checked by the operator
+type hardwareSample struct {
+	Number  int    `json:"number"`
+	Feature string `json:"feature"`
+	Error   string `json:"error,omitempty"`
+	// Printed is filled in by the operator after checking the printout
+	Printed *bool `json:"printed"`
+}
+
+// hardwareProfile holds the profile fields deduced from the queries
+type hardwareProfile struct {
+	Name       string `json:"name"`
+	NVGraphics bool   `json:"nv_graphics"`
+	NVCapacity int    `json:"nv_capacity,omitempty"`
+}
+
+// hardwareFeatures are the samples printed by the suite
+var hardwareFeatures = []struct {
+	name  string
+	print func(p *Escpos) error
+}{
+	{"bold", func(p *Escpos) error { return styled(p, Style{Bold: true}) }},
+	{"underline", func(p *Escpos) error { return styled(p, Style{Underline: UnderlineDouble}) }},
+	{"reverse", func(p *Escpos) error { return styled(p, Style{Reverse: true}) }},
+	{"upside down", func(p *Escpos) error { return styled(p, Style{UpsideDown: true}) }},
+	{"rotate", func(p *Escpos) error { return styled(p, Style{Rotate: true}) }},
+	{"size 2x2", func(p *Escpos) error { return styled(p, Style{Width: 2, Height: 2}) }},
+	{"justify right", func(p *Escpos) error { return styled(p, Style{Justify: JustifyRight}) }},
+	{"font B", func(p *Escpos) error { return withFont(p, FontB) }},
+	{"font C", func(p *Escpos) error { return withFont(p, FontC) }},
+	{"code page PC858", func(p *Escpos) error {
+		_, err := p.WriteWithEncoding("€ àéîõü\n", charmap.CodePage858, CodePagePC858)
+		return err
+	}},
+	{"code page WPC1252", func(p *Escpos) error {
+		_, err := p.WriteWithEncoding("€ àéîõü\n", charmap.Windows1252, CodePageWPC1252)
+		return err
+	}},
+	{"barcode EAN13", func(p *Escpos) error { _, err := p.EAN13("4006381333931"); return err }},
+	{"barcode CODE39", func(p *Escpos) error { _, err := p.CODE39("ESCPOS"); return err }},
+	{"barcode CODE128", func(p *Escpos) error { _, err := p.BarcodeB(BarcodeBCode128, []byte("{BESCPOS")); return err }},
+	{"native QR code", func(p *Escpos) error {
+		_, err := p.QRCode("https://github.com/schawnndev/escpos", QRCodeModel2, 4, QRCodeErrorCorrectionLevelM)
+		return err
+	}},
+	{"raster GS v 0", func(p *Escpos) error { return raster(p, RasterGSv0) }},
+	{"raster GS ( L", func(p *Escpos) error { return raster(p, RasterGraphics) }},
+	{"print density", func(p *Escpos) error {
+		if _, err := p.SetPrintDensity(3); err != nil {
+			return err
+		}
+		_, err := p.PrintHeadTestPattern()
+		return err
+	}},
+	{"partial cut", func(p *Escpos) error { _, err := p.PartialCut(); return err }},
+}
+
+// styled prints a sample line with a style
+func styled(p *Escpos, s Style) error {
+	_, err := p.WriteStyled("Sample ABC 123\n", s)
+	return err
+}
+
+// withFont prints a sample line with a font
+func withFont(p *Escpos, f uint8) error {
+	if _, err := p.SetFont(f); err != nil {
+		return err
+	}
+	_, err := p.Write("Sample ABC 123\n")
+	if err != nil {
+		return err
+	}
+	_, err = p.SetFont(FontA)
+	return err
+}
+
+// raster prints a gradient with a raster image command
+func raster(p *Escpos, c RasterCommand) error {
+	profile := p.Profile()
+	profile.RasterCommand = c
+	p.SetProfile(profile)
+
+	img := image.NewGray(image.Rect(0, 0, 256, 64))
+	for x := 0; x < 256; x++ {
+		for y := 0; y < 64; y++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(x)})
+		}
+	}
+	_, err := p.PrintImageWithProcessing(img, ImageProcessDither, true, true)
+	return err
+}
+
+// TestHardware runs the conformance suite on the printer at ESCPOS_PRINTER
+func TestHardware(t *testing.T) {
+	addr := os.Getenv("ESCPOS_PRINTER")
+	if addr == "" {
+		t.Skip("ESCPOS_PRINTER is not set")
+	}
+	printer, err := NewNetworkPrinter(addr, WithTimeout(5*time.Second))
+	require.NoError(t, err)
+	defer printer.Close()
+	p := New(printer)
+
+	report := hardwareReport{Date: time.Now(), Address: addr, Queries: map[string]string{}}
+	query := func(name string, fn func() (any, error)) {
+		v, err := fn()
+		if err != nil {
+			report.Queries[name] = "error: " + err.Error()
+			return
+		}
+		report.Queries[name] = fmt.Sprint(v)
+	}
+
+	query("online", func() (any, error) { return p.IsOnline() })
+	query("status", func() (any, error) { return p.FullStatus() })
+	if info, err := p.GetPrinterID(); err == nil {
+		report.Identity = &info
+		report.Profile.Name = info.Maker + " " + info.Model
+	} else {
+		report.Queries["identity"] = "error: " + err.Error()
+	}
+	p.SetProfile(Profile{NVGraphics: true})
+	if capacity, err := p.NVImageCapacity(); err == nil {
+		report.Profile.NVGraphics, report.Profile.NVCapacity = true, capacity
+	} else {
+		report.Queries["nv capacity"] = "error: " + err.Error()
+	}
+
+	_, err = p.Initialize()
+	require.NoError(t, err)
+	p.SetProfile(Profile{})
+	for i, f := range hardwareFeatures {
+		sample := hardwareSample{Number: i + 1, Feature: f.name}
+		_, err := p.Write(fmt.Sprintf("[%d] %s\n", sample.Number, f.name))
+		require.NoError(t, err)
+		if err := f.print(p); err != nil {
+			sample.Error = err.Error()
+			t.Logf("%s: %v", f.name, err)
+		}
+		_, err = p.Initialize()
+		require.NoError(t, err)
+		p.SetProfile(Profile{})
+		require.NoError(t, p.Print())
+		report.Samples = append(report.Samples, sample)
+	}
+	require.NoError(t, p.PrintAndCut())
+
+	path := os.Getenv("ESCPOS_REPORT")
+	if path == "" {
+		path = "hardware-report.json"
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	t.Logf("capability report written to %s", path)
+}
@@ -0,0 +1,57 @@
+package escpos
+
+import "fmt"
+
+// Height in dots of each band of the head test pattern
+const testBandHeight = 48
+
+// PrintHeadTestPattern prints raster patterns revealing dead heating
+// elements of the print head, as white streaks in the black bands or
+// missing lines: a black band, a checkerboard and its inverse, vertical
+// lines on the even then the odd dots, and a final black band. The pattern
+// spans the print width of the profile.
+func (e *Escpos) PrintHeadTestPattern() (int, error) {
+	widthBytes := e.PrintWidth() / 8
+
+	bands := []struct {
+		name string
+		row  func(y, x int) byte
+	}{
+		{"black", func(y, x int) byte { return 0xFF }},
+		{"checkerboard", func(y, x int) byte { return checker(y, x, 0) }},
+		{"inverse checkerboard", func(y, x int) byte { return checker(y, x, 1) }},
+		{"even lines", func(y, x int) byte { return 0xAA }},
+		{"odd lines", func(y, x int) byte { return 0x55 }},
+		{"black", func(y, x int) byte { return 0xFF }},
+	}
+
+	written := 0
+	n, err := e.Write(fmt.Sprintf("HEAD TEST %d dots\n", widthBytes*8))
+	written += n
+	if err != nil {
+		return written, err
+	}
+	for _, band := range bands {
+		data := make([]byte, widthBytes*testBandHeight)
+		for y := 0; y < testBandHeight; y++ {
+			for x := 0; x < widthBytes; x++ {
+				data[y*widthBytes+x] = band.row(y, x)
+			}
+		}
+		n, err := e.printRaster(rasterImage{widthBytes: widthBytes, height: testBandHeight, data: data})
+		written += n
+		if err != nil {
+			return written, fmt.Errorf("failed to print %s band: %w", band.name, err)
+		}
+	}
+	return written, nil
+}
+
+// checker returns the byte at row y and byte column x of a checkerboard of
+// 8 dot squares, phase 1 being the inverse board
+func checker(y, x, phase int) byte {
+	if (y/8+x+phase)%2 == 0 {
+		return 0xFF
+	}
+	return 0x00
+}
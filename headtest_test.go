@@ -0,0 +1,43 @@
+package escpos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintHeadTestPattern tests the bands of the head test pattern
+func TestPrintHeadTestPattern(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{PrintWidth: 384})
+
+	_, err := p.PrintHeadTestPattern()
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	output := mock.Bytes()
+	title := []byte("HEAD TEST 384 dots\n")
+	require.True(t, bytes.HasPrefix(output, title))
+
+	header := []byte{gs, 'v', '0', 0, 48, 0, 48, 0}
+	assert.Equal(t, 6, bytes.Count(output, header))
+	band := len(header) + 48*48
+	assert.Len(t, output, len(title)+6*band)
+
+	bandData := func(i int) []byte {
+		start := len(title) + i*band + len(header)
+		return output[start : start+48*48]
+	}
+	assert.Equal(t, bytes.Repeat([]byte{0xFF}, 48*48), bandData(0))
+	// The checkerboards are the inverse of each other
+	for i, b := range bandData(1) {
+		assert.Equal(t, ^b, bandData(2)[i])
+	}
+	assert.Equal(t, []byte{0xFF, 0x00, 0xFF}, bandData(1)[:3])
+	assert.Equal(t, []byte{0x00, 0xFF, 0x00}, bandData(1)[8*48:8*48+3])
+	assert.Equal(t, bytes.Repeat([]byte{0xAA}, 48*48), bandData(3))
+	assert.Equal(t, bytes.Repeat([]byte{0x55}, 48*48), bandData(4))
+}
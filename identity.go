@@ -0,0 +1,69 @@
+package escpos
+
+import "fmt"
+
+// PrinterInfo identifies a printer, as reported by GS I
+type PrinterInfo struct {
+	ModelID  byte // printer model ID (GS I 1)
+	TypeID   byte // printer type ID (GS I 2), a combination of feature bits
+	Firmware string
+	Maker    string
+	Model    string
+	Serial   string
+}
+
+// Header of the GS I text replies
+const identityHeader = 0x5F
+
+// GetPrinterID queries the identity of the printer (GS I): its model and
+// type IDs, firmware version, maker and model names and serial number. Fleet
+// management tools use it to inventory devices.
+func (e *Escpos) GetPrinterID() (PrinterInfo, error) {
+	var info PrinterInfo
+	var err error
+	if info.ModelID, err = e.requestByte([]byte{gs, 'I', 1}); err != nil {
+		return info, fmt.Errorf("failed to query model ID: %w", err)
+	}
+	if info.TypeID, err = e.requestByte([]byte{gs, 'I', 2}); err != nil {
+		return info, fmt.Errorf("failed to query type ID: %w", err)
+	}
+
+	texts := []struct {
+		n     byte
+		name  string
+		value *string
+	}{
+		{65, "firmware version", &info.Firmware},
+		{66, "maker name", &info.Maker},
+		{67, "model name", &info.Model},
+		{68, "serial number", &info.Serial},
+	}
+	for _, t := range texts {
+		resp, err := e.request([]byte{gs, 'I', t.n})
+		if err != nil {
+			return info, fmt.Errorf("failed to query %s: %w", t.name, err)
+		}
+		if len(resp) == 0 || resp[0] != identityHeader {
+			return info, fmt.Errorf("invalid %s response: % X", t.name, resp)
+		}
+		*t.value = string(resp[1:])
+	}
+	return info, nil
+}
+
+// requestByte sends a command and reads the single byte response of the printer
+func (e *Escpos) requestByte(cmd []byte) (byte, error) {
+	if err := e.sendRequest(cmd); err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, 1)
+	n, err := e.readStatus(buf)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("%w: no response", ErrTimeout)
+	}
+	return buf[0], nil
+}
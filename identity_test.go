@@ -0,0 +1,84 @@
+package escpos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// identityPrinter answers the GS I queries with the response of their parameter
+type identityPrinter struct {
+	MockPrinter
+	responses map[byte][]byte
+	pending   []byte
+}
+
+func (p *identityPrinter) Write(b []byte) (int, error) {
+	if len(b) == 3 && b[0] == gs && b[1] == 'I' {
+		p.pending = p.responses[b[2]]
+	}
+	return p.MockPrinter.Write(b)
+}
+
+func (p *identityPrinter) Read(b []byte) (int, error) {
+	n := copy(b, p.pending)
+	p.pending = p.pending[n:]
+	return n, nil
+}
+
+// TestGetPrinterID tests querying the printer identity
+func TestGetPrinterID(t *testing.T) {
+	printer := &identityPrinter{responses: map[byte][]byte{
+		1:  {0x20},
+		2:  {0x02},
+		65: []byte("_30.01 ESC/POS\x00"),
+		66: []byte("_EPSON\x00"),
+		67: []byte("_TM-T88VI\x00"),
+		68: []byte("_X5Q1234567\x00"),
+	}}
+	p := New(printer)
+
+	info, err := p.GetPrinterID()
+	require.NoError(t, err)
+	assert.Equal(t, PrinterInfo{
+		ModelID:  0x20,
+		TypeID:   0x02,
+		Firmware: "30.01 ESC/POS",
+		Maker:    "EPSON",
+		Model:    "TM-T88VI",
+		Serial:   "X5Q1234567",
+	}, info)
+
+	expected := []byte{gs, 'I', 1, gs, 'I', 2, gs, 'I', 65, gs, 'I', 66, gs, 'I', 67, gs, 'I', 68}
+	assert.Equal(t, expected, printer.Bytes())
+}
+
+// TestGetPrinterIDErrors tests the missing and invalid identity replies
+func TestGetPrinterIDErrors(t *testing.T) {
+	// No reply
+	_, err := New(&identityPrinter{}).GetPrinterID()
+	assert.Error(t, err)
+
+	// Text reply without its header
+	printer := &identityPrinter{responses: map[byte][]byte{1: {0x20}, 2: {0x02}, 65: []byte("30.01\x00")}}
+	_, err = New(printer).GetPrinterID()
+	assert.ErrorContains(t, err, "firmware version")
+}
+
+// TestGetPrinterIDPolling tests waiting for identity replies which arrive late
+func TestGetPrinterIDPolling(t *testing.T) {
+	mock := NewMockPrinter()
+	mock.QueueStatus(nil, []byte{0x20}, nil, nil, []byte{0x02},
+		nil, []byte("_30.01\x00"), []byte("_EPSON\x00"), nil, []byte("_TM-"), []byte("T88VI\x00"), []byte("_X5Q\x00"))
+	p := New(mock, WithStatusPollInterval(time.Millisecond))
+
+	info, err := p.GetPrinterID()
+	require.NoError(t, err)
+	assert.Equal(t, PrinterInfo{ModelID: 0x20, TypeID: 0x02, Firmware: "30.01", Maker: "EPSON", Model: "TM-T88VI", Serial: "X5Q"}, info)
+
+	// No reply within the status timeout
+	_, err = New(NewMockPrinter(), WithStatusTimeout(20*time.Millisecond)).GetPrinterID()
+	assert.ErrorIs(t, err, ErrTimeout)
+}
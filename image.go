@@ -2,7 +2,6 @@
 package escpos
 
 import (
-	"fmt"
 	"github.com/kovidgoyal/imaging"
 	"image"
 	"image/color"
@@ -12,12 +11,18 @@ import (
 // It uses Floyd-Steinberg dithering to convert the image to black and white.
 // highDensityVertical and highDensityHorizontal control the density of the image.
 // The image is rasterized and converted to a byte array for printing (header included).
-// todo: add support for fragmentHeight, center, and maxWidth
+// todo: add support for center and maxWidth
 func PrepareImageForPrinting(img image.Image, highDensityVertical bool, highDensityHorizontal bool) (data []byte, err error) {
-	im, err := transformImage(img)
+	raster, err := ditherImage(img, highDensityVertical, highDensityHorizontal)
 	if err != nil {
 		return nil, err
 	}
+	return raster.command()
+}
+
+// ditherImage converts an image to a dithered raster image
+func ditherImage(img image.Image, highDensityVertical bool, highDensityHorizontal bool) (rasterImage, error) {
+	im := transformImage(img)
 
 	densityByte := byte(0)
 	if !highDensityHorizontal {
@@ -27,48 +32,21 @@ func PrepareImageForPrinting(img image.Image, highDensityVertical bool, highDens
 		densityByte += 2
 	}
 
-	raster := rasterizeImage(im)
-
 	width, height := im.Bounds().Dx(), im.Bounds().Dy()
-	widthBytes := (width + 7) / 8
-
-	header := append([]byte{0x1D}, []byte("v0")...)
-	header = append(header, densityByte)
-
-	if res, err := intLowHigh(widthBytes, 2); err != nil {
-		return nil, err
-	} else {
-		header = append(header, res...)
-	}
 
-	if res, err := intLowHigh(height, 2); err != nil {
-		return nil, err
-	} else {
-		header = append(header, res...)
-	}
-
-	result := append(header, raster...)
-
-	return result, nil
+	return rasterImage{
+		density:    densityByte,
+		widthBytes: (width + 7) / 8,
+		width:      width,
+		height:     height,
+		data:       rasterizeImage(im),
+	}, nil
 }
 
 // transformImage converts an image to a pure black and white image using Floyd-Steinberg dithering.
-func transformImage(imgSource interface{}) (*image.NRGBA, error) {
-	var imgOriginal image.Image
-	var err error
-
-	switch source := imgSource.(type) {
-	case image.Image:
-		imgOriginal = source
-	case string:
-		imgOriginal, err = imaging.Open(source)
-		if err != nil {
-			return nil, err
-		}
-	}
-
+func transformImage(img image.Image) *image.NRGBA {
 	// convert to rgba
-	rgba := imaging.Clone(imgOriginal)
+	rgba := imaging.Clone(img)
 
 	bounds := rgba.Bounds()
 	white := imaging.New(bounds.Max.X, bounds.Max.Y, color.RGBA{R: 255, G: 255, B: 255, A: 255})
@@ -83,53 +61,58 @@ func transformImage(imgSource interface{}) (*image.NRGBA, error) {
 	result = imaging.Invert(gray)
 
 	// Convert to pure black and white and apply Floyd-Steinberg dithering
-	result = applyFloydSteinbergDithering(result)
-
-	return result, nil
+	return applyFloydSteinbergDithering(result)
 }
 
 // applyFloydSteinbergDithering applies Floyd-Steinberg dithering to an image.
 // It also converts the image to a binary format (black and white).
 // And reverses the colors (black becomes white and vice versa).
+// The pixels are read and written in the Pix slices directly, and the errors
+// are spread over two reused rows, as receipts of thousands of rows are
+// dithered on small boards.
 func applyFloydSteinbergDithering(img image.Image) *image.NRGBA {
-	binary := imaging.New(img.Bounds().Dx(), img.Bounds().Dy(), color.White)
-	bounds := img.Bounds()
-	width, height := bounds.Dx(), bounds.Dy()
-	errors := make([][]float64, height)
-	for i := range errors {
-		errors[i] = make([]float64, width)
+	src, ok := img.(*image.NRGBA)
+	if !ok {
+		src = imaging.Clone(img)
 	}
+	width, height := src.Rect.Dx(), src.Rect.Dy()
+	binary := imaging.New(width, height, color.White)
+
+	// Errors of the current row and of the next one
+	cur := make([]float64, width)
+	next := make([]float64, width)
 
 	for y := 0; y < height; y++ {
+		in := src.Pix[src.PixOffset(src.Rect.Min.X, src.Rect.Min.Y+y):]
+		out := binary.Pix[y*binary.Stride:]
 		for x := 0; x < width; x++ {
-			c := img.At(x, y)
-			r, _, _, _ := c.RGBA()
-			// Convert from uint32 to float64 (0-255 range)
-			oldPixel := float64(r>>8) + errors[y][x]
+			// The red channel of the grayscale image, premultiplied by alpha
+			// as color.Color would
+			r := uint32(in[x*4]) * 0x101 * (uint32(in[x*4+3]) * 0x101) / 0xffff
+			oldPixel := float64(r>>8) + cur[x]
 			newPixel := 0.0
 			if oldPixel >= 128 {
 				newPixel = 255.0
-			}
-			// Set the actual pixel
-			if newPixel != 0 {
-				binary.Set(x, y, color.Black)
+				out[x*4], out[x*4+1], out[x*4+2] = 0, 0, 0
 			}
 
 			// Distribute the error
 			quantError := oldPixel - newPixel
 			if x+1 < width {
-				errors[y][x+1] += quantError * 7.0 / 16.0
+				cur[x+1] += quantError * 7.0 / 16.0
 			}
 			if y+1 < height {
 				if x-1 >= 0 {
-					errors[y+1][x-1] += quantError * 3.0 / 16.0
+					next[x-1] += quantError * 3.0 / 16.0
 				}
-				errors[y+1][x] += quantError * 5.0 / 16.0
+				next[x] += quantError * 5.0 / 16.0
 				if x+1 < width {
-					errors[y+1][x+1] += quantError * 1.0 / 16.0
+					next[x+1] += quantError * 1.0 / 16.0
 				}
 			}
 		}
+		cur, next = next, cur
+		clear(next)
 	}
 
 	return binary
@@ -137,52 +120,24 @@ func applyFloydSteinbergDithering(img image.Image) *image.NRGBA {
 
 // rasterizeImage convert binary image to bytes
 func rasterizeImage(img *image.NRGBA) []byte {
-	bounds := img.Bounds()
-	width, height := bounds.Dx(), bounds.Dy()
+	width, height := img.Rect.Dx(), img.Rect.Dy()
 
 	// For binary images, we need 1 bit per pixel
 	// Calculate bytes needed: width * height / 8 (rounded up)
 	bytesPerRow := (width + 7) / 8
-	dataSize := bytesPerRow * height
-	data := make([]byte, dataSize)
+	data := make([]byte, bytesPerRow*height)
 
 	for y := 0; y < height; y++ {
+		pix := img.Pix[img.PixOffset(img.Rect.Min.X, img.Rect.Min.Y+y):]
+		row := data[y*bytesPerRow:]
 		for x := 0; x < width; x++ {
-			// Get pixel color
-			c := img.At(x, y)
-			r, _, _, _ := c.RGBA()
-
-			// If pixel is black (0), set the bit
-			// In binary mode, 0 is black, and 1 is white
-			if r == 0 {
-				// Calculate byte position and bit position within byte
-				bytePos := y*bytesPerRow + x/8
-				bitPos := uint(7 - (x % 8)) // MSB first
-
-				data[bytePos] |= 1 << bitPos
+			// In binary mode, 0 is black, and 1 is white: black pixels,
+			// or transparent ones, set their bit, MSB first
+			if pix[x*4] == 0 || pix[x*4+3] == 0 {
+				row[x/8] |= 0x80 >> (x % 8)
 			}
 		}
 	}
 
 	return data
 }
-
-// intLowHigh generates multiple bytes for a number: In lower and higher parts, or more parts as needed.
-func intLowHigh(inpNumber int, outBytes int) ([]byte, error) {
-	if outBytes < 1 || outBytes > 4 {
-		return nil, fmt.Errorf("can only output 1-4 bytes")
-	}
-
-	maxInput := 256<<(outBytes*8) - 1
-	if inpNumber < 0 || inpNumber > maxInput {
-		return nil, fmt.Errorf("number too large. Can only output up to %d in %d bytes", maxInput, outBytes)
-	}
-
-	out := make([]byte, outBytes)
-	for i := 0; i < outBytes; i++ {
-		out[i] = byte(inpNumber % 256)
-		inpNumber /= 256
-	}
-
-	return out, nil
-}
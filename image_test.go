@@ -0,0 +1,98 @@
+package escpos
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/kovidgoyal/imaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// referenceDither is the per-pixel Floyd-Steinberg dithering the Pix based
+// implementation must match
+func referenceDither(img image.Image) *image.NRGBA {
+	binary := imaging.New(img.Bounds().Dx(), img.Bounds().Dy(), color.White)
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	errors := make([][]float64, height)
+	for i := range errors {
+		errors[i] = make([]float64, width)
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, _, _, _ := img.At(x, y).RGBA()
+			oldPixel := float64(r>>8) + errors[y][x]
+			newPixel := 0.0
+			if oldPixel >= 128 {
+				newPixel = 255.0
+				binary.Set(x, y, color.Black)
+			}
+			quantError := oldPixel - newPixel
+			if x+1 < width {
+				errors[y][x+1] += quantError * 7.0 / 16.0
+			}
+			if y+1 < height {
+				if x-1 >= 0 {
+					errors[y+1][x-1] += quantError * 3.0 / 16.0
+				}
+				errors[y+1][x] += quantError * 5.0 / 16.0
+				if x+1 < width {
+					errors[y+1][x+1] += quantError * 1.0 / 16.0
+				}
+			}
+		}
+	}
+	return binary
+}
+
+// gradientImage returns a horizontal gradient with a fading alpha
+func gradientImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 255 / width), G: uint8(y), B: 90, A: uint8(255 - y%64)})
+		}
+	}
+	return img
+}
+
+// TestApplyFloydSteinbergDithering tests that the dithering matches the per-pixel reference
+func TestApplyFloydSteinbergDithering(t *testing.T) {
+	img := gradientImage(53, 40)
+	assert.Equal(t, referenceDither(img).Pix, applyFloydSteinbergDithering(img).Pix)
+
+	// Other image types are converted first
+	gray := imaging.Grayscale(img)
+	assert.Equal(t, referenceDither(gray).Pix, applyFloydSteinbergDithering(image.Image(gray)).Pix)
+}
+
+// TestRasterizeImage tests the bits of the black and transparent pixels
+func TestRasterizeImage(t *testing.T) {
+	img := imaging.New(10, 2, color.White)
+	img.Set(0, 0, color.Black)
+	img.Set(9, 0, color.Black)
+	img.Set(1, 1, color.Transparent)
+
+	assert.Equal(t, []byte{0x80, 0x40, 0x40, 0x00}, rasterizeImage(img))
+}
+
+// TestTransformImageSubImage tests the images not starting at the origin
+func TestTransformImageSubImage(t *testing.T) {
+	img := gradientImage(64, 64).SubImage(image.Rect(8, 8, 40, 24))
+	r, err := ditherImage(img, true, true)
+	require.NoError(t, err)
+	assert.Equal(t, 4, r.widthBytes)
+	assert.Equal(t, 16, r.height)
+}
+
+// BenchmarkDitherImage measures the processing of a full width receipt image
+func BenchmarkDitherImage(b *testing.B) {
+	img := gradientImage(576, 2000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := ditherImage(img, true, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package escpos
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/kovidgoyal/imaging"
+)
+
+// ImageOptions holds the parameters of PrintImageWithOptions
+type ImageOptions struct {
+	// Process is ImageProcessDither or ImageProcessThreshold
+	Process uint8
+	// HighDensityVertical and HighDensityHorizontal select the full
+	// resolution of the printer, see PrintImageWithProcessing
+	HighDensityVertical, HighDensityHorizontal bool
+	// Threshold is the luminance, 1-255, under which a pixel is printed black
+	// by ImageProcessThreshold (0: 128). Raise it for pale logos printed
+	// blank, lower it for dark ones printed solid black.
+	Threshold uint8
+	// Brightness and Contrast adjust the image before processing, in
+	// percents from -100 to 100 (0: unchanged)
+	Brightness, Contrast float64
+	// Gamma corrects the image before processing: below 1 darkens the mid
+	// tones, above 1 lightens them (0: unchanged)
+	Gamma float64
+	// Crop trims the white or transparent borders of the image before
+	// processing, as exported logos often carry large margins wasting paper
+	Crop bool
+	// Align places the image on the paper by padding its raster data with
+	// white dots, as ESC a does not move raster images on every firmware
+	// (JustifyLeft: no padding)
+	Align Justify
+}
+
+// PrintImageWithOptions prints an image with the processing and placement of opts
+func (e *Escpos) PrintImageWithOptions(img image.Image, opts ImageOptions) (int, error) {
+	raster, err := processImage(img, opts)
+	if err != nil {
+		return 0, err
+	}
+	return e.printRaster(raster.aligned(opts.Align, e.PrintWidth()))
+}
+
+// validate checks the options before processing an image
+func (opts ImageOptions) validate() error {
+	if opts.Align > JustifyRight {
+		return fmt.Errorf("invalid image alignment: %d", opts.Align)
+	}
+	if opts.Brightness < -100 || opts.Brightness > 100 {
+		return fmt.Errorf("image brightness %g out of range -100 to 100", opts.Brightness)
+	}
+	if opts.Contrast < -100 || opts.Contrast > 100 {
+		return fmt.Errorf("image contrast %g out of range -100 to 100", opts.Contrast)
+	}
+	if opts.Gamma < 0 {
+		return fmt.Errorf("invalid image gamma: %g", opts.Gamma)
+	}
+	return nil
+}
+
+// adjusted crops img and applies the brightness, contrast and gamma
+// adjustments to it
+func (opts ImageOptions) adjusted(img image.Image) image.Image {
+	if opts.Crop {
+		img = cropBorders(img)
+	}
+	if opts.Brightness != 0 {
+		img = imaging.AdjustBrightness(img, opts.Brightness)
+	}
+	if opts.Contrast != 0 {
+		img = imaging.AdjustContrast(img, opts.Contrast)
+	}
+	if opts.Gamma != 0 && opts.Gamma != 1 {
+		img = imaging.AdjustGamma(img, opts.Gamma)
+	}
+	return img
+}
+
+// Luminance from which a pixel is part of a border trimmed by Crop, tolerating
+// the noise of JPEG images
+const cropLuminance = 245
+
+// cropBorders trims the rows and columns of white or transparent pixels
+// around an image. Blank images are left as is.
+func cropBorders(img image.Image) image.Image {
+	src, ok := img.(*image.NRGBA)
+	if !ok {
+		src = imaging.Clone(img)
+	}
+	width, height := src.Rect.Dx(), src.Rect.Dy()
+	blank := func(x, y int) bool {
+		i := y*src.Stride + x*4
+		p := src.Pix[i : i+4]
+		if p[3] == 0 {
+			return true
+		}
+		// Flattened on white paper
+		flatten := func(v uint8) int {
+			return (int(v)*int(p[3]) + 255*(255-int(p[3]))) / 255
+		}
+		luminance := (299*flatten(p[0]) + 587*flatten(p[1]) + 114*flatten(p[2])) / 1000
+		return luminance >= cropLuminance
+	}
+
+	minX, minY, maxX, maxY := width, height, -1, -1
+	for y := range height {
+		for x := range width {
+			if blank(x, y) {
+				continue
+			}
+			minX, maxX = min(minX, x), max(maxX, x)
+			minY, maxY = min(minY, y), max(maxY, y)
+		}
+	}
+	if maxX < 0 || (minX == 0 && minY == 0 && maxX == width-1 && maxY == height-1) {
+		return img
+	}
+	return imaging.Crop(src, image.Rect(minX, minY, maxX+1, maxY+1).Add(src.Rect.Min))
+}
+
+// threshold returns the luminance threshold of ImageProcessThreshold
+func (opts ImageOptions) threshold() int {
+	if opts.Threshold == 0 {
+		return 128
+	}
+	return int(opts.Threshold)
+}
+
+// aligned pads the rows of the image with white bytes to the print width of
+// width dots, placing the image as align. The image fills the whole width so
+// the justification of the printer cannot move it again.
+func (r rasterImage) aligned(align Justify, width int) rasterImage {
+	dotsPerByte := r.printedWidth() / max(r.widthBytes, 1)
+	free := width/dotsPerByte - r.widthBytes
+	if align == JustifyLeft || free <= 0 {
+		return r
+	}
+	left := free
+	if align == JustifyCenter {
+		left = free / 2
+	}
+
+	widthBytes := r.widthBytes + free
+	data := make([]byte, widthBytes*r.height)
+	for y := range r.height {
+		copy(data[y*widthBytes+left:], r.data[y*r.widthBytes:(y+1)*r.widthBytes])
+	}
+	if r.width > 0 {
+		r.width += free * 8
+	}
+	r.widthBytes, r.data = widthBytes, data
+	return r
+}
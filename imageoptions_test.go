@@ -0,0 +1,124 @@
+package escpos
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintImageWithOptionsAlign tests the padding of the aligned images
+func TestPrintImageWithOptionsAlign(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 1))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.Black}, image.Point{}, draw.Src)
+
+	for align, row := range map[Justify][]byte{
+		JustifyLeft:   {0xff, 0xff},
+		JustifyCenter: {0, 0, 0, 0xff, 0xff, 0, 0, 0},
+		JustifyRight:  {0, 0, 0, 0, 0, 0, 0xff, 0xff},
+	} {
+		mock := NewMockPrinter()
+		p := New(mock)
+		p.printWidthDots = 64
+
+		_, err := p.PrintImageWithOptions(img, ImageOptions{Align: align, HighDensityVertical: true, HighDensityHorizontal: true})
+		require.NoError(t, err)
+		require.NoError(t, p.Print())
+
+		expected := append([]byte{gs, 'v', '0', 0, byte(len(row)), 0, 1, 0}, row...)
+		assert.Equal(t, expected, mock.Bytes(), "align %d", align)
+	}
+}
+
+// TestPrintImageWithOptionsWide tests that images as wide as the paper are not padded
+func TestPrintImageWithOptionsWide(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 1))
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.printWidthDots = 64
+
+	_, err := p.PrintImageWithOptions(img, ImageOptions{Align: JustifyCenter, HighDensityVertical: true, HighDensityHorizontal: true})
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, []byte{gs, 'v', '0', 0, 8, 0, 1, 0}, mock.Bytes()[:8])
+
+	_, err = p.PrintImageWithOptions(img, ImageOptions{Align: 3})
+	assert.Error(t, err)
+}
+
+// TestRasterAlignedLowDensity tests the padding of images doubled in width
+func TestRasterAlignedLowDensity(t *testing.T) {
+	r := rasterImage{density: 1, widthBytes: 1, height: 1, data: []byte{0xff}}
+	aligned := r.aligned(JustifyRight, 64)
+	assert.Equal(t, 4, aligned.widthBytes)
+	assert.Equal(t, []byte{0, 0, 0, 0xff}, aligned.data)
+}
+
+// TestPrintImageWithOptionsAdjustments tests the threshold and the adjustments
+func TestPrintImageWithOptionsAdjustments(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.Gray{Y: 100}}, image.Point{}, draw.Src)
+
+	for name, tc := range map[string]struct {
+		opts  ImageOptions
+		black bool
+	}{
+		"default threshold": {ImageOptions{}, true},
+		"low threshold":     {ImageOptions{Threshold: 90}, false},
+		"brightness":        {ImageOptions{Brightness: 50}, false},
+		"gamma":             {ImageOptions{Gamma: 3}, false},
+		"contrast":          {ImageOptions{Contrast: 100, Threshold: 90}, true},
+	} {
+		mock := NewMockPrinter()
+		p := New(mock)
+		tc.opts.Process = ImageProcessThreshold
+		_, err := p.PrintImageWithOptions(img, tc.opts)
+		require.NoError(t, err, name)
+		require.NoError(t, p.Print())
+
+		row := byte(0)
+		if tc.black {
+			row = 0xff
+		}
+		assert.Equal(t, row, mock.Bytes()[8], name)
+	}
+}
+
+// TestImageOptionsValidate tests the rejected adjustments
+func TestImageOptionsValidate(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	p := New(NewMockPrinter())
+	for _, opts := range []ImageOptions{
+		{Brightness: 101},
+		{Contrast: -150},
+		{Gamma: -1},
+		{Process: 7},
+	} {
+		_, err := p.PrintImageWithOptions(img, opts)
+		assert.Error(t, err, "%+v", opts)
+	}
+}
+
+// TestImageOptionsCrop tests trimming the white and transparent borders
+func TestImageOptionsCrop(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	draw.Draw(img, image.Rect(0, 0, 40, 10), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(10, 5, 26, 7), &image.Uniform{C: color.Black}, image.Point{}, draw.Src)
+	img.Set(30, 6, color.Gray{Y: 250}) // JPEG noise
+
+	mock := NewMockPrinter()
+	p := New(mock)
+	_, err := p.PrintImageWithOptions(img, ImageOptions{Crop: true, HighDensityVertical: true, HighDensityHorizontal: true})
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, []byte{gs, 'v', '0', 0, 2, 0, 2, 0, 0xff, 0xff, 0xff, 0xff}, mock.Bytes())
+
+	// Sub-images and blank images
+	sub := img.SubImage(image.Rect(12, 4, 40, 20))
+	assert.Equal(t, image.Rect(0, 0, 14, 2), cropBorders(sub).Bounds())
+	blank := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	assert.Equal(t, blank, cropBorders(blank))
+}
@@ -0,0 +1,77 @@
+package escpos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+
+	"github.com/kovidgoyal/imaging"
+)
+
+// Maximum size of an image downloaded by PrintImageURL
+const maxImageDownload = 32 << 20
+
+// Maximum number of pixels of the images decoded from untrusted data, 64MB
+// once decoded
+const maxImagePixels = 16 << 20
+
+// PrintImageFile prints the PNG, JPEG, GIF, BMP or TIFF image of a file with
+// opts, rotated as its EXIF orientation says
+func (e *Escpos) PrintImageFile(path string, opts ImageOptions) (int, error) {
+	img, err := imaging.Open(path, imaging.AutoOrientation(true))
+	if err != nil {
+		return 0, fmt.Errorf("failed to open image: %w", err)
+	}
+	return e.PrintImageWithOptions(img, opts)
+}
+
+// PrintImageReader prints the image read from r with opts, see PrintImageFile
+// The images of more than 16M pixels are rejected before being decoded.
+func (e *Escpos) PrintImageReader(r io.Reader, opts ImageOptions) (int, error) {
+	r, err := checkImageSize(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode image: %w", err)
+	}
+	img, err := imaging.Decode(r, imaging.AutoOrientation(true))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return e.PrintImageWithOptions(img, opts)
+}
+
+// PrintImageURL downloads an image of at most 32MB with an HTTP GET request
+// and prints it with opts, see PrintImageReader. Nothing is printed when the
+// download fails or ctx is done first.
+func (e *Escpos) PrintImageURL(ctx context.Context, url string, opts ImageOptions) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("invalid image URL: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to download image: %s", resp.Status)
+	}
+
+	return e.PrintImageReader(io.LimitReader(resp.Body, maxImageDownload), opts)
+}
+
+// checkImageSize reads the header of the image of r, rejecting the images of
+// more than maxImagePixels pixels, and returns a reader of the whole image
+func checkImageSize(r io.Reader) (io.Reader, error) {
+	var header bytes.Buffer
+	config, _, err := image.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("image of %dx%d pixels exceeds the maximum of %d pixels", config.Width, config.Height, maxImagePixels)
+	}
+	return io.MultiReader(&header, r), nil
+}
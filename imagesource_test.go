@@ -0,0 +1,101 @@
+package escpos
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blackPNG returns a black 16x1 PNG image
+func blackPNG(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 1))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.Black}, image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// blackRaster is the command printing the image of blackPNG
+var blackRaster = []byte{gs, 'v', '0', 0, 2, 0, 1, 0, 0xff, 0xff}
+
+// highDensity are the image options of blackRaster
+var highDensity = ImageOptions{HighDensityVertical: true, HighDensityHorizontal: true}
+
+// TestPrintImageFile tests printing an image file
+func TestPrintImageFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logo.png")
+	require.NoError(t, os.WriteFile(path, blackPNG(t), 0o600))
+
+	mock := NewMockPrinter()
+	p := New(mock)
+	_, err := p.PrintImageFile(path, highDensity)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, blackRaster, mock.Bytes())
+
+	_, err = p.PrintImageFile(filepath.Join(t.TempDir(), "missing.png"), highDensity)
+	assert.Error(t, err)
+}
+
+// TestPrintImageReader tests printing an image read from a reader
+func TestPrintImageReader(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	_, err := p.PrintImageReader(bytes.NewReader(blackPNG(t)), highDensity)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, blackRaster, mock.Bytes())
+
+	_, err = p.PrintImageReader(strings.NewReader("not an image"), highDensity)
+	assert.Error(t, err)
+
+	// Rejected from its header, before being decoded
+	_, err = p.PrintImageReader(bytes.NewReader(pngHeader(50000, 50000)), highDensity)
+	assert.ErrorContains(t, err, "exceeds the maximum")
+}
+
+// TestPrintImageURL tests printing a downloaded image
+func TestPrintImageURL(t *testing.T) {
+	data := blackPNG(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/logo.png":
+			w.Write(data)
+		case "/huge.png":
+			w.Write(pngHeader(50000, 50000))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	mock := NewMockPrinter()
+	p := New(mock)
+	_, err := p.PrintImageURL(context.Background(), server.URL+"/logo.png", highDensity)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, blackRaster, mock.Bytes())
+
+	_, err = p.PrintImageURL(context.Background(), server.URL+"/missing.png", highDensity)
+	assert.ErrorContains(t, err, "404")
+
+	_, err = p.PrintImageURL(context.Background(), server.URL+"/huge.png", highDensity)
+	assert.ErrorContains(t, err, "exceeds the maximum")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = p.PrintImageURL(ctx, server.URL+"/logo.png", highDensity)
+	assert.Error(t, err)
+}
@@ -0,0 +1,147 @@
+package escpos
+
+import (
+	"image"
+	"image/color"
+	"io"
+)
+
+// Default number of rows encoded at once by ImageStream
+const defaultStreamBand = 256
+
+// ImageStream is an io.Reader of the commands printing an image, dithered
+// like ImageProcessDither and encoded one band of rows at a time: only the
+// band being read is held in memory, so huge images, such as end-of-day
+// reports rendered as graphics, can be piped straight to the printer. The
+// image may compute its pixels on demand.
+type ImageStream struct {
+	img     image.Image
+	command RasterCommand
+	band    int
+
+	y         int       // next row to dither
+	cur, next []float64 // dithering errors of the next row and the one after
+	pending   []byte    // encoded data not read yet
+}
+
+// NewImageStream returns a stream of the raster commands of command printing
+// img in bands of bandHeight rows (0: 256 rows)
+func NewImageStream(img image.Image, command RasterCommand, bandHeight int) *ImageStream {
+	if bandHeight <= 0 {
+		bandHeight = defaultStreamBand
+	}
+	width := img.Bounds().Dx()
+	return &ImageStream{
+		img:     img,
+		command: command,
+		band:    bandHeight,
+		cur:     make([]float64, width),
+		next:    make([]float64, width),
+	}
+}
+
+// Read reads the encoded commands of the image
+func (s *ImageStream) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		band, ok := s.nextBand()
+		if !ok {
+			return 0, io.EOF
+		}
+		cmd, err := band.commandFor(s.command)
+		if err != nil {
+			return 0, err
+		}
+		s.pending = cmd
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// nextBand dithers the next band of rows, false once the image is done
+func (s *ImageStream) nextBand() (rasterImage, bool) {
+	bounds := s.img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if s.y >= height || width == 0 {
+		return rasterImage{}, false
+	}
+
+	rows := min(s.band, height-s.y)
+	r := rasterImage{widthBytes: (width + 7) / 8, width: width, height: rows}
+	r.data = make([]byte, r.widthBytes*rows)
+	for i := range rows {
+		s.ditherRow(r.data[i*r.widthBytes:(i+1)*r.widthBytes], bounds.Min.Y+s.y, s.y+1 < height)
+		s.y++
+	}
+	return r, true
+}
+
+// ditherRow dithers the row y of the image into out, spreading the errors on
+// the next row when there is one
+func (s *ImageStream) ditherRow(out []byte, y int, hasNext bool) {
+	bounds := s.img.Bounds()
+	width := bounds.Dx()
+	for x := 0; x < width; x++ {
+		oldPixel := float64(darkness(s.img, bounds.Min.X+x, y)) + s.cur[x]
+		newPixel := 0.0
+		if oldPixel >= 128 {
+			newPixel = 255.0
+			out[x/8] |= 0x80 >> (x % 8)
+		}
+
+		quantError := oldPixel - newPixel
+		if x+1 < width {
+			s.cur[x+1] += quantError * 7.0 / 16.0
+		}
+		if hasNext {
+			if x-1 >= 0 {
+				s.next[x-1] += quantError * 3.0 / 16.0
+			}
+			s.next[x] += quantError * 5.0 / 16.0
+			if x+1 < width {
+				s.next[x+1] += quantError * 1.0 / 16.0
+			}
+		}
+	}
+	s.cur, s.next = s.next, s.cur
+	clear(s.next)
+}
+
+// darkness returns the darkness of a pixel flattened on white paper, 0
+// (white) to 255 (black)
+func darkness(img image.Image, x, y int) uint8 {
+	var c color.NRGBA
+	switch src := img.(type) {
+	case *image.NRGBA:
+		i := src.PixOffset(x, y)
+		c = color.NRGBA{R: src.Pix[i], G: src.Pix[i+1], B: src.Pix[i+2], A: src.Pix[i+3]}
+	case *image.Gray:
+		return 255 - src.Pix[src.PixOffset(x, y)]
+	default:
+		c = color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+	}
+
+	flatten := func(v uint8) float64 {
+		return (float64(v)*float64(c.A) + 255*float64(255-c.A)) / 255
+	}
+	luminance := 0.299*flatten(c.R) + 0.587*flatten(c.G) + 0.114*flatten(c.B)
+	return 255 - uint8(luminance+0.5)
+}
+
+// PrintImageStream prints an image dithered one band at a time, see
+// ImageStream, without holding its whole raster in memory
+func (e *Escpos) PrintImageStream(img image.Image) (int, error) {
+	s := NewImageStream(img, e.profile.RasterCommand, e.profile.MaxImageHeight)
+	written := 0
+	for {
+		band, ok := s.nextBand()
+		if !ok {
+			return written, nil
+		}
+		n, err := e.printRaster(band)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+}
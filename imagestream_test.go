@@ -0,0 +1,64 @@
+package escpos
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestImageStreamBands tests the commands of the bands of an image
+func TestImageStreamBands(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 3))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.Black}, image.Point{}, draw.Src)
+
+	data, err := io.ReadAll(iotest.OneByteReader(NewImageStream(img, RasterGSv0, 2)))
+	require.NoError(t, err)
+
+	expected := []byte{gs, 'v', '0', 0, 2, 0, 2, 0, 0xff, 0xff, 0xff, 0xff}
+	expected = append(expected, gs, 'v', '0', 0, 2, 0, 1, 0, 0xff, 0xff)
+	assert.Equal(t, expected, data)
+
+	data, err = io.ReadAll(NewImageStream(img, RasterGraphics, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{gs, '(', 'L', 16, 0, 48, 112, 48, 1, 1, 49, 16, 0, 3, 0}, data[:15])
+}
+
+// TestImageStreamMatchesDither tests that streaming dithers like ImageProcessDither
+func TestImageStreamMatchesDither(t *testing.T) {
+	img := gradientImage(53, 40)
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+
+	r, err := ditherImage(img, true, true)
+	require.NoError(t, err)
+	data, err := io.ReadAll(NewImageStream(img, RasterGSv0, 7))
+	require.NoError(t, err)
+
+	var dithered []byte
+	for _, band := range r.split(7) {
+		cmd, err := band.command()
+		require.NoError(t, err)
+		dithered = append(dithered, cmd...)
+	}
+	assert.Equal(t, dithered, data)
+}
+
+// TestPrintImageStream tests printing a stream in bands of the profile height
+func TestPrintImageStream(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{MaxImageHeight: 2})
+
+	_, err := p.PrintImageStream(image.NewGray(image.Rect(0, 0, 8, 5)))
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	assert.Equal(t, 3, mock.CountCommand([]byte{gs, 'v', '0'}))
+}
@@ -0,0 +1,148 @@
+package escpos
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// CutMode is the cut after each copy printed by PrintCopies
+type CutMode uint8
+
+const (
+	// CutNone leaves the copies uncut, for jobs ending with their own cut
+	CutNone CutMode = iota
+	// CutFull fully cuts the paper after each copy
+	CutFull
+	// CutPartial partially cuts the paper after each copy
+	CutPartial
+)
+
+// ErrJobDone is returned by Commit and Abort on a job already committed or aborted
+var ErrJobDone = errors.New("job already committed or aborted")
+
+// Job is a print job built with the methods of Escpos in memory: nothing
+// reaches the printer until Commit, and Abort discards it, so a receipt
+// failing midway is never half printed.
+type Job struct {
+	*Escpos
+	parent *Escpos
+	buf    *documentBuffer
+	done   bool
+}
+
+// BeginJob starts a job with the current style, encoding and settings of the
+// printer. Data buffered before BeginJob is not part of the job and is sent
+// by the next Print. Status queries are not available in a job.
+func (e *Escpos) BeginJob() *Job {
+	buf := &documentBuffer{}
+	out := &countingWriter{w: buf}
+
+	job := *e
+	job.dst = bufio.NewWriter(out)
+	job.out = out
+	job.reader = buf
+	job.logger, job.metrics = nil, nil
+	job.warnings = slices.Clone(e.warnings)
+	job.nvImages = slices.Clone(e.nvImages)
+	return &Job{Escpos: &job, parent: e, buf: buf}
+}
+
+// Commit sends the job to the printer and applies the style and settings it
+// left to the printer. The job cannot be used afterwards.
+func (j *Job) Commit() error {
+	return j.commit(1, CutNone)
+}
+
+// PrintCopies commits the job n times, cut as cutMode after each copy, such
+// as a merchant copy and a customer copy, without composing it again. The
+// style of the printer at BeginJob is restored before each copy.
+func (j *Job) PrintCopies(n int, cutMode CutMode) error {
+	if n < 1 {
+		return fmt.Errorf("invalid number of copies: %d", n)
+	}
+	if cutMode > CutPartial {
+		return fmt.Errorf("invalid cut mode: %d", cutMode)
+	}
+	return j.commit(n, cutMode)
+}
+
+// commit sends the job n times, then applies its state to the printer
+func (j *Job) commit(n int, cutMode CutMode) error {
+	if _, err := j.apply(n, cutMode); err != nil {
+		return err
+	}
+	return j.parent.Print()
+}
+
+// apply writes the job n times to the buffer of the printer and applies its
+// state to the printer, without sending the buffer
+func (j *Job) apply(n int, cutMode CutMode) (int, error) {
+	if j.done {
+		return 0, ErrJobDone
+	}
+	j.done = true
+	if j.err != nil {
+		return 0, j.err
+	}
+	if err := j.dst.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to build job: %w", err)
+	}
+
+	p := j.parent
+	start := p.Style
+	written := 0
+	for i := range n {
+		if i > 0 {
+			// The previous copy left the style of the end of the job
+			p.Style = j.Style
+			n, err := p.ApplyStyle(start)
+			written += n
+			if err != nil {
+				return written, err
+			}
+		}
+		n, err := p.WriteRaw(j.buf.Bytes())
+		written += n
+		if err != nil {
+			return written, err
+		}
+		n, err = p.cutCopy(cutMode)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+
+	// The job state replaces the printer state, except for the connection
+	state := *j.Escpos
+	state.dst, state.out, state.reader = p.dst, p.out, p.reader
+	state.logger, state.metrics = p.logger, p.metrics
+	state.job, state.jobStart, state.reportedStatus = p.job, p.jobStart, p.reportedStatus
+	state.lastStatusType, state.lastStatus, state.lastStatusTime = p.lastStatusType, p.lastStatus, p.lastStatusTime
+	state.err = p.err
+	*p = state
+	return written, nil
+}
+
+// cutCopy cuts the paper as cutMode after a copy
+func (e *Escpos) cutCopy(cutMode CutMode) (int, error) {
+	switch cutMode {
+	case CutFull:
+		return e.Cut()
+	case CutPartial:
+		return e.PartialCut()
+	}
+	return 0, nil
+}
+
+// Abort discards the job, leaving the printer as it was before BeginJob
+func (j *Job) Abort() error {
+	if j.done {
+		return ErrJobDone
+	}
+	j.done = true
+	j.buf.Reset()
+	return nil
+}
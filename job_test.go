@@ -0,0 +1,101 @@
+package escpos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJobCommit tests that a job reaches the printer on Commit only
+func TestJobCommit(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	job := p.BeginJob()
+	job.SetBold(true)
+	job.Write("Total")
+	assert.Empty(t, mock.Bytes())
+	assert.False(t, p.Style.Bold)
+
+	require.NoError(t, job.Commit())
+	assert.Equal(t, "\x1bE\x01Total", mock.String())
+	assert.True(t, p.Style.Bold)
+
+	assert.ErrorIs(t, job.Commit(), ErrJobDone)
+	assert.ErrorIs(t, job.Abort(), ErrJobDone)
+}
+
+// TestJobAbort tests that an aborted job leaves the printer unchanged
+func TestJobAbort(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.Write("before ")
+
+	job := p.BeginJob()
+	job.SetBold(true)
+	job.Write("half a receipt")
+	require.NoError(t, job.Abort())
+	assert.ErrorIs(t, job.Commit(), ErrJobDone)
+
+	require.NoError(t, p.Print())
+	assert.Equal(t, "before ", mock.String())
+	assert.False(t, p.Style.Bold)
+}
+
+// TestJobCommitError tests reporting a failed write on Commit
+func TestJobCommitError(t *testing.T) {
+	mock := NewMockPrinter()
+	mock.FailWrites(errors.New("offline"))
+	p := New(mock)
+
+	job := p.BeginJob()
+	job.Write("Total")
+	assert.Error(t, job.Commit())
+}
+
+// TestJobMetrics tests that a committed job is reported once
+func TestJobMetrics(t *testing.T) {
+	metrics := &recordingMetrics{}
+	p := New(NewMockPrinter(), WithMetrics(metrics))
+
+	job := p.BeginJob()
+	job.Write("Total\n")
+	require.NoError(t, job.Print())
+	assert.Empty(t, metrics.jobs)
+
+	require.NoError(t, job.Commit())
+	assert.Equal(t, []error{nil}, metrics.jobs)
+}
+
+// TestJobPrintCopies tests printing the copies of a job, cut between them
+func TestJobPrintCopies(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	job := p.BeginJob()
+	job.SetBold(true)
+	job.Write("Copy\n")
+	require.NoError(t, job.PrintCopies(2, CutPartial))
+
+	receipt := "\x1bE\x01Copy\n"
+	cut := "\x1dVB\x00"
+	assert.Equal(t, receipt+cut+"\x1bE\x00"+receipt+cut, mock.String())
+	assert.True(t, p.Style.Bold)
+	assert.ErrorIs(t, job.PrintCopies(2, CutFull), ErrJobDone)
+}
+
+// TestJobPrintCopiesInvalid tests the rejected copies
+func TestJobPrintCopiesInvalid(t *testing.T) {
+	p := New(NewMockPrinter())
+	assert.Error(t, p.BeginJob().PrintCopies(0, CutFull))
+	assert.Error(t, p.BeginJob().PrintCopies(2, 5))
+
+	mock := NewMockPrinter()
+	p = New(mock)
+	job := p.BeginJob()
+	job.Write("Ticket\n")
+	require.NoError(t, job.PrintCopies(3, CutNone))
+	assert.Equal(t, "Ticket\nTicket\nTicket\n", mock.String())
+}
@@ -0,0 +1,300 @@
+package escpos
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/kovidgoyal/imaging"
+)
+
+// JSONReceipt is the JSON document printed by PrintJSON:
+//
+//	{"elements": [
+//		{"type": "text", "text": "MY SHOP", "align": "center", "bold": true, "width": 2, "height": 2},
+//		{"type": "row", "columns": [
+//			{"text": "2", "width": 3, "align": "right"},
+//			{"text": "Croissant"},
+//			{"text": "3.00", "percent": 25, "align": "right"}]},
+//		{"type": "image", "image": "<base64 PNG or JPEG>", "align": "center"},
+//		{"type": "barcode", "symbology": "EAN13", "data": "4006381333931"},
+//		{"type": "qr", "data": "https://example.com/r/42", "size": 6, "level": "M"},
+//		{"type": "cut", "partial": true},
+//		{"type": "drawer", "pin": 0}
+//	]}
+type JSONReceipt struct {
+	Elements []JSONElement `json:"elements"`
+}
+
+// JSONElement is an element of a JSONReceipt. Type selects the element and
+// the fields it uses:
+//   - text: a line of Text, with Align, Bold, Underline (0-2), Reverse, Width and Height (1-8)
+//   - row: a line of Columns, laid out as a table row
+//   - image: Image, a base64 PNG or JPEG scaled down to the print width, with Align
+//   - barcode: Data encoded with Symbology (UPCA, UPCE, EAN13, EAN8, CODE39,
+//     ITF, CODABAR, CODE93 or CODE128), with Align
+//   - qr: Data encoded as a QR code of module Size (1-16, default 6) and
+//     error correction Level (L, M, Q or H, default M), with Align
+//   - cut: a cut, Partial or full
+//   - drawer: a pulse on the drawer Pin (0 or 1) of Pulse (1-8, default 2) times 100ms
+type JSONElement struct {
+	Type string `json:"type"`
+
+	Text      string `json:"text,omitempty"`
+	Align     string `json:"align,omitempty"`
+	Bold      bool   `json:"bold,omitempty"`
+	Underline uint8  `json:"underline,omitempty"`
+	Reverse   bool   `json:"reverse,omitempty"`
+	Width     uint8  `json:"width,omitempty"`
+	Height    uint8  `json:"height,omitempty"`
+
+	Columns []JSONColumn `json:"columns,omitempty"`
+
+	Image     string `json:"image,omitempty"`
+	Symbology string `json:"symbology,omitempty"`
+	Data      string `json:"data,omitempty"`
+	Size      uint8  `json:"size,omitempty"`
+	Level     string `json:"level,omitempty"`
+
+	Partial bool  `json:"partial,omitempty"`
+	Pin     uint8 `json:"pin,omitempty"`
+	Pulse   uint8 `json:"pulse,omitempty"`
+}
+
+// JSONColumn is a column of a row element: its Text is laid out in Width
+// characters, or Percent percent of the line, or shares the rest of the line
+// with the other columns without either
+type JSONColumn struct {
+	Text    string `json:"text"`
+	Width   int    `json:"width,omitempty"`
+	Percent int    `json:"percent,omitempty"`
+	Align   string `json:"align,omitempty"`
+	Wrap    bool   `json:"wrap,omitempty"`
+}
+
+// Barcode types of the JSON barcode elements
+var jsonSymbologies = map[string]uint8{
+	"UPCA":    BarcodeUPCA,
+	"UPCE":    BarcodeUPCE,
+	"EAN13":   BarcodeEAN13,
+	"EAN8":    BarcodeEAN8,
+	"CODE39":  BarcodeCode39,
+	"ITF":     BarcodeITF,
+	"CODABAR": BarcodeCodabar,
+	"CODE93":  BarcodeBCode93,
+	"CODE128": BarcodeBCode128Auto,
+}
+
+// QR code error correction levels of the JSON qr elements
+var jsonQRLevels = map[string]uint8{
+	"":  QRCodeErrorCorrectionLevelM,
+	"L": QRCodeErrorCorrectionLevelL,
+	"M": QRCodeErrorCorrectionLevelM,
+	"Q": QRCodeErrorCorrectionLevelQ,
+	"H": QRCodeErrorCorrectionLevelH,
+}
+
+// PrintJSON validates a JSONReceipt document and prints it. Nothing is
+// printed when the document is invalid or an element fails to print, such as
+// a barcode of invalid data: the error names the first invalid element.
+func (e *Escpos) PrintJSON(doc []byte) (int, error) {
+	var r JSONReceipt
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&r); err != nil {
+		return 0, fmt.Errorf("invalid JSON receipt: %w", err)
+	}
+
+	images := make([]image.Image, len(r.Elements))
+	for i, el := range r.Elements {
+		img, err := el.validate()
+		if err != nil {
+			return 0, fmt.Errorf("invalid JSON receipt: element %d (%s): %w", i, el.Type, err)
+		}
+		images[i] = img
+	}
+
+	// Elements failing when printed, such as invalid barcode data, discard
+	// the job before anything reaches the buffer of the printer
+	job := e.BeginJob()
+	for i, el := range r.Elements {
+		if _, err := job.printJSONElement(el, images[i]); err != nil {
+			job.Abort()
+			return 0, fmt.Errorf("failed to print element %d (%s): %w", i, el.Type, err)
+		}
+	}
+	return job.apply(1, CutNone)
+}
+
+// validate checks an element, returning the decoded image of image elements
+func (el JSONElement) validate() (image.Image, error) {
+	if _, err := jsonJustify(el.Align); err != nil {
+		return nil, err
+	}
+	switch el.Type {
+	case "text":
+		if el.Underline > 2 {
+			return nil, fmt.Errorf("underline %d out of range 0-2", el.Underline)
+		}
+		if el.Width > 8 || el.Height > 8 {
+			return nil, fmt.Errorf("size %dx%d out of range 1-8", el.Width, el.Height)
+		}
+	case "row":
+		if len(el.Columns) == 0 {
+			return nil, fmt.Errorf("row has no columns")
+		}
+		for _, c := range el.Columns {
+			if _, err := jsonJustify(c.Align); err != nil {
+				return nil, err
+			}
+		}
+	case "image":
+		return decodeImageData(el.Image)
+	case "barcode":
+		if _, ok := jsonSymbologies[strings.ToUpper(el.Symbology)]; !ok {
+			return nil, fmt.Errorf("unknown symbology %q", el.Symbology)
+		}
+		if el.Data == "" {
+			return nil, fmt.Errorf("barcode has no data")
+		}
+	case "qr":
+		if _, ok := jsonQRLevels[strings.ToUpper(el.Level)]; !ok {
+			return nil, fmt.Errorf("unknown error correction level %q", el.Level)
+		}
+		if el.Size > 16 {
+			return nil, fmt.Errorf("size %d out of range 1-16", el.Size)
+		}
+		if el.Data == "" {
+			return nil, fmt.Errorf("QR code has no data")
+		}
+	case "cut":
+	case "drawer":
+		if el.Pin > 1 {
+			return nil, fmt.Errorf("drawer pin %d out of range 0-1", el.Pin)
+		}
+		if el.Pulse > 8 {
+			return nil, fmt.Errorf("pulse %d out of range 1-8", el.Pulse)
+		}
+	default:
+		return nil, fmt.Errorf("unknown element type")
+	}
+	return nil, nil
+}
+
+// printJSONElement prints a validated element
+func (e *Escpos) printJSONElement(el JSONElement, img image.Image) (int, error) {
+	justify, _ := jsonJustify(el.Align)
+
+	switch el.Type {
+	case "text":
+		s := Style{
+			Bold:      el.Bold,
+			Underline: el.Underline,
+			Reverse:   el.Reverse,
+			Width:     el.Width,
+			Height:    el.Height,
+			Justify:   justify,
+		}
+		return e.WriteStyled(strings.TrimSuffix(el.Text, "\n")+"\n", s)
+	case "row":
+		cols := make([]Column, len(el.Columns))
+		cells := make([]string, len(el.Columns))
+		for i, c := range el.Columns {
+			align, _ := jsonJustify(c.Align)
+			cols[i] = Column{Width: c.Width, Percent: c.Percent, Align: align}
+			if c.Wrap {
+				cols[i].Overflow = OverflowWrap
+			}
+			cells[i] = c.Text
+		}
+		t := NewTable(cols...)
+		if err := t.AddRow(cells...); err != nil {
+			return 0, err
+		}
+		return e.WriteTable(t)
+	case "image":
+		return e.printFitted(img, justify)
+	}
+
+	written := 0
+	err := e.WithStyle(Style{Justify: justify}, func() error {
+		var n int
+		var err error
+		switch el.Type {
+		case "barcode":
+			n, err = e.printSymbology(jsonSymbologies[strings.ToUpper(el.Symbology)], el.Data)
+		case "qr":
+			size := el.Size
+			if size == 0 {
+				size = 6
+			}
+			n, err = e.QRCode(el.Data, QRCodeModel2, size, jsonQRLevels[strings.ToUpper(el.Level)])
+		case "cut":
+			if el.Partial {
+				n, err = e.PartialCut()
+			} else {
+				n, err = e.Cut()
+			}
+		case "drawer":
+			pulse := el.Pulse
+			if pulse == 0 {
+				pulse = 2
+			}
+			n, err = e.OpenDrawer(el.Pin, pulse)
+		}
+		written += n
+		return err
+	})
+	return written, err
+}
+
+// jsonJustify maps the align attribute of an element to a justification
+func jsonJustify(align string) (Justify, error) {
+	switch strings.ToLower(align) {
+	case "", "left":
+		return JustifyLeft, nil
+	case "center":
+		return JustifyCenter, nil
+	case "right":
+		return JustifyRight, nil
+	}
+	return JustifyLeft, fmt.Errorf("unknown alignment %q", align)
+}
+
+// decodeImageData decodes a base64 PNG or JPEG image of at most
+// maxImagePixels pixels
+func decodeImageData(b64 string) (image.Image, error) {
+	data, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image data: %w", err)
+	}
+	r, err := checkImageSize(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+	img, err := imaging.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+	return img, nil
+}
+
+// printFitted prints an image dithered, scaled down to the print width and
+// placed as align
+func (e *Escpos) printFitted(img image.Image, align Justify) (int, error) {
+	if img.Bounds().Dx() > e.PrintWidth() {
+		img = imaging.Resize(img, e.PrintWidth(), 0, imaging.Lanczos)
+	}
+	return e.PrintImageWithOptions(img, ImageOptions{Process: ImageProcessDither, Align: align})
+}
+
+// printSymbology prints a barcode of a function A or function B symbology
+func (e *Escpos) printSymbology(symbology uint8, data string) (int, error) {
+	if symbology >= BarcodeBUPCA {
+		return e.BarcodeB(symbology, []byte(data))
+	}
+	return e.Barcode(symbology, data)
+}
@@ -0,0 +1,96 @@
+package escpos
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintJSON tests printing a receipt described in JSON
+func TestPrintJSON(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetCharsPerLine(20)
+
+	doc := `{"elements": [
+		{"type": "text", "text": "SHOP", "align": "center", "bold": true},
+		{"type": "row", "columns": [
+			{"text": "2", "width": 3, "align": "right"},
+			{"text": "Tea"},
+			{"text": "3.00", "width": 6, "align": "right"}]},
+		{"type": "barcode", "symbology": "ean13", "data": "4006381333931"},
+		{"type": "qr", "data": "https://example.com"},
+		{"type": "cut", "partial": true},
+		{"type": "drawer"}
+	]}`
+	_, err := p.PrintJSON([]byte(doc))
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	out := mock.String()
+	assert.Contains(t, out, "\x1bE\x01\x1ba\x01SHOP\n\x1bE\x00\x1ba\x00")
+	assert.Contains(t, out, "  2 Tea         3.00\n")
+	assert.Contains(t, out, "\x1dk\x024006381333931\x00")
+	assert.Contains(t, out, "\x1d(k\x16\x001P0https://example.com")
+	assert.Contains(t, out, "\x1dVB\x00")
+	assert.Contains(t, out, "\x1bp\x00\x02\x02")
+	assert.False(t, p.Style.Bold)
+}
+
+// TestPrintJSONImage tests printing an image element
+func TestPrintJSONImage(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 16, 8))))
+	doc := `{"elements": [{"type": "image", "image": "` + base64.StdEncoding.EncodeToString(buf.Bytes()) + `"}]}`
+
+	mock := NewMockPrinter()
+	p := New(mock)
+	_, err := p.PrintJSON([]byte(doc))
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Contains(t, mock.String(), "\x1dv0")
+}
+
+// pngHeader returns the start of a PNG image of the given size, enough for
+// image.DecodeConfig
+func pngHeader(width, height uint32) []byte {
+	ihdr := []byte("IHDR")
+	ihdr = binary.BigEndian.AppendUint32(ihdr, width)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, height)
+	ihdr = append(ihdr, 8, 2, 0, 0, 0)
+
+	data := []byte("\x89PNG\r\n\x1a\n")
+	data = binary.BigEndian.AppendUint32(data, uint32(len(ihdr)-4))
+	data = append(data, ihdr...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(ihdr))
+}
+
+// TestPrintJSONInvalid tests that nothing is printed from an invalid document
+func TestPrintJSONInvalid(t *testing.T) {
+	for doc, message := range map[string]string{
+		`{"elements": [{"type": "text", "text": "a"}, {"type": "table"}]}`:                                                 "element 1 (table): unknown element type",
+		`{"elements": [{"type": "barcode", "symbology": "PDF417", "data": "1"}]}`:                                          `unknown symbology "PDF417"`,
+		`{"elements": [{"type": "text", "text": "a", "align": "middle"}]}`:                                                 `unknown alignment "middle"`,
+		`{"elements": [{"type": "text", "colour": "red"}]}`:                                                                "unknown field",
+		`{"elements": [{"type": "image", "image": "aGVsbG8="}]}`:                                                           "invalid image",
+		`{"elements": [{"type": "qr", "data": "x", "level": "Z"}]}`:                                                        "unknown error correction level",
+		`{"elements": [{"type": "row", "columns": [{"text": "a", "align": "top"}]}]}`:                                      `unknown alignment "top"`,
+		`{"elements": [{"type": "text", "text": "PAID"}, {"type": "barcode", "symbology": "EAN13", "data": "abc"}]}`:       "EAN-13 code should have 12 or 13 digits",
+		`{"elements": [{"type": "image", "image": "` + base64.StdEncoding.EncodeToString(pngHeader(50000, 50000)) + `"}]}`: "exceeds the maximum",
+	} {
+		mock := NewMockPrinter()
+		p := New(mock)
+		_, err := p.PrintJSON([]byte(doc))
+		require.Error(t, err, doc)
+		assert.Contains(t, err.Error(), message)
+		require.NoError(t, p.Print())
+		assert.Empty(t, mock.Bytes())
+	}
+}
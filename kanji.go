@@ -0,0 +1,103 @@
+package escpos
+
+import (
+	"fmt"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// Kanji code systems for SetKanjiCodeSystem
+const (
+	KanjiCodeJIS          uint8 = 0 // JIS code
+	KanjiCodeShiftJIS     uint8 = 1 // Shift JIS
+	KanjiCodeShiftJIS2004 uint8 = 2 // Shift JIS-2004
+)
+
+// SetKanjiMode enters (FS &) or leaves (FS .) the Kanji character mode used
+// by Japanese models to print double-byte characters
+func (e *Escpos) SetKanjiMode(on bool) (int, error) {
+	cmd := []byte{fs, '.'}
+	if on {
+		cmd = []byte{fs, '&'}
+	}
+	// The mode is only known to the printer once the command is written
+	n, err := e.WriteRaw(cmd)
+	if err == nil {
+		e.kanjiMode = on
+	}
+	return n, err
+}
+
+// SetKanjiCodeSystem selects the encoding of Kanji characters (FS C)
+// Use KanjiCodeJIS, KanjiCodeShiftJIS or KanjiCodeShiftJIS2004
+func (e *Escpos) SetKanjiCodeSystem(code uint8) (int, error) {
+	if code > KanjiCodeShiftJIS2004 {
+		return 0, fmt.Errorf("invalid Kanji code system: %d", code)
+	}
+	n, err := e.WriteRaw([]byte{fs, 'C', code})
+	if err == nil {
+		e.kanjiCode = code
+	}
+	return n, err
+}
+
+// SetKanjiUnderline sets the underline mode of Kanji characters (FS -)
+// Use 0 for no underline, 1 for single underline, and 2 for double underline
+func (e *Escpos) SetKanjiUnderline(u uint8) (int, error) {
+	if u > 2 {
+		u = 0
+	}
+	return e.WriteRaw([]byte{fs, '-', u})
+}
+
+// SetKanjiSpacing sets the left and right spacing of Kanji characters (FS S),
+// in horizontal motion units
+func (e *Escpos) SetKanjiSpacing(left, right uint8) (int, error) {
+	return e.WriteRaw([]byte{fs, 'S', left, right})
+}
+
+// WriteSJIS prints a string encoded in Shift JIS, entering Kanji mode with
+// the Shift JIS code system first when needed
+func (e *Escpos) WriteSJIS(data string) (int, error) {
+	if e.kanjiCode != KanjiCodeShiftJIS {
+		if _, err := e.SetKanjiCodeSystem(KanjiCodeShiftJIS); err != nil {
+			return 0, err
+		}
+	}
+	if !e.kanjiMode {
+		if _, err := e.SetKanjiMode(true); err != nil {
+			return 0, err
+		}
+	}
+	return e.WriteRawWithEncoding([]byte(e.prepareText(data)), japanese.ShiftJIS)
+}
+
+// WriteEUCKR prints a string encoded in EUC-KR (KS X 1001, code page 949),
+// entering the double-byte character mode (FS &) of Korean models first when
+// needed
+func (e *Escpos) WriteEUCKR(data string) (int, error) {
+	return e.writeDoubleByte(data, korean.EUCKR)
+}
+
+// WriteBig5 prints a string encoded in Big5 (Traditional Chinese), entering
+// the double-byte character mode (FS &) of Taiwanese models first when needed
+func (e *Escpos) WriteBig5(data string) (int, error) {
+	return e.writeDoubleByte(data, traditionalchinese.Big5)
+}
+
+// writeDoubleByte prints a string with a double-byte encoding, in Kanji mode
+func (e *Escpos) writeDoubleByte(data string, enc encoding.Encoding) (int, error) {
+	written := 0
+	if !e.kanjiMode {
+		n, err := e.SetKanjiMode(true)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	n, err := e.WriteRawWithEncoding([]byte(e.prepareText(data)), enc)
+	return written + n, err
+}
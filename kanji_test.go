@@ -0,0 +1,102 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestKanjiCommands tests the Kanji mode commands
+func TestKanjiCommands(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.SetKanjiCodeSystem(KanjiCodeShiftJIS)
+	assert.NoError(t, err)
+	_, err = p.SetKanjiMode(true)
+	assert.NoError(t, err)
+	_, err = p.SetKanjiUnderline(1)
+	assert.NoError(t, err)
+	_, err = p.SetKanjiSpacing(2, 3)
+	assert.NoError(t, err)
+	_, err = p.SetKanjiMode(false)
+	assert.NoError(t, err)
+
+	_, err = p.SetKanjiCodeSystem(3)
+	assert.Error(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{
+		fs, 'C', 1,
+		fs, '&',
+		fs, '-', 1,
+		fs, 'S', 2, 3,
+		fs, '.',
+	}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestWriteSJIS tests that Kanji mode is entered once before Shift JIS text
+func TestWriteSJIS(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.WriteSJIS("日本")
+	assert.NoError(t, err)
+	_, err = p.WriteSJIS("語")
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{
+		fs, 'C', 1,
+		fs, '&',
+		0x93, 0xFA, 0x96, 0x7B,
+		0x8C, 0xEA,
+	}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+func TestWriteEUCKRAndBig5(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.WriteEUCKR("한")
+	assert.NoError(t, err)
+	_, err = p.WriteBig5("中")
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{
+		fs, '&',
+		0xC7, 0xD1,
+		0xA4, 0xA4,
+	}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestKanjiModeFailedWrite tests that a failed command leaves the Kanji state unchanged
+func TestKanjiModeFailedWrite(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetStickyErrors(true)
+	require.Error(t, p.SetProfile(Profile{Charset: CharsetKorea + 1}))
+
+	_, err := p.SetKanjiCodeSystem(KanjiCodeShiftJIS)
+	assert.Error(t, err)
+	_, err = p.SetKanjiMode(true)
+	assert.Error(t, err)
+
+	// Once the error is cleared, the mode and code system are sent again
+	p.SetStickyErrors(false)
+	_, err = p.WriteSJIS("")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, []byte{fs, 'C', 1, fs, '&'}, mock.Bytes())
+}
@@ -0,0 +1,299 @@
+// Package kiosk implements the self-service ticket flow: print a ticket
+// through a spool queue, present it to the customer, then wait for it to be
+// taken, retracting it when it is forgotten.
+package kiosk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/spool"
+)
+
+// ErrExpired is returned by PrintAndPresent when the ticket expired in the queue before it could be printed
+var ErrExpired = errors.New("ticket expired before it could be printed")
+
+// EventType identifies the type of a kiosk event
+type EventType uint8
+
+// Kiosk events
+const (
+	EventPresented EventType = iota // the ticket was presented to the customer
+	EventTaken                      // the customer took the ticket
+	EventTimeout                    // the ticket was not taken in time
+	EventRetracted                  // the ticket was retracted after a timeout
+)
+
+// String returns the name of the event type
+func (t EventType) String() string {
+	switch t {
+	case EventPresented:
+		return "presented"
+	case EventTaken:
+		return "taken"
+	case EventTimeout:
+		return "timeout"
+	case EventRetracted:
+		return "retracted"
+	}
+	return "unknown"
+}
+
+// Event is reported by a kiosk during the presentation of a ticket
+type Event struct {
+	Type  EventType
+	Time  time.Time
+	JobID string
+}
+
+// Presenter controls the ticket presenter of a kiosk printer
+type Presenter interface {
+	// Present ejects the printed ticket to the customer
+	Present(p *escpos.Escpos) error
+	// Retract pulls a forgotten ticket back into the printer
+	Retract(p *escpos.Escpos) error
+	// Taken reports whether the presented ticket was taken
+	Taken(p *escpos.Escpos) (bool, error)
+}
+
+// CommandPresenter is a Presenter driven by the model specific commands of
+// the printer, found in its programming manual
+type CommandPresenter struct {
+	// PresentCommand ejects the ticket
+	PresentCommand []byte
+	// RetractCommand retracts the ticket, nil if the presenter cannot retract
+	RetractCommand []byte
+	// StatusType is the DLE EOT status type reporting the presenter sensor
+	StatusType byte
+	// PaperMask selects the status bits set while a ticket waits in the presenter
+	PaperMask byte
+}
+
+// Present sends the present command
+func (c CommandPresenter) Present(p *escpos.Escpos) error {
+	if _, err := p.WriteRaw(c.PresentCommand); err != nil {
+		return err
+	}
+	return p.Print()
+}
+
+// Retract sends the retract command
+func (c CommandPresenter) Retract(p *escpos.Escpos) error {
+	if c.RetractCommand == nil {
+		return fmt.Errorf("the presenter cannot retract tickets")
+	}
+	if _, err := p.WriteRaw(c.RetractCommand); err != nil {
+		return err
+	}
+	return p.Print()
+}
+
+// Taken queries the presenter sensor
+func (c CommandPresenter) Taken(p *escpos.Escpos) (bool, error) {
+	status, err := p.QueryStatus(c.StatusType)
+	if err != nil {
+		return false, err
+	}
+	if len(status) == 0 {
+		return false, fmt.Errorf("no answer to the presenter status request")
+	}
+	return status[0]&c.PaperMask == 0, nil
+}
+
+// Kiosk combines a spool queue, a presenter and a status monitor
+type Kiosk struct {
+	queue     *spool.Queue
+	control   *escpos.Escpos
+	presenter Presenter
+
+	takeTimeout  time.Duration
+	pollInterval time.Duration
+	retract      bool
+	onEvent      func(Event)
+	queueOpts    []spool.Option
+
+	mu sync.Mutex // one ticket is presented at a time
+}
+
+// Option configures a Kiosk
+type Option func(*Kiosk)
+
+// WithTakeTimeout sets how long a presented ticket may wait to be taken (default: 30s)
+func WithTakeTimeout(d time.Duration) Option {
+	return func(k *Kiosk) {
+		if d > 0 {
+			k.takeTimeout = d
+		}
+	}
+}
+
+// WithPollInterval sets the interval of the presenter sensor polling and of
+// the print retries (default: 250ms)
+func WithPollInterval(d time.Duration) Option {
+	return func(k *Kiosk) {
+		if d > 0 {
+			k.pollInterval = d
+		}
+	}
+}
+
+// WithoutRetract leaves the tickets that were not taken in the presenter
+func WithoutRetract() Option {
+	return func(k *Kiosk) {
+		k.retract = false
+	}
+}
+
+// WithEventHandler sets the function called with the kiosk events
+func WithEventHandler(fn func(Event)) Option {
+	return func(k *Kiosk) {
+		k.onEvent = fn
+	}
+}
+
+// WithQueueOptions sets the options of the underlying spool queue
+func WithQueueOptions(opts ...spool.Option) Option {
+	return func(k *Kiosk) {
+		k.queueOpts = append(k.queueOpts, opts...)
+	}
+}
+
+// New creates a kiosk printing to printer and presenting the tickets with presenter
+func New(printer escpos.Printer, presenter Presenter, opts ...Option) *Kiosk {
+	k := &Kiosk{
+		control:      escpos.New(printer),
+		presenter:    presenter,
+		takeTimeout:  30 * time.Second,
+		pollInterval: 250 * time.Millisecond,
+		retract:      true,
+	}
+	for _, opt := range opts {
+		opt(k)
+	}
+	k.queue = spool.New(printer, k.queueOpts...)
+	return k
+}
+
+// Queue returns the queue delivering the tickets, to subscribe to its events
+func (k *Kiosk) Queue() *spool.Queue {
+	return k.queue
+}
+
+// PrintAndPresent prints a ticket, presents it and waits until it is taken
+// or the take timeout elapses, in which case it is retracted. doc is the
+// ESC/POS data of the ticket, see spool.Record. It returns the last event
+// of the presentation: EventTaken, EventTimeout or EventRetracted.
+func (k *Kiosk) PrintAndPresent(ctx context.Context, doc []byte) (Event, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	id, err := k.print(ctx, doc)
+	if err != nil {
+		return Event{}, err
+	}
+
+	if err := k.presenter.Present(k.control); err != nil {
+		return Event{}, fmt.Errorf("failed to present ticket: %w", err)
+	}
+	k.emit(EventPresented, id)
+
+	deadline := time.Now().Add(k.takeTimeout)
+	for {
+		taken, err := k.presenter.Taken(k.control)
+		if err != nil {
+			return Event{}, fmt.Errorf("failed to query presenter: %w", err)
+		}
+		if taken {
+			return k.emit(EventTaken, id), nil
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+		if err := k.wait(ctx); err != nil {
+			return Event{}, err
+		}
+	}
+
+	ev := k.emit(EventTimeout, id)
+	if !k.retract {
+		return ev, nil
+	}
+	if err := k.presenter.Retract(k.control); err != nil {
+		return ev, fmt.Errorf("failed to retract ticket: %w", err)
+	}
+	return k.emit(EventRetracted, id), nil
+}
+
+// print enqueues the ticket and processes the queue until it is printed
+func (k *Kiosk) print(ctx context.Context, doc []byte) (string, error) {
+	var mu sync.Mutex
+	var printed, expired bool
+	var id string
+
+	unsubscribe := k.queue.Subscribe(func(ev spool.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ev.Job.ID != id {
+			return
+		}
+		switch ev.Type {
+		case spool.EventJobPrinted:
+			printed = true
+		case spool.EventJobExpired:
+			expired = true
+		}
+	})
+	defer unsubscribe()
+
+	mu.Lock()
+	id, err := k.queue.Enqueue(spool.Job{Data: doc})
+	mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		err := k.queue.Process()
+
+		mu.Lock()
+		done, lost := printed, expired
+		mu.Unlock()
+		switch {
+		case done:
+			return id, nil
+		case lost:
+			return "", ErrExpired
+		}
+
+		if err == nil {
+			// the queue was drained without our ticket, which cannot happen
+			return "", fmt.Errorf("ticket %s left the queue without being printed", id)
+		}
+		if err := k.wait(ctx); err != nil {
+			return "", err
+		}
+	}
+}
+
+// wait waits for the poll interval or the end of ctx
+func (k *Kiosk) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(k.pollInterval):
+		return nil
+	}
+}
+
+// emit reports an event to the event handler and returns it
+func (k *Kiosk) emit(t EventType, id string) Event {
+	ev := Event{Type: t, Time: time.Now(), JobID: id}
+	if k.onEvent != nil {
+		k.onEvent(ev)
+	}
+	return ev
+}
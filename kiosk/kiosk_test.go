@@ -0,0 +1,157 @@
+package kiosk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/emulator"
+	"github.com/schawnndev/escpos/spool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePresenter is taken after a number of sensor polls (never when negative)
+type fakePresenter struct {
+	takenAfter int
+	polls      int
+	presented  int
+	retracted  int
+}
+
+func (f *fakePresenter) Present(p *escpos.Escpos) error {
+	f.presented++
+	return nil
+}
+
+func (f *fakePresenter) Retract(p *escpos.Escpos) error {
+	f.retracted++
+	return nil
+}
+
+func (f *fakePresenter) Taken(p *escpos.Escpos) (bool, error) {
+	f.polls++
+	return f.takenAfter >= 0 && f.polls > f.takenAfter, nil
+}
+
+func ticket(t *testing.T) []byte {
+	data, err := spool.Record(func(p *escpos.Escpos) error {
+		p.Write("Ticket A-042\n")
+		_, err := p.Cut()
+		return err
+	})
+	require.NoError(t, err)
+	return data
+}
+
+// TestPrintAndPresentTaken tests the flow of a ticket taken by the customer
+func TestPrintAndPresentTaken(t *testing.T) {
+	em := emulator.New()
+	presenter := &fakePresenter{takenAfter: 2}
+	var events []EventType
+	k := New(em, presenter,
+		WithPollInterval(time.Millisecond),
+		WithEventHandler(func(ev Event) { events = append(events, ev.Type) }),
+	)
+
+	ev, err := k.PrintAndPresent(context.Background(), ticket(t))
+	require.NoError(t, err)
+	assert.Equal(t, EventTaken, ev.Type)
+	assert.Equal(t, "job-1", ev.JobID)
+	assert.Equal(t, []EventType{EventPresented, EventTaken}, events)
+	assert.Equal(t, 1, presenter.presented)
+	assert.Equal(t, 0, presenter.retracted)
+	assert.Contains(t, em.Text(), "Ticket A-042")
+}
+
+// TestPrintAndPresentTimeout tests retracting a forgotten ticket
+func TestPrintAndPresentTimeout(t *testing.T) {
+	presenter := &fakePresenter{takenAfter: -1}
+	var events []EventType
+	k := New(emulator.New(), presenter,
+		WithTakeTimeout(10*time.Millisecond),
+		WithPollInterval(time.Millisecond),
+		WithEventHandler(func(ev Event) { events = append(events, ev.Type) }),
+	)
+
+	ev, err := k.PrintAndPresent(context.Background(), ticket(t))
+	require.NoError(t, err)
+	assert.Equal(t, EventRetracted, ev.Type)
+	assert.Equal(t, []EventType{EventPresented, EventTimeout, EventRetracted}, events)
+	assert.Equal(t, 1, presenter.retracted)
+
+	// Without retraction, the ticket stays in the presenter
+	events = nil
+	k = New(emulator.New(), presenter,
+		WithTakeTimeout(10*time.Millisecond),
+		WithPollInterval(time.Millisecond),
+		WithoutRetract(),
+		WithEventHandler(func(ev Event) { events = append(events, ev.Type) }),
+	)
+	ev, err = k.PrintAndPresent(context.Background(), ticket(t))
+	require.NoError(t, err)
+	assert.Equal(t, EventTimeout, ev.Type)
+	assert.Equal(t, []EventType{EventPresented, EventTimeout}, events)
+}
+
+// offlinePrinter never accepts data
+type offlinePrinter struct {
+	*emulator.Emulator
+}
+
+func (o offlinePrinter) Write(p []byte) (int, error) {
+	return 0, errors.New("offline")
+}
+
+// TestPrintAndPresentOffline tests giving up on a ticket that cannot be printed
+func TestPrintAndPresentOffline(t *testing.T) {
+	presenter := &fakePresenter{}
+
+	k := New(offlinePrinter{emulator.New()}, presenter,
+		WithPollInterval(time.Millisecond),
+		WithQueueOptions(spool.WithDefaultTTL(5*time.Millisecond)),
+	)
+	_, err := k.PrintAndPresent(context.Background(), ticket(t))
+	assert.ErrorIs(t, err, ErrExpired)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	k = New(offlinePrinter{emulator.New()}, presenter, WithPollInterval(time.Millisecond))
+	_, err = k.PrintAndPresent(ctx, ticket(t))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, presenter.presented)
+}
+
+// TestCommandPresenter tests the command driven presenter
+func TestCommandPresenter(t *testing.T) {
+	em := emulator.New()
+	p := escpos.New(em)
+	presenter := CommandPresenter{
+		PresentCommand: []byte{0x1C, 'P', 'E'},
+		RetractCommand: []byte{0x1C, 'P', 'R'},
+		StatusType:     escpos.RT_STATUS_PAPER,
+		PaperMask:      0x04,
+	}
+
+	require.NoError(t, presenter.Present(p))
+	assert.True(t, bytes.HasSuffix(em.Bytes(), []byte{0x1C, 'P', 'E'}))
+
+	em.SetStatus(escpos.RT_STATUS_PAPER, 0x16)
+	taken, err := presenter.Taken(p)
+	require.NoError(t, err)
+	assert.False(t, taken)
+
+	em.SetStatus(escpos.RT_STATUS_PAPER, 0x12)
+	taken, err = presenter.Taken(p)
+	require.NoError(t, err)
+	assert.True(t, taken)
+
+	require.NoError(t, presenter.Retract(p))
+	assert.True(t, bytes.HasSuffix(em.Bytes(), []byte{0x1C, 'P', 'R'}))
+
+	presenter.RetractCommand = nil
+	assert.Error(t, presenter.Retract(p))
+}
@@ -0,0 +1,108 @@
+package escpos
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LineItem describes a single ordered article.
+// It is shared by the helpers that print order related tickets.
+type LineItem struct {
+	Name      string   // Article name, e.g. "Flat White"
+	Quantity  int      // Number of units ordered (values below 1 are treated as 1)
+	Modifiers []string // Customisations printed below the name, e.g. "Oat milk"
+	Note      string   // Free-form note, e.g. the customer name
+}
+
+// quantity returns the number of units of the item, at least 1
+func (li LineItem) quantity() int {
+	if li.Quantity < 1 {
+		return 1
+	}
+	return li.Quantity
+}
+
+// PrintItemLabels prints one small ticket per ordered unit, as used for drink
+// labels in coffee shops.  Every ticket shows the order number, the item name,
+// its modifiers, the note and a "n/total" counter, and is followed by a cut.
+//
+// The data is only buffered: call Print() afterwards to send it to the printer.
+func (e *Escpos) PrintItemLabels(orderNumber string, items []LineItem) error {
+	total := 0
+	for _, item := range items {
+		total += item.quantity()
+	}
+
+	index := 0
+	for _, item := range items {
+		for i := 0; i < item.quantity(); i++ {
+			index++
+			if err := e.printItemLabel(orderNumber, item, index, total); err != nil {
+				return fmt.Errorf("failed to print label %d/%d: %w", index, total, err)
+			}
+		}
+	}
+	return nil
+}
+
+// printItemLabel prints a single label and cuts it
+func (e *Escpos) printItemLabel(orderNumber string, item LineItem, index, total int) error {
+	if !e.config.DisableJustify {
+		if _, err := e.SetJustify(JustifyLeft); err != nil {
+			return err
+		}
+	}
+
+	// Order number header
+	if err := e.setLabelEmphasis(true); err != nil {
+		return err
+	}
+	if _, err := e.SetSize(2, 2); err != nil {
+		return err
+	}
+	if _, err := e.Write("#" + orderNumber + "\n"); err != nil {
+		return err
+	}
+
+	// Item name
+	if _, err := e.SetSize(2, 1); err != nil {
+		return err
+	}
+	if _, err := e.Write(item.Name + "\n"); err != nil {
+		return err
+	}
+	if _, err := e.SetSize(1, 1); err != nil {
+		return err
+	}
+	if err := e.setLabelEmphasis(false); err != nil {
+		return err
+	}
+
+	for _, modifier := range item.Modifiers {
+		if _, err := e.Write(" + " + modifier + "\n"); err != nil {
+			return err
+		}
+	}
+	if note := strings.TrimSpace(item.Note); note != "" {
+		if _, err := e.Write(note + "\n"); err != nil {
+			return err
+		}
+	}
+	if total > 1 {
+		if _, err := e.Write(fmt.Sprintf("%d/%d\n", index, total)); err != nil {
+			return err
+		}
+	}
+
+	_, err := e.Cut()
+	return err
+}
+
+// setLabelEmphasis toggles bold mode unless it is disabled in the printer configuration
+func (e *Escpos) setLabelEmphasis(b bool) error {
+	if e.config.DisableBold {
+		return nil
+	}
+	_, err := e.SetBold(b)
+	return err
+}
@@ -0,0 +1,55 @@
+package escpos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPrintItemLabels tests printing one label per ordered unit
+func TestPrintItemLabels(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	items := []LineItem{
+		{Name: "Flat White", Quantity: 2, Modifiers: []string{"Oat milk", "Extra shot"}},
+		{Name: "Espresso", Note: "Alice"},
+	}
+
+	err := p.PrintItemLabels("42", items)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	output := mock.Bytes()
+	cut := []byte{gs, 'V', 'A', 0x00}
+
+	// One cut per unit, the last command being a cut
+	assert.Equal(t, 3, bytes.Count(output, cut))
+	assert.True(t, bytes.HasSuffix(output, cut))
+
+	assert.Equal(t, 3, bytes.Count(output, []byte("#42\n")))
+	assert.Equal(t, 2, bytes.Count(output, []byte(" + Oat milk\n")))
+	assert.Contains(t, string(output), "Alice\n")
+	assert.Contains(t, string(output), "1/3\n")
+	assert.Contains(t, string(output), "3/3\n")
+}
+
+// TestPrintItemLabelsSingle tests that a single label has no counter
+func TestPrintItemLabelsSingle(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetConfig(PrinterConfig{DisableBold: true})
+
+	err := p.PrintItemLabels("7", []LineItem{{Name: "Latte"}})
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	output := mock.Bytes()
+	assert.NotContains(t, string(output), "1/1")
+	assert.NotContains(t, string(output), string([]byte{esc, 'E'}))
+}
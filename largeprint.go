@@ -0,0 +1,49 @@
+package escpos
+
+// Large print settings
+const (
+	largePrintScale       = 2  // multiplier of the character sizes
+	largePrintLineSpacing = 80 // line spacing, in motion units
+)
+
+// SetLargePrint toggles the large print mode, for visually impaired
+// customers: the character sizes set afterwards are doubled (up to 8), the
+// line spacing is increased, and the layouts built on the line width, such
+// as WriteBilingual, use fewer columns. The current size is applied again.
+func (e *Escpos) SetLargePrint(on bool) (int, error) {
+	e.largePrint = on
+	return e.applyLargePrint()
+}
+
+// LargePrint reports whether the large print mode is on
+func (e *Escpos) LargePrint() bool {
+	return e.largePrint
+}
+
+// applyLargePrint sends the character size and line spacing of the large print mode
+func (e *Escpos) applyLargePrint() (int, error) {
+	s := e.Style.normalized()
+	written, err := e.SetSize(s.Height, s.Width)
+	if err != nil {
+		return written, err
+	}
+
+	n, err := e.restoreLineSpacing()
+	return written + n, err
+}
+
+// restoreLineSpacing sets the line spacing of the current mode again
+func (e *Escpos) restoreLineSpacing() (int, error) {
+	if e.largePrint {
+		return e.SetLineSpacing(largePrintLineSpacing)
+	}
+	return e.SetDefaultLineSpacing()
+}
+
+// sizeScale returns the multiplier of the character sizes
+func (e *Escpos) sizeScale() uint8 {
+	if e.largePrint {
+		return largePrintScale
+	}
+	return 1
+}
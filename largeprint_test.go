@@ -0,0 +1,58 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLargePrint tests scaling the sizes and the line spacing
+func TestLargePrint(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.SetLargePrint(true)
+	require.NoError(t, err)
+	assert.True(t, p.LargePrint())
+	_, err = p.SetSize(1, 2)
+	require.NoError(t, err)
+	_, err = p.SetSize(8, 8)
+	require.NoError(t, err)
+	_, err = p.SetLargePrint(false)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := []byte{
+		gs, '!', 0x11, esc, '3', largePrintLineSpacing,
+		gs, '!', 0x31, // 2x4
+		gs, '!', 0x77, // 8x8, not scaled past the maximum
+		gs, '!', 0x77, esc, '2',
+	}
+	assert.Equal(t, expected, mock.Bytes())
+	// The style keeps the requested size
+	assert.Equal(t, uint8(8), p.Style.Width)
+}
+
+// TestLargePrintInitialize tests that the mode survives a reset of the printer
+func TestLargePrintInitialize(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	_, err := p.SetLargePrint(true)
+	require.NoError(t, err)
+	_, err = p.Initialize()
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := []byte{gs, '!', 0x11, esc, '3', largePrintLineSpacing, esc, '@', gs, '!', 0x11, esc, '3', largePrintLineSpacing}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestLargePrintColumns tests the layouts using fewer columns
+func TestLargePrintColumns(t *testing.T) {
+	p := New(NewMockPrinter())
+	assert.Equal(t, 48, p.CharsPerLine())
+	_, err := p.SetLargePrint(true)
+	require.NoError(t, err)
+	assert.Equal(t, 24, p.CharsPerLine())
+}
@@ -0,0 +1,146 @@
+package escpos
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Option configures an Escpos instance created by New
+type Option func(*Escpos)
+
+// WithLogger logs the activity of the printer at debug level: the start of
+// each job, the byte count, command categories and duration of each flush,
+// and the result of each status query. A job is the data buffered between
+// two flushes.
+func WithLogger(logger *slog.Logger) Option {
+	return func(e *Escpos) {
+		e.logger = logger
+	}
+}
+
+// Command categories counted in the flush log entries
+const (
+	categoryText    = "text"
+	categoryStyle   = "style"
+	categoryBarcode = "barcode"
+	categoryQRCode  = "qrcode"
+	categoryImage   = "image"
+	categoryCut     = "cut"
+	categoryStatus  = "status"
+	categoryOther   = "other"
+)
+
+// jobLog is the state of the job being logged
+type jobLog struct {
+	id       int
+	started  bool
+	bytes    int
+	commands map[string]int
+}
+
+// logging reports whether debug logging is enabled
+func (e *Escpos) logging() bool {
+	return e.logger != nil && e.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// logWrite counts raw data written to the buffer in the current job
+func (e *Escpos) logWrite(data []byte) {
+	if !e.logging() {
+		return
+	}
+	if !e.job.started {
+		e.job.id++
+		e.job.started = true
+		e.job.bytes = 0
+		e.job.commands = map[string]int{}
+		e.logger.Debug("escpos job started", "job", e.job.id)
+	}
+	e.job.bytes += len(data)
+	e.job.commands[commandCategory(data)]++
+}
+
+// logFlush logs the flush of the current job
+func (e *Escpos) logFlush(start time.Time, err error) {
+	if !e.logging() || !e.job.started {
+		return
+	}
+	attrs := []any{
+		"job", e.job.id,
+		"bytes", e.job.bytes,
+		"duration", time.Since(start),
+	}
+	var commands []any
+	for _, c := range []string{categoryText, categoryStyle, categoryBarcode, categoryQRCode, categoryImage, categoryCut, categoryStatus, categoryOther} {
+		if n := e.job.commands[c]; n > 0 {
+			commands = append(commands, c, n)
+		}
+	}
+	attrs = append(attrs, slog.Group("commands", commands...))
+	if err != nil {
+		attrs = append(attrs, "err", err)
+	}
+	e.logger.Debug("escpos flush", attrs...)
+	e.job.started = false
+}
+
+// logStatus logs the result of a status query
+func (e *Escpos) logStatus(statusType byte, status []byte, err error) {
+	if !e.logging() {
+		return
+	}
+	if err != nil {
+		e.logger.Debug("escpos status", "type", statusType, "err", err)
+		return
+	}
+	e.logger.Debug("escpos status", "type", statusType, "status", status)
+}
+
+// commandCategory classifies raw data by its first command
+func commandCategory(data []byte) string {
+	if len(data) == 0 {
+		return categoryOther
+	}
+	if data[0] >= 0x20 || data[0] == '\n' || data[0] == '\r' || data[0] == '\t' {
+		return categoryText
+	}
+	if len(data) < 2 {
+		return categoryOther
+	}
+	switch data[0] {
+	case esc:
+		switch data[1] {
+		case 'E', '-', '!', 'a', 'M', 'G', '{', 'V', 't', 'R', '2', '3', ' ':
+			return categoryStyle
+		case '*':
+			return categoryImage
+		case 'i', 'm':
+			return categoryCut
+		}
+	case gs:
+		switch data[1] {
+		case '!', 'B', 'h', 'w', 'H', 'f', 'L', 'W':
+			return categoryStyle
+		case 'k':
+			return categoryBarcode
+		case 'v', '8', '*', '/':
+			return categoryImage
+		case 'V':
+			return categoryCut
+		case '(':
+			if len(data) > 2 {
+				switch data[2] {
+				case 'k':
+					return categoryQRCode
+				case 'L':
+					return categoryImage
+				}
+			}
+		case 'r', 'a':
+			return categoryStatus
+		}
+	case dle:
+		return categoryStatus
+	}
+	return categoryOther
+}
@@ -0,0 +1,55 @@
+package escpos
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWithLogger tests logging the jobs, flushes and status queries
+func TestWithLogger(t *testing.T) {
+	var log bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&log, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	mock := NewMockPrinter()
+	mock.SetStatus([]byte{0x12})
+	p := New(mock, WithLogger(logger))
+
+	p.SetBold(true)
+	p.Write("Hello\n")
+	p.EAN13("1234567890128")
+	require.NoError(t, p.PrintAndCut())
+	_, err := p.QueryStatus(RT_STATUS_PAPER)
+	require.NoError(t, err)
+
+	out := log.String()
+	assert.Contains(t, out, `msg="escpos job started" job=1`)
+	assert.Contains(t, out, "msg=\"escpos flush\" job=1 bytes=")
+	assert.Contains(t, out, "commands.style=1 commands.barcode=1 commands.cut=1")
+	assert.Contains(t, out, `msg="escpos job started" job=2`)
+	assert.Contains(t, out, "commands.status=1")
+	assert.Contains(t, out, `msg="escpos status" type=4 status="\x12"`)
+}
+
+// TestWithLoggerLevel tests that nothing is logged above debug level
+func TestWithLoggerLevel(t *testing.T) {
+	var log bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&log, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	p := New(NewMockPrinter(), WithLogger(logger))
+
+	p.Write("Hello\n")
+	require.NoError(t, p.Print())
+	assert.Empty(t, log.String())
+}
+
+// TestCommandCategory tests classifying the raw data
+func TestCommandCategory(t *testing.T) {
+	assert.Equal(t, categoryText, commandCategory([]byte("abc")))
+	assert.Equal(t, categoryStyle, commandCategory([]byte{esc, 'E', 1}))
+	assert.Equal(t, categoryQRCode, commandCategory([]byte{gs, '(', 'k', 3, 0}))
+	assert.Equal(t, categoryImage, commandCategory([]byte{gs, 'v', '0'}))
+	assert.Equal(t, categoryCut, commandCategory([]byte{gs, 'V', 0x41, 0}))
+	assert.Equal(t, categoryOther, commandCategory([]byte{esc, '@'}))
+}
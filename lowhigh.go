@@ -0,0 +1,35 @@
+package escpos
+
+import "fmt"
+
+// LowHigh16 encodes n as the 2 bytes (nL, nH or pL, pH) used by the ESC/POS
+// length and size parameters, low byte first. n must be between 0-65535.
+func LowHigh16(n int) ([]byte, error) {
+	return intLowHigh(n, 2)
+}
+
+// LowHigh32 encodes n as the 4 bytes (p1 to p4) used by the GS 8 L extended
+// commands, low byte first. n must be between 0-4294967295.
+func LowHigh32(n int) ([]byte, error) {
+	return intLowHigh(n, 4)
+}
+
+// intLowHigh generates multiple bytes for a number: In lower and higher parts, or more parts as needed.
+func intLowHigh(inpNumber int, outBytes int) ([]byte, error) {
+	if outBytes < 1 || outBytes > 4 {
+		return nil, fmt.Errorf("can only output 1-4 bytes")
+	}
+
+	maxInput := int64(1)<<(outBytes*8) - 1
+	if inpNumber < 0 || int64(inpNumber) > maxInput {
+		return nil, fmt.Errorf("number out of range. Can only output 0-%d in %d bytes, got %d", maxInput, outBytes, inpNumber)
+	}
+
+	out := make([]byte, outBytes)
+	for i := 0; i < outBytes; i++ {
+		out[i] = byte(inpNumber % 256)
+		inpNumber /= 256
+	}
+
+	return out, nil
+}
@@ -0,0 +1,55 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLowHigh16 tests encoding 2 byte parameters
+func TestLowHigh16(t *testing.T) {
+	b, err := LowHigh16(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0}, b)
+
+	b, err = LowHigh16(303)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x2F, 0x01}, b)
+
+	b, err = LowHigh16(65535)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xFF, 0xFF}, b)
+
+	_, err = LowHigh16(65536)
+	assert.Error(t, err)
+	_, err = LowHigh16(-1)
+	assert.Error(t, err)
+}
+
+// TestLowHigh32 tests encoding 4 byte parameters
+func TestLowHigh32(t *testing.T) {
+	b, err := LowHigh32(0x01020304)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x04, 0x03, 0x02, 0x01}, b)
+
+	b, err = LowHigh32(1<<32 - 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xFF, 0xFF, 0xFF, 0xFF}, b)
+
+	_, err = LowHigh32(1 << 32)
+	assert.Error(t, err)
+}
+
+// TestIntLowHigh tests the byte count limits
+func TestIntLowHigh(t *testing.T) {
+	b, err := intLowHigh(255, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xFF}, b)
+
+	_, err = intLowHigh(256, 1)
+	assert.Error(t, err)
+	_, err = intLowHigh(1, 0)
+	assert.Error(t, err)
+	_, err = intLowHigh(1, 5)
+	assert.Error(t, err)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"image"
 	"io"
+	"log/slog"
+	"strings"
 	"time"
 
 	"golang.org/x/text/encoding"
@@ -41,8 +43,13 @@ const (
 
 // Font type constants
 const (
-	FontA uint8 = 0 // Font A (12x24)
-	FontB uint8 = 1 // Font B (9x24)
+	FontA        uint8 = 0  // Font A (12x24)
+	FontB        uint8 = 1  // Font B (9x24)
+	FontC        uint8 = 2  // Font C (9x17 on most models)
+	FontD        uint8 = 3  // Font D, model specific
+	FontE        uint8 = 4  // Font E, model specific
+	FontSpecialA uint8 = 97 // Special font A, model specific
+	FontSpecialB uint8 = 98 // Special font B, model specific
 )
 
 // QR code error correction levels
@@ -70,6 +77,25 @@ const (
 	BarcodeCodabar uint8 = 6
 )
 
+// Function B barcode types, used with BarcodeB (GS k m n d1...dn)
+const (
+	BarcodeBUPCA                uint8 = 65
+	BarcodeBUPCE                uint8 = 66
+	BarcodeBEAN13               uint8 = 67
+	BarcodeBEAN8                uint8 = 68
+	BarcodeBCode39              uint8 = 69
+	BarcodeBITF                 uint8 = 70
+	BarcodeBCodabar             uint8 = 71
+	BarcodeBCode93              uint8 = 72
+	BarcodeBCode128             uint8 = 73
+	BarcodeBGS1128              uint8 = 74
+	BarcodeBGS1DataBarOmni      uint8 = 75
+	BarcodeBGS1DataBarTruncated uint8 = 76
+	BarcodeBGS1DataBarLimited   uint8 = 77
+	BarcodeBGS1DataBarExpanded  uint8 = 78
+	BarcodeBCode128Auto         uint8 = 79
+)
+
 // HRI position constants
 const (
 	HRIPositionNone  uint8 = 0
@@ -152,11 +178,83 @@ type PrinterConfig struct {
 // Escpos represents a ESC/POS printer connection
 type Escpos struct {
 	dst      *bufio.Writer
-	reader   io.Reader // Added reader for status queries
+	out      *countingWriter // printer side of dst
+	reader   io.Reader       // Added reader for status queries
 	Style    Style
 	config   PrinterConfig
 	enc      encoding.Encoding // default encoding used by Write()
 	codepage uint8             // current active code page
+	profile  Profile           // capabilities of the printer model
+
+	// barcode settings, used when barcodes are rendered as images
+	barcodeHeight uint8
+	barcodeWidth  uint8
+	hriPosition   uint8
+
+	// Kanji mode state, see WriteSJIS
+	kanjiMode bool
+	kanjiCode uint8
+
+	// handling of the characters missing from the encoding, see SetEncodingPolicy
+	encPolicy       EncodingPolicy
+	replacement     rune
+	onEncodingError func(r rune, printed string)
+
+	// line ending of the written text, see SetNewlinePolicy
+	newline NewlinePolicy
+
+	// large print mode, see SetLargePrint
+	largePrint bool
+
+	// non-fatal issues met while building the job, see Warnings
+	warnings []Warning
+
+	// print density and speed levels, sent together by ESC 7
+	density int
+	speed   int
+
+	// NV bit images stored with FS q, by index minus one, see StoreNVImage
+	nvImages []rasterImage
+
+	// blockEnd is called at the end of each line and image band by the
+	// documents with reverse output, see Document.SetReverseOutput
+	blockEnd func() error
+
+	// code39Delimiters wraps Code 39 data in '*' start/stop characters
+	code39Delimiters bool
+
+	// last status query, reported by Diagnostics
+	lastStatusType byte
+	lastStatus     []byte
+	lastStatusTime time.Time
+
+	// print width and characters per line overriding the profile, see
+	// SetPaperWidth and SetCharsPerLine
+	printWidthDots int
+	charsPerLine   int
+
+	// first failed write in the sticky error mode, see SetStickyErrors
+	sticky bool
+	err    error
+
+	// debug logging of the jobs, see WithLogger
+	logger *slog.Logger
+	job    jobLog
+
+	// monitoring of the jobs and the printer status, see WithMetrics
+	metrics        Metrics
+	jobStart       time.Time
+	reportedStatus *PrinterStatus
+
+	// polling of the status responses, see WithStatusTimeout
+	statusTimeout      time.Duration
+	statusPollInterval time.Duration
+
+	// buffered size sending the buffer, see WithAutoFlush
+	autoFlush int
+
+	// removal of the control characters of the text, see WithSanitizedText
+	sanitize bool
 }
 
 // New creates a new Escpos printer instance.
@@ -165,14 +263,25 @@ type Escpos struct {
 // Western European languages.  PC850 has near-universal support on thermal
 // ESC/POS printers; Windows-1252 (code page 16) is often silently ignored by
 // cheaper or older printer firmware.  Call SetEncoding to switch to a
-// different character set.
-func New(printer Printer) *Escpos {
-	return &Escpos{
-		dst:      bufio.NewWriter(printer),
-		reader:   printer,
-		enc:      charmap.CodePage850,
-		codepage: CodePagePC850,
+// different character set. Options such as WithLogger configure the instance.
+func New(printer Printer, opts ...Option) *Escpos {
+	out := &countingWriter{w: printer}
+	e := &Escpos{
+		dst:           bufio.NewWriter(out),
+		out:           out,
+		reader:        printer,
+		enc:           charmap.CodePage850,
+		codepage:      CodePagePC850,
+		barcodeHeight: 162,
+		barcodeWidth:  3,
+
+		statusTimeout:      defaultStatusTimeout,
+		statusPollInterval: defaultStatusPollInterval,
 	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
 }
 
 // SetConfig sets the printer configuration options
@@ -181,21 +290,28 @@ func (e *Escpos) SetConfig(conf PrinterConfig) {
 }
 
 // Print sends the buffered data to the printer
-func (e *Escpos) Print() error {
-	if err := e.dst.Flush(); err != nil {
+func (e *Escpos) Print() (err error) {
+	defer func() { e.completeJob(err) }()
+	if e.err != nil {
+		return e.err
+	}
+	if err := e.flush(); err != nil {
 		return fmt.Errorf("failed to send data to printer: %w", err)
 	}
 	return nil
 }
 
 // PrintAndCut sends the buffered data to the printer and performs a cut
-func (e *Escpos) PrintAndCut() error {
-	_, err := e.Cut()
-	if err != nil {
+func (e *Escpos) PrintAndCut() (err error) {
+	defer func() { e.completeJob(err) }()
+	if e.err != nil {
+		return e.err
+	}
+	if _, err := e.Cut(); err != nil {
 		return fmt.Errorf("failed to perform cut: %w", err)
 	}
 
-	if err := e.dst.Flush(); err != nil {
+	if err := e.flush(); err != nil {
 		return fmt.Errorf("failed to send data to printer: %w", err)
 	}
 	return nil
@@ -203,8 +319,17 @@ func (e *Escpos) PrintAndCut() error {
 
 // WriteRaw writes raw bytes directly to the printer
 func (e *Escpos) WriteRaw(data []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
 	if len(data) > 0 {
-		return e.dst.Write(data)
+		n, err := e.dst.Write(data)
+		e.startJob(data)
+		e.logWrite(data[:n])
+		if err == nil {
+			err = e.autoFlushed()
+		}
+		return n, e.fail(err)
 	}
 	return 0, nil
 }
@@ -235,17 +360,29 @@ func (e *Escpos) SetEncoding(enc encoding.Encoding, codepage uint8) (int, error)
 }
 
 // Write prints a string using the current style settings.
-// By default (Windows-1252 encoding set in New), accented characters like
+// By default (PC850 encoding set in New), accented characters like
 // é, ç, à, ù, è are automatically converted from UTF-8 to the printer's
 // active code page.  The ESC t code-page command is re-sent before each
-// write so the correct character set is always active, even after a call
-// to Initialize() which resets the printer.
+// write containing non-ASCII text so the correct character set is always
+// active, even after a call to Initialize() which resets the printer.
 func (e *Escpos) Write(data string) (int, error) {
+	if e.blockEnd != nil {
+		return e.writeBlocks(data)
+	}
+	return e.write(data)
+}
+
+// write writes a string with the default encoding
+func (e *Escpos) write(data string) (int, error) {
+	data = e.prepareText(data)
 	if e.enc != nil {
 		// Always re-assert the code page before writing so we stay correct
-		// even after Initialize() or other printer resets.
-		if _, err := e.SetCodePage(e.codepage); err != nil {
-			return 0, fmt.Errorf("failed to set code page before write: %w", err)
+		// even after Initialize() or other printer resets.  Plain ASCII is
+		// identical in every code page, so it does not need the switch.
+		if !isASCII(data) {
+			if _, err := e.SetCodePage(e.codepage); err != nil {
+				return 0, fmt.Errorf("failed to set code page before write: %w", err)
+			}
 		}
 		return e.WriteRawWithEncoding([]byte(data), e.enc)
 	}
@@ -256,7 +393,7 @@ func (e *Escpos) Write(data string) (int, error) {
 // Note: GBK-capable printers handle the character set switch internally; no
 // ESC t code-page command is sent.
 func (e *Escpos) WriteGBK(data string) (int, error) {
-	return e.WriteRawWithEncoding([]byte(data), simplifiedchinese.GBK)
+	return e.WriteRawWithEncoding([]byte(e.prepareText(data)), simplifiedchinese.GBK)
 }
 
 // WriteWEU writes a string to the printer using Western European encoding (CP850).
@@ -272,24 +409,18 @@ func (e *Escpos) WriteWithEncoding(data string, enc encoding.Encoding, codepage
 	if _, err := e.SetCodePage(codepage); err != nil {
 		return 0, fmt.Errorf("failed to set code page: %w", err)
 	}
-	return e.WriteRawWithEncoding([]byte(data), enc)
+	return e.WriteRawWithEncoding([]byte(e.prepareText(data)), enc)
 }
 
 // WriteRawWithEncoding writes raw bytes to the printer after converting them from UTF-8
 // to the specified encoding
 func (e *Escpos) WriteRawWithEncoding(data []byte, enc encoding.Encoding) (int, error) {
-	// Create an encoder for the target encoding
-	encoder := enc.NewEncoder()
-
 	// The input data is already in UTF-8, no need to decode first
-	// Just encode directly from UTF-8 to the target encoding
-	encBytes, err := encoder.Bytes(data)
+	// Just encode directly from UTF-8 to the target encoding, handling the
+	// unsupported characters with the encoding policy
+	encBytes, err := e.encode(data, enc)
 	if err != nil {
-		// Handle unsupported characters
-		encBytes, err = encoding.ReplaceUnsupported(encoder).Bytes(data)
-		if err != nil {
-			return 0, fmt.Errorf("failed to encode data: %w", err)
-		}
+		return 0, err
 	}
 
 	// Write the converted text
@@ -301,33 +432,32 @@ func (e *Escpos) WriteRawWithEncoding(data []byte, enc encoding.Encoding) (int,
 // c = (2 << 3) * (width - 1) + (height - 1)
 func (e *Escpos) SetSize(height, width uint8) (int, error) {
 	// Ensure values are between 1 and 8
-	if width < 1 {
-		width = 1
-	} else if width > 8 {
-		width = 8
-	}
-
-	if height < 1 {
-		height = 1
-	} else if height > 8 {
-		height = 8
-	}
-
-	sizeByte := (2<<3)*(width-1) + (height - 1)
+	width = e.clamped("width", width, 1, 8)
+	height = e.clamped("height", height, 1, 8)
 
 	// Update the style
 	e.Style.Height = height
 	e.Style.Width = width
 
+	if e.star() {
+		return e.WriteRaw(starSize(min(height*e.sizeScale(), 8), min(width*e.sizeScale(), 8)))
+	}
 	// Send the command to the printer
-	return e.WriteRaw([]byte{gs, '!', sizeByte})
+	return e.WriteRaw([]byte{gs, '!', e.sizeByte(height, width)})
+}
+
+// sizeByte returns the GS ! parameter of a character size, scaled in large print mode
+func (e *Escpos) sizeByte(height, width uint8) byte {
+	width = min(width*e.sizeScale(), 8)
+	height = min(height*e.sizeScale(), 8)
+	return (2<<3)*(width-1) + (height - 1)
 }
 
 // SetJustify sets the justification for text
 // Use JustifyLeft, JustifyCenter, or JustifyRight constants
 func (e *Escpos) SetJustify(j Justify) (int, error) {
 	if e.config.DisableJustify {
-		return 0, fmt.Errorf("justification is disabled in the printer configuration")
+		return 0, fmt.Errorf("justification is %w", ErrFeatureDisabled)
 	}
 	if j > JustifyRight {
 		j = JustifyLeft
@@ -335,6 +465,9 @@ func (e *Escpos) SetJustify(j Justify) (int, error) {
 	// Update the style
 	e.Style.Justify = j
 
+	if e.star() {
+		return e.WriteRaw(starJustify(j))
+	}
 	return e.WriteRaw([]byte{esc, 'a', byte(j)})
 }
 
@@ -342,7 +475,11 @@ func (e *Escpos) SetJustify(j Justify) (int, error) {
 // Use true for bold, false for normal
 func (e *Escpos) SetBold(b bool) (int, error) {
 	if e.config.DisableBold {
-		return 0, fmt.Errorf("bold mode is disabled in the printer configuration")
+		return 0, fmt.Errorf("bold mode is %w", ErrFeatureDisabled)
+	}
+	e.Style.Bold = b
+	if e.star() {
+		return e.WriteRaw(starBold(b))
 	}
 	return e.WriteRaw([]byte{esc, 'E', boolToByte(b)})
 }
@@ -351,11 +488,12 @@ func (e *Escpos) SetBold(b bool) (int, error) {
 // Use 0 for no underline, 1 for single underline, and 2 for double underline
 func (e *Escpos) SetUnderline(u uint8) (int, error) {
 	if e.config.DisableUnderline {
-		return 0, fmt.Errorf("underline mode is disabled in the printer configuration")
+		return 0, fmt.Errorf("underline mode is %w", ErrFeatureDisabled)
 	}
 	if u > 2 {
 		u = 0
 	}
+	e.Style.Underline = u
 	return e.WriteRaw([]byte{esc, '-', u})
 }
 
@@ -363,8 +501,9 @@ func (e *Escpos) SetUnderline(u uint8) (int, error) {
 // Use true for upside-down, false for normal
 func (e *Escpos) SetUpsideDown(u bool) (int, error) {
 	if e.config.DisableUpsideDown {
-		return 0, fmt.Errorf("upside-down mode is disabled in the printer configuration")
+		return 0, fmt.Errorf("upside-down mode is %w", ErrFeatureDisabled)
 	}
+	e.Style.UpsideDown = u
 	return e.WriteRaw([]byte{esc, '{', boolToByte(u)})
 }
 
@@ -372,8 +511,9 @@ func (e *Escpos) SetUpsideDown(u bool) (int, error) {
 // Use true for rotated, false for normal
 func (e *Escpos) SetRotate(r bool) (int, error) {
 	if e.config.DisableRotate {
-		return 0, fmt.Errorf("rotation mode is disabled in the printer configuration")
+		return 0, fmt.Errorf("rotation mode is %w", ErrFeatureDisabled)
 	}
+	e.Style.Rotate = r
 	return e.WriteRaw([]byte{esc, 'V', boolToByte(r)})
 }
 
@@ -381,26 +521,53 @@ func (e *Escpos) SetRotate(r bool) (int, error) {
 // Use true for reverse, false for normal
 func (e *Escpos) SetReverse(r bool) (int, error) {
 	if e.config.DisableReverse {
-		return 0, fmt.Errorf("reverse mode is disabled in the printer configuration")
+		return 0, fmt.Errorf("reverse mode is %w", ErrFeatureDisabled)
 	}
+	e.Style.Reverse = r
 	return e.WriteRaw([]byte{gs, 'B', boolToByte(r)})
 }
 
 // SetFont sets the font type
-// Use FontA (12x24) or FontB (9x24)
+// Use FontA (12x24), FontB (9x24), FontC, or the model specific FontD, FontE,
+// FontSpecialA and FontSpecialB. Unknown fonts fall back to FontA. When the
+// profile lists the fonts of the printer, other fonts are rejected.
 func (e *Escpos) SetFont(f uint8) (int, error) {
-	if f > FontB {
+	if f > FontE && f != FontSpecialA && f != FontSpecialB {
 		f = FontA
 	}
+	if !e.profile.SupportsFont(f) {
+		return 0, fmt.Errorf("font %d is not supported by %s", f, e.profile.name())
+	}
 	return e.WriteRaw([]byte{esc, 'M', f})
 }
 
+// SetSmoothing sets the smoothing mode (GS b), which rounds the edges of
+// large scaled characters
+// Use true for smoothing, false for normal
+func (e *Escpos) SetSmoothing(b bool) (int, error) {
+	return e.WriteRaw([]byte{gs, 'b', boolToByte(b)})
+}
+
+// SetCharacterSpacing sets the right-side spacing of characters (ESC SP)
+// n: spacing in horizontal motion units (0-255, default: 0)
+func (e *Escpos) SetCharacterSpacing(n uint8) (int, error) {
+	return e.WriteRaw([]byte{esc, ' ', n})
+}
+
+// SetDoubleStrike sets the double-strike mode (ESC G), which darkens text on
+// printers with weak print heads
+// Use true for double-strike, false for normal
+func (e *Escpos) SetDoubleStrike(b bool) (int, error) {
+	return e.WriteRaw([]byte{esc, 'G', boolToByte(b)})
+}
+
 // SetHRIPosition sets the position of the HRI (Human Readable Interpretation) characters
 // Use the HRIPosition constants
 func (e *Escpos) SetHRIPosition(p uint8) (int, error) {
 	if p > HRIPositionBoth {
 		return 0, fmt.Errorf("invalid HRI position: must be between 0-3")
 	}
+	e.hriPosition = p
 	return e.WriteRaw([]byte{gs, 'H', p})
 }
 
@@ -413,9 +580,16 @@ func (e *Escpos) SetHRIFont(p bool) (int, error) {
 
 // SetBarcodeHeight sets the height for barcodes in dots (default: 162)
 func (e *Escpos) SetBarcodeHeight(p uint8) (int, error) {
+	e.barcodeHeight = p
 	return e.WriteRaw([]byte{gs, 'h', p})
 }
 
+// SetCode39Delimiters enables wrapping Code 39 data in '*' start/stop
+// characters, for printers that do not add them by themselves
+func (e *Escpos) SetCode39Delimiters(enabled bool) {
+	e.code39Delimiters = enabled
+}
+
 // SetBarcodeWidth sets the width for barcodes (2-6, default: 3)
 func (e *Escpos) SetBarcodeWidth(p uint8) (int, error) {
 	if p < 2 {
@@ -424,6 +598,7 @@ func (e *Escpos) SetBarcodeWidth(p uint8) (int, error) {
 	if p > 6 {
 		p = 6
 	}
+	e.barcodeWidth = p
 	return e.WriteRaw([]byte{gs, 'w', p})
 }
 
@@ -452,6 +627,7 @@ func (e *Escpos) EAN8(code string) (int, error) {
 }
 
 // CODE39 prints a CODE39 barcode
+// code can contain A-Z, 0-9, space and -.$/+%, optionally wrapped in '*' start/stop characters
 func (e *Escpos) CODE39(code string) (int, error) {
 	return e.Barcode(BarcodeCode39, code)
 }
@@ -462,6 +638,7 @@ func (e *Escpos) ITF(code string) (int, error) {
 }
 
 // CODABAR prints a CODABAR barcode
+// code must start and end with a A-D start/stop character and contain 0-9 and -$:/.+ in between
 func (e *Escpos) CODABAR(code string) (int, error) {
 	return e.Barcode(BarcodeCodabar, code)
 }
@@ -472,49 +649,105 @@ func (e *Escpos) CODABAR(code string) (int, error) {
 func (e *Escpos) Barcode(barcodeType uint8, code string) (int, error) {
 	// Validate barcode type
 	if barcodeType > BarcodeCodabar {
-		return 0, fmt.Errorf("invalid barcode type: %d", barcodeType)
+		return 0, invalidBarcode(barcodeType, "invalid barcode type: %d", barcodeType)
 	}
 
 	// Validate code based on barcode type
 	switch barcodeType {
 	case BarcodeUPCA, BarcodeUPCE:
 		if len(code) != 11 && len(code) != 12 {
-			return 0, fmt.Errorf("UPC code should have 11 or 12 digits")
+			return 0, invalidBarcode(barcodeType, "UPC code should have 11 or 12 digits")
 		}
 		if !onlyDigits(code) {
-			return 0, fmt.Errorf("UPC code can only contain digits")
+			return 0, invalidBarcode(barcodeType, "UPC code can only contain digits")
 		}
 	case BarcodeEAN13:
 		if len(code) != 12 && len(code) != 13 {
-			return 0, fmt.Errorf("EAN-13 code should have 12 or 13 digits")
+			return 0, invalidBarcode(barcodeType, "EAN-13 code should have 12 or 13 digits")
 		}
 		if !onlyDigits(code) {
-			return 0, fmt.Errorf("EAN-13 code can only contain digits")
+			return 0, invalidBarcode(barcodeType, "EAN-13 code can only contain digits")
 		}
 	case BarcodeEAN8:
 		if len(code) != 7 && len(code) != 8 {
-			return 0, fmt.Errorf("EAN-8 code should have 7 or 8 digits")
+			return 0, invalidBarcode(barcodeType, "EAN-8 code should have 7 or 8 digits")
 		}
 		if !onlyDigits(code) {
-			return 0, fmt.Errorf("EAN-8 code can only contain digits")
+			return 0, invalidBarcode(barcodeType, "EAN-8 code can only contain digits")
 		}
 	case BarcodeITF:
 		if len(code) < 2 || len(code)%2 != 0 {
-			return 0, fmt.Errorf("ITF code must have an even number of digits (at least 2)")
+			return 0, invalidBarcode(barcodeType, "ITF code must have an even number of digits (at least 2)")
 		}
 		if !onlyDigits(code) {
-			return 0, fmt.Errorf("ITF code can only contain digits")
+			return 0, invalidBarcode(barcodeType, "ITF code can only contain digits")
+		}
+	case BarcodeCode39:
+		if err := validateCode39(code); err != nil {
+			return 0, err
+		}
+		if e.code39Delimiters && !strings.HasPrefix(code, "*") {
+			code = "*" + code + "*"
+		}
+	case BarcodeCodabar:
+		if err := validateCodabar(code); err != nil {
+			return 0, err
 		}
 	}
 
+	if !e.profile.SupportsBarcode(barcodeType) {
+		e.warn(WarningFallback, "%s does not support barcode type %d, printed as an image", e.profile.name(), barcodeType)
+		return e.BarcodeAsImage(barcodeType, code, 0, 0)
+	}
+	if e.blockEnd != nil {
+		// Rendered to be rotated with the reversed document
+		return e.BarcodeAsImage(barcodeType, code, 0, 0)
+	}
+
+	if e.star() {
+		return e.WriteRaw(e.starBarcode(barcodeType, code))
+	}
 	byteCode := append([]byte(code), 0)
 	return e.WriteRaw(append([]byte{gs, 'k', barcodeType}, byteCode...))
 }
 
+// BarcodeB prints a barcode using the GS k function B form, where the data is
+// preceded by its length instead of being NUL terminated. It gives access to
+// symbologies (and vendor extensions) not modelled by the typed API, such as
+// CODE128 or GS1 DataBar.
+// symbology: one of the BarcodeB* constants, or any function B type (65 and above)
+// data: the bytes to encode (1-255 bytes), passed to the printer as-is
+func (e *Escpos) BarcodeB(symbology uint8, data []byte) (int, error) {
+	if symbology < BarcodeBUPCA {
+		return 0, invalidBarcode(symbology, "invalid function B barcode type: %d (must be at least 65)", symbology)
+	}
+	if len(data) < 1 || len(data) > 255 {
+		return 0, invalidBarcode(symbology, "function B barcode data must be between 1-255 bytes, got %d", len(data))
+	}
+
+	if !e.profile.SupportsBarcode(symbology) {
+		e.warn(WarningFallback, "%s does not support barcode type %d, printed as an image", e.profile.name(), symbology)
+		return e.BarcodeAsImage(symbology, string(data), 0, 0)
+	}
+	if e.blockEnd != nil {
+		// Rendered to be rotated with the reversed document
+		return e.BarcodeAsImage(symbology, string(data), 0, 0)
+	}
+
+	if e.star() {
+		if _, ok := starBarcodeTypes[functionA(symbology)]; !ok {
+			e.warn(WarningFallback, "the Star Line Mode has no barcode type %d, printed as an image", symbology)
+			return e.BarcodeAsImage(symbology, string(data), 0, 0)
+		}
+		return e.WriteRaw(e.starBarcode(symbology, string(data)))
+	}
+	return e.WriteRaw(append([]byte{gs, 'k', symbology, byte(len(data))}, data...))
+}
+
 // QRCode prints a QR code
 //
 // Parameters:
-//   - code: the data to encode (max 7089 characters for Model 2, 1167 for Model 1)
+//   - code: the data to encode (max 7089 bytes for Model 2, 1167 for Model 1)
 //   - model: QR code model to use (QRCodeModel1 or QRCodeModel2)
 //   - size: size of QR code modules in dots (1-16)
 //   - correctionLevel: error correction level with these options:
@@ -526,32 +759,52 @@ func (e *Escpos) Barcode(barcodeType uint8, code string) (int, error) {
 // Returns the number of bytes written and any error encountered.
 // Use Model 2 for most applications as it offers better capacity and features.
 func (e *Escpos) QRCode(code string, model uint8, size uint8, correctionLevel uint8) (int, error) {
+	return e.QRCodeBytes([]byte(code), model, size, correctionLevel)
+}
+
+// QRCodeBytes prints a QR code encoding binary data, such as EMVCo payment
+// payloads or compressed tickets, without going through a string.
+// The data is limited to 7089 bytes for Model 2 and 1167 bytes for Model 1,
+// the other parameters are the same as for QRCode.
+func (e *Escpos) QRCodeBytes(data []byte, model uint8, size uint8, correctionLevel uint8) (int, error) {
 	// Check model capacity limits
 	maxLength := 7089 // Default for Model 2
 	if model == QRCodeModel1 {
 		maxLength = 1167
 	}
 
-	if len(code) > maxLength {
-		return 0, fmt.Errorf("QR code data too long (max %d characters for the selected model)", maxLength)
+	if len(data) == 0 {
+		return 0, fmt.Errorf("QR code data cannot be empty")
+	}
+	if len(data) > maxLength {
+		return 0, fmt.Errorf("QR code data too long (max %d bytes for the selected model)", maxLength)
 	}
 
 	// Validate and adjust parameters
-	if size < 1 {
-		size = 1
-	} else if size > 16 {
-		size = 16
-	}
+	size = e.clamped("QR code module size", size, 1, 16)
 
 	if correctionLevel < QRCodeErrorCorrectionLevelL || correctionLevel > QRCodeErrorCorrectionLevelH {
+		e.warn(WarningClamped, "invalid QR code error correction level %d, using L", correctionLevel)
 		correctionLevel = QRCodeErrorCorrectionLevelL
 	}
 
 	// Validate model parameter
 	if model != QRCodeModel1 && model != QRCodeModel2 {
+		e.warn(WarningClamped, "invalid QR code model %d, using model 2", model)
 		model = QRCodeModel2 // Default to Model 2 if invalid
 	}
 
+	if !e.profile.SupportsQRCode() || e.blockEnd != nil {
+		if e.blockEnd == nil {
+			e.warn(WarningFallback, "%s does not support QR codes, printed as an image", e.profile.name())
+		}
+		bc, err := encodeQRCode(data, correctionLevel)
+		if err != nil {
+			return 0, err
+		}
+		return e.printQRCodeImage(bc, bc.Bounds().Dx()*int(size))
+	}
+
 	var written int
 	var err error
 
@@ -574,12 +827,15 @@ func (e *Escpos) QRCode(code string, model uint8, size uint8, correctionLevel ui
 	}
 
 	// Store the data in the buffer
-	var codeLength = len(code) + 3
-	var pL, pH byte
-	pH = byte(codeLength / 256)
-	pL = byte(codeLength % 256)
+	// pL and pH count the data and the cn, fn and m bytes
+	pLH, err := LowHigh16(len(data) + 3)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode QR code data length: %w", err)
+	}
 
-	written, err = e.WriteRaw(append([]byte{gs, '(', 'k', pL, pH, 49, 80, 48}, []byte(code)...))
+	cmd := make([]byte, 0, 8+len(data))
+	cmd = append(cmd, gs, '(', 'k', pLH[0], pLH[1], 49, 80, 48)
+	written, err = e.WriteRaw(append(cmd, data...))
 	if err != nil {
 		return written, fmt.Errorf("failed to store QR code data: %w", err)
 	}
@@ -602,29 +858,53 @@ func (e *Escpos) QRCode(code string, model uint8, size uint8, correctionLevel ui
 //
 // Returns the number of bytes written and any error encountered
 func (e *Escpos) PrintImageWithProcessing(image image.Image, processMethod uint8, highDensityVertical bool, highDensityHorizontal bool) (int, error) {
-	switch processMethod {
+	raster, err := processImage(image, ImageOptions{
+		Process:               processMethod,
+		HighDensityVertical:   highDensityVertical,
+		HighDensityHorizontal: highDensityHorizontal,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return e.printRaster(raster)
+}
+
+// processImage converts an image to a raster image with the processing of opts
+func processImage(image image.Image, opts ImageOptions) (rasterImage, error) {
+	if err := opts.validate(); err != nil {
+		return rasterImage{}, err
+	}
+	image = opts.adjusted(image)
+
+	switch opts.Process {
 	case ImageProcessDither:
-		data, err := PrepareImageForPrinting(image, highDensityVertical, highDensityHorizontal)
+		raster, err := ditherImage(image, opts.HighDensityVertical, opts.HighDensityHorizontal)
 		if err != nil {
-			return 0, fmt.Errorf("failed to transform dithered image: %w", err)
+			return rasterImage{}, fmt.Errorf("failed to transform dithered image: %w", err)
 		}
-		return e.WriteRaw(data)
+		return raster, nil
 
 	case ImageProcessThreshold:
 		// Use the traditional threshold-based conversion
-		xL, xH, yL, yH, data := printImage(image)
-		return e.WriteRaw(append([]byte{gs, 'v', 48, 0, xL, xH, yL, yH}, data...))
+		xL, xH, yL, yH, data := printImage(image, opts.threshold())
+		return rasterImage{
+			widthBytes: int(xL) | int(xH)<<8,
+			height:     int(yL) | int(yH)<<8,
+			data:       data,
+		}, nil
 
 	default:
-		return 0, fmt.Errorf("unknown image processing method: %d", processMethod)
+		return rasterImage{}, fmt.Errorf("unknown image processing method: %d", opts.Process)
 	}
-
 }
 
 // PrintNVBitImage prints a pre-stored bit image with index p and mode
 // p: image index (1-based)
 // mode: print mode (0-3)
 func (e *Escpos) PrintNVBitImage(p uint8, mode uint8) (int, error) {
+	if err := e.checkUpright("NV bit images"); err != nil {
+		return 0, err
+	}
 	if p == 0 {
 		return 0, fmt.Errorf("NV bit image index must be at least 1")
 	}
@@ -642,7 +922,30 @@ func (e *Escpos) LineFeed() (int, error) {
 
 // LineFeedN prints and feeds the paper p lines
 func (e *Escpos) LineFeedN(p uint8) (int, error) {
-	return e.WriteRaw([]byte{esc, 'd', p})
+	return e.endBlock(e.WriteRaw([]byte{esc, 'd', p}))
+}
+
+// FeedDots prints and feeds the paper n dots (ESC J)
+func (e *Escpos) FeedDots(n uint8) (int, error) {
+	return e.endBlock(e.WriteRaw([]byte{esc, 'J', n}))
+}
+
+// ReverseFeedDots prints and feeds the paper back n dots (ESC K), e.g. to
+// pull it back before cutting and reduce the blank margin at the top of the
+// next ticket. Printers limit the distance fed back.
+func (e *Escpos) ReverseFeedDots(n uint8) (int, error) {
+	if e.profile.NoReverseFeed {
+		return 0, fmt.Errorf("%s cannot feed the paper back", e.profile.name())
+	}
+	return e.endBlock(e.WriteRaw([]byte{esc, 'K', n}))
+}
+
+// ReverseFeedLines prints and feeds the paper back n lines (ESC e)
+func (e *Escpos) ReverseFeedLines(n uint8) (int, error) {
+	if e.profile.NoReverseFeed {
+		return 0, fmt.Errorf("%s cannot feed the paper back", e.profile.name())
+	}
+	return e.endBlock(e.WriteRaw([]byte{esc, 'e', n}))
 }
 
 // SetDefaultLineSpacing sets the line spacing to the default (1/6 inch)
@@ -655,9 +958,23 @@ func (e *Escpos) SetLineSpacing(p uint8) (int, error) {
 	return e.WriteRaw([]byte{esc, '3', p})
 }
 
-// Initialize resets the printer to its default settings
+// Initialize resets the printer to its default settings, then selects the
+// encoding and international character set of the profile, and the large
+// print mode, again
 func (e *Escpos) Initialize() (int, error) {
-	return e.WriteRaw([]byte{esc, '@'})
+	e.Style = Style{}
+	e.kanjiMode, e.kanjiCode = false, KanjiCodeJIS
+	n, err := e.WriteRaw([]byte{esc, '@'})
+	if err != nil {
+		return n, err
+	}
+	m, err := e.applyProfileCharset()
+	n += m
+	if err != nil || !e.largePrint {
+		return n, err
+	}
+	m, err = e.applyLargePrint()
+	return n + m, err
 }
 
 // SetMotionUnits sets the horizontal (x) and vertical (y) motion units
@@ -667,14 +984,56 @@ func (e *Escpos) SetMotionUnits(x, y uint8) (int, error) {
 	return e.WriteRaw([]byte{gs, 'P', x, y})
 }
 
-// Cut feeds the paper to the cutting position and cuts it
+// Cut feeds the paper to the cutting position and cuts it,
+// applying the cut offset of the profile. Printers without a cutter print a
+// tear line instead.
 func (e *Escpos) Cut() (int, error) {
-	return e.WriteRaw([]byte{gs, 'V', 'A', 0x00})
+	if e.profile.NoCutter {
+		return e.TearLine()
+	}
+	return e.cut('A', e.profile.CutFeed)
 }
 
-// PartialCut performs a partial paper cut
+// PartialCut performs a partial paper cut, applying the cut offset of the
+// profile. Printers without a cutter print a tear line instead.
 func (e *Escpos) PartialCut() (int, error) {
-	return e.WriteRaw([]byte{gs, 'V', 'B', 0x00})
+	if e.profile.NoCutter {
+		return e.TearLine()
+	}
+	return e.cut('B', e.profile.CutFeed)
+}
+
+// CutWithFeed feeds the paper to the cutting position plus feedDots dots,
+// instead of the CutFeed of the profile, and cuts it. Printers without a
+// cutter print a tear line instead.
+func (e *Escpos) CutWithFeed(feedDots uint8) (int, error) {
+	if e.profile.NoCutter {
+		return e.TearLine()
+	}
+	return e.cut('A', feedDots)
+}
+
+// PartialCutWithFeed feeds the paper to the cutting position plus feedDots
+// dots, instead of the CutFeed of the profile, and performs a partial cut.
+// Printers without a cutter print a tear line instead.
+func (e *Escpos) PartialCutWithFeed(feedDots uint8) (int, error) {
+	if e.profile.NoCutter {
+		return e.TearLine()
+	}
+	return e.cut('B', feedDots)
+}
+
+// cut feeds the paper to the cutting position plus feed dots, cuts it with
+// mode m, then feeds back the CutReverseFeed of the profile
+func (e *Escpos) cut(m byte, feed uint8) (int, error) {
+	if e.star() {
+		return e.WriteRaw(starCut(m))
+	}
+	cmd := []byte{gs, 'V', m, feed}
+	if e.profile.CutReverseFeed > 0 && !e.profile.NoReverseFeed {
+		cmd = append(cmd, esc, 'K', e.profile.CutReverseFeed)
+	}
+	return e.WriteRaw(cmd)
 }
 
 // OpenDrawer opens the cash drawer connected to the printer
@@ -682,12 +1041,12 @@ func (e *Escpos) PartialCut() (int, error) {
 // time: pulse duration (1-8) * 100ms
 func (e *Escpos) OpenDrawer(pin uint8, time uint8) (int, error) {
 	if pin > 1 {
+		e.warn(WarningClamped, "drawer pin %d out of range 0-1, using 0", pin)
 		pin = 0
 	}
-	if time < 1 {
-		time = 1
-	} else if time > 8 {
-		time = 8
+	time = e.clamped("drawer pulse", time, 1, 8)
+	if e.star() {
+		return e.WriteRaw(starDrawer(pin))
 	}
 	return e.WriteRaw([]byte{esc, 'p', pin, time, time})
 }
@@ -699,8 +1058,21 @@ func (e *Escpos) SetCodePage(codepage uint8) (int, error) {
 }
 
 // QueryStatus sends a real-time status request to the printer and returns the response
-// The parameter 'statusType' should be one of the RT_STATUS_* constants
+// The parameter 'statusType' should be one of the RT_STATUS_* constants.
+// The buffered data is sent first, then the printer is polled until it
+// answers or the status timeout expires, see WithStatusTimeout; an empty
+// response is returned on timeout.
 func (e *Escpos) QueryStatus(statusType byte) ([]byte, error) {
+	if e.reader == nil {
+		return nil, fmt.Errorf("reader not available")
+	}
+
+	// Send the pending data before the request, so the answers of the
+	// commands it holds are not taken for the status
+	if err := e.flush(); err != nil {
+		return nil, fmt.Errorf("failed to flush pending data: %w", err)
+	}
+
 	// Send the real-time status request
 	_, err := e.WriteRaw([]byte{dle, 0x04, statusType})
 	if err != nil {
@@ -708,24 +1080,23 @@ func (e *Escpos) QueryStatus(statusType byte) ([]byte, error) {
 	}
 
 	// Flush the buffer to ensure the command is sent immediately
-	err = e.dst.Flush()
+	err = e.flush()
 	if err != nil {
 		return nil, fmt.Errorf("failed to flush status request: %w", err)
 	}
 
-	// Give the printer some time to respond
-	time.Sleep(100 * time.Millisecond)
-
 	// Read the response
-	if e.reader == nil {
-		return nil, fmt.Errorf("reader not available")
-	}
-
 	buf := make([]byte, 1)
-	n, err := e.reader.Read(buf)
+	n, err := e.readStatus(buf)
 	if err != nil {
+		e.logStatus(statusType, nil, err)
 		return nil, fmt.Errorf("failed to read status response: %w", err)
 	}
+	e.logStatus(statusType, buf[:n], nil)
+
+	e.lastStatusType = statusType
+	e.lastStatusTime = time.Now()
+	e.lastStatus = buf[:n]
 
 	if n == 0 {
 		return []byte{}, nil
@@ -792,6 +1163,46 @@ func boolToByte(b bool) byte {
 	return 0x00
 }
 
+// validateCode39 checks that a Code 39 code only uses the Code 39 character set
+func validateCode39(code string) error {
+	body, offset := code, 0
+	if strings.HasPrefix(body, "*") || strings.HasSuffix(body, "*") {
+		if len(body) < 2 || !strings.HasPrefix(body, "*") || !strings.HasSuffix(body, "*") {
+			return invalidBarcode(BarcodeCode39, "CODE39 code must either start and end with '*' or not use it at all")
+		}
+		// Positions are reported in the code as given, delimiters included
+		body, offset = body[1:len(body)-1], 1
+	}
+	if body == "" {
+		return invalidBarcode(BarcodeCode39, "CODE39 code cannot be empty")
+	}
+	for i, c := range body {
+		if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || strings.ContainsRune(" -.$/+%", c)) {
+			return invalidBarcode(BarcodeCode39, "CODE39 code contains an invalid character %q at position %d (allowed: A-Z, 0-9, space and -.$/+%%)", c, i+offset)
+		}
+	}
+	return nil
+}
+
+// validateCodabar checks that a Codabar code has start/stop characters and only uses the Codabar character set
+func validateCodabar(code string) error {
+	if len(code) < 3 {
+		return invalidBarcode(BarcodeCodabar, "CODABAR code must have a start character, at least one data character and a stop character")
+	}
+	isStartStop := func(c byte) bool {
+		return c >= 'A' && c <= 'D' || c >= 'a' && c <= 'd'
+	}
+	if !isStartStop(code[0]) || !isStartStop(code[len(code)-1]) {
+		return invalidBarcode(BarcodeCodabar, "CODABAR code must start and end with a start/stop character (A-D)")
+	}
+	for i, c := range code[1 : len(code)-1] {
+		if !(c >= '0' && c <= '9' || strings.ContainsRune("-$:/.+", c)) {
+			return invalidBarcode(BarcodeCodabar, "CODABAR code contains an invalid character %q at position %d (allowed: 0-9 and -$:/.+)", c, i+1)
+		}
+	}
+	return nil
+}
+
 // onlyDigits checks if a string contains only digits
 func onlyDigits(s string) bool {
 	if s == "" {
@@ -804,3 +1215,13 @@ func onlyDigits(s string) bool {
 	}
 	return true
 }
+
+// isASCII checks if a string only contains 7-bit ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
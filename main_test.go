@@ -1,46 +1,19 @@
 package escpos
 
 import (
-	"bytes"
 	"image"
 	"image/color"
 	"testing"
 
+	"github.com/schawnndev/escpos/escpostest"
 	"github.com/stretchr/testify/assert"
 )
 
-// MockPrinter implements the Printer interface for testing
-type MockPrinter struct {
-	buf    bytes.Buffer
-	status []byte
-}
-
-func (m *MockPrinter) Close() error {
-	return nil
-}
-
-func (m *MockPrinter) Write(p []byte) (n int, err error) {
-	return m.buf.Write(p)
-}
-
-func (m *MockPrinter) Read(p []byte) (n int, err error) {
-	if len(m.status) > 0 {
-		n = copy(p, m.status)
-		return n, nil
-	}
-	return 0, nil
-}
-
-func (m *MockPrinter) Bytes() []byte {
-	return m.buf.Bytes()
-}
-
-func (m *MockPrinter) SetStatus(status []byte) {
-	m.status = status
-}
+// MockPrinter is the mock printer of the escpostest package
+type MockPrinter = escpostest.MockPrinter
 
 func NewMockPrinter() *MockPrinter {
-	return &MockPrinter{}
+	return escpostest.NewMockPrinter()
 }
 
 // TestNew tests creating a new Escpos instance
@@ -89,6 +62,21 @@ func TestWrite(t *testing.T) {
 	assert.Equal(t, []byte(text), mock.Bytes())
 }
 
+// TestWriteCodePage tests that the code page is only re-sent before non-ASCII text
+func TestWriteCodePage(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.Write("Cafe ")
+	assert.NoError(t, err)
+	_, err = p.Write("Crème")
+	assert.NoError(t, err)
+	assert.NoError(t, p.Print())
+
+	expected := append([]byte("Cafe "), esc, 't', CodePagePC850, 'C', 'r', 0x8A, 'm', 'e')
+	assert.Equal(t, expected, mock.Bytes())
+}
+
 // TestPrint tests flushing data to the printer
 func TestPrint(t *testing.T) {
 	mock := NewMockPrinter()
@@ -176,6 +164,48 @@ func TestSetJustify(t *testing.T) {
 	assert.Contains(t, err.Error(), "justification is disabled")
 }
 
+// TestSetFont tests selecting fonts
+func TestSetFont(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.SetFont(FontC)
+	assert.NoError(t, err)
+	_, err = p.SetFont(FontSpecialB)
+	assert.NoError(t, err)
+	_, err = p.SetFont(42) // unknown, falls back to Font A
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{esc, 'M', FontC, esc, 'M', FontSpecialB, esc, 'M', FontA}
+	assert.Equal(t, expected, mock.Bytes())
+
+	// Fonts missing from the profile are rejected
+	p.SetProfile(ProfileEpsonTMT20II)
+	_, err = p.SetFont(FontB)
+	assert.NoError(t, err)
+	_, err = p.SetFont(FontC)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not supported by Epson TM-T20II")
+}
+
+// TestSetSmoothing tests toggling smoothing
+func TestSetSmoothing(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.SetSmoothing(true)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{gs, 'b', 1}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
 // TestSetBold tests setting bold mode
 func TestSetBold(t *testing.T) {
 	mock := NewMockPrinter()
@@ -252,6 +282,38 @@ func TestSetUnderline(t *testing.T) {
 	assert.Contains(t, err.Error(), "underline mode is disabled")
 }
 
+// TestSetCharacterSpacing tests setting the character spacing
+func TestSetCharacterSpacing(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.SetCharacterSpacing(4)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{esc, ' ', 4}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestSetDoubleStrike tests toggling the double-strike mode
+func TestSetDoubleStrike(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.SetDoubleStrike(true)
+	assert.NoError(t, err)
+	_, err = p.SetDoubleStrike(false)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{esc, 'G', 1, esc, 'G', 0}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
 // TestBarcode tests printing barcodes
 func TestBarcode(t *testing.T) {
 	// Test valid EAN13
@@ -284,6 +346,91 @@ func TestBarcode(t *testing.T) {
 	assert.Contains(t, err.Error(), "should have 12 or 13 digits")
 }
 
+// TestBarcodeB tests printing function B barcodes
+func TestBarcodeB(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	// CODE128 with code set B selected
+	data := []byte("{BHello")
+	_, err := p.BarcodeB(BarcodeBCode128, data)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := append([]byte{gs, 'k', BarcodeBCode128, byte(len(data))}, data...)
+	assert.Equal(t, expected, mock.Bytes())
+
+	// Function A types are rejected
+	_, err = p.BarcodeB(BarcodeEAN13, []byte("1234567890128"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid function B barcode type")
+
+	// Length must fit in one byte
+	_, err = p.BarcodeB(BarcodeBCode128, make([]byte, 256))
+	assert.Error(t, err)
+	_, err = p.BarcodeB(BarcodeBCode128, nil)
+	assert.Error(t, err)
+}
+
+// TestBarcodeCharset tests the CODE39 and CODABAR character set validation
+func TestBarcodeCharset(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.CODE39("ABC-12 $/+%.")
+	assert.NoError(t, err)
+	_, err = p.CODE39("*ABC*")
+	assert.NoError(t, err)
+	_, err = p.CODABAR("A40156B")
+	assert.NoError(t, err)
+	_, err = p.CODABAR("c1-2$3:4/5.6+d")
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	invalid := []struct {
+		barcodeType uint8
+		code        string
+	}{
+		{BarcodeCode39, "abc"},
+		{BarcodeCode39, "AB*C"},
+		{BarcodeCode39, "*ABC"},
+		{BarcodeCode39, "**"},
+		{BarcodeCode39, ""},
+		{BarcodeCodabar, "40156"},
+		{BarcodeCodabar, "A40156"},
+		{BarcodeCodabar, "A401X6B"},
+		{BarcodeCodabar, "AB"},
+	}
+	for _, tc := range invalid {
+		_, err = p.Barcode(tc.barcodeType, tc.code)
+		assert.Error(t, err, tc.code)
+	}
+}
+
+// TestCode39Delimiters tests wrapping Code 39 data in start/stop characters
+func TestCode39Delimiters(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetCode39Delimiters(true)
+
+	_, err := p.CODE39("ABC")
+	assert.NoError(t, err)
+	_, err = p.CODE39("*DEF*")
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := append([]byte{gs, 'k', BarcodeCode39}, []byte("*ABC*\x00")...)
+	expected = append(expected, gs, 'k', BarcodeCode39)
+	expected = append(expected, []byte("*DEF*\x00")...)
+	assert.Equal(t, expected, mock.Bytes())
+}
+
 // TestQRCode tests printing QR codes
 func TestQRCode(t *testing.T) {
 	mock := NewMockPrinter()
@@ -335,6 +482,34 @@ func TestQRCode(t *testing.T) {
 	assert.Contains(t, string(output), string(modelCmd))
 }
 
+// TestQRCodeBytes tests printing QR codes from binary data
+func TestQRCodeBytes(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	// 300 bytes of binary data, including NUL and non UTF-8 bytes
+	data := make([]byte, 300)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	_, err := p.QRCodeBytes(data, QRCodeModel2, 4, QRCodeErrorCorrectionLevelL)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	// 303 = 0x012F
+	dataCmd := append([]byte{gs, '(', 'k', 0x2F, 0x01, 49, 80, 48}, data...)
+	assert.Contains(t, string(mock.Bytes()), string(dataCmd))
+
+	// Empty and oversized data
+	_, err = p.QRCodeBytes(nil, QRCodeModel2, 4, QRCodeErrorCorrectionLevelL)
+	assert.Error(t, err)
+	_, err = p.QRCodeBytes(make([]byte, 1168), QRCodeModel1, 4, QRCodeErrorCorrectionLevelL)
+	assert.Error(t, err)
+}
+
 // TestCut tests cutting the paper
 func TestCut(t *testing.T) {
 	mock := NewMockPrinter()
@@ -365,6 +540,59 @@ func TestPartialCut(t *testing.T) {
 	assert.Equal(t, expected, mock.Bytes())
 }
 
+// TestFeedDots tests feeding the paper forward and back
+func TestFeedDots(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.FeedDots(30)
+	assert.NoError(t, err)
+	_, err = p.ReverseFeedDots(24)
+	assert.NoError(t, err)
+	_, err = p.ReverseFeedLines(2)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{esc, 'J', 30, esc, 'K', 24, esc, 'e', 2}
+	assert.Equal(t, expected, mock.Bytes())
+
+	// Printers unable to feed back
+	p.SetProfile(Profile{NoReverseFeed: true})
+	_, err = p.ReverseFeedDots(24)
+	assert.Error(t, err)
+	_, err = p.ReverseFeedLines(2)
+	assert.Error(t, err)
+}
+
+// TestCutWithFeed tests cutting with a custom feed
+func TestCutWithFeed(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{CutFeed: 10, CutReverseFeed: 20})
+
+	_, err := p.CutWithFeed(40)
+	assert.NoError(t, err)
+	_, err = p.PartialCutWithFeed(0)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	// The custom feed replaces the CutFeed of the profile
+	expected := []byte{gs, 'V', 'A', 40, esc, 'K', 20, gs, 'V', 'B', 0, esc, 'K', 20}
+	assert.Equal(t, expected, mock.Bytes())
+
+	// No reverse feed on printers unable to feed back
+	mock.Reset()
+	p.SetProfile(Profile{CutReverseFeed: 20, NoReverseFeed: true})
+	_, err = p.CutWithFeed(40)
+	assert.NoError(t, err)
+	assert.NoError(t, p.Print())
+	assert.Equal(t, []byte{gs, 'V', 'A', 40}, mock.Bytes())
+}
+
 // TestOpenDrawer tests opening the cash drawer
 func TestOpenDrawer(t *testing.T) {
 	mock := NewMockPrinter()
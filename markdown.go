@@ -0,0 +1,226 @@
+package escpos
+
+import (
+	"strings"
+)
+
+// PrintMarkdown prints a Markdown document, supporting a subset of the syntax:
+//   - headings: "#" in double width and height, "##" in double height, the
+//     other levels in bold, all in bold
+//   - **bold** or __bold__, and *emphasis* or _emphasis_ printed underlined,
+//     printers having no italics
+//   - horizontal rules (---, *** or ___), printed as a divider
+//   - bullet lists (-, * or +) and numbered lists
+//   - fenced code blocks (```), printed verbatim in Font B
+//
+// Consecutive lines are joined into paragraphs, separated by an empty line.
+func (e *Escpos) PrintMarkdown(src string) (int, error) {
+	written := 0
+	write := func(n int, err error) error {
+		written += n
+		return err
+	}
+
+	var paragraph []string
+	flush := func() error {
+		if len(paragraph) == 0 {
+			return nil
+		}
+		text := strings.Join(paragraph, " ")
+		paragraph = nil
+		return write(e.writeInline(text+"\n", e.Style))
+	}
+
+	code := false
+	blank := false
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if err := flush(); err != nil {
+				return written, err
+			}
+			code = !code
+			font := FontA
+			if code {
+				font = FontB
+			}
+			if err := write(e.SetFont(font)); err != nil {
+				return written, err
+			}
+			continue
+		}
+		if code {
+			if err := write(e.Write(line + "\n")); err != nil {
+				return written, err
+			}
+			continue
+		}
+
+		if trimmed == "" {
+			if err := flush(); err != nil {
+				return written, err
+			}
+			blank = true
+			continue
+		}
+		if blank && written > 0 {
+			if err := write(e.Write("\n")); err != nil {
+				return written, err
+			}
+		}
+		blank = false
+
+		if level, text, ok := markdownHeading(trimmed); ok {
+			if err := flush(); err != nil {
+				return written, err
+			}
+			s := e.Style
+			s.Bold = true
+			switch level {
+			case 1:
+				s.Width, s.Height = 2, 2
+			case 2:
+				s.Height = 2
+			}
+			if err := write(e.writeInline(text+"\n", s)); err != nil {
+				return written, err
+			}
+			continue
+		}
+		if isMarkdownRule(trimmed) {
+			if err := flush(); err != nil {
+				return written, err
+			}
+			if err := write(e.Divider('-')); err != nil {
+				return written, err
+			}
+			continue
+		}
+		if marker, text, ok := markdownListItem(trimmed); ok {
+			if err := flush(); err != nil {
+				return written, err
+			}
+			if err := write(e.writeInline(marker+" "+text+"\n", e.Style)); err != nil {
+				return written, err
+			}
+			continue
+		}
+		paragraph = append(paragraph, trimmed)
+	}
+
+	if err := flush(); err != nil {
+		return written, err
+	}
+	if code {
+		// Unterminated code block
+		return written, write(e.SetFont(FontA))
+	}
+	return written, nil
+}
+
+// writeInline writes text with the inline emphasis, on top of the style s
+func (e *Escpos) writeInline(text string, s Style) (int, error) {
+	written := 0
+	bold, emphasis := false, false
+	var run strings.Builder
+
+	emit := func() error {
+		if run.Len() == 0 {
+			return nil
+		}
+		style := s
+		style.Bold = s.Bold || bold
+		if emphasis && style.Underline == UnderlineNone {
+			style.Underline = UnderlineSingle
+		}
+		n, err := e.WriteStyled(run.String(), style)
+		written += n
+		run.Reset()
+		return err
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("\\*_`", text[i+1]) >= 0:
+			i++
+			run.WriteByte(text[i])
+		case c == '_' && i > 0 && isWordByte(text[i-1]) && i+1 < len(text) && isWordByte(text[i+1]):
+			// snake_case
+			run.WriteByte(c)
+		case (c == '*' || c == '_') && i+1 < len(text) && text[i+1] == c && (bold || strings.Contains(text[i+2:], text[i:i+2])):
+			if err := emit(); err != nil {
+				return written, err
+			}
+			bold = !bold
+			i++
+		case (c == '*' || c == '_') && i+1 < len(text) && text[i+1] == c:
+			// Unmatched strong emphasis is printed as is
+			run.WriteString(text[i : i+2])
+			i++
+		case (c == '*' || c == '_') && (emphasis || strings.Contains(text[i+1:], text[i:i+1])):
+			if err := emit(); err != nil {
+				return written, err
+			}
+			emphasis = !emphasis
+		case c == '`':
+			// Inline code is printed verbatim
+			end := strings.IndexByte(text[i+1:], '`')
+			if end < 0 {
+				run.WriteByte(c)
+				continue
+			}
+			run.WriteString(text[i+1 : i+1+end])
+			i += end + 1
+		default:
+			run.WriteByte(c)
+		}
+	}
+	if err := emit(); err != nil {
+		return written, err
+	}
+	return written, nil
+}
+
+// isWordByte reports whether c is an ASCII letter or digit
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// markdownHeading parses a "# Heading" line
+func markdownHeading(line string) (int, string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level == len(line) || line[level] != ' ' {
+		return 0, "", false
+	}
+	return level, strings.TrimSpace(strings.TrimRight(line[level:], "#")), true
+}
+
+// isMarkdownRule reports whether a line is a horizontal rule, three or more
+// '-', '*' or '_' optionally separated by spaces
+func isMarkdownRule(line string) bool {
+	stripped := strings.ReplaceAll(line, " ", "")
+	if len(stripped) < 3 {
+		return false
+	}
+	return strings.Count(stripped, stripped[:1]) == len(stripped) && strings.Contains("-*_", stripped[:1])
+}
+
+// markdownListItem parses a bullet or numbered list item, returning its marker
+func markdownListItem(line string) (string, string, bool) {
+	if len(line) > 2 && strings.IndexByte("-*+", line[0]) >= 0 && line[1] == ' ' {
+		return "-", strings.TrimSpace(line[2:]), true
+	}
+	digits := 0
+	for digits < len(line) && line[digits] >= '0' && line[digits] <= '9' {
+		digits++
+	}
+	if digits > 0 && digits+2 < len(line) && (line[digits] == '.' || line[digits] == ')') && line[digits+1] == ' ' {
+		return line[:digits+1], strings.TrimSpace(line[digits+2:]), true
+	}
+	return "", "", false
+}
@@ -0,0 +1,60 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintMarkdown tests printing the supported Markdown syntax
+func TestPrintMarkdown(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetCharsPerLine(10)
+
+	src := "# Title\n" +
+		"Some **bold** and\n" +
+		"*emphasis* text.\n" +
+		"\n" +
+		"- one\n" +
+		"2. two\n" +
+		"---\n" +
+		"```\n" +
+		"a *b*\n" +
+		"```\n"
+	_, err := p.PrintMarkdown(src)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := "\x1bE\x01\x1d!\x11Title\n\x1bE\x00\x1d!\x00" +
+		"Some \x1bE\x01bold\x1bE\x00 and \x1b-\x01emphasis\x1b-\x00 text.\n" +
+		"\n" +
+		"- one\n" +
+		"2. two\n" +
+		"----------\n" +
+		"\x1bM\x01a *b*\n\x1bM\x00"
+	assert.Equal(t, expected, mock.String())
+}
+
+// TestPrintMarkdownInline tests the edge cases of the inline emphasis
+func TestPrintMarkdownInline(t *testing.T) {
+	for src, expected := range map[string]string{
+		"snake_case_name":   "snake_case_name\n",
+		"2 * 3 = 6":         "2 * 3 = 6\n",
+		`\*literal\*`:       "*literal*\n",
+		"`**code**`":        "**code**\n",
+		"## Sub":            "\x1bE\x01\x1d!\x01Sub\n\x1bE\x00\x1d!\x00",
+		"### Small":         "\x1bE\x01Small\n\x1bE\x00",
+		"__bold__ _em_":     "\x1bE\x01bold\x1bE\x00 \x1b-\x01em\x1b-\x00\n",
+		"* * *":             "------------------------------------------------\n",
+		"no closing **bold": "no closing **bold\n",
+	} {
+		mock := NewMockPrinter()
+		p := New(mock)
+		_, err := p.PrintMarkdown(src)
+		require.NoError(t, err)
+		require.NoError(t, p.Print())
+		assert.Equal(t, expected, mock.String(), src)
+	}
+}
@@ -0,0 +1,54 @@
+package escpos
+
+import "time"
+
+// Metrics receives the activity of a printer, to be exported to a monitoring
+// system such as Prometheus (jobs per minute, failures, paper-out events).
+// The methods are called synchronously and should return quickly.
+type Metrics interface {
+	// OnWrite is called with the number of bytes of each write to the printer
+	OnWrite(bytes int)
+	// OnJobComplete is called by Print and PrintAndCut with the time since
+	// the first command of the job was buffered and the error, if any
+	OnJobComplete(duration time.Duration, err error)
+	// OnStatusChange is called by FullStatus when the status differs from
+	// the previous one, and for the first status
+	OnStatusChange(status PrinterStatus)
+}
+
+// WithMetrics reports the activity of the printer to m (nil: no metrics)
+func WithMetrics(m Metrics) Option {
+	return func(e *Escpos) {
+		e.metrics = m
+		e.out.onWrite = nil
+		if m != nil {
+			e.out.onWrite = m.OnWrite
+		}
+	}
+}
+
+// startJob starts the clock of the job on the first command buffered. The
+// real-time commands, such as status queries, do not start a job.
+func (e *Escpos) startJob(data []byte) {
+	if e.metrics != nil && e.jobStart.IsZero() && data[0] != dle {
+		e.jobStart = time.Now()
+	}
+}
+
+// completeJob reports the end of the job started, if any
+func (e *Escpos) completeJob(err error) {
+	if e.metrics == nil || e.jobStart.IsZero() {
+		return
+	}
+	e.metrics.OnJobComplete(time.Since(e.jobStart), err)
+	e.jobStart = time.Time{}
+}
+
+// reportStatus reports a status differing from the previous one
+func (e *Escpos) reportStatus(status PrinterStatus) {
+	if e.metrics == nil || (e.reportedStatus != nil && *e.reportedStatus == status) {
+		return
+	}
+	e.reportedStatus = &status
+	e.metrics.OnStatusChange(status)
+}
@@ -0,0 +1,89 @@
+package escpos
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMetrics records the calls of the metrics hooks
+type recordingMetrics struct {
+	written  int
+	jobs     []error
+	statuses []PrinterStatus
+}
+
+func (m *recordingMetrics) OnWrite(bytes int) {
+	m.written += bytes
+}
+
+func (m *recordingMetrics) OnJobComplete(duration time.Duration, err error) {
+	m.jobs = append(m.jobs, err)
+}
+
+func (m *recordingMetrics) OnStatusChange(status PrinterStatus) {
+	m.statuses = append(m.statuses, status)
+}
+
+// TestMetricsJobs tests reporting the bytes written and the jobs
+func TestMetricsJobs(t *testing.T) {
+	metrics := &recordingMetrics{}
+	mock := NewMockPrinter()
+	p := New(mock, WithMetrics(metrics))
+
+	p.Write("Hello\n")
+	require.NoError(t, p.PrintAndCut())
+	assert.Equal(t, len(mock.Bytes()), metrics.written)
+	assert.Equal(t, []error{nil}, metrics.jobs)
+
+	// Flushing an empty buffer is not a job
+	require.NoError(t, p.Print())
+	assert.Len(t, metrics.jobs, 1)
+
+	mock.FailWrites(errors.New("offline"))
+	p.Write("World\n")
+	err := p.Print()
+	require.Error(t, err)
+	require.Len(t, metrics.jobs, 2)
+	assert.Equal(t, err, metrics.jobs[1])
+}
+
+// TestMetricsStatusChange tests reporting the status changes only
+func TestMetricsStatusChange(t *testing.T) {
+	metrics := &recordingMetrics{}
+	mock := NewMockPrinter()
+	mock.SetStatus([]byte{0x12})
+	p := New(mock, WithMetrics(metrics))
+
+	_, err := p.FullStatus()
+	require.NoError(t, err)
+	_, err = p.FullStatus()
+	require.NoError(t, err)
+	require.Len(t, metrics.statuses, 1)
+	assert.True(t, metrics.statuses[0].Online)
+
+	mock.SetStatus([]byte{0x12 | RT_MASK_NOPAPER})
+	_, err = p.FullStatus()
+	require.NoError(t, err)
+	require.Len(t, metrics.statuses, 2)
+	assert.True(t, metrics.statuses[1].PaperOut)
+
+	// Status queries are not jobs
+	assert.Empty(t, metrics.jobs)
+}
+
+// TestMetricsNil tests that nil metrics report nothing
+func TestMetricsNil(t *testing.T) {
+	metrics := &recordingMetrics{}
+	mock := NewMockPrinter()
+	p := New(mock, WithMetrics(metrics), WithMetrics(nil))
+
+	p.Write("Hello\n")
+	require.NoError(t, p.PrintAndCut())
+	assert.NotEmpty(t, mock.Bytes())
+	assert.Zero(t, metrics.written)
+	assert.Empty(t, metrics.jobs)
+}
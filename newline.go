@@ -0,0 +1,48 @@
+package escpos
+
+import "strings"
+
+// NewlinePolicy is the line ending sent for the line breaks of the written text
+type NewlinePolicy uint8
+
+const (
+	// NewlineKeep sends the text as is (default)
+	NewlineKeep NewlinePolicy = iota
+	// NewlineLF ends lines with LF, printing and feeding one line
+	NewlineLF
+	// NewlineCRLF ends lines with CR LF, for the emulation modes where LF
+	// does not return the carriage
+	NewlineCRLF
+	// NewlineCR ends lines with CR, for the printers configured to print and
+	// feed on CR
+	NewlineCR
+)
+
+// lineEndings holds the line ending of each policy
+var lineEndings = [...]string{NewlineLF: "\n", NewlineCRLF: "\r\n", NewlineCR: "\r"}
+
+// newlineReplacer turns the CR LF, CR and LF line breaks into LF
+var newlineReplacer = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// SetNewlinePolicy sets the line ending of the text written with Write and
+// the other text functions. Except with NewlineKeep, the CR LF, CR and LF
+// line breaks of the text are all turned into the line ending of the policy,
+// so text with mixed line endings is not double spaced.
+func (e *Escpos) SetNewlinePolicy(p NewlinePolicy) {
+	e.newline = p
+}
+
+// normalizeNewlines returns data with its line breaks turned into the line
+// ending of the newline policy
+func (e *Escpos) normalizeNewlines(data string) string {
+	if e.newline == NewlineKeep || int(e.newline) >= len(lineEndings) {
+		return data
+	}
+	if strings.ContainsRune(data, '\r') {
+		data = newlineReplacer.Replace(data)
+	}
+	if e.newline == NewlineLF {
+		return data
+	}
+	return strings.ReplaceAll(data, "\n", lineEndings[e.newline])
+}
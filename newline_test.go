@@ -0,0 +1,48 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TestNewlinePolicy tests the normalization of the line endings
+func TestNewlinePolicy(t *testing.T) {
+	text := "a\r\nb\nc\rd"
+	tests := []struct {
+		policy   NewlinePolicy
+		expected string
+	}{
+		{NewlineKeep, text},
+		{NewlineLF, "a\nb\nc\nd"},
+		{NewlineCRLF, "a\r\nb\r\nc\r\nd"},
+		{NewlineCR, "a\rb\rc\rd"},
+	}
+
+	for _, tt := range tests {
+		mock := NewMockPrinter()
+		p := New(mock)
+		p.SetNewlinePolicy(tt.policy)
+
+		_, err := p.Write(text)
+		require.NoError(t, err)
+		require.NoError(t, p.Print())
+		assert.Equal(t, tt.expected, string(mock.Bytes()), "policy %d", tt.policy)
+	}
+}
+
+// TestNewlinePolicyEncoding tests the normalization of the text written with an encoding
+func TestNewlinePolicyEncoding(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetNewlinePolicy(NewlineCRLF)
+
+	_, err := p.WriteWithEncoding("é\n", charmap.CodePage850, CodePagePC850)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := []byte{esc, 't', CodePagePC850, 0x82, '\r', '\n'}
+	assert.Equal(t, expected, mock.Bytes())
+}
@@ -0,0 +1,186 @@
+package escpos
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"strconv"
+)
+
+// Limits of the FS q NV bit images, in bytes of 8 dots
+const (
+	maxNVBitImageWidthBytes  = 1023
+	maxNVBitImageHeightBytes = 288
+)
+
+// Limits of the GS ( L NV graphics, in dots
+const (
+	maxNVGraphicsWidth  = 8192
+	maxNVGraphicsHeight = 2304
+)
+
+// StoreNVImage stores a logo in the non-volatile memory of the printer, to be
+// printed with PrintNVImage on every receipt without sending it again.
+// NV memory wears out: store logos once, at installation, not on every print.
+//
+// Printers with the NVGraphics profile capability store the image with
+// GS ( L under the key code made of the two digits of index ("07" for 7).
+// Other printers use FS q, which replaces every NV bit image at once: the
+// images stored before with this Escpos are sent again, so index must be at
+// most one more than the number of images stored so far.
+func (e *Escpos) StoreNVImage(index uint8, img image.Image) (int, error) {
+	if index == 0 {
+		return 0, fmt.Errorf("NV image index must be at least 1")
+	}
+	raster, err := ditherImage(img, true, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to transform NV image: %w", err)
+	}
+
+	if e.profile.NVGraphics {
+		return e.storeNVGraphics(index, raster)
+	}
+	return e.storeNVBitImage(index, raster)
+}
+
+// storeNVGraphics stores a raster image under the key code of index (GS ( L function 67)
+func (e *Escpos) storeNVGraphics(index uint8, r rasterImage) (int, error) {
+	width, height := r.widthBytes*8, r.height
+	if width > maxNVGraphicsWidth || height > maxNVGraphicsHeight {
+		return 0, fmt.Errorf("NV image of %dx%d dots exceeds the maximum of %dx%d dots", width, height, maxNVGraphicsWidth, maxNVGraphicsHeight)
+	}
+	if err := e.checkNVCapacity(len(r.data)); err != nil {
+		return 0, err
+	}
+
+	kc1, kc2 := nvKeyCode(index)
+	params := []byte{48, 67, 48, kc1, kc2, 1, byte(width), byte(width >> 8), byte(height), byte(height >> 8), 49}
+	cmd, err := graphicsCommand(params, r.data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode NV image: %w", err)
+	}
+	return e.WriteRaw(cmd)
+}
+
+// storeNVBitImage defines the NV bit images with FS q, index replacing or
+// following the images stored before
+func (e *Escpos) storeNVBitImage(index uint8, r rasterImage) (int, error) {
+	if int(index) > len(e.nvImages)+1 {
+		return 0, fmt.Errorf("NV bit image %d cannot be stored before image %d: FS q stores the images in sequence", index, len(e.nvImages)+1)
+	}
+	heightBytes := (r.height + 7) / 8
+	if r.widthBytes > maxNVBitImageWidthBytes || heightBytes > maxNVBitImageHeightBytes {
+		return 0, fmt.Errorf("NV bit image of %dx%d dots exceeds the maximum of %dx%d dots", r.widthBytes*8, r.height, maxNVBitImageWidthBytes*8, maxNVBitImageHeightBytes*8)
+	}
+
+	images := append([]rasterImage{}, e.nvImages...)
+	if int(index) > len(images) {
+		images = append(images, r)
+	} else {
+		images[index-1] = r
+	}
+
+	cmd := []byte{fs, 'q', byte(len(images))}
+	size := 0
+	for _, img := range images {
+		columns := img.columns()
+		size += len(columns)
+		heightBytes := (img.height + 7) / 8
+		cmd = append(cmd, byte(img.widthBytes), byte(img.widthBytes>>8), byte(heightBytes), byte(heightBytes>>8))
+		cmd = append(cmd, columns...)
+	}
+	if err := e.checkNVCapacity(size); err != nil {
+		return 0, err
+	}
+
+	n, err := e.WriteRaw(cmd)
+	if err != nil {
+		return n, err
+	}
+	e.nvImages = images
+	return n, nil
+}
+
+// checkNVCapacity checks that size bytes of images fit in the NV memory of the profile
+func (e *Escpos) checkNVCapacity(size int) error {
+	if c := e.profile.NVCapacity; c > 0 && size > c {
+		return fmt.Errorf("NV images of %d bytes exceed the NV memory of %d bytes of %s", size, c, e.profile.name())
+	}
+	return nil
+}
+
+// PrintNVImage prints a logo stored with StoreNVImage, in normal size
+func (e *Escpos) PrintNVImage(index uint8) (int, error) {
+	if err := e.checkUpright("NV images"); err != nil {
+		return 0, err
+	}
+	if index == 0 {
+		return 0, fmt.Errorf("NV image index must be at least 1")
+	}
+	if !e.profile.NVGraphics {
+		// FS p n m
+		return e.WriteRaw([]byte{fs, 'p', index, 0})
+	}
+	kc1, kc2 := nvKeyCode(index)
+	return e.WriteRaw([]byte{gs, '(', 'L', 6, 0, 48, 69, kc1, kc2, 1, 1})
+}
+
+// DeleteNVImage deletes a logo stored with StoreNVImage (GS ( L function 66).
+// Printers without the NVGraphics capability cannot delete a single FS q
+// image: store another image under the same index instead.
+func (e *Escpos) DeleteNVImage(index uint8) (int, error) {
+	if !e.profile.NVGraphics {
+		return 0, fmt.Errorf("%s cannot delete a single NV bit image", e.profile.name())
+	}
+	if index == 0 {
+		return 0, fmt.Errorf("NV image index must be at least 1")
+	}
+	kc1, kc2 := nvKeyCode(index)
+	return e.WriteRaw([]byte{gs, '(', 'L', 4, 0, 48, 66, kc1, kc2})
+}
+
+// NVImageCapacity asks the printer for the remaining NV graphics memory in
+// bytes (GS ( L function 51)
+func (e *Escpos) NVImageCapacity() (int, error) {
+	if !e.profile.NVGraphics {
+		return 0, fmt.Errorf("%s does not report its NV memory", e.profile.name())
+	}
+	resp, err := e.request([]byte{gs, '(', 'L', 2, 0, 48, 51})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query NV capacity: %w", err)
+	}
+	if len(resp) < 2 || resp[0] != 0x37 || resp[1] != 0x31 {
+		return 0, fmt.Errorf("invalid NV capacity response: % X", resp)
+	}
+	capacity, err := strconv.Atoi(string(bytes.TrimSpace(resp[2:])))
+	if err != nil {
+		return 0, fmt.Errorf("invalid NV capacity: %w", err)
+	}
+	return capacity, nil
+}
+
+// nvKeyCode returns the key code of an NV image index, its two decimal digits
+func nvKeyCode(index uint8) (byte, byte) {
+	return '0' + index/10, '0' + index%10
+}
+
+// graphicsCommand builds a GS ( L command with the parameters from m on and
+// data, switching to GS 8 L when the length does not fit in 2 bytes
+func graphicsCommand(params, data []byte) ([]byte, error) {
+	length := len(params) + len(data)
+	if length <= 0xFFFF {
+		pLH, err := LowHigh16(length)
+		if err != nil {
+			return nil, err
+		}
+		cmd := append([]byte{gs, '(', 'L'}, pLH...)
+		return append(append(cmd, params...), data...), nil
+	}
+
+	p, err := LowHigh32(length)
+	if err != nil {
+		return nil, err
+	}
+	cmd := append([]byte{gs, '8', 'L'}, p...)
+	return append(append(cmd, params...), data...), nil
+}
@@ -0,0 +1,119 @@
+package escpos
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// solidImage returns a black image of the given size
+func solidImage(width, height int) image.Image {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	return img
+}
+
+// TestStoreNVBitImage tests storing images with FS q
+func TestStoreNVBitImage(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.StoreNVImage(1, solidImage(8, 8))
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := []byte{fs, 'q', 1, 1, 0, 1, 0}
+	expected = append(expected, bytes.Repeat([]byte{0xFF}, 8)...)
+	assert.Equal(t, expected, mock.Bytes())
+
+	// The second image is sent with the first one
+	_, err = p.StoreNVImage(2, solidImage(8, 4))
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	second := mock.Bytes()[len(expected):]
+	assert.Equal(t, []byte{fs, 'q', 2, 1, 0, 1, 0}, second[:7])
+	assert.Equal(t, []byte{1, 0, 1, 0}, second[15:19])
+	assert.Equal(t, bytes.Repeat([]byte{0xF0}, 8), second[19:])
+
+	// Images are stored in sequence
+	_, err = p.StoreNVImage(4, solidImage(8, 8))
+	assert.Error(t, err)
+
+	_, err = p.PrintNVImage(2)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, []byte{fs, 'p', 2, 0}, mock.Bytes()[len(mock.Bytes())-4:])
+
+	_, err = p.DeleteNVImage(1)
+	assert.Error(t, err)
+}
+
+// TestStoreNVGraphics tests storing, printing and deleting images by key code with GS ( L
+func TestStoreNVGraphics(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{NVGraphics: true})
+
+	_, err := p.StoreNVImage(7, solidImage(16, 2))
+	require.NoError(t, err)
+	_, err = p.PrintNVImage(7)
+	require.NoError(t, err)
+	_, err = p.DeleteNVImage(7)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := []byte{
+		gs, '(', 'L', 15, 0, 48, 67, 48, '0', '7', 1, 16, 0, 2, 0, 49, 0xFF, 0xFF, 0xFF, 0xFF,
+		gs, '(', 'L', 6, 0, 48, 69, '0', '7', 1, 1,
+		gs, '(', 'L', 4, 0, 48, 66, '0', '7',
+	}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestStoreNVImageCapacity tests rejecting images exceeding the NV memory of the profile
+func TestStoreNVImageCapacity(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Name: "Test printer", NVCapacity: 16})
+
+	_, err := p.StoreNVImage(1, solidImage(16, 8))
+	require.NoError(t, err)
+	_, err = p.StoreNVImage(2, solidImage(8, 8))
+	assert.ErrorContains(t, err, "exceed the NV memory of 16 bytes of Test printer")
+
+	p.SetProfile(Profile{NVGraphics: true, NVCapacity: 16})
+	_, err = p.StoreNVImage(1, solidImage(64, 8))
+	assert.Error(t, err)
+
+	_, err = p.StoreNVImage(0, solidImage(8, 8))
+	assert.Error(t, err)
+}
+
+// TestNVImageCapacity tests querying the remaining NV memory
+func TestNVImageCapacity(t *testing.T) {
+	mock := NewMockPrinter()
+	mock.SetStatus([]byte{0x37, 0x31, '2', '6', '2', '1', '4', '4', 0})
+	p := New(mock)
+
+	_, err := p.NVImageCapacity()
+	assert.Error(t, err)
+
+	p.SetProfile(Profile{NVGraphics: true})
+	capacity, err := p.NVImageCapacity()
+	require.NoError(t, err)
+	assert.Equal(t, 262144, capacity)
+	assert.Equal(t, []byte{gs, '(', 'L', 2, 0, 48, 51}, mock.Bytes())
+}
+
+// TestGraphicsCommandExtended tests switching to GS 8 L for large data
+func TestGraphicsCommandExtended(t *testing.T) {
+	cmd, err := graphicsCommand([]byte{48, 67}, make([]byte, 70000))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{gs, '8', 'L', 0x72, 0x11, 0x01, 0x00, 48, 67}, cmd[:9])
+}
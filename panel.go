@@ -0,0 +1,133 @@
+package escpos
+
+import "fmt"
+
+// Paper sensors of SelectPaperEndSensors and SelectPaperSensorsToStop
+const (
+	PaperSensorNearEnd uint8 = 0x03 // roll paper near-end sensor
+	PaperSensorEnd     uint8 = 0x0C // roll paper end sensor
+)
+
+// Buzzer sounds the integrated buzzer (ESC B n t), supported by most printers
+// fitted with one, e.g. to signal "take your receipt" on a kiosk.
+// times: number of beeps (1-9)
+// duration: duration of each beep (1-9) * 50ms
+func (e *Escpos) Buzzer(times, duration uint8) (int, error) {
+	times = e.clamped("buzzer times", times, 1, 9)
+	duration = e.clamped("buzzer duration", duration, 1, 9)
+	return e.WriteRaw([]byte{esc, 'B', times, duration})
+}
+
+// EnablePanelButtons enables or disables the panel buttons (ESC c 5), such
+// as the feed button, which unattended kiosks lock so the paper cannot be
+// pulled out of the printer. The printer enables the buttons when n is 0.
+func (e *Escpos) EnablePanelButtons(b bool) (int, error) {
+	return e.WriteRaw([]byte{esc, 'c', '5', boolToByte(!b)})
+}
+
+// LightState is a state of the panel light set by SetPanelLight
+type LightState uint8
+
+const (
+	LightOff   LightState = iota // no light
+	LightOn                      // steady light, e.g. ready
+	LightBlink                   // blinking light, e.g. "take your receipt"
+	LightError                   // error light, e.g. paper out or jam
+)
+
+// PanelLight holds the commands setting the panel light of a printer model
+// to each state, nil for the states it lacks. The commands differ between
+// models and firmware: take them from the command reference of the model.
+type PanelLight struct {
+	Off, On, Blink, Error []byte
+}
+
+// command returns the command of a light state, nil if unsupported
+func (l *PanelLight) command(state LightState) []byte {
+	switch state {
+	case LightOff:
+		return l.Off
+	case LightOn:
+		return l.On
+	case LightBlink:
+		return l.Blink
+	case LightError:
+		return l.Error
+	}
+	return nil
+}
+
+// SetPanelLight sets the front panel or bezel light of the printer, so
+// kiosks can signal "take your receipt" or an error through the printer
+// hardware. The profile must hold the PanelLight commands of the model.
+func (e *Escpos) SetPanelLight(state LightState) (int, error) {
+	if e.profile.PanelLight == nil {
+		return 0, fmt.Errorf("%s has no controllable panel light", e.profile.name())
+	}
+	if !e.profile.SupportsPanelLight(state) {
+		return 0, fmt.Errorf("%s does not support the panel light state %d", e.profile.name(), state)
+	}
+	return e.WriteRaw(e.profile.PanelLight.command(state))
+}
+
+// SelectPaperSensorsToStop selects the sensors stopping printing when they
+// detect the paper running out (ESC c 4). mask is 0 or PaperSensorNearEnd,
+// with 0 printing until the end of the roll.
+func (e *Escpos) SelectPaperSensorsToStop(mask uint8) (int, error) {
+	if mask&^PaperSensorNearEnd != 0 {
+		return 0, fmt.Errorf("invalid paper sensor mask %#02x, only the near-end sensor can stop printing", mask)
+	}
+	return e.WriteRaw([]byte{esc, 'c', '4', mask})
+}
+
+// SelectPaperEndSensors selects the sensors reporting the paper end on the
+// paper-end signal of the parallel interface (ESC c 3). mask is a
+// combination of PaperSensorNearEnd and PaperSensorEnd.
+func (e *Escpos) SelectPaperEndSensors(mask uint8) (int, error) {
+	if mask&^(PaperSensorNearEnd|PaperSensorEnd) != 0 {
+		return 0, fmt.Errorf("invalid paper sensor mask %#02x", mask)
+	}
+	return e.WriteRaw([]byte{esc, 'c', '3', mask})
+}
+
+// ExtensionsEpson holds the Epson panel commands, register them with
+// Profile.RegisterExtensions("epson", ExtensionsEpson) and run them with
+// Escpos.Extension("epson", name, args...):
+//   - "buzzer" (pattern, cycles): beeper pattern (ESC ( A fn 48), pattern 1-10, cycles 1-63
+var ExtensionsEpson = ExtensionSet{
+	"buzzer": func(args ...any) ([]byte, error) {
+		b, err := byteArgs(args, 2)
+		if err != nil {
+			return nil, err
+		}
+		pattern := clamp(b[0], 1, 10)
+		cycles := clamp(b[1], 1, 63)
+		return []byte{esc, '(', 'A', 4, 0, 48, '0' + pattern - 1, cycles, 0}, nil
+	},
+}
+
+// ExtensionsStar holds the Star panel commands, register them with
+// Profile.RegisterExtensions("star", ExtensionsStar) and run them with
+// Escpos.Extension("star", name, args...):
+//   - "buzzer" (buzzer, on, off): drives an external buzzer (ESC GS BEL), buzzer 1-2,
+//     on and off times in 20ms units
+var ExtensionsStar = ExtensionSet{
+	"buzzer": func(args ...any) ([]byte, error) {
+		b, err := byteArgs(args, 3)
+		if err != nil {
+			return nil, err
+		}
+		return []byte{esc, gs, 0x07, clamp(b[0], 1, 2), b[1], b[2]}, nil
+	},
+}
+
+// clamp limits v to the [lo, hi] range
+func clamp(v, lo, hi uint8) uint8 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
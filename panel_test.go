@@ -0,0 +1,107 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBuzzer tests sounding the integrated buzzer
+func TestBuzzer(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.Buzzer(2, 3)
+	assert.NoError(t, err)
+
+	// Out of range values are clamped
+	_, err = p.Buzzer(0, 20)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{esc, 'B', 2, 3, esc, 'B', 1, 9}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestPanelLight tests setting the panel light with the commands of the profile
+func TestPanelLight(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Name: "Kiosk", PanelLight: &PanelLight{
+		Off:   []byte{esc, 'L', 0},
+		Blink: []byte{esc, 'L', 2},
+	}})
+
+	_, err := p.SetPanelLight(LightBlink)
+	assert.NoError(t, err)
+	_, err = p.SetPanelLight(LightOff)
+	assert.NoError(t, err)
+	_, err = p.SetPanelLight(LightError)
+	assert.ErrorContains(t, err, "Kiosk does not support the panel light state 3")
+
+	assert.NoError(t, p.Print())
+	assert.Equal(t, []byte{esc, 'L', 2, esc, 'L', 0}, mock.Bytes())
+
+	p.SetProfile(Profile{Name: "Receipt printer"})
+	_, err = p.SetPanelLight(LightOn)
+	assert.ErrorContains(t, err, "Receipt printer has no controllable panel light")
+}
+
+// TestPanelExtensions tests the Epson and Star panel extension sets
+func TestPanelExtensions(t *testing.T) {
+	profile := Profile{}
+	profile.RegisterExtensions("epson", ExtensionsEpson)
+	profile.RegisterExtensions("star", ExtensionsStar)
+
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(profile)
+
+	_, err := p.Extension("epson", "buzzer", 3, 2)
+	assert.NoError(t, err)
+	_, err = p.Extension("star", "buzzer", 1, 10, uint8(5))
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{esc, '(', 'A', 4, 0, 48, '2', 2, 0, esc, gs, 0x07, 1, 10, 5}
+	assert.Equal(t, expected, mock.Bytes())
+
+	// Invalid arguments
+	_, err = p.Extension("epson", "buzzer", 3)
+	assert.Error(t, err)
+	_, err = p.Extension("star", "buzzer", 1, "10", 5)
+	assert.Error(t, err)
+	_, err = p.Extension("star", "buzzer", 1, 300, 5)
+	assert.Error(t, err)
+}
+
+// TestPanelButtonsAndSensors tests the panel button and paper sensor settings
+func TestPanelButtonsAndSensors(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.EnablePanelButtons(false)
+	assert.NoError(t, err)
+	_, err = p.EnablePanelButtons(true)
+	assert.NoError(t, err)
+	_, err = p.SelectPaperSensorsToStop(PaperSensorNearEnd)
+	assert.NoError(t, err)
+	_, err = p.SelectPaperEndSensors(PaperSensorNearEnd | PaperSensorEnd)
+	assert.NoError(t, err)
+
+	// Invalid masks
+	_, err = p.SelectPaperSensorsToStop(PaperSensorEnd)
+	assert.Error(t, err)
+	_, err = p.SelectPaperEndSensors(0x10)
+	assert.Error(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{esc, 'c', '5', 1, esc, 'c', '5', 0, esc, 'c', '4', 0x03, esc, 'c', '3', 0x0F}
+	assert.Equal(t, expected, mock.Bytes())
+}
@@ -0,0 +1,67 @@
+package escpos
+
+import "fmt"
+
+// printableWidths are the printable widths in millimeters of the common paper rolls
+var printableWidths = map[int]float64{
+	58: 48,
+	80: 72,
+}
+
+// SetPaperWidth sets the print width used by the layout helpers (tables,
+// dividers, wrapping, image scaling) from the width in millimeters of the
+// paper roll, such as 58 or 80, overriding the print width of the profile.
+// The margins of other widths are taken as 4mm on each side. Pass 0 to use
+// the print width of the profile again.
+func (e *Escpos) SetPaperWidth(mm int) error {
+	if mm == 0 {
+		e.printWidthDots = 0
+		return nil
+	}
+	printable, ok := printableWidths[mm]
+	if !ok {
+		printable = float64(mm - 8)
+	}
+	if printable < 10 {
+		return fmt.Errorf("paper width of %dmm is too narrow", mm)
+	}
+	// Print heads have a whole number of bytes
+	e.printWidthDots = (e.profile.dots(printable) + 4) / 8 * 8
+	return nil
+}
+
+// SetCharsPerLine sets the number of Font A characters of normal size on a
+// line, overriding the print width for the text layout helpers, for printers
+// with narrower fonts or margins. Pass 0 to compute it from the print width
+// again.
+func (e *Escpos) SetCharsPerLine(n int) error {
+	if n < 0 {
+		return fmt.Errorf("invalid number of characters per line: %d", n)
+	}
+	e.charsPerLine = n
+	return nil
+}
+
+// PrintWidth returns the printable width in dots, set by SetPaperWidth or by
+// the profile
+func (e *Escpos) PrintWidth() int {
+	if e.printWidthDots > 0 {
+		return e.printWidthDots
+	}
+	return e.profile.printWidth()
+}
+
+// CharsPerLine returns the number of Font A characters of the current size
+// that fit on a line, set by SetCharsPerLine or the profile, or computed
+// from the print width
+func (e *Escpos) CharsPerLine() int {
+	scale := int(min(max(e.Style.Width, 1)*e.sizeScale(), 8))
+	cpl := e.charsPerLine
+	if cpl == 0 {
+		cpl = e.profile.CharsPerLine
+	}
+	if cpl > 0 {
+		return cpl / scale
+	}
+	return e.PrintWidth() / (fontAWidth * scale)
+}
@@ -0,0 +1,41 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSetPaperWidth tests the print width computed from the paper width
+func TestSetPaperWidth(t *testing.T) {
+	p := New(NewMockPrinter())
+	assert.Equal(t, 576, p.PrintWidth())
+	assert.Equal(t, 48, p.CharsPerLine())
+
+	require.NoError(t, p.SetPaperWidth(58))
+	assert.Equal(t, 384, p.PrintWidth())
+	assert.Equal(t, 32, p.CharsPerLine())
+
+	require.NoError(t, p.SetPaperWidth(112))
+	assert.Equal(t, 832, p.PrintWidth())
+
+	assert.Error(t, p.SetPaperWidth(12))
+	require.NoError(t, p.SetPaperWidth(0))
+	assert.Equal(t, 576, p.PrintWidth())
+}
+
+// TestSetCharsPerLine tests the characters per line set explicitly or by the profile
+func TestSetCharsPerLine(t *testing.T) {
+	p := New(NewMockPrinter())
+	p.SetProfile(Profile{Name: "Test", CharsPerLine: 42})
+	assert.Equal(t, 42, p.CharsPerLine())
+	p.Style.Width = 2
+	assert.Equal(t, 21, p.CharsPerLine())
+
+	require.NoError(t, p.SetCharsPerLine(64))
+	assert.Equal(t, 32, p.CharsPerLine())
+	assert.Error(t, p.SetCharsPerLine(-1))
+	require.NoError(t, p.SetCharsPerLine(0))
+	assert.Equal(t, 21, p.CharsPerLine())
+}
@@ -0,0 +1,47 @@
+package escpos
+
+import "fmt"
+
+// Horizontal tab
+const ht byte = 0x09
+
+// Maximum number of tab stops accepted by ESC D
+const maxTabPositions = 32
+
+// SetAbsolutePosition moves the print position to dots from the beginning of
+// the line (ESC $), in horizontal motion units
+func (e *Escpos) SetAbsolutePosition(dots uint16) (int, error) {
+	return e.WriteRaw([]byte{esc, '$', byte(dots), byte(dots >> 8)})
+}
+
+// SetRelativePosition moves the print position by dots from the current
+// position (ESC \), to the left when dots is negative, in horizontal motion units
+func (e *Escpos) SetRelativePosition(dots int16) (int, error) {
+	n := uint16(dots)
+	return e.WriteRaw([]byte{esc, '\\', byte(n), byte(n >> 8)})
+}
+
+// SetTabPositions sets the horizontal tab stops (ESC D), in characters from
+// the beginning of the line. positions must be in ascending order, up to 32
+// stops. An empty list clears all the tab stops.
+func (e *Escpos) SetTabPositions(positions []uint8) (int, error) {
+	if len(positions) > maxTabPositions {
+		return 0, fmt.Errorf("too many tab positions: %d (max %d)", len(positions), maxTabPositions)
+	}
+	for i, p := range positions {
+		if p == 0 {
+			return 0, fmt.Errorf("tab positions must be between 1-255")
+		}
+		if i > 0 && p <= positions[i-1] {
+			return 0, fmt.Errorf("tab positions must be in ascending order")
+		}
+	}
+
+	cmd := append([]byte{esc, 'D'}, positions...)
+	return e.WriteRaw(append(cmd, 0))
+}
+
+// Tab moves the print position to the next tab stop (HT)
+func (e *Escpos) Tab() (int, error) {
+	return e.WriteRaw([]byte{ht})
+}
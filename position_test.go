@@ -0,0 +1,56 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPositioning tests the absolute and relative print positions
+func TestPositioning(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.SetAbsolutePosition(300)
+	assert.NoError(t, err)
+	_, err = p.SetRelativePosition(24)
+	assert.NoError(t, err)
+	_, err = p.SetRelativePosition(-24)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{
+		esc, '$', 0x2C, 0x01,
+		esc, '\\', 24, 0,
+		esc, '\\', 0xE8, 0xFF,
+	}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestTabPositions tests setting tab stops and tabbing
+func TestTabPositions(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.SetTabPositions([]uint8{10, 20, 30})
+	assert.NoError(t, err)
+	_, err = p.Tab()
+	assert.NoError(t, err)
+	_, err = p.SetTabPositions(nil)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{esc, 'D', 10, 20, 30, 0, ht, esc, 'D', 0}
+	assert.Equal(t, expected, mock.Bytes())
+
+	_, err = p.SetTabPositions([]uint8{10, 5})
+	assert.Error(t, err)
+	_, err = p.SetTabPositions([]uint8{0, 5})
+	assert.Error(t, err)
+	_, err = p.SetTabPositions(make([]uint8, 33))
+	assert.Error(t, err)
+}
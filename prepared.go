@@ -0,0 +1,38 @@
+package escpos
+
+import (
+	"fmt"
+	"image"
+)
+
+// RasterImage is an image processed once by PrepareImage, printed as many
+// times as needed without dithering it again, such as the logo at the top of
+// every receipt. It is safe to print from several goroutines.
+type RasterImage struct {
+	raster rasterImage
+	align  Justify
+}
+
+// PrepareImage processes an image with opts for printing it later with PrintTo
+func PrepareImage(img image.Image, opts ImageOptions) (*RasterImage, error) {
+	raster, err := processImage(img, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare image: %w", err)
+	}
+	return &RasterImage{raster: raster, align: opts.Align}, nil
+}
+
+// Width returns the width of the printed image in dots
+func (r *RasterImage) Width() int {
+	return r.raster.printedWidth()
+}
+
+// Height returns the number of rows of the image
+func (r *RasterImage) Height() int {
+	return r.raster.height
+}
+
+// PrintTo prints the image on e, aligned for its print width
+func (r *RasterImage) PrintTo(e *Escpos) (int, error) {
+	return e.printRaster(r.raster.aligned(r.align, e.PrintWidth()))
+}
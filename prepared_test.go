@@ -0,0 +1,42 @@
+package escpos
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrepareImage tests printing a prepared image on printers of different widths
+func TestPrepareImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 1))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.Black}, image.Point{}, draw.Src)
+
+	logo, err := PrepareImage(img, ImageOptions{Align: JustifyRight, HighDensityVertical: true, HighDensityHorizontal: true})
+	require.NoError(t, err)
+	assert.Equal(t, 16, logo.Width())
+	assert.Equal(t, 1, logo.Height())
+
+	for width, row := range map[int][]byte{
+		32: {0, 0, 0xff, 0xff},
+		48: {0, 0, 0, 0, 0xff, 0xff},
+	} {
+		mock := NewMockPrinter()
+		p := New(mock)
+		p.printWidthDots = width
+
+		_, err := logo.PrintTo(p)
+		require.NoError(t, err)
+		require.NoError(t, p.Print())
+		assert.Equal(t, append([]byte{gs, 'v', '0', 0, byte(len(row)), 0, 1, 0}, row...), mock.Bytes())
+	}
+}
+
+// TestPrepareImageInvalid tests preparing an image with invalid options
+func TestPrepareImageInvalid(t *testing.T) {
+	_, err := PrepareImage(image.NewGray(image.Rect(0, 0, 8, 8)), ImageOptions{Gamma: -2})
+	assert.Error(t, err)
+}
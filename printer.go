@@ -1,6 +1,8 @@
 package escpos
 
 import (
+	"errors"
+	"fmt"
 	"net"
 	"time"
 )
@@ -17,6 +19,9 @@ type networkPrinter struct {
 	readTimeout    time.Duration
 	writeTimeout   time.Duration
 	connectTimeout time.Duration
+	deadline       time.Time
+	readDeadline   time.Time
+	writeDeadline  time.Time
 }
 
 // PrinterOption defines a function that configures a network printer
@@ -58,7 +63,8 @@ func WithWriteTimeout(d time.Duration) PrinterOption {
 // Note: This sets a one-time deadline. For recurring timeouts, use WithTimeout instead.
 func WithDeadline(t time.Time) PrinterOption {
 	return func(np *networkPrinter) error {
-		return np.conn.SetDeadline(t)
+		np.deadline = t
+		return nil
 	}
 }
 
@@ -66,7 +72,8 @@ func WithDeadline(t time.Time) PrinterOption {
 // Note: This sets a one-time deadline. For recurring timeouts, use WithReadTimeout instead.
 func WithReadDeadline(t time.Time) PrinterOption {
 	return func(np *networkPrinter) error {
-		return np.conn.SetReadDeadline(t)
+		np.readDeadline = t
+		return nil
 	}
 }
 
@@ -74,7 +81,8 @@ func WithReadDeadline(t time.Time) PrinterOption {
 // Note: This sets a one-time deadline. For recurring timeouts, use WithWriteTimeout instead.
 func WithWriteDeadline(t time.Time) PrinterOption {
 	return func(np *networkPrinter) error {
-		return np.conn.SetWriteDeadline(t)
+		np.writeDeadline = t
+		return nil
 	}
 }
 
@@ -99,13 +107,40 @@ func NewNetworkPrinter(address string, opts ...PrinterOption) (Printer, error) {
 	}
 
 	if err != nil {
-		return nil, err
+		return nil, timeoutError(err)
 	}
 
 	np.conn = conn
+
+	// Absolute deadlines can only be applied once the connection exists
+	if err := np.applyDeadlines(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
 	return np, nil
 }
 
+// applyDeadlines sets the absolute deadlines configured through the With*Deadline options
+func (np *networkPrinter) applyDeadlines() error {
+	if !np.deadline.IsZero() {
+		if err := np.conn.SetDeadline(np.deadline); err != nil {
+			return err
+		}
+	}
+	if !np.readDeadline.IsZero() {
+		if err := np.conn.SetReadDeadline(np.readDeadline); err != nil {
+			return err
+		}
+	}
+	if !np.writeDeadline.IsZero() {
+		if err := np.conn.SetWriteDeadline(np.writeDeadline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (np *networkPrinter) Read(p []byte) (n int, err error) {
 	// Set read deadline before each read operation
 	if np.readTimeout > 0 {
@@ -117,7 +152,8 @@ func (np *networkPrinter) Read(p []byte) (n int, err error) {
 			return 0, err
 		}
 	}
-	return np.conn.Read(p)
+	n, err = np.conn.Read(p)
+	return n, timeoutError(err)
 }
 
 func (np *networkPrinter) Write(p []byte) (n int, err error) {
@@ -131,7 +167,22 @@ func (np *networkPrinter) Write(p []byte) (n int, err error) {
 			return 0, err
 		}
 	}
-	return np.conn.Write(p)
+	n, err = np.conn.Write(p)
+	return n, timeoutError(err)
+}
+
+// timeoutError wraps the network timeouts with ErrTimeout
+func timeoutError(err error) error {
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
+
+// Transport describes the connection for Diagnostics
+func (np *networkPrinter) Transport() string {
+	return "tcp " + np.conn.RemoteAddr().String()
 }
 
 func (np *networkPrinter) Close() error {
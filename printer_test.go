@@ -111,6 +111,7 @@ func TestWithReadTimeout(t *testing.T) {
 	_, err = printer.Read(buf)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "timeout")
+	assert.ErrorIs(t, err, ErrTimeout)
 }
 
 // TestWithWriteTimeout tests the WithWriteTimeout option
@@ -213,6 +214,27 @@ func TestWithDeadline(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// TestWithReadWriteDeadline tests that the absolute deadlines are applied once connected
+func TestWithReadWriteDeadline(t *testing.T) {
+	addr, cleanup := mockTCPServer(t, func(conn net.Conn) {
+		defer conn.Close()
+		// Server that never responds
+		time.Sleep(1 * time.Second)
+	})
+	defer cleanup()
+
+	deadline := time.Now().Add(50 * time.Millisecond)
+	printer, err := NewNetworkPrinter(addr, WithReadDeadline(deadline), WithWriteDeadline(deadline))
+	require.NoError(t, err)
+	defer printer.Close()
+
+	// Read should fail at the deadline
+	buf := make([]byte, 1024)
+	_, err = printer.Read(buf)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout")
+}
+
 // TestReadTimeoutPriority tests that readTimeout takes priority over timeout
 func TestReadTimeoutPriority(t *testing.T) {
 	addr, cleanup := mockTCPServer(t, func(conn net.Conn) {
@@ -0,0 +1,253 @@
+package escpos
+
+import (
+	"fmt"
+	"slices"
+
+	"golang.org/x/text/encoding"
+)
+
+// Profile describes the capabilities and limits of a printer model.
+// Features depending on a capability consult the profile set with SetProfile;
+// the zero value describes a generic printer without any known limit.
+type Profile struct {
+	// Name of the printer model, used in error messages
+	Name string
+
+	// PrintWidth is the printable width in dots (0: 576 dots, an 80mm printer)
+	PrintWidth int
+	// DPI is the resolution of the print head (0: 203 dpi)
+	DPI int
+	// CharsPerLine is the number of Font A characters of normal size on a
+	// line (0: computed from PrintWidth)
+	CharsPerLine int
+
+	// Encoding is the default encoding of Write for the printers of a
+	// region, selected with its CodePage when the profile is set and after
+	// Initialize (nil: PC850)
+	Encoding encoding.Encoding
+	// CodePage is the ESC t code page of Encoding
+	CodePage uint8
+	// Charset is the international character set (ESC R), selected with the
+	// encoding, or alone when it is not CharsetUSA
+	Charset uint8
+	// ThaiCodePage is the ESC t code page of the Thai characters, such as 21
+	// (Thai character code 11) on the Epson printers (0: no Thai code page)
+	ThaiCodePage uint8
+	// ThaiThreePass is set for printers printing the Thai vowel and tone
+	// marks in separate passes rather than composing them, see WriteThai
+	ThaiThreePass bool
+	// CodePages lists the ESC t code pages WriteUTF8 may switch to (nil: the
+	// common code pages)
+	CodePages []uint8
+	// CodePageEncodings holds the encodings of the code pages numbered
+	// differently from the CodePage constants by the printer vendor
+	CodePageEncodings map[uint8]encoding.Encoding
+
+	// MaxImageHeight is the maximum number of rows of a single raster image
+	// command (0: no limit). Printers silently drop the rows past their limit,
+	// so taller images are split into several commands.
+	MaxImageHeight int
+	// RejectTallImages makes image printing fail instead of splitting images
+	// taller than MaxImageHeight.
+	RejectTallImages bool
+
+	// Dialect is the command set of the printer (default: DialectESCPOS)
+	Dialect Dialect
+
+	// RasterCommand selects the command printing raster images
+	// (default: RasterGSv0)
+	RasterCommand RasterCommand
+
+	// DensityCommand selects the command controlling the print density and
+	// speed (default: DensityGSParenK)
+	DensityCommand DensityCommand
+
+	// UnsupportedBarcodes lists the GS k barcode types the printer cannot
+	// print. Barcodes of these types are rendered as images instead. Listing
+	// a function A type also covers its function B equivalent.
+	UnsupportedBarcodes []uint8
+	// Fonts lists the fonts selectable with SetFont (nil: any font is accepted)
+	Fonts []uint8
+
+	// NoCutter is set for printers without an autocutter. Cut and PartialCut
+	// print a tear line instead, see TearLine.
+	NoCutter bool
+	// TearBarDistance is the distance in dots from the print head to the tear
+	// bar (0: 12mm)
+	TearBarDistance int
+	// CutterDistance is the distance in dots from the print head to the
+	// cutter (0: 15mm), see FeedToCutPosition
+	CutterDistance int
+
+	// CutFeed is the extra distance in dots fed before cutting, for models
+	// whose cutter sits further from the print head than the cut command feeds
+	CutFeed uint8
+	// CutReverseFeed is the distance in dots fed back after cutting, to
+	// recover the top margin wasted by CutFeed on the next ticket (ignored
+	// with NoReverseFeed)
+	CutReverseFeed uint8
+	// NoReverseFeed is set for printers unable to feed the paper back
+	NoReverseFeed bool
+
+	// NVGraphics is set for printers storing NV images by key code with
+	// GS ( L, instead of the legacy FS q bit images
+	NVGraphics bool
+	// NVCapacity is the size in bytes of the NV image memory (0: unknown)
+	NVCapacity int
+
+	// Clock is the real-time clock protocol of the printer, for the fiscal
+	// and label models fitted with one (nil: no clock)
+	Clock Clock
+
+	// PanelLight holds the commands of the front panel or bezel light of the
+	// kiosk models fitted with one (nil: no controllable light)
+	PanelLight *PanelLight
+
+	// NoQRCode is set for printers ignoring the GS ( k QR code commands.
+	// QR codes are rendered as images instead.
+	NoQRCode bool
+
+	// Extensions holds the vendor specific commands, by vendor, registered
+	// with RegisterExtension
+	Extensions map[string]ExtensionSet
+}
+
+// RasterCommand is a command family printing raster images
+type RasterCommand uint8
+
+const (
+	// RasterGSv0 prints images with GS v 0, supported by most printers
+	RasterGSv0 RasterCommand = iota
+	// RasterGraphics stores images in the print buffer with GS ( L, or GS 8 L
+	// for data over 64KB, then prints them. Current Epson models deprecate
+	// GS v 0 and clip large images printed with it.
+	RasterGraphics
+)
+
+// name returns the model name for error messages
+func (p Profile) name() string {
+	if p.Name == "" {
+		return "the printer"
+	}
+	return p.Name
+}
+
+// codePageEncoding returns the encoding of an ESC t code page
+func (p Profile) codePageEncoding(codepage uint8) (encoding.Encoding, bool) {
+	if enc, ok := p.CodePageEncodings[codepage]; ok {
+		return enc, true
+	}
+	enc, ok := codePageEncodings[codepage]
+	return enc, ok
+}
+
+// Default printable width in dots, of an 80mm printer
+const defaultPrintWidth = 576
+
+// printWidth returns the printable width in dots
+func (p Profile) printWidth() int {
+	if p.PrintWidth > 0 {
+		return p.PrintWidth
+	}
+	return defaultPrintWidth
+}
+
+// Default resolution of the print head
+const defaultDPI = 203
+
+// dots converts a length in millimeters to dots at the resolution of the head
+func (p Profile) dots(mm float64) int {
+	dpi := p.DPI
+	if dpi <= 0 {
+		dpi = defaultDPI
+	}
+	return int(mm*float64(dpi)/25.4 + 0.5)
+}
+
+// SupportsBarcode reports whether the printer can print the GS k barcode type natively
+func (p Profile) SupportsBarcode(symbology uint8) bool {
+	for _, t := range p.UnsupportedBarcodes {
+		if functionA(t) == functionA(symbology) {
+			return false
+		}
+	}
+	return true
+}
+
+// SupportsFont reports whether the printer has the font, any font being
+// accepted when the profile does not list them
+func (p Profile) SupportsFont(font uint8) bool {
+	return p.Fonts == nil || slices.Contains(p.Fonts, font)
+}
+
+// SupportsClock reports whether the printer has a real-time clock
+func (p Profile) SupportsClock() bool {
+	return p.Clock != nil
+}
+
+// SupportsPanelLight reports whether the printer can set its panel light to state
+func (p Profile) SupportsPanelLight(state LightState) bool {
+	return p.PanelLight != nil && p.PanelLight.command(state) != nil
+}
+
+// SupportsQRCode reports whether the printer can print QR codes natively
+func (p Profile) SupportsQRCode() bool {
+	return !p.NoQRCode
+}
+
+// SetProfile sets the capability profile of the printer, selecting the
+// encoding and international character set of the profile, if any. The
+// error, such as an invalid character set, is also recorded in the sticky
+// error mode.
+func (e *Escpos) SetProfile(p Profile) error {
+	e.profile = p
+	_, err := e.applyProfileCharset()
+	return e.fail(err)
+}
+
+// Profile returns the capability profile of the printer
+func (e *Escpos) Profile() Profile {
+	return e.profile
+}
+
+// printRaster sends a raster image, splitting it into bands when it is taller
+// than the maximum image height of the profile
+func (e *Escpos) printRaster(r rasterImage) (int, error) {
+	if max := e.profile.MaxImageHeight; max > 0 && r.height > max && e.profile.RejectTallImages {
+		return 0, fmt.Errorf("image height of %d dots exceeds the maximum of %d dots supported by %s", r.height, max, e.profile.name())
+	}
+
+	if width := r.printedWidth(); width > e.PrintWidth() {
+		e.warn(WarningImage, "image width of %d dots exceeds the print width of %d dots, the printer clips it", width, e.PrintWidth())
+	}
+	bands := r.split(e.profile.MaxImageHeight)
+	if len(bands) > 1 {
+		e.warn(WarningImage, "image height of %d dots split in %d bands", r.height, len(bands))
+	}
+
+	written := 0
+	for _, band := range bands {
+		if e.blockEnd != nil {
+			band = band.rotated()
+		}
+		cmd, err := band.commandFor(e.profile.RasterCommand)
+		if e.star() {
+			cmd, err = band.starCommand()
+		}
+		if err != nil {
+			return written, fmt.Errorf("failed to encode image: %w", err)
+		}
+		n, err := e.WriteRaw(cmd)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		if e.blockEnd != nil {
+			if err := e.blockEnd(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,114 @@
+package escpos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPrintImageMaxHeight tests splitting images taller than the profile limit
+func TestPrintImageMaxHeight(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Name: "Test printer", MaxImageHeight: 24})
+
+	img := createTestImage(64, 64)
+	_, err := p.PrintImageWithProcessing(img, ImageProcessThreshold, false, false)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	// 64 rows split into 24 + 24 + 16 rows of 8 bytes
+	output := mock.Bytes()
+	assert.Equal(t, 3, bytes.Count(output, []byte{gs, 'v', '0'}))
+	assert.Equal(t, []byte{gs, 'v', '0', 0, 8, 0, 24, 0}, output[:8])
+	assert.Equal(t, []byte{gs, 'v', '0', 0, 8, 0, 24, 0}, output[8+8*24:16+8*24])
+	assert.Equal(t, []byte{gs, 'v', '0', 0, 8, 0, 16, 0}, output[16+16*24:24+16*24])
+	assert.Len(t, output, 3*8+64*8)
+}
+
+// TestPrintImageRejectTall tests rejecting images taller than the profile limit
+func TestPrintImageRejectTall(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Name: "Test printer", MaxImageHeight: 32, RejectTallImages: true})
+
+	_, err := p.PrintImageWithProcessing(createTestImage(64, 64), ImageProcessDither, true, true)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds the maximum of 32 dots supported by Test printer")
+
+	err = p.Print()
+	assert.NoError(t, err)
+	assert.Empty(t, mock.Bytes())
+
+	// Images within the limit are printed as a single command
+	_, err = p.PrintImageWithProcessing(createTestImage(32, 32), ImageProcessDither, true, true)
+	assert.NoError(t, err)
+	err = p.Print()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, bytes.Count(mock.Bytes(), []byte{gs, 'v', '0'}))
+}
+
+// TestCutOffset tests that the cut offset of the profile is applied by the cuts
+func TestCutOffset(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{CutFeed: 24, CutReverseFeed: 24})
+
+	_, err := p.Cut()
+	assert.NoError(t, err)
+	_, err = p.PartialCut()
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{
+		gs, 'V', 'A', 24, esc, 'K', 24,
+		gs, 'V', 'B', 24, esc, 'K', 24,
+	}
+	assert.Equal(t, expected, mock.Bytes())
+
+	// Only the extra feed
+	p.SetProfile(Profile{CutFeed: 10})
+	_, err = p.Cut()
+	assert.NoError(t, err)
+	err = p.Print()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{gs, 'V', 'A', 10}, mock.Bytes()[len(expected):])
+}
+
+// TestPrintImageRasterGraphics tests printing images with GS ( L
+func TestPrintImageRasterGraphics(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{RasterCommand: RasterGraphics, MaxImageHeight: 24})
+
+	_, err := p.PrintImageWithProcessing(createTestImage(16, 32), ImageProcessDither, true, true)
+	assert.NoError(t, err)
+	err = p.Print()
+	assert.NoError(t, err)
+
+	output := mock.Bytes()
+	assert.Zero(t, bytes.Count(output, []byte{gs, 'v', '0'}))
+	// 32 rows split into 24 + 8 rows of 2 bytes, stored then printed
+	assert.Equal(t, []byte{gs, '(', 'L', 58, 0, 48, 112, 48, 1, 1, 49, 16, 0, 24, 0}, output[:15])
+	assert.Equal(t, []byte{gs, '(', 'L', 2, 0, 48, 50}, output[15+48:15+48+7])
+	assert.Equal(t, []byte{gs, '(', 'L', 26, 0, 48, 112, 48, 1, 1, 49, 16, 0, 8, 0}, output[70:85])
+	assert.Len(t, output, 2*22+64)
+}
+
+// TestRasterGraphicsCommand tests the scales and the GS 8 L form of GS ( L raster images
+func TestRasterGraphicsCommand(t *testing.T) {
+	r := rasterImage{density: 3, widthBytes: 1, height: 1, data: []byte{0xFF}}
+	cmd, err := r.commandFor(RasterGraphics)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{gs, '(', 'L', 11, 0, 48, 112, 48, 2, 2, 49, 8, 0, 1, 0, 0xFF, gs, '(', 'L', 2, 0, 48, 50}, cmd)
+
+	large := rasterImage{widthBytes: 72, height: 1000, data: make([]byte, 72000)}
+	cmd, err = large.commandFor(RasterGraphics)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{gs, '8', 'L'}, cmd[:3])
+}
@@ -0,0 +1,255 @@
+package escpos
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// Default QR code module size of the printers, in dots
+const defaultQRCodeModuleSize = 3
+
+// QRCodeOptions holds the native parameters of a GS ( k QR code for QRCodeAdvanced
+type QRCodeOptions struct {
+	// Model is QRCodeModel1 or QRCodeModel2 (0: Model 2)
+	Model uint8
+	// ModuleSize is the size of a module in dots, 1-16 (0: the largest size
+	// fitting MaxWidth, or the printer default of 3 dots without MaxWidth)
+	ModuleSize uint8
+	// ErrorCorrection is one of the QRCodeErrorCorrectionLevel* constants (0: level L)
+	ErrorCorrection uint8
+	// MaxWidth is the maximum width of the symbol in dots (0: no limit)
+	MaxWidth int
+	// Version is the symbol version, 1-40, the scanner expects (0: any).
+	// Symbols of another version are not printed.
+	Version int
+	// QuerySize asks the printer for the size of the stored symbol
+	// (GS ( k function 182) instead of estimating it with the software encoder,
+	// whose mode selection may differ from the printer. The transport must
+	// be able to read the printer responses.
+	QuerySize bool
+}
+
+// QRCodeSymbol describes a QR code symbol stored in the printer
+type QRCodeSymbol struct {
+	// Width and Height of the symbol in dots
+	Width, Height int
+	// Printable is false when the data cannot be encoded as a symbol
+	Printable bool
+}
+
+// QRCodeAdvanced prints a QR code with every native GS ( k parameter, failing
+// instead of adjusting invalid parameters. The module size can be negotiated
+// from the maximum width of the symbol, and the symbol version checked
+// before printing, for scanners expecting an exact symbol.
+func (e *Escpos) QRCodeAdvanced(data []byte, opts QRCodeOptions) (int, error) {
+	if err := e.checkUpright("native QR codes"); err != nil {
+		return 0, err
+	}
+	if !e.profile.SupportsQRCode() {
+		return 0, fmt.Errorf("%s does not support native QR codes, use QRCodeAsImage instead", e.profile.name())
+	}
+	if err := opts.validate(len(data)); err != nil {
+		return 0, err
+	}
+	if opts.Model == 0 {
+		opts.Model = QRCodeModel2
+	}
+	if opts.ErrorCorrection == 0 {
+		opts.ErrorCorrection = QRCodeErrorCorrectionLevelL
+	}
+
+	// Estimate the number of modules and the module size before sending anything
+	var size uint8
+	if !opts.QuerySize {
+		modules := 0
+		if opts.needsModules() {
+			bc, err := encodeQRCode(data, opts.ErrorCorrection)
+			if err != nil {
+				return 0, fmt.Errorf("failed to estimate QR code size: %w", err)
+			}
+			modules = bc.Bounds().Dx()
+			if err := opts.checkVersion(modules); err != nil {
+				return 0, err
+			}
+		}
+		var err error
+		if size, err = opts.moduleSize(modules); err != nil {
+			return 0, err
+		}
+	}
+
+	pLH, err := LowHigh16(len(data) + 3)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode QR code data length: %w", err)
+	}
+	cmd := []byte{
+		gs, '(', 'k', 4, 0, 49, 65, opts.Model, 0,
+		gs, '(', 'k', 3, 0, 49, 69, opts.ErrorCorrection,
+		gs, '(', 'k', pLH[0], pLH[1], 49, 80, 48,
+	}
+	written, err := e.WriteRaw(append(cmd, data...))
+	if err != nil {
+		return written, fmt.Errorf("failed to store QR code data: %w", err)
+	}
+
+	if opts.QuerySize {
+		// With 1 dot modules the width of the symbol is its number of modules
+		n, err := e.WriteRaw([]byte{gs, '(', 'k', 3, 0, 49, 67, 1})
+		written += n
+		if err != nil {
+			return written, fmt.Errorf("failed to set QR code size: %w", err)
+		}
+		symbol, err := e.QRCodeSymbolSize()
+		if err != nil {
+			return written, err
+		}
+		if !symbol.Printable {
+			return written, fmt.Errorf("the printer cannot encode the QR code data")
+		}
+		if err := opts.checkVersion(symbol.Width); err != nil {
+			return written, err
+		}
+		if size, err = opts.moduleSize(symbol.Width); err != nil {
+			return written, err
+		}
+	}
+
+	n, err := e.WriteRaw([]byte{
+		gs, '(', 'k', 3, 0, 49, 67, size,
+		gs, '(', 'k', 3, 0, 49, 81, 48,
+	})
+	written += n
+	if err != nil {
+		return written, fmt.Errorf("failed to print QR code: %w", err)
+	}
+	return written, nil
+}
+
+// QRCodeSymbolSize asks the printer for the size of the QR code symbol
+// stored with the current parameters (GS ( k function 182)
+func (e *Escpos) QRCodeSymbolSize() (QRCodeSymbol, error) {
+	resp, err := e.request([]byte{gs, '(', 'k', 3, 0, 49, 82, 48})
+	if err != nil {
+		return QRCodeSymbol{}, fmt.Errorf("failed to query QR code size: %w", err)
+	}
+	return parseSymbolSize(resp)
+}
+
+// request sends a command and reads the printer response, up to its NUL
+// terminator, polling the printer as QueryStatus does
+func (e *Escpos) request(cmd []byte) ([]byte, error) {
+	return e.requestUntil(cmd, 0)
+}
+
+// requestUntil sends a command and reads the printer response, up to the
+// terminator byte end
+func (e *Escpos) requestUntil(cmd []byte, end byte) ([]byte, error) {
+	if err := e.sendRequest(cmd); err != nil {
+		return nil, err
+	}
+
+	var resp []byte
+	buf := make([]byte, 64)
+	for len(resp) < 256 {
+		n, err := e.readStatus(buf)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("%w: no response", ErrTimeout)
+		}
+		resp = append(resp, buf[:n]...)
+		if i := bytes.IndexByte(resp, end); i >= 0 {
+			return resp[:i], nil
+		}
+	}
+	return nil, fmt.Errorf("response too long")
+}
+
+// parseSymbolSize parses a symbol size response: the header 0x37 0x76, then
+// the width, height, an unused field and the printable status as ASCII
+// fields separated by 0x1F
+func parseSymbolSize(resp []byte) (QRCodeSymbol, error) {
+	if len(resp) < 2 || resp[0] != 0x37 || resp[1] != 0x76 {
+		return QRCodeSymbol{}, fmt.Errorf("invalid symbol size response: % X", resp)
+	}
+	fields := bytes.Split(resp[2:], []byte{0x1F})
+	if len(fields) < 4 {
+		return QRCodeSymbol{}, fmt.Errorf("invalid symbol size response: % X", resp)
+	}
+
+	width, err := strconv.Atoi(string(fields[0]))
+	if err != nil {
+		return QRCodeSymbol{}, fmt.Errorf("invalid symbol width: %w", err)
+	}
+	height, err := strconv.Atoi(string(fields[1]))
+	if err != nil {
+		return QRCodeSymbol{}, fmt.Errorf("invalid symbol height: %w", err)
+	}
+	return QRCodeSymbol{Width: width, Height: height, Printable: string(fields[3]) == "0"}, nil
+}
+
+// validate checks the options for data bytes of QR code data
+func (o QRCodeOptions) validate(length int) error {
+	if length == 0 {
+		return fmt.Errorf("QR code data cannot be empty")
+	}
+	maxLength, maxVersion := 7089, 40
+	switch o.Model {
+	case 0, QRCodeModel2:
+	case QRCodeModel1:
+		maxLength, maxVersion = 1167, 14
+	default:
+		return fmt.Errorf("invalid QR code model: %d", o.Model)
+	}
+	if length > maxLength {
+		return fmt.Errorf("QR code data too long (max %d bytes for the selected model)", maxLength)
+	}
+	if o.ModuleSize > 16 {
+		return fmt.Errorf("invalid QR code module size: %d, must be 1-16", o.ModuleSize)
+	}
+	if o.ErrorCorrection != 0 && (o.ErrorCorrection < QRCodeErrorCorrectionLevelL || o.ErrorCorrection > QRCodeErrorCorrectionLevelH) {
+		return fmt.Errorf("invalid QR code error correction level: %d", o.ErrorCorrection)
+	}
+	if o.Version < 0 || o.Version > maxVersion {
+		return fmt.Errorf("invalid QR code version: %d, must be 1-%d for the selected model", o.Version, maxVersion)
+	}
+	if o.MaxWidth < 0 {
+		return fmt.Errorf("invalid QR code maximum width: %d", o.MaxWidth)
+	}
+	return nil
+}
+
+// needsModules reports whether the number of modules of the symbol must be known
+func (o QRCodeOptions) needsModules() bool {
+	return o.Version > 0 || o.MaxWidth > 0
+}
+
+// checkVersion checks the version of a symbol with the given number of modules
+func (o QRCodeOptions) checkVersion(modules int) error {
+	if o.Version == 0 {
+		return nil
+	}
+	if version := (modules - 17) / 4; version != o.Version {
+		return fmt.Errorf("QR code has version %d, expected version %d", version, o.Version)
+	}
+	return nil
+}
+
+// moduleSize returns the module size to print a symbol of the given number of modules
+func (o QRCodeOptions) moduleSize(modules int) (uint8, error) {
+	size := int(o.ModuleSize)
+	switch {
+	case size == 0 && o.MaxWidth > 0:
+		size = min(16, o.MaxWidth/modules)
+		if size < 1 {
+			return 0, fmt.Errorf("QR code of %d modules does not fit in %d dots", modules, o.MaxWidth)
+		}
+	case size == 0:
+		size = defaultQRCodeModuleSize
+	case o.MaxWidth > 0 && modules*size > o.MaxWidth:
+		return 0, fmt.Errorf("QR code of %d dots exceeds the maximum width of %d dots", modules*size, o.MaxWidth)
+	}
+	return uint8(size), nil
+}
@@ -0,0 +1,115 @@
+package escpos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQRCodeAdvanced tests printing a QR code with a module size negotiated from the maximum width
+func TestQRCodeAdvanced(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	// "HELLO" is a version 1 symbol of 21 modules
+	_, err := p.QRCodeAdvanced([]byte("HELLO"), QRCodeOptions{MaxWidth: 200, Version: 1})
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := []byte{
+		gs, '(', 'k', 4, 0, 49, 65, QRCodeModel2, 0,
+		gs, '(', 'k', 3, 0, 49, 69, QRCodeErrorCorrectionLevelL,
+		gs, '(', 'k', 8, 0, 49, 80, 48, 'H', 'E', 'L', 'L', 'O',
+		gs, '(', 'k', 3, 0, 49, 67, 9,
+		gs, '(', 'k', 3, 0, 49, 81, 48,
+	}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestQRCodeAdvancedInvalid tests that invalid parameters are rejected
+func TestQRCodeAdvancedInvalid(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	for _, opts := range []QRCodeOptions{
+		{Model: 3},
+		{ModuleSize: 17},
+		{ErrorCorrection: 52},
+		{Version: 41},
+		{Model: QRCodeModel1, Version: 15},
+		{Version: 2},
+		{ModuleSize: 8, MaxWidth: 100},
+		{MaxWidth: 20},
+	} {
+		_, err := p.QRCodeAdvanced([]byte("HELLO"), opts)
+		assert.Error(t, err, "%+v", opts)
+	}
+	_, err := p.QRCodeAdvanced(nil, QRCodeOptions{})
+	assert.Error(t, err)
+
+	require.NoError(t, p.Print())
+	assert.Empty(t, mock.Bytes())
+
+	p.SetProfile(Profile{Name: "Test printer", NoQRCode: true})
+	_, err = p.QRCodeAdvanced([]byte("HELLO"), QRCodeOptions{})
+	assert.ErrorContains(t, err, "Test printer does not support native QR codes")
+}
+
+// TestQRCodeAdvancedQuerySize tests negotiating the module size from the size reported by the printer
+func TestQRCodeAdvancedQuerySize(t *testing.T) {
+	mock := NewMockPrinter()
+	mock.SetStatus([]byte{0x37, 0x76, '2', '5', 0x1F, '2', '5', 0x1F, '0', 0x1F, '0', 0})
+	p := New(mock)
+
+	_, err := p.QRCodeAdvanced([]byte("HELLO"), QRCodeOptions{MaxWidth: 100, Version: 2, QuerySize: true})
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := []byte{
+		gs, '(', 'k', 4, 0, 49, 65, QRCodeModel2, 0,
+		gs, '(', 'k', 3, 0, 49, 69, QRCodeErrorCorrectionLevelL,
+		gs, '(', 'k', 8, 0, 49, 80, 48, 'H', 'E', 'L', 'L', 'O',
+		gs, '(', 'k', 3, 0, 49, 67, 1,
+		gs, '(', 'k', 3, 0, 49, 82, 48,
+		gs, '(', 'k', 3, 0, 49, 67, 4,
+		gs, '(', 'k', 3, 0, 49, 81, 48,
+	}
+	assert.Equal(t, expected, mock.Bytes())
+
+	// The printer reports a symbol that cannot be printed
+	mock.SetStatus([]byte{0x37, 0x76, '0', 0x1F, '0', 0x1F, '0', 0x1F, '1', 0})
+	_, err = p.QRCodeAdvanced([]byte("HELLO"), QRCodeOptions{MaxWidth: 100, QuerySize: true})
+	assert.Error(t, err)
+}
+
+// TestQRCodeSymbolSizePolling tests polling a printer answering slowly and in parts
+func TestQRCodeSymbolSizePolling(t *testing.T) {
+	mock := NewMockPrinter()
+	mock.QueueStatus(nil, nil, []byte{0x37, 0x76, '2', '5', 0x1F}, nil, []byte{'2', '5', 0x1F, '0', 0x1F, '0', 0})
+	p := New(mock, WithStatusPollInterval(time.Millisecond))
+
+	symbol, err := p.QRCodeSymbolSize()
+	require.NoError(t, err)
+	assert.Equal(t, QRCodeSymbol{Width: 25, Height: 25, Printable: true}, symbol)
+
+	// No answer within the status timeout
+	p = New(NewMockPrinter(), WithStatusTimeout(20*time.Millisecond))
+	_, err = p.QRCodeSymbolSize()
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+// TestParseSymbolSize tests parsing the symbol size responses
+func TestParseSymbolSize(t *testing.T) {
+	symbol, err := parseSymbolSize([]byte{0x37, 0x76, '1', '0', '5', 0x1F, '1', '0', '5', 0x1F, '0', 0x1F, '0'})
+	require.NoError(t, err)
+	assert.Equal(t, QRCodeSymbol{Width: 105, Height: 105, Printable: true}, symbol)
+
+	_, err = parseSymbolSize([]byte{0x37, 0x76, '1'})
+	assert.Error(t, err)
+	_, err = parseSymbolSize([]byte{0x10, 0x76})
+	assert.Error(t, err)
+	_, err = parseSymbolSize([]byte{0x37, 0x76, 'x', 0x1F, '1', 0x1F, '0', 0x1F, '0'})
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package escpos
+
+import (
+	"fmt"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Size of a QR code module in dots when QRCodeAsImage is given no size
+const defaultQRCodeModuleDots = 4
+
+// QRCodeAsImage renders a QR code locally and prints it through the raster
+// image pipeline, for printers ignoring GS ( k.
+// sizeDots: width and height of the image in dots, 0 for 4 dots per module
+// ecLevel: one of the QRCodeErrorCorrectionLevel* constants
+func (e *Escpos) QRCodeAsImage(data string, sizeDots int, ecLevel uint8) (int, error) {
+	bc, err := encodeQRCode([]byte(data), ecLevel)
+	if err != nil {
+		return 0, err
+	}
+
+	if sizeDots == 0 {
+		sizeDots = bc.Bounds().Dx() * defaultQRCodeModuleDots
+	}
+	return e.printQRCodeImage(bc, sizeDots)
+}
+
+// printQRCodeImage scales an encoded QR code to sizeDots and prints it as a raster image
+func (e *Escpos) printQRCodeImage(bc barcode.Barcode, sizeDots int) (int, error) {
+	if modules := bc.Bounds().Dx(); sizeDots < modules {
+		return 0, fmt.Errorf("QR code needs a size of at least %d dots", modules)
+	}
+
+	scaled, err := barcode.Scale(bc, sizeDots, sizeDots)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scale QR code: %w", err)
+	}
+
+	raster, err := ditherImage(scaled, true, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to rasterize QR code: %w", err)
+	}
+	return e.printRaster(raster)
+}
+
+// encodeQRCode encodes a QR code with the software encoder
+func encodeQRCode(data []byte, ecLevel uint8) (barcode.Barcode, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("QR code data cannot be empty")
+	}
+
+	level := qr.L
+	switch ecLevel {
+	case QRCodeErrorCorrectionLevelM:
+		level = qr.M
+	case QRCodeErrorCorrectionLevelQ:
+		level = qr.Q
+	case QRCodeErrorCorrectionLevelH:
+		level = qr.H
+	}
+
+	bc, err := qr.Encode(string(data), level, qr.Auto)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return bc, nil
+}
@@ -0,0 +1,51 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestQRCodeAsImage tests rendering a QR code as a raster image
+func TestQRCodeAsImage(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	// A version 1 QR code is 21 modules wide, 4 dots per module by default
+	_, err := p.QRCodeAsImage("HELLO", 0, QRCodeErrorCorrectionLevelM)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	output := mock.Bytes()
+	widthBytes := (21*4 + 7) / 8
+	assert.Equal(t, []byte{gs, 'v', '0', 0, byte(widthBytes), 0, 84, 0}, output[:8])
+	assert.Len(t, output, 8+widthBytes*84)
+
+	// Too small
+	_, err = p.QRCodeAsImage("HELLO", 20, QRCodeErrorCorrectionLevelM)
+	assert.Error(t, err)
+
+	// Empty
+	_, err = p.QRCodeAsImage("", 0, QRCodeErrorCorrectionLevelM)
+	assert.Error(t, err)
+}
+
+// TestQRCodeFallback tests the automatic fallback for printers ignoring GS ( k
+func TestQRCodeFallback(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{NoQRCode: true})
+
+	_, err := p.QRCode("HELLO", QRCodeModel2, 5, QRCodeErrorCorrectionLevelL)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	// 21 modules of 5 dots
+	output := mock.Bytes()
+	assert.Equal(t, []byte{gs, 'v', '0', 0, 14, 0, 105, 0}, output[:8])
+	assert.NotContains(t, string(output), string([]byte{gs, '(', 'k'}))
+}
@@ -0,0 +1,113 @@
+package escpos
+
+// rasterImage is a monochrome image ready to be sent with GS v 0 or GS ( L
+type rasterImage struct {
+	density    byte // GS v 0 mode: +1 for double width, +2 for double height
+	widthBytes int  // width of a row in bytes, 8 dots per byte
+	width      int  // width in dots, the last byte of a row being padded (0: widthBytes * 8)
+	height     int  // number of rows
+	data       []byte
+}
+
+// command returns the GS v 0 command printing the image
+func (r rasterImage) command() ([]byte, error) {
+	header := []byte{gs, 'v', '0', r.density}
+
+	if res, err := intLowHigh(r.widthBytes, 2); err != nil {
+		return nil, err
+	} else {
+		header = append(header, res...)
+	}
+
+	if res, err := intLowHigh(r.height, 2); err != nil {
+		return nil, err
+	} else {
+		header = append(header, res...)
+	}
+
+	return append(header, r.data...), nil
+}
+
+// commandFor returns the command printing the image with the given command family
+func (r rasterImage) commandFor(c RasterCommand) ([]byte, error) {
+	if c == RasterGraphics {
+		return r.graphicsCommand()
+	}
+	return r.command()
+}
+
+// graphicsCommand returns the GS ( L commands storing the image in the print
+// buffer (function 112) and printing it (function 50)
+func (r rasterImage) graphicsCommand() ([]byte, error) {
+	// GS v 0 doubles the size with the density bits, GS ( L with the scales
+	bx, by := byte(1), byte(1)
+	if r.density&1 != 0 {
+		bx = 2
+	}
+	if r.density&2 != 0 {
+		by = 2
+	}
+
+	width, err := LowHigh16(r.widthBytes * 8)
+	if err != nil {
+		return nil, err
+	}
+	height, err := LowHigh16(r.height)
+	if err != nil {
+		return nil, err
+	}
+
+	params := []byte{48, 112, 48, bx, by, 49, width[0], width[1], height[0], height[1]}
+	cmd, err := graphicsCommand(params, r.data)
+	if err != nil {
+		return nil, err
+	}
+	return append(cmd, gs, '(', 'L', 2, 0, 48, 50), nil
+}
+
+// printedWidth returns the width of the printed image in dots, doubled by
+// the low horizontal density
+func (r rasterImage) printedWidth() int {
+	if r.density&1 != 0 {
+		return r.widthBytes * 16
+	}
+	return r.widthBytes * 8
+}
+
+// split cuts the image into horizontal bands of at most maxHeight rows
+func (r rasterImage) split(maxHeight int) []rasterImage {
+	if maxHeight <= 0 || r.height <= maxHeight {
+		return []rasterImage{r}
+	}
+
+	var bands []rasterImage
+	for y := 0; y < r.height; y += maxHeight {
+		h := min(maxHeight, r.height-y)
+		bands = append(bands, rasterImage{
+			density:    r.density,
+			widthBytes: r.widthBytes,
+			width:      r.width,
+			height:     h,
+			data:       r.data[y*r.widthBytes : (y+h)*r.widthBytes],
+		})
+	}
+	return bands
+}
+
+// columns returns the image in the column format of FS q: for each dot
+// column from left to right, its bytes of 8 vertical dots from top to
+// bottom, the most significant bit at the top. The height is padded to a
+// multiple of 8 dots.
+func (r rasterImage) columns() []byte {
+	heightBytes := (r.height + 7) / 8
+	width := r.widthBytes * 8
+	out := make([]byte, width*heightBytes)
+	for x := 0; x < width; x++ {
+		for y := 0; y < r.height; y++ {
+			if r.data[y*r.widthBytes+x/8]&(0x80>>(x%8)) != 0 {
+				out[x*heightBytes+y/8] |= 0x80 >> (y % 8)
+			}
+		}
+	}
+	return out
+}
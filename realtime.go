@@ -0,0 +1,52 @@
+package escpos
+
+import "fmt"
+
+// Real-time command bytes
+const (
+	enq byte = 0x05
+	dc4 byte = 0x14
+)
+
+// Pulse duration of OpenDrawerRealtime, in 100ms units
+const realtimeDrawerPulse = 2
+
+// OpenDrawerRealtime opens the cash drawer connected to pin (0 or 1) with a
+// 200ms pulse (DLE DC4 1). Real-time commands are executed on receipt, even
+// while the printer is busy printing or in an error state, and are sent
+// ahead of the data waiting in the write buffer.
+func (e *Escpos) OpenDrawerRealtime(pin uint8) (int, error) {
+	if pin > 1 {
+		pin = 0
+	}
+	return e.realtime([]byte{dle, dc4, 1, pin, realtimeDrawerPulse})
+}
+
+// RecoverAndRestart recovers from a recoverable error, such as a cutter jam
+// once cleared, and resumes printing from the line where the error occurred
+// (DLE ENQ 1)
+func (e *Escpos) RecoverAndRestart() (int, error) {
+	return e.realtime([]byte{dle, enq, 1})
+}
+
+// RecoverAndCancel recovers from a recoverable error and clears the receive
+// and print buffers of the printer (DLE ENQ 2)
+func (e *Escpos) RecoverAndCancel() (int, error) {
+	return e.realtime([]byte{dle, enq, 2})
+}
+
+// ClearBufferRealtime clears the receive and print buffers of the printer
+// (DLE DC4 8), and drops the data waiting in the write buffer
+func (e *Escpos) ClearBufferRealtime() (int, error) {
+	e.dst.Reset(e.out)
+	return e.realtime([]byte{dle, dc4, 8, 1, 3, 20, 1, 6, 2, 8})
+}
+
+// realtime sends a real-time command immediately, bypassing the write buffer
+func (e *Escpos) realtime(cmd []byte) (int, error) {
+	n, err := WriteFull(e.out, cmd)
+	if err != nil {
+		return n, fmt.Errorf("failed to send real-time command: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,51 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRealtimeCommands tests that real-time commands are sent ahead of the buffered data
+func TestRealtimeCommands(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.Write("pending")
+	require.NoError(t, err)
+
+	_, err = p.OpenDrawerRealtime(1)
+	require.NoError(t, err)
+	_, err = p.RecoverAndRestart()
+	require.NoError(t, err)
+	_, err = p.RecoverAndCancel()
+	require.NoError(t, err)
+
+	expected := []byte{
+		dle, dc4, 1, 1, 2,
+		dle, enq, 1,
+		dle, enq, 2,
+	}
+	assert.Equal(t, expected, mock.Bytes())
+
+	require.NoError(t, p.Print())
+	assert.Equal(t, append(expected, "pending"...), mock.Bytes())
+}
+
+// TestClearBufferRealtime tests that clearing the buffers drops the buffered data
+func TestClearBufferRealtime(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.Write("dropped")
+	require.NoError(t, err)
+	_, err = p.ClearBufferRealtime()
+	require.NoError(t, err)
+	_, err = p.Write("kept")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := []byte{dle, dc4, 8, 1, 3, 20, 1, 6, 2, 8, 'k', 'e', 'p', 't'}
+	assert.Equal(t, expected, mock.Bytes())
+}
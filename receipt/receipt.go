@@ -0,0 +1,207 @@
+// Package receipt describes receipts as a tree of nodes (lines, key/value
+// pairs, dividers, barcodes, QR codes and images) rendered by a Target, such
+// as an Escpos printer or an ePOS-Print XML document, so the usual header,
+// items and totals layout is written once instead of by every application.
+package receipt
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/kovidgoyal/imaging"
+	"github.com/schawnndev/escpos"
+)
+
+// Target is the output of a receipt, implemented by *escpos.Escpos
+type Target interface {
+	WithStyle(s escpos.Style, fn func() error) error
+	Write(text string) (int, error)
+	WriteKV(left, right string) (int, error)
+	WriteTable(t *escpos.Table) (int, error)
+	Divider(c rune) (int, error)
+	Barcode(barcodeType uint8, code string) (int, error)
+	QRCode(code string, model uint8, size uint8, correctionLevel uint8) (int, error)
+	PrintImageWithProcessing(img image.Image, processMethod uint8, highDensityVertical bool, highDensityHorizontal bool) (int, error)
+	PrintWidth() int
+}
+
+// Node is an element of a receipt
+type Node interface {
+	// Render writes the node to e
+	Render(e Target) error
+}
+
+// Receipt is the root of a receipt, its nodes printed in order
+type Receipt struct {
+	Nodes []Node
+}
+
+// New creates a receipt made of nodes
+func New(nodes ...Node) *Receipt {
+	return &Receipt{Nodes: nodes}
+}
+
+// Add appends nodes to the receipt
+func (r *Receipt) Add(nodes ...Node) *Receipt {
+	r.Nodes = append(r.Nodes, nodes...)
+	return r
+}
+
+// Render writes the nodes of the receipt to e, without cutting the paper
+func (r *Receipt) Render(e Target) error {
+	return render(e, r.Nodes)
+}
+
+// Document renders the receipt to a new document
+func (r *Receipt) Document() (*escpos.Document, error) {
+	d := escpos.NewDocument()
+	if err := r.Render(d.Escpos); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// render writes nodes in order
+func render(e Target, nodes []Node) error {
+	for i, n := range nodes {
+		if err := n.Render(e); err != nil {
+			return fmt.Errorf("node %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Section groups nodes under an optional title, printed in bold
+type Section struct {
+	Title string
+	Nodes []Node
+}
+
+// Render writes the title and the nodes of the section
+func (s Section) Render(e Target) error {
+	if s.Title != "" {
+		if err := (Line{Text: s.Title, Style: escpos.Style{Bold: true}}).Render(e); err != nil {
+			return err
+		}
+	}
+	return render(e, s.Nodes)
+}
+
+// Line is a line of text printed with a style
+type Line struct {
+	Text  string
+	Style escpos.Style
+}
+
+// Render writes the text followed by a line feed
+func (l Line) Render(e Target) error {
+	return e.WithStyle(l.Style, func() error {
+		_, err := e.Write(l.Text + "\n")
+		return err
+	})
+}
+
+// Divider is a line of Char across the paper, '-' by default
+type Divider struct {
+	Char rune
+}
+
+// Render writes the divider
+func (d Divider) Render(e Target) error {
+	c := d.Char
+	if c == 0 {
+		c = '-'
+	}
+	return e.WithStyle(escpos.Style{}, func() error {
+		_, err := e.Divider(c)
+		return err
+	})
+}
+
+// KV is a key flush left and its value flush right on the same line, such as
+// a total. When both do not fit, the value is printed on the next line.
+type KV struct {
+	Key, Value string
+	Style      escpos.Style
+}
+
+// Render writes the pair
+func (kv KV) Render(e Target) error {
+	s := kv.Style
+	s.Justify = escpos.JustifyLeft
+	return e.WithStyle(s, func() error {
+		_, err := e.WriteKV(kv.Key, kv.Value)
+		return err
+	})
+}
+
+// Barcode is a centered barcode of the symbology Type (e.g. escpos.BarcodeEAN13)
+type Barcode struct {
+	Type uint8
+	Data string
+}
+
+// Render writes the barcode
+func (b Barcode) Render(e Target) error {
+	return e.WithStyle(escpos.Style{Justify: escpos.JustifyCenter}, func() error {
+		_, err := e.Barcode(b.Type, b.Data)
+		return err
+	})
+}
+
+// QR is a centered QR code. Size defaults to 6 and Level to
+// escpos.QRCodeErrorCorrectionLevelM.
+type QR struct {
+	Data  string
+	Size  uint8
+	Level uint8
+}
+
+// Render writes the QR code
+func (q QR) Render(e Target) error {
+	size, level := q.Size, q.Level
+	if size == 0 {
+		size = 6
+	}
+	if level == 0 {
+		level = escpos.QRCodeErrorCorrectionLevelM
+	}
+	return e.WithStyle(escpos.Style{Justify: escpos.JustifyCenter}, func() error {
+		_, err := e.QRCode(q.Data, escpos.QRCodeModel2, size, level)
+		return err
+	})
+}
+
+// Image is a centered image, such as a logo, dithered to black and white and
+// scaled down to the print width when wider
+type Image struct {
+	Image image.Image
+}
+
+// Render writes the image
+func (i Image) Render(e Target) error {
+	if i.Image == nil {
+		return fmt.Errorf("no image")
+	}
+	img := i.Image
+	if width := e.PrintWidth(); img.Bounds().Dx() > width {
+		img = imaging.Resize(img, width, 0, imaging.Lanczos)
+	}
+	return e.WithStyle(escpos.Style{Justify: escpos.JustifyCenter}, func() error {
+		_, err := e.PrintImageWithProcessing(img, escpos.ImageProcessDither, true, true)
+		return err
+	})
+}
+
+// Table is a table of rows laid out in columns, such as the items of a receipt
+type Table struct {
+	Table *escpos.Table
+}
+
+// Render writes the rows of the table
+func (t Table) Render(e Target) error {
+	return e.WithStyle(escpos.Style{}, func() error {
+		_, err := e.WriteTable(t.Table)
+		return err
+	})
+}
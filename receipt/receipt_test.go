@@ -0,0 +1,90 @@
+package receipt
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRender tests the commands written for the text nodes
+func TestRender(t *testing.T) {
+	d := escpos.NewDocument()
+	d.SetProfile(escpos.Profile{Name: "Test", PrintWidth: 384})
+
+	r := New(
+		Line{Text: "SHOP", Style: escpos.Style{Justify: escpos.JustifyCenter}},
+		Divider{},
+		Section{Title: "Items", Nodes: []Node{KV{Key: "Tea", Value: "2.50"}}},
+		Divider{Char: '='},
+	)
+	r.Add(KV{Key: "TOTAL", Value: "2.50", Style: escpos.Style{Bold: true}})
+	require.NoError(t, r.Render(d.Escpos))
+
+	data, err := d.Bytes()
+	require.NoError(t, err)
+	expected := "\x1ba\x01SHOP\n\x1ba\x00" +
+		strings.Repeat("-", 32) + "\n" +
+		"\x1bE\x01Items\n\x1bE\x00" +
+		"Tea" + strings.Repeat(" ", 25) + "2.50\n" +
+		strings.Repeat("=", 32) + "\n" +
+		"\x1bE\x01TOTAL" + strings.Repeat(" ", 23) + "2.50\n\x1bE\x00"
+	assert.Equal(t, expected, string(data))
+	assert.Equal(t, escpos.Style{}, d.Style)
+}
+
+// TestKVOverflow tests a pair too long for one line
+func TestKVOverflow(t *testing.T) {
+	d := escpos.NewDocument()
+	d.SetProfile(escpos.Profile{Name: "Test", PrintWidth: 120})
+
+	require.NoError(t, KV{Key: "Long label", Value: "1.00"}.Render(d.Escpos))
+	data, err := d.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, "Long label\n      1.00\n", string(data))
+}
+
+// TestRenderCodes tests the barcode, QR code and image nodes
+func TestRenderCodes(t *testing.T) {
+	d, err := New(
+		Barcode{Type: escpos.BarcodeEAN13, Data: "4006381333931"},
+		QR{Data: "https://example.com"},
+		Image{Image: image.NewGray(image.Rect(0, 0, 8, 8))},
+	).Document()
+	require.NoError(t, err)
+
+	data, err := d.Bytes()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "4006381333931")
+	assert.Contains(t, string(data), "https://example.com")
+	assert.Contains(t, string(data), "\x1dv0")
+	assert.Equal(t, 3, strings.Count(string(data), "\x1ba\x01"))
+
+	_, err = New(Image{}).Document()
+	assert.Error(t, err)
+}
+
+// TestRenderTable tests the table node
+func TestRenderTable(t *testing.T) {
+	d := escpos.NewDocument()
+	d.SetProfile(escpos.Profile{Name: "Test", PrintWidth: 120})
+
+	table := escpos.NewTable(escpos.Column{}, escpos.Column{Width: 4, Align: escpos.JustifyRight})
+	require.NoError(t, table.AddRow("Tea", "2.50"))
+	require.NoError(t, New(Table{Table: table}).Render(d.Escpos))
+
+	data, err := d.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, "Tea   2.50\n", string(data))
+}
+
+// TestRenderImageScaled tests an image wider than the print width
+func TestRenderImageScaled(t *testing.T) {
+	d := escpos.NewDocument()
+	require.NoError(t, d.SetPaperWidth(58))
+	require.NoError(t, Image{Image: image.NewGray(image.Rect(0, 0, 800, 100))}.Render(d.Escpos))
+	assert.Empty(t, d.Warnings())
+}
@@ -0,0 +1,539 @@
+package escpos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// receiptLineProps are the properties set by a ReceiptLine property line,
+// applying to the lines after it
+type receiptLineProps struct {
+	widths []int // 0: shares the rest of the line
+	border int   // spaces between columns, -1 for a vertical line
+	align  Justify
+	wrap   bool
+}
+
+// receiptLineCell is a column of a ReceiptLine line
+type receiptLineCell struct {
+	runes []styledRune
+	align Justify
+}
+
+// styledRune is a character of a ReceiptLine column with its decoration
+type styledRune struct {
+	r rune
+	s Style
+}
+
+// PrintReceiptLine prints a document written in the ReceiptLine markup of
+// the OFSC (https://www.ofsc.or.jp/receiptline/en/):
+//   - columns separated by '|', aligned by the spaces around their text:
+//     "Item |" left, "| 1.00" right, "| Total |" or "Total" centered
+//   - decorations toggled by '_' (underline), '"' (bold), '`' (reverse) and
+//     runs of '^' (^ double width, ^^ double height, ^^^ to ^^^^^^^^^
+//     scaled 2 to 8 times), reset at the end of each column
+//   - '\' escaping the next character, or "\xNN" a character code
+//   - a line of '-' printed as a rule, and a line of '=' cutting the paper
+//   - property lines such as {width:*,10; border:line; align:left; text:nowrap},
+//     {code:012345678905; option:upc,2,72,hri}, {code:URL; option:qrcode,4,m}
+//     and {image:<base64 PNG or JPEG>}
+//
+// Unknown properties are ignored, as other ReceiptLine renderers do.
+func (e *Escpos) PrintReceiptLine(src string) (int, error) {
+	written := 0
+	props := receiptLineProps{border: 1, align: JustifyCenter, wrap: true}
+
+	for i, line := range strings.Split(strings.ReplaceAll(strings.TrimSuffix(src, "\n"), "\r\n", "\n"), "\n") {
+		var n int
+		var err error
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			n, err = e.Write("\n")
+		case strings.Trim(trimmed, "-") == "":
+			n, err = e.Divider('-')
+		case strings.Trim(trimmed, "=") == "":
+			n, err = e.Cut()
+		case strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}") && !strings.HasSuffix(trimmed, `\}`):
+			n, err = e.receiptLineProperties(trimmed[1:len(trimmed)-1], &props)
+		default:
+			n, err = e.receiptLineColumns(line, props)
+		}
+		written += n
+		if err != nil {
+			return written, fmt.Errorf("ReceiptLine line %d: %w", i+1, err)
+		}
+	}
+	return written, nil
+}
+
+// receiptLineProperties applies the properties of a property line, printing
+// its code or image
+func (e *Escpos) receiptLineProperties(src string, props *receiptLineProps) (int, error) {
+	values := map[string]string{}
+	for _, p := range splitUnescaped(src, ';') {
+		key, value, _ := strings.Cut(p, ":")
+		values[strings.ToLower(strings.TrimSpace(key))] = unescapeReceiptLine(strings.TrimSpace(value))
+	}
+
+	if v, ok := values["width"]; ok {
+		props.widths = nil
+		if v != "auto" && v != "" {
+			for w := range strings.SplitSeq(v, ",") {
+				w = strings.TrimSpace(w)
+				if w == "*" || w == "auto" {
+					props.widths = append(props.widths, 0)
+					continue
+				}
+				n, err := strconv.Atoi(w)
+				if err != nil || n < 0 {
+					return 0, fmt.Errorf("invalid width %q", w)
+				}
+				props.widths = append(props.widths, n)
+			}
+		}
+	}
+	if v, ok := values["border"]; ok {
+		switch v {
+		case "line":
+			props.border = -1
+		case "space":
+			props.border = 1
+		case "none":
+			props.border = 0
+		default:
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 || n > 2 {
+				return 0, fmt.Errorf("invalid border %q", v)
+			}
+			props.border = n
+		}
+	}
+	if v, ok := values["align"]; ok {
+		align, err := jsonJustify(v)
+		if err != nil {
+			return 0, err
+		}
+		props.align = align
+	}
+	if v, ok := values["text"]; ok {
+		switch v {
+		case "wrap":
+			props.wrap = true
+		case "nowrap":
+			props.wrap = false
+		default:
+			return 0, fmt.Errorf("invalid text property %q", v)
+		}
+	}
+
+	if data, ok := values["image"]; ok {
+		img, err := decodeImageData(data)
+		if err != nil {
+			return 0, err
+		}
+		return e.printFitted(img, props.align)
+	}
+	if data, ok := values["code"]; ok {
+		return e.receiptLineCode(data, values["option"], props.align)
+	}
+	return 0, nil
+}
+
+// receiptLineCode prints the barcode or QR code of a code property, its
+// option being "type,width,height,hri" or "qrcode,size,level"
+func (e *Escpos) receiptLineCode(data, option string, align Justify) (int, error) {
+	opts := strings.Split(strings.ToLower(option), ",")
+	for i := range opts {
+		opts[i] = strings.TrimSpace(opts[i])
+	}
+	number := func(i int, def, lo, hi int) (int, error) {
+		if i >= len(opts) || opts[i] == "" {
+			return def, nil
+		}
+		n, err := strconv.Atoi(opts[i])
+		if err != nil || n < lo || n > hi {
+			return 0, fmt.Errorf("invalid code option %q", opts[i])
+		}
+		return n, nil
+	}
+
+	written := 0
+	write := func(n int, err error) error {
+		written += n
+		return err
+	}
+	err := e.WithStyle(Style{Justify: align}, func() error {
+		if opts[0] == "qrcode" {
+			size, err := number(1, 3, 1, 16)
+			if err != nil {
+				return err
+			}
+			level := "l"
+			if len(opts) > 2 && opts[2] != "" {
+				level = opts[2]
+			}
+			ec, ok := jsonQRLevels[strings.ToUpper(level)]
+			if !ok {
+				return fmt.Errorf("unknown error correction level %q", level)
+			}
+			return write(e.QRCode(data, QRCodeModel2, uint8(size), ec))
+		}
+
+		symbology, err := receiptLineSymbology(opts[0], data)
+		if err != nil {
+			return err
+		}
+		width, err := number(1, 2, 2, 6)
+		if err != nil {
+			return err
+		}
+		height, err := number(2, 72, 1, 255)
+		if err != nil {
+			return err
+		}
+		hri := HRIPositionNone
+		if len(opts) > 3 && opts[3] == "hri" {
+			hri = HRIPositionBelow
+		}
+		if err := write(e.SetBarcodeWidth(uint8(width))); err != nil {
+			return err
+		}
+		if err := write(e.SetBarcodeHeight(uint8(height))); err != nil {
+			return err
+		}
+		if err := write(e.SetHRIPosition(hri)); err != nil {
+			return err
+		}
+		return write(e.printSymbology(symbology, data))
+	})
+	return written, err
+}
+
+// receiptLineSymbology maps the barcode type of a code option to a symbology
+func receiptLineSymbology(name, data string) (uint8, error) {
+	switch name {
+	case "", "code128":
+		return BarcodeBCode128Auto, nil
+	case "code93":
+		return BarcodeBCode93, nil
+	case "code39":
+		return BarcodeCode39, nil
+	case "itf":
+		return BarcodeITF, nil
+	case "codabar", "nw7":
+		return BarcodeCodabar, nil
+	case "upc":
+		if len(data) >= 11 {
+			return BarcodeUPCA, nil
+		}
+		return BarcodeUPCE, nil
+	case "ean", "jan":
+		if len(data) <= 8 {
+			return BarcodeEAN8, nil
+		}
+		return BarcodeEAN13, nil
+	}
+	return 0, fmt.Errorf("unknown barcode type %q", name)
+}
+
+// receiptLineColumns prints a line of columns
+func (e *Escpos) receiptLineColumns(line string, props receiptLineProps) (int, error) {
+	raw := splitUnescaped(line, '|')
+	if len(raw) > 1 && strings.TrimSpace(raw[0]) == "" {
+		raw = raw[1:]
+	}
+	if len(raw) > 1 && strings.TrimSpace(raw[len(raw)-1]) == "" {
+		raw = raw[:len(raw)-1]
+	}
+	cells := make([]receiptLineCell, len(raw))
+	for i, r := range raw {
+		cells[i] = parseReceiptLineColumn(r, e.Style)
+	}
+
+	sep := props.border
+	if sep < 0 {
+		sep = 1
+	}
+	cols := make([]Column, len(cells))
+	for i := range cols {
+		if i < len(props.widths) {
+			cols[i].Width = props.widths[i]
+		}
+	}
+	cpl := e.CharsPerLine()
+	widths, err := NewTable(cols...).widths(cpl - (len(cells)-1)*(sep-1))
+	if err != nil {
+		return 0, err
+	}
+	total := (len(cells) - 1) * sep
+	for _, w := range widths {
+		total += w
+	}
+
+	lines := make([][][]styledRune, len(cells))
+	height := 1
+	for i, c := range cells {
+		lines[i] = c.lines(widths[i], props.wrap)
+		height = max(height, len(lines[i]))
+	}
+
+	// Columns of fixed widths narrower than the line are aligned as a whole
+	offset := 0
+	switch props.align {
+	case JustifyCenter:
+		offset = max(cpl-total, 0) / 2
+	case JustifyRight:
+		offset = max(cpl-total, 0)
+	}
+
+	written := 0
+	for l := range height {
+		var out []styledRune
+		plain := func(s string) {
+			for _, r := range s {
+				out = append(out, styledRune{r, e.Style})
+			}
+		}
+		plain(strings.Repeat(" ", offset))
+		for i, c := range cells {
+			if i > 0 {
+				if props.border < 0 {
+					plain("|")
+				} else {
+					plain(strings.Repeat(" ", sep))
+				}
+			}
+			var text []styledRune
+			if l < len(lines[i]) {
+				text = lines[i][l]
+			}
+			gap := max(widths[i]-runesWidth(text), 0)
+			left := 0
+			switch c.align {
+			case JustifyRight:
+				left = gap
+			case JustifyCenter:
+				left = gap / 2
+			}
+			plain(strings.Repeat(" ", left))
+			out = append(out, text...)
+			plain(strings.Repeat(" ", gap-left))
+		}
+		for len(out) > 0 && out[len(out)-1].r == ' ' && out[len(out)-1].s == e.Style {
+			out = out[:len(out)-1]
+		}
+		out = append(out, styledRune{'\n', e.Style})
+
+		n, err := e.writeStyledRunes(out)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// writeStyledRunes writes the runs of characters of the same style
+func (e *Escpos) writeStyledRunes(text []styledRune) (int, error) {
+	written := 0
+	for len(text) > 0 {
+		end := 1
+		for end < len(text) && text[end].s == text[0].s {
+			end++
+		}
+		var b strings.Builder
+		for _, sr := range text[:end] {
+			b.WriteRune(sr.r)
+		}
+		var n int
+		var err error
+		if text[0].s == e.Style {
+			n, err = e.Write(b.String())
+		} else {
+			n, err = e.WriteStyled(b.String(), text[0].s)
+		}
+		written += n
+		if err != nil {
+			return written, err
+		}
+		text = text[end:]
+	}
+	return written, nil
+}
+
+// parseReceiptLineColumn parses the text of a column and its decorations,
+// on top of the style base
+func parseReceiptLineColumn(raw string, base Style) receiptLineCell {
+	cell := receiptLineCell{align: JustifyCenter}
+	leading := strings.HasPrefix(raw, " ")
+	trailing := strings.HasSuffix(raw, " ") && !strings.HasSuffix(raw, `\ `)
+	switch {
+	case leading && !trailing:
+		cell.align = JustifyRight
+	case trailing && !leading:
+		cell.align = JustifyLeft
+	}
+
+	text := []rune(strings.TrimLeft(raw, " "))
+	if trailing {
+		text = []rune(strings.TrimRight(string(text), " "))
+	}
+	s := base
+	scale := 0
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '\\':
+			if i+1 >= len(text) {
+				cell.runes = append(cell.runes, styledRune{c, s})
+				continue
+			}
+			if r, ok := hexEscape(text[i+1:]); ok {
+				cell.runes = append(cell.runes, styledRune{r, s})
+				i += 3
+				continue
+			}
+			i++
+			cell.runes = append(cell.runes, styledRune{text[i], s})
+		case '_':
+			if s.Underline == UnderlineNone {
+				s.Underline = UnderlineSingle
+			} else {
+				s.Underline = UnderlineNone
+			}
+		case '"':
+			s.Bold = !s.Bold
+		case '`':
+			s.Reverse = !s.Reverse
+		case '^':
+			n := 1
+			for i+1 < len(text) && text[i+1] == '^' {
+				n++
+				i++
+			}
+			if n == scale {
+				n = 0
+			}
+			scale = n
+			s.Width, s.Height = receiptLineScale(n, base)
+		default:
+			cell.runes = append(cell.runes, styledRune{c, s})
+		}
+	}
+	return cell
+}
+
+// receiptLineScale returns the character size of a run of n '^'
+func receiptLineScale(n int, base Style) (uint8, uint8) {
+	switch {
+	case n == 0:
+		return base.Width, base.Height
+	case n == 1:
+		return 2, 1
+	case n == 2:
+		return 1, 2
+	}
+	size := uint8(min(n-1, 8))
+	return size, size
+}
+
+// hexEscape decodes the "xNN" of a "\xNN" escape
+func hexEscape(text []rune) (rune, bool) {
+	if len(text) < 3 || text[0] != 'x' {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(string(text[1:3]), 16, 8)
+	if err != nil {
+		return 0, false
+	}
+	return rune(n), true
+}
+
+// lines lays the column out on lines of width characters, wrapped at the
+// spaces or truncated. Characters wider than the column are narrowed to fit.
+func (c receiptLineCell) lines(width int, wrap bool) [][]styledRune {
+	var lines [][]styledRune
+	var line []styledRune
+	for _, sr := range c.runes {
+		if runeWidth(sr) > width {
+			sr.s.Width = uint8(max(width, 1))
+		}
+		if runesWidth(line)+runeWidth(sr) > width && len(line) > 0 {
+			if !wrap {
+				break
+			}
+			var next []styledRune
+			if sp := lastSpace(line); sp >= 0 && sr.r != ' ' {
+				next = append(next, line[sp+1:]...)
+				line = line[:sp]
+			}
+			lines = append(lines, line)
+			line = next
+		}
+		if sr.r == ' ' && len(line) == 0 && len(lines) > 0 {
+			continue
+		}
+		line = append(line, sr)
+	}
+	return append(lines, line)
+}
+
+// lastSpace returns the index of the last space of a line, -1 if none
+func lastSpace(line []styledRune) int {
+	for i := len(line) - 1; i >= 0; i-- {
+		if line[i].r == ' ' {
+			return i
+		}
+	}
+	return -1
+}
+
+// runeWidth returns the number of characters a rune takes on a line
+func runeWidth(sr styledRune) int {
+	return int(max(sr.s.Width, 1))
+}
+
+// runesWidth returns the number of characters text takes on a line
+func runesWidth(text []styledRune) int {
+	w := 0
+	for _, sr := range text {
+		w += runeWidth(sr)
+	}
+	return w
+}
+
+// splitUnescaped splits s at the separators not escaped by a '\'
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescapeReceiptLine removes the escapes of a property value
+func unescapeReceiptLine(s string) string {
+	var b strings.Builder
+	text := []rune(s)
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) {
+			if r, ok := hexEscape(text[i+1:]); ok {
+				b.WriteRune(r)
+				i += 3
+				continue
+			}
+			i++
+		}
+		b.WriteRune(text[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,137 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintReceiptLineColumns tests the alignment of the columns
+func TestPrintReceiptLineColumns(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetCharsPerLine(20)
+
+	_, err := p.PrintReceiptLine("RECEIPT\n" +
+		"Apple | 1.00\n" +
+		"|Left |\n" +
+		"| Right|\n" +
+		"\n" +
+		"-\n")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := "      RECEIPT\n" +
+		"Apple           1.00\n" +
+		"Left\n" +
+		"               Right\n" +
+		"\n" +
+		"--------------------\n"
+	assert.Equal(t, expected, mock.String())
+}
+
+// TestPrintReceiptLineDecorations tests the decorations and escapes
+func TestPrintReceiptLineDecorations(t *testing.T) {
+	for src, expected := range map[string]string{
+		`"Bold" text `:   "\x1bE\x01Bold\x1bE\x00 text\n",
+		"_U_ `R` ":       "\x1b-\x01U\x1b-\x00 \x1dB\x01R\x1dB\x00\n",
+		"^W ":            "\x1d!\x10W\x1d!\x00\n",
+		"^^^Big ":        "\x1d!\x11Big\x1d!\x00\n",
+		"^^^Big^^^ end ": "\x1d!\x11Big\x1d!\x00 end\n",
+		`a\|b\"c\x41 `:   "a|b\"cA\n",
+	} {
+		mock := NewMockPrinter()
+		p := New(mock)
+		_, err := p.PrintReceiptLine(src)
+		require.NoError(t, err)
+		require.NoError(t, p.Print())
+		assert.Equal(t, expected, mock.String(), src)
+	}
+}
+
+// TestPrintReceiptLineProperties tests the width, border and text properties
+func TestPrintReceiptLineProperties(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetCharsPerLine(20)
+
+	_, err := p.PrintReceiptLine("{width:*,6; border:line}\n" +
+		"Item | 1.00\n" +
+		"{border:none; text:nowrap; width:4,4; align:left}\n" +
+		"Truncated | Abc\n" +
+		"{width:auto; border:space; text:wrap}\n" +
+		"Wrapped words here | X\n")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := "Item         |  1.00\n" +
+		"Trun Abc\n" +
+		"Wrapped            X\n" +
+		"words\n" +
+		"here\n"
+	assert.Equal(t, expected, mock.String())
+}
+
+// TestPrintReceiptLineCodes tests the code, image and cut lines
+func TestPrintReceiptLineCodes(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.PrintReceiptLine("{code:012345678905; option:upc,3,60,hri}\n" +
+		"{code:https://example.com; option:qrcode,4,h}\n" +
+		"=\n")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	out := mock.String()
+	assert.Contains(t, out, "\x1ba\x01\x1dw\x03\x1dh\x3c\x1dH\x02\x1dk\x00012345678905\x00")
+	assert.Contains(t, out, "\x1d(k\x03\x001C\x04")
+	assert.Contains(t, out, "\x1d(k\x03\x001E3")
+	assert.Contains(t, out, "\x1dV")
+}
+
+// TestPrintReceiptLineErrors tests the invalid properties
+// TestPrintReceiptLineWideCharacters tests characters scaled wider than their column
+func TestPrintReceiptLineWideCharacters(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.PrintReceiptLine("{width:2,*}\n|^^^^^AB|total|")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Contains(t, mock.String(), "total")
+	assert.Equal(t, 2, mock.CountCommand([]byte{gs, '!', 0x13}), "A and B narrowed to double width")
+}
+
+// FuzzPrintReceiptLine tests that no markup makes PrintReceiptLine panic
+func FuzzPrintReceiptLine(f *testing.F) {
+	for _, src := range []string{
+		"{width:2,*}\n|^^^^^AB|total|",
+		"Item | ^^^Price\n{border:line}\nA|B|C",
+		"{width:*,3; text:nowrap}\n`Long column of text` | 12.00",
+		"---\n===\n\\x41 \"bold\" _under_",
+	} {
+		f.Add(src)
+	}
+	f.Fuzz(func(t *testing.T, src string) {
+		p := New(NewMockPrinter())
+		p.PrintReceiptLine(src)
+	})
+}
+
+func TestPrintReceiptLineErrors(t *testing.T) {
+	p := New(NewMockPrinter())
+	for _, src := range []string{
+		"{width:x}",
+		"{border:3}",
+		"{align:middle}",
+		"{code:123; option:pdf417}",
+		"{code:123; option:qrcode,4,z}",
+		"{image:!!}",
+		"{width:30,30}\nA | B",
+	} {
+		_, err := p.PrintReceiptLine(src)
+		assert.Error(t, err, src)
+	}
+}
@@ -0,0 +1,159 @@
+package escpos
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// blockMark is the start of a block of a reversed document, with the
+// commands restoring the style in effect there
+type blockMark struct {
+	offset int
+	prefix []byte
+}
+
+// SetReverseOutput makes the document print its last line first, upside
+// down, so the receipt reads correctly on printers mounted upside down or
+// when it is torn off towards the customer. It must be set on an empty
+// document.
+//
+// Each line written with Write and each band of an image becomes a block;
+// blocks are printed in reverse order, each one starting with the text style
+// in effect where it was written, and images are rotated. Barcodes and QR
+// codes are printed as rotated images, and each feed ends a block; the NV
+// images, grayscale images and QRCodeAdvanced symbols cannot be rotated and
+// return an error. Cut the paper after printing the document, not inside it.
+func (d *Document) SetReverseOutput(on bool) error {
+	if d.length() > 0 || len(d.embeds) > 0 {
+		return fmt.Errorf("reverse output must be set on an empty document")
+	}
+	if !on {
+		d.blocks, d.blockEnd = nil, nil
+		return nil
+	}
+	if d.config.DisableUpsideDown {
+		return fmt.Errorf("upside-down mode is %w", ErrFeatureDisabled)
+	}
+	d.blocks = []blockMark{{offset: 0, prefix: d.stylePrefix()}}
+	d.blockEnd = d.markBlock
+	return nil
+}
+
+// markBlock ends the current block of a reversed document
+func (d *Document) markBlock() error {
+	if err := d.dst.Flush(); err != nil {
+		return err
+	}
+	d.blocks = append(d.blocks, blockMark{offset: d.buf.Len(), prefix: d.stylePrefix()})
+	return nil
+}
+
+// stylePrefix returns the commands setting every attribute of the current
+// style, upside down
+func (d *Document) stylePrefix() []byte {
+	s := d.Style.normalized()
+	var cmd []byte
+	if !d.config.DisableBold {
+		cmd = append(cmd, esc, 'E', boolToByte(s.Bold))
+	}
+	cmd = append(cmd, gs, '!', d.sizeByte(s.Height, s.Width))
+	if !d.config.DisableUnderline {
+		cmd = append(cmd, esc, '-', s.Underline)
+	}
+	if !d.config.DisableReverse {
+		cmd = append(cmd, gs, 'B', boolToByte(s.Reverse))
+	}
+	if !d.config.DisableRotate {
+		cmd = append(cmd, esc, 'V', boolToByte(s.Rotate))
+	}
+	if !d.config.DisableJustify {
+		cmd = append(cmd, esc, 'a', byte(s.Justify))
+	}
+	return append(cmd, esc, '{', 1)
+}
+
+// appendReversed appends the blocks of a reversed document to dst in reverse order
+func (d *Document) appendReversed(dst []byte) ([]byte, error) {
+	if err := d.dst.Flush(); err != nil {
+		return nil, err
+	}
+	data := d.buf.Bytes()
+	end := len(data)
+	for i := len(d.blocks) - 1; i >= 0; i-- {
+		b := d.blocks[i]
+		if b.offset < end {
+			dst = append(dst, b.prefix...)
+			dst = append(dst, data[b.offset:end]...)
+		}
+		end = b.offset
+	}
+	return append(dst, esc, '{', 0), nil
+}
+
+// writeBlocks writes text line by line, ending a block after each line
+func (e *Escpos) writeBlocks(data string) (int, error) {
+	written := 0
+	for _, line := range strings.SplitAfter(data, "\n") {
+		if line == "" {
+			continue
+		}
+		n, err := e.write(line)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		if strings.HasSuffix(line, "\n") {
+			if err := e.blockEnd(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// endBlock ends the block of a reversed document after a command printing
+// or feeding the paper, passing its result through
+func (e *Escpos) endBlock(n int, err error) (int, error) {
+	if err != nil || e.blockEnd == nil {
+		return n, err
+	}
+	return n, e.blockEnd()
+}
+
+// checkUpright fails in a reversed document for the content that cannot be
+// turned upside down
+func (e *Escpos) checkUpright(what string) error {
+	if e.blockEnd != nil {
+		return fmt.Errorf("%s cannot be printed in a reversed document", what)
+	}
+	return nil
+}
+
+// rotated returns the image turned by 180 degrees. Each row is reversed,
+// then shifted left by the padding of its last byte so the image keeps its
+// left edge.
+func (r rasterImage) rotated() rasterImage {
+	pad := 0
+	if r.width > 0 {
+		pad = r.widthBytes*8 - r.width
+	}
+	out := r
+	out.data = make([]byte, len(r.data))
+	for y := range r.height {
+		row := r.data[y*r.widthBytes : (y+1)*r.widthBytes]
+		dst := out.data[(r.height-1-y)*r.widthBytes : (r.height-y)*r.widthBytes]
+		for i, b := range row {
+			dst[len(row)-1-i] = bits.Reverse8(b)
+		}
+		if pad > 0 {
+			for i := range dst {
+				dst[i] <<= pad
+				if i+1 < len(dst) {
+					dst[i] |= dst[i+1] >> (8 - pad)
+				}
+			}
+		}
+	}
+	return out
+}
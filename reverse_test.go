@@ -0,0 +1,136 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReverseOutput tests printing the lines of a document in reverse order, upside down
+func TestReverseOutput(t *testing.T) {
+	doc := NewDocument()
+	require.NoError(t, doc.SetReverseOutput(true))
+
+	doc.SetBold(true)
+	doc.Write("SHOP\n")
+	doc.SetBold(false)
+	doc.Write("Coffee\nTea\n")
+
+	data, err := doc.Bytes()
+	require.NoError(t, err)
+
+	prefix := func(bold byte) []byte {
+		return []byte{esc, 'E', bold, gs, '!', 0, esc, '-', 0, gs, 'B', 0, esc, 'V', 0, esc, 'a', 0, esc, '{', 1}
+	}
+	var expected []byte
+	expected = append(append(expected, prefix(0)...), "Tea\n"...)
+	expected = append(append(expected, prefix(1)...), esc, 'E', 0)
+	expected = append(expected, "Coffee\n"...)
+	expected = append(append(expected, prefix(0)...), esc, 'E', 1)
+	expected = append(expected, "SHOP\n"...)
+	expected = append(expected, esc, '{', 0)
+	assert.Equal(t, expected, data)
+}
+
+// indexOf returns the index of s in data
+func indexOf(data []byte, s string) int {
+	for i := 0; i+len(s) <= len(data); i++ {
+		if string(data[i:i+len(s)]) == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// TestReverseOutputImage tests that image bands are rotated and printed in reverse order
+func TestReverseOutputImage(t *testing.T) {
+	doc := NewDocument()
+	doc.SetProfile(Profile{MaxImageHeight: 1})
+	require.NoError(t, doc.SetReverseOutput(true))
+
+	_, err := doc.printRaster(rasterImage{widthBytes: 2, height: 2, data: []byte{0x80, 0x00, 0x00, 0x03}})
+	require.NoError(t, err)
+
+	data, err := doc.Bytes()
+	require.NoError(t, err)
+
+	p := doc.stylePrefix()
+	var expected []byte
+	expected = append(append(expected, p...), gs, 'v', '0', 0, 2, 0, 1, 0, 0xC0, 0x00)
+	expected = append(append(expected, p...), gs, 'v', '0', 0, 2, 0, 1, 0, 0x00, 0x01)
+	expected = append(expected, esc, '{', 0)
+	assert.Equal(t, expected, data)
+}
+
+// TestRasterRotated tests turning an image whose rows are padded
+func TestRasterRotated(t *testing.T) {
+	r := rasterImage{widthBytes: 2, width: 12, height: 2, data: []byte{0xC0, 0x00, 0x00, 0x10}}
+	assert.Equal(t, []byte{0x80, 0x00, 0x00, 0x30}, r.rotated().data)
+
+	// Without padding
+	r = rasterImage{widthBytes: 2, height: 2, data: []byte{0xC0, 0x00, 0x00, 0x10}}
+	assert.Equal(t, []byte{0x08, 0x00, 0x00, 0x03}, r.rotated().data)
+}
+
+// TestReverseOutputSymbols tests that barcodes, QR codes and feeds are
+// blocks of their own, the symbols rotated as images
+func TestReverseOutputSymbols(t *testing.T) {
+	doc := NewDocument()
+	require.NoError(t, doc.SetReverseOutput(true))
+
+	doc.Write("A\n")
+	_, err := doc.EAN8("9638507")
+	require.NoError(t, err)
+	_, err = doc.FeedDots(16)
+	require.NoError(t, err)
+	_, err = doc.QRCode("hello", QRCodeModel2, 3, QRCodeErrorCorrectionLevelL)
+	require.NoError(t, err)
+	doc.Write("B\n")
+
+	data, err := doc.Bytes()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "\x1dk")
+	assert.NotContains(t, string(data), "\x1d(k")
+
+	b, qr, feed, a := indexOf(data, "B\n"), indexOf(data, "\x1dv0"), indexOf(data, "\x1bJ\x10"), indexOf(data, "A\n")
+	barcode := qr + 1 + indexOf(data[qr+1:], "\x1dv0")
+	assert.True(t, b >= 0 && b < qr && qr < feed && feed < barcode && barcode < a, "order %d %d %d %d %d", b, qr, feed, barcode, a)
+
+	// Symbols the printer draws itself cannot be rotated
+	_, err = doc.QRCodeAdvanced([]byte("hello"), QRCodeOptions{})
+	assert.Error(t, err)
+	_, err = doc.PrintNVImage(1)
+	assert.Error(t, err)
+}
+
+// TestReverseOutputInvalid tests the documents that cannot be reversed
+func TestReverseOutputInvalid(t *testing.T) {
+	doc := NewDocument()
+	doc.Write("text")
+	assert.Error(t, doc.SetReverseOutput(true))
+
+	doc = NewDocument()
+	doc.SetConfig(PrinterConfig{DisableUpsideDown: true})
+	assert.Error(t, doc.SetReverseOutput(true))
+
+	doc = NewDocument()
+	require.NoError(t, doc.SetReverseOutput(true))
+	assert.Error(t, doc.Embed(NewDocument()))
+}
+
+// TestReverseOutputRollback tests rolling back the blocks of a reversed document
+func TestReverseOutputRollback(t *testing.T) {
+	doc := NewDocument()
+	require.NoError(t, doc.SetReverseOutput(true))
+	doc.Write("A\n")
+	sp := doc.Savepoint()
+	doc.Write("Bread\nCake\n")
+	require.NoError(t, doc.RollbackTo(sp))
+	doc.Write("D\n")
+
+	data, err := doc.Bytes()
+	require.NoError(t, err)
+	assert.Less(t, indexOf(data, "D"), indexOf(data, "A"))
+	assert.Equal(t, -1, indexOf(data, "Bread"))
+}
@@ -0,0 +1,121 @@
+package escpos
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// isRightToLeft reports whether r is a Hebrew or Arabic letter
+func isRightToLeft(r rune) bool {
+	if r >= 0x0660 && r <= 0x0669 || isArabicMark(r) {
+		return false
+	}
+	return r >= 0x0590 && r <= 0x08FF || r >= 0xFB1D && r <= 0xFEFC
+}
+
+// isStrongLeftToRight reports whether r is a letter written left to right
+func isStrongLeftToRight(r rune) bool {
+	return unicode.IsLetter(r) && !isRightToLeft(r)
+}
+
+// isRightToLeftParagraph reports whether the first letter of text is written right to left
+func isRightToLeftParagraph(text []rune) bool {
+	for _, r := range text {
+		if isRightToLeft(r) {
+			return true
+		}
+		if isStrongLeftToRight(r) {
+			return false
+		}
+	}
+	return false
+}
+
+// bidiOrder returns a line in visual order. A line starting with a
+// right-to-left letter is reversed, keeping its left-to-right runs; in the
+// other lines only the right-to-left runs, such as an Arabic name in a
+// French sentence, are reversed.
+func bidiOrder(line []rune) []rune {
+	if isRightToLeftParagraph(line) {
+		return visualOrder(line)
+	}
+	out := make([]rune, 0, len(line))
+	for i := 0; i < len(line); {
+		if !isRightToLeft(line[i]) {
+			out = append(out, line[i])
+			i++
+			continue
+		}
+		// The run ends at its last right-to-left letter before a left-to-right one
+		end := i + 1
+		for j := end; j < len(line) && !isStrongLeftToRight(line[j]); j++ {
+			if isRightToLeft(line[j]) {
+				end = j + 1
+			}
+		}
+		out = append(out, visualOrder(line[i:end])...)
+		i = end
+	}
+	return out
+}
+
+// arabicEncoding returns the encoding WriteArabic prints with: code page 864
+// with its presentation forms, or code page 1256 when the profile lists only
+// the latter, whose letters are printed unjoined
+func (e *Escpos) arabicEncoding() (enc encoding.Encoding, codepage uint8, shaped bool) {
+	cps := e.profile.CodePages
+	if cps != nil && !slices.Contains(cps, CodePageCP864) && slices.Contains(cps, CodePageCP1256) {
+		return charmap.Windows1256, CodePageCP1256, false
+	}
+	return CodePage864, CodePageCP864, true
+}
+
+// WriteArabic prints right-to-left text, such as Arabic or Arabic mixed with
+// numbers and Latin words. Each paragraph is wrapped for the characters per
+// line, its letters are joined with their contextual forms, and each line is
+// reordered for printing and ended with a line feed. Paragraphs starting
+// with an Arabic letter are right-justified when the current justification
+// is left. The text is printed with code page 864, or code page 1256 when
+// the profile lists it but not 864, then the code page of the default
+// encoding is restored.
+func (e *Escpos) WriteArabic(text string) (int, error) {
+	enc, codepage, shaped := e.arabicEncoding()
+	cols := e.CharsPerLine()
+
+	written := 0
+	for _, paragraph := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		lines := wrapText(paragraph, cols)
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+
+		var b strings.Builder
+		for _, line := range lines {
+			var letters []rune
+			if shaped {
+				letters = shapeLetters(line)
+			} else {
+				letters = slices.DeleteFunc([]rune(line), isArabicMark)
+			}
+			b.WriteString(string(bidiOrder(letters)) + "\n")
+		}
+
+		style := e.Style
+		if style.Justify == JustifyLeft && isRightToLeftParagraph([]rune(paragraph)) {
+			style.Justify = JustifyRight
+		}
+		err := e.WithStyle(style, func() error {
+			n, err := e.writeInCodePage(b.String(), enc, codepage)
+			written += n
+			return err
+		})
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,53 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TestBidiOrder tests the visual order of mixed lines
+func TestBidiOrder(t *testing.T) {
+	tests := []struct {
+		name, text, expected string
+	}{
+		{"right to left", "سلام 12", "12 مالس"},
+		{"left to right", "Total سلام عليكم: 12", "Total مكيلع مالس: 12"},
+		{"latin only", "Total (12)", "Total (12)"},
+		{"hebrew", "שלום", "םולש"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, string(bidiOrder([]rune(tt.text))), tt.name)
+	}
+}
+
+// TestWriteArabic tests the shaping, justification and code page switches
+func TestWriteArabic(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.WriteArabic("مرحبا\n")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	shaped, err := CodePage864.NewEncoder().String(ShapeArabic("مرحبا"))
+	require.NoError(t, err)
+	assert.Equal(t, "\x1ba\x02\x1bt\x28"+shaped+"\n\x1bt\x02\x1ba\x00", mock.String())
+	assert.Equal(t, JustifyLeft, p.Style.Justify)
+}
+
+// TestWriteArabicCP1256 tests the unjoined letters of code page 1256
+func TestWriteArabicCP1256(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Name: "Test", CodePages: []uint8{CodePagePC850, CodePageCP1256}})
+	p.Style.Justify = JustifyCenter
+
+	_, err := p.WriteArabic("سَلام")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	text, err := charmap.Windows1256.NewEncoder().String("مالس")
+	require.NoError(t, err)
+	assert.Equal(t, "\x1bt\x34"+text+"\n\x1bt\x02", mock.String())
+}
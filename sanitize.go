@@ -0,0 +1,42 @@
+package escpos
+
+import "strings"
+
+// WithSanitizedText passes all the text written by Write and the other text
+// methods through Sanitize, for applications printing untrusted input such as
+// product names or customer notes. Commands are not affected.
+func WithSanitizedText() Option {
+	return func(e *Escpos) {
+		e.sanitize = true
+	}
+}
+
+// Sanitize returns s without the control characters that could start a
+// printer command, such as ESC, GS or DLE, so that untrusted text cannot open
+// the cash drawer, cut the paper or change the settings. Line feeds, carriage
+// returns and tabs are kept; the C1 controls and DEL are removed as well.
+func Sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			return r
+		case r < 0x20 || r >= 0x7f && r < 0xa0:
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// WriteSafe writes untrusted text, see Sanitize
+func (e *Escpos) WriteSafe(data string) (int, error) {
+	return e.Write(Sanitize(data))
+}
+
+// prepareText returns text to write with its control characters removed in
+// the sanitized mode and its line breaks normalized
+func (e *Escpos) prepareText(data string) string {
+	if e.sanitize {
+		data = Sanitize(data)
+	}
+	return e.normalizeNewlines(data)
+}
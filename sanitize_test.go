@@ -0,0 +1,60 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSanitize tests the removal of the control characters
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Coffee", "Coffee"},
+		{"Café\tx2\r\n", "Café\tx2\r\n"},
+		{"Tea\x1bp\x00\x19", "Teap"},
+		{"Cake\x1dV\x00", "CakeV"},
+		{"\x10\x14\x01\x00\x01", ""},
+		{"a\x7fb\u0085c", "abc"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, Sanitize(tt.input), "input %q", tt.input)
+	}
+}
+
+// TestWriteSafe tests writing untrusted text
+func TestWriteSafe(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.WriteSafe("Tea\x1bp\x00\x19\n")
+	require.NoError(t, err)
+	_, err = p.Write("\x1bE\x01")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	assert.Equal(t, "Teap\n\x1bE\x01", mock.String())
+}
+
+// TestWithSanitizedText tests sanitizing all the text writes
+func TestWithSanitizedText(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock, WithSanitizedText())
+
+	_, err := p.Write("Tea\x1bp\x00\x19\n")
+	require.NoError(t, err)
+	_, err = p.WriteWEU("Crème\x1d\x56\x00")
+	require.NoError(t, err)
+	_, err = p.Cut()
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	assert.NotContains(t, mock.String(), "\x1bp")
+	assert.NotContains(t, mock.String(), "\x1dV\x00")
+	assert.Contains(t, mock.String(), "Teap")
+	assert.Equal(t, 1, mock.CountCommand([]byte{gs, 'V'}))
+}
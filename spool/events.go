@@ -0,0 +1,88 @@
+package spool
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType identifies the type of a queue event
+type EventType uint8
+
+// Queue events
+const (
+	EventJobPrinted  EventType = iota // a job was sent to the printer
+	EventJobExpired                   // a job outlived its TTL before it could be printed
+	EventPaperOut                     // the printer ran out of paper, jobs are held
+	EventRollChanged                  // paper was reloaded after a paper out
+)
+
+// String returns the name of the event type
+func (t EventType) String() string {
+	switch t {
+	case EventJobPrinted:
+		return "job printed"
+	case EventJobExpired:
+		return "job expired"
+	case EventPaperOut:
+		return "paper out"
+	case EventRollChanged:
+		return "roll changed"
+	}
+	return "unknown"
+}
+
+// Event is published by a queue to its subscribers
+type Event struct {
+	Type EventType
+	Time time.Time
+	// Job is the job concerned by job events
+	Job Job
+	// Held is the number of jobs waiting in the queue, for paper events
+	Held int
+}
+
+// bus dispatches events to subscribers
+type bus struct {
+	mu     sync.Mutex
+	subs   map[int]func(Event)
+	nextID int
+}
+
+// subscribe registers fn and returns the function unregistering it
+func (b *bus) subscribe(fn func(Event)) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = make(map[int]func(Event))
+	}
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = fn
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs, id)
+	}
+}
+
+// publish calls the subscribers with ev, in subscription order
+func (b *bus) publish(ev Event) {
+	b.mu.Lock()
+	subs := make([]func(Event), 0, len(b.subs))
+	for id := 0; id < b.nextID; id++ {
+		if fn, ok := b.subs[id]; ok {
+			subs = append(subs, fn)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(ev)
+	}
+}
+
+// Subscribe registers fn to be called with every event of the queue, from
+// the goroutine processing the queue. It returns the function unregistering fn.
+func (q *Queue) Subscribe(fn func(Event)) func() {
+	return q.events.subscribe(fn)
+}
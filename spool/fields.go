@@ -0,0 +1,159 @@
+package spool
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/schawnndev/escpos"
+)
+
+// Names of the fields resolved when a job is printed
+const (
+	// FieldPrintTime is the time the job is printed, formatted with the
+	// layout of the field (default: "2006-01-02 15:04")
+	FieldPrintTime = "print_time"
+	// FieldSequence is the number of the job among the jobs printed by the queue, starting at 1
+	FieldSequence = "sequence"
+	// FieldWaiting is the number of jobs still waiting in the queue behind the job
+	FieldWaiting = "waiting"
+)
+
+// Field is a value inserted in the data of a job when it is printed instead
+// of when it is submitted, so jobs held in the queue during an outage show
+// the actual print time
+type Field struct {
+	// Offset is the position in the job data where the value is inserted
+	Offset int
+	// Name is one of the Field* constants
+	Name string
+	// Format is the time layout of time fields
+	Format string
+}
+
+// printContext holds the values of the fields of a job being printed
+type printContext struct {
+	time     time.Time
+	sequence int
+	waiting  int
+}
+
+// value returns the text of a field
+func (f Field) value(pc printContext) (string, error) {
+	switch f.Name {
+	case FieldPrintTime:
+		layout := f.Format
+		if layout == "" {
+			layout = "2006-01-02 15:04"
+		}
+		return pc.time.Format(layout), nil
+	case FieldSequence:
+		return strconv.Itoa(pc.sequence), nil
+	case FieldWaiting:
+		return strconv.Itoa(pc.waiting), nil
+	}
+	return "", fmt.Errorf("unknown field %q", f.Name)
+}
+
+// validateFields checks that the fields of a job are known and within its data
+func validateFields(job Job) error {
+	for _, f := range job.Fields {
+		if _, err := f.value(printContext{}); err != nil {
+			return err
+		}
+		if f.Offset < 0 || f.Offset > len(job.Data) {
+			return fmt.Errorf("field %q is outside of the job data", f.Name)
+		}
+	}
+	for _, c := range job.Checkpoints {
+		if c < 0 || c > len(job.Data) {
+			return fmt.Errorf("checkpoint %d is outside of the job data", c)
+		}
+	}
+	if !slices.IsSorted(job.Checkpoints) {
+		return fmt.Errorf("checkpoints are not in ascending order")
+	}
+	return nil
+}
+
+// resolve returns the data of a job with the values of its fields inserted,
+// and its checkpoints moved by the values inserted before them
+func (j Job) resolve(pc printContext) ([]byte, []int, error) {
+	if len(j.Fields) == 0 {
+		return j.Data, j.Checkpoints, nil
+	}
+
+	fields := slices.Clone(j.Fields)
+	slices.SortStableFunc(fields, func(a, b Field) int { return a.Offset - b.Offset })
+
+	data := make([]byte, 0, len(j.Data)+32*len(fields))
+	checkpoints := slices.Clone(j.Checkpoints)
+	last := 0
+	for _, f := range fields {
+		v, err := f.value(pc)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = append(data, j.Data[last:f.Offset]...)
+		data = append(data, v...)
+		last = f.Offset
+
+		// A field at a checkpoint starts the segment following it
+		for i, c := range j.Checkpoints {
+			if f.Offset < c {
+				checkpoints[i] += len(v)
+			}
+		}
+	}
+	return append(data, j.Data[last:]...), checkpoints, nil
+}
+
+// Template records the fields of a job built with RecordJob
+type Template struct {
+	p           *escpos.Escpos
+	buf         *recorder
+	fields      []Field
+	checkpoints []int
+}
+
+// Field inserts a field at the current position of the job, its value being
+// resolved when the job is printed. format is the time layout of time fields.
+func (t *Template) Field(name, format string) error {
+	f := Field{Name: name, Format: format}
+	if _, err := f.value(printContext{}); err != nil {
+		return err
+	}
+	if err := t.p.Print(); err != nil {
+		return err
+	}
+	f.Offset = t.buf.Len()
+	t.fields = append(t.fields, f)
+	return nil
+}
+
+// Checkpoint marks the current position of the job, at the start of a line,
+// as a point where printing can resume when the paper runs out. See
+// WithRollEndResume.
+func (t *Template) Checkpoint() error {
+	if err := t.p.Print(); err != nil {
+		return err
+	}
+	t.checkpoints = append(t.checkpoints, t.buf.Len())
+	return nil
+}
+
+// RecordJob builds a job with the commands written by fn, which can insert
+// fields resolved at print time through t. The values are written as is,
+// so fields are meant for ASCII text.
+func RecordJob(fn func(p *escpos.Escpos, t *Template) error) (Job, error) {
+	buf := &recorder{}
+	t := &Template{p: escpos.New(buf), buf: buf}
+	if err := fn(t.p, t); err != nil {
+		return Job{}, err
+	}
+	if err := t.p.Print(); err != nil {
+		return Job{}, err
+	}
+	return Job{Data: buf.Bytes(), Fields: t.fields, Checkpoints: t.checkpoints}, nil
+}
@@ -0,0 +1,68 @@
+package spool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQueueFields tests resolving the fields of held jobs at print time
+func TestQueueFields(t *testing.T) {
+	printer := &flakyPrinter{Emulator: emulator.New()}
+	printer.setOffline(true)
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	q := New(printer, WithClock(clock.Now))
+
+	for range 2 {
+		job, err := RecordJob(func(p *escpos.Escpos, tpl *Template) error {
+			p.Write("Printed at ")
+			if err := tpl.Field(FieldPrintTime, "15:04"); err != nil {
+				return err
+			}
+			p.Write("\nTicket #")
+			if err := tpl.Field(FieldSequence, ""); err != nil {
+				return err
+			}
+			p.Write(", ")
+			if err := tpl.Field(FieldWaiting, ""); err != nil {
+				return err
+			}
+			_, err := p.Write(" waiting\n")
+			return err
+		})
+		require.NoError(t, err)
+		require.Len(t, job.Fields, 3)
+
+		_, err = q.Enqueue(job)
+		require.NoError(t, err)
+	}
+	assert.Error(t, q.Process())
+
+	// The printer comes back after 40 minutes
+	clock.now = clock.now.Add(40 * time.Minute)
+	printer.setOffline(false)
+	require.NoError(t, q.Process())
+
+	expected := "Printed at 12:40\nTicket #1, 1 waiting\n" +
+		"Printed at 12:40\nTicket #2, 0 waiting\n"
+	assert.Equal(t, expected, printer.Text())
+}
+
+// TestInvalidFields tests rejecting jobs with invalid fields
+func TestInvalidFields(t *testing.T) {
+	q := New(emulator.New())
+
+	_, err := q.Enqueue(Job{Data: []byte("abc"), Fields: []Field{{Offset: 1, Name: "unknown"}}})
+	assert.Error(t, err)
+	_, err = q.Enqueue(Job{Data: []byte("abc"), Fields: []Field{{Offset: 4, Name: FieldSequence}}})
+	assert.Error(t, err)
+
+	_, err = RecordJob(func(p *escpos.Escpos, tpl *Template) error {
+		return tpl.Field("unknown", "")
+	})
+	assert.Error(t, err)
+}
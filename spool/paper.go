@@ -0,0 +1,88 @@
+package spool
+
+import (
+	"fmt"
+
+	"github.com/schawnndev/escpos"
+)
+
+// ErrPaperOut is returned by Process while the printer has no paper, the
+// same error as escpos.ErrPaperOut
+var ErrPaperOut = escpos.ErrPaperOut
+
+// WithPaperCheck makes the queue check the paper status (DLE EOT 4) before
+// printing, holding the jobs while the printer is out of paper. Running out of
+// paper and reloading it publish EventPaperOut and EventRollChanged.
+func WithPaperCheck() Option {
+	return func(q *Queue) {
+		q.paperCheck = true
+	}
+}
+
+// WithRollChangeSlip prints an operator slip once paper is reloaded, with the
+// time of the roll change, the number of jobs held and the next receipt
+// number returned by nextReceipt (omitted when nil). It implies WithPaperCheck.
+func WithRollChangeSlip(nextReceipt func() string) Option {
+	return func(q *Queue) {
+		q.paperCheck = true
+		q.rollSlip = true
+		q.nextReceipt = nextReceipt
+	}
+}
+
+// checkPaper queries the paper status, publishing the paper out and roll change events
+func (q *Queue) checkPaper() error {
+	if q.status == nil {
+		q.status = escpos.New(q.printer)
+	}
+
+	status, err := q.status.PaperStatus()
+	if err != nil {
+		return fmt.Errorf("failed to query paper status: %w", err)
+	}
+
+	held := q.Len()
+	if status == 0 {
+		if !q.paperOut {
+			q.paperOut = true
+			q.events.publish(Event{Type: EventPaperOut, Time: q.now(), Held: held})
+		}
+		return ErrPaperOut
+	}
+
+	if q.paperOut {
+		q.paperOut = false
+		ev := Event{Type: EventRollChanged, Time: q.now(), Held: held}
+		if q.rollSlip {
+			if err := q.printRollSlip(ev); err != nil {
+				return err
+			}
+		}
+		q.events.publish(ev)
+	}
+	return nil
+}
+
+// printRollSlip prints the operator slip of a roll change
+func (q *Queue) printRollSlip(ev Event) error {
+	data, err := Record(func(p *escpos.Escpos) error {
+		p.SetBold(true)
+		p.Write("PAPER ROLL CHANGED\n")
+		p.SetBold(false)
+		p.Write(fmt.Sprintf("Time: %s\n", ev.Time.Format("2006-01-02 15:04:05")))
+		p.Write(fmt.Sprintf("Jobs held: %d\n", ev.Held))
+		if q.nextReceipt != nil {
+			p.Write(fmt.Sprintf("Next receipt: %s\n", q.nextReceipt()))
+		}
+		_, err := p.Cut()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build roll change slip: %w", err)
+	}
+
+	if _, err := escpos.WriteFull(q.printer, data); err != nil {
+		return fmt.Errorf("failed to print roll change slip: %w", err)
+	}
+	return nil
+}
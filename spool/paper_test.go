@@ -0,0 +1,72 @@
+package spool
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQueuePaperOut tests holding jobs while out of paper and printing the roll change slip
+func TestQueuePaperOut(t *testing.T) {
+	em := emulator.New()
+	em.SetStatus(escpos.RT_STATUS_PAPER, 0x60)
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)}
+
+	q := New(em, WithClock(clock.Now), WithRollChangeSlip(func() string { return "R-0042" }))
+	var events []Event
+	q.Subscribe(func(ev Event) { events = append(events, ev) })
+
+	_, err := q.Enqueue(Job{ID: "receipt", Data: receipt(t, "receipt")})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, q.Process(), ErrPaperOut)
+	assert.ErrorIs(t, q.Process(), ErrPaperOut)
+	assert.Equal(t, 1, q.Len())
+	require.Len(t, events, 1)
+	assert.Equal(t, EventPaperOut, events[0].Type)
+	assert.Equal(t, 1, events[0].Held)
+
+	em.SetStatus(escpos.RT_STATUS_PAPER, 0x12)
+	require.NoError(t, q.Process())
+
+	require.Len(t, events, 3)
+	assert.Equal(t, EventRollChanged, events[1].Type)
+	assert.Equal(t, 1, events[1].Held)
+	assert.Equal(t, EventJobPrinted, events[2].Type)
+	assert.Equal(t, "receipt", events[2].Job.ID)
+
+	lines := strings.Split(em.Text(), "\n")
+	assert.Equal(t, []string{
+		"PAPER ROLL CHANGED",
+		"Time: 2024-01-01 12:30:00",
+		"Jobs held: 1",
+		"Next receipt: R-0042",
+		strings.Repeat("-", 48),
+		"receipt",
+		"",
+	}, lines)
+}
+
+// TestQueueSubscribe tests unsubscribing from the queue events
+func TestQueueSubscribe(t *testing.T) {
+	q := New(emulator.New())
+
+	var count int
+	cancel := q.Subscribe(func(ev Event) { count++ })
+
+	_, err := q.Enqueue(Job{Data: receipt(t, "first")})
+	require.NoError(t, err)
+	require.NoError(t, q.Process())
+	assert.Equal(t, 1, count)
+
+	cancel()
+	_, err = q.Enqueue(Job{Data: receipt(t, "second")})
+	require.NoError(t, err)
+	require.NoError(t, q.Process())
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,90 @@
+package spool
+
+import (
+	"fmt"
+
+	"github.com/schawnndev/escpos"
+)
+
+// Default banner printed before the rest of a job interrupted by the end of the roll
+const defaultResumeBanner = "*** CONTINUED ***"
+
+// resumePoint is the position where an interrupted job resumes printing
+type resumePoint struct {
+	id          string
+	data        []byte // resolved data of the job
+	checkpoints []int  // checkpoints resolved with data
+	offset      int
+}
+
+// WithRollEndResume finishes the jobs interrupted by the end of the paper
+// roll on the next roll: only the part from the last checkpoint printed on,
+// after a banner line (default: "*** CONTINUED ***"), instead of losing the
+// tail of the receipt. The jobs with Checkpoints are sent segment by segment,
+// checking the paper between segments. It implies WithPaperCheck.
+func WithRollEndResume(banner string) Option {
+	return func(q *Queue) {
+		if banner == "" {
+			banner = defaultResumeBanner
+		}
+		q.paperCheck = true
+		q.resumeBanner = banner
+	}
+}
+
+// printSegments sends prefix, then the segments of a job between its
+// checkpoints, checking the paper after each segment. When the paper runs
+// out, the job is kept to resume at the start of the last segment sent,
+// which may have been cut short. The job resumes with the data and the
+// checkpoints resolved for the interrupted attempt, the print-time fields
+// changing length between attempts.
+func (q *Queue) printSegments(job Job, prefix, data []byte, checkpoints []int) error {
+	start := 0
+	if r := q.resume; r != nil && r.id != job.ID {
+		// The interrupted job expired
+		q.resume = nil
+	} else if r != nil {
+		data, checkpoints, start = r.data, r.checkpoints, r.offset
+		banner, err := Record(func(p *escpos.Escpos) error {
+			p.SetJustify(escpos.JustifyCenter)
+			p.SetBold(true)
+			p.Write(q.resumeBanner + "\n")
+			p.SetBold(false)
+			_, err := p.SetJustify(escpos.JustifyLeft)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to build resume banner: %w", err)
+		}
+		prefix = append(prefix, banner...)
+	}
+	if _, err := escpos.WriteFull(q.printer, prefix); err != nil {
+		return fmt.Errorf("failed to print job %s: %w", job.ID, err)
+	}
+
+	for start < len(data) {
+		end := len(data)
+		for _, c := range checkpoints {
+			if c > start && c < end {
+				end = c
+				break
+			}
+		}
+
+		if _, err := escpos.WriteFull(q.printer, data[start:end]); err != nil {
+			if start > 0 {
+				q.resume = &resumePoint{id: job.ID, data: data, checkpoints: checkpoints, offset: start}
+			}
+			return fmt.Errorf("failed to print job %s: %w", job.ID, err)
+		}
+		if end < len(data) {
+			if err := q.checkPaper(); err != nil {
+				q.resume = &resumePoint{id: job.ID, data: data, checkpoints: checkpoints, offset: start}
+				return err
+			}
+		}
+		start = end
+	}
+	q.resume = nil
+	return nil
+}
@@ -0,0 +1,131 @@
+package spool
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rollEndPrinter is an emulator running out of paper when a line is printed
+type rollEndPrinter struct {
+	*emulator.Emulator
+	lastLine string
+	writes   []string
+}
+
+func (p *rollEndPrinter) Write(b []byte) (int, error) {
+	p.writes = append(p.writes, string(b))
+	n, err := p.Emulator.Write(b)
+	if strings.Contains(string(b), p.lastLine) {
+		p.SetStatus(escpos.RT_STATUS_PAPER, 0x60)
+	}
+	return n, err
+}
+
+// TestQueueRollEndResume tests finishing a receipt on the next roll
+func TestQueueRollEndResume(t *testing.T) {
+	job, err := RecordJob(func(p *escpos.Escpos, tpl *Template) error {
+		p.Write("Receipt #")
+		if err := tpl.Field(FieldSequence, ""); err != nil {
+			return err
+		}
+		p.Write("\n")
+		for _, line := range []string{"Coffee", "Tea", "Cake"} {
+			if err := tpl.Checkpoint(); err != nil {
+				return err
+			}
+			p.Write(line + "\n")
+		}
+		return tpl.Checkpoint()
+	})
+	require.NoError(t, err)
+
+	printer := &rollEndPrinter{Emulator: emulator.New(), lastLine: "Tea"}
+	printer.SetStatus(escpos.RT_STATUS_PAPER, 0x12)
+	q := New(printer, WithRollEndResume(""))
+
+	_, err = q.Enqueue(job)
+	require.NoError(t, err)
+	assert.ErrorIs(t, q.Process(), ErrPaperOut)
+	assert.Equal(t, 1, q.Len())
+	assert.Equal(t, "Receipt #1\nCoffee\nTea\n", printer.Text())
+
+	// A new roll is loaded
+	printer.lastLine = "no more paper outs"
+	printer.SetStatus(escpos.RT_STATUS_PAPER, 0x12)
+	require.NoError(t, q.Process())
+	assert.Equal(t, 0, q.Len())
+
+	lines := strings.Split(printer.Text(), "\n")
+	assert.Equal(t, []string{"Receipt #1", "Coffee", "Tea"}, lines[:3])
+	assert.Equal(t, "*** CONTINUED ***", strings.TrimSpace(lines[3]))
+	assert.Equal(t, []string{"Tea", "Cake", ""}, lines[4:])
+}
+
+// TestResolveCheckpoints tests that checkpoints follow the values inserted before them
+func TestResolveCheckpoints(t *testing.T) {
+	job := Job{
+		Data:        []byte("ab\ncd\n"),
+		Fields:      []Field{{Offset: 1, Name: FieldSequence}, {Offset: 3, Name: FieldWaiting}},
+		Checkpoints: []int{3, 6},
+	}
+	data, checkpoints, err := job.resolve(printContext{sequence: 12, waiting: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "a12b\n3cd\n", string(data))
+	assert.Equal(t, []int{5, 9}, checkpoints)
+
+	job.Checkpoints = []int{4, 2}
+	assert.Error(t, validateFields(job))
+	job.Checkpoints = []int{7}
+	assert.Error(t, validateFields(job))
+}
+
+// TestQueueRollEndResumeFieldLength tests resuming a job whose print-time
+// fields resolve to a different length on the next attempt
+func TestQueueRollEndResumeFieldLength(t *testing.T) {
+	job, err := RecordJob(func(p *escpos.Escpos, tpl *Template) error {
+		p.Write("Waiting: ")
+		if err := tpl.Field(FieldWaiting, ""); err != nil {
+			return err
+		}
+		p.Write("\n")
+		for _, line := range []string{"Coffee", "Tea", "Cake"} {
+			if err := tpl.Checkpoint(); err != nil {
+				return err
+			}
+			p.Write(line + "\n")
+		}
+		return tpl.Checkpoint()
+	})
+	require.NoError(t, err)
+
+	printer := &rollEndPrinter{Emulator: emulator.New(), lastLine: "Tea"}
+	printer.SetStatus(escpos.RT_STATUS_PAPER, 0x12)
+	q := New(printer, WithRollEndResume(""))
+
+	_, err = q.Enqueue(job)
+	require.NoError(t, err)
+	assert.ErrorIs(t, q.Process(), ErrPaperOut)
+	assert.Equal(t, "Waiting: 0\nCoffee\nTea\n", printer.Text())
+
+	// Ten more jobs wait when the new roll is loaded: FieldWaiting now has two digits
+	for range 10 {
+		_, err = q.Enqueue(Job{Data: []byte("x\n")})
+		require.NoError(t, err)
+	}
+	printer.lastLine = "no more paper outs"
+	printer.SetStatus(escpos.RT_STATUS_PAPER, 0x12)
+	printer.writes = nil
+	require.NoError(t, q.Process())
+
+	lines := strings.Split(printer.Text(), "\n")
+	assert.Equal(t, "*** CONTINUED ***", strings.TrimSpace(lines[3]))
+	assert.Equal(t, []string{"Tea", "Cake", "x"}, lines[4:7])
+	// The segments are still cut at the checkpoints
+	assert.Contains(t, printer.writes, "Tea\n")
+	assert.Contains(t, printer.writes, "Cake\n")
+}
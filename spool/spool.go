@@ -0,0 +1,525 @@
+// Package spool provides a print queue delivering jobs to a printer in
+// submission order, retrying while the printer is unreachable.
+package spool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/schawnndev/escpos"
+)
+
+const (
+	esc byte = 0x1B
+	gs  byte = 0x1D
+)
+
+// Job is a print job, holding the raw ESC/POS data to send to the printer
+type Job struct {
+	// ID identifies the job in callbacks, generated when empty
+	ID string
+	// Data is the ESC/POS command stream of the job
+	Data []byte
+	// TTL is the time after which a job that could not be printed is
+	// expired instead of printed (0: the default TTL of the queue)
+	TTL time.Duration
+	// GroupKey marks the jobs belonging to the same physical ticket, such as
+	// a kitchen ticket and its allergy note. See WithCutCoalescing.
+	GroupKey string
+	// Fields are inserted in Data when the job is printed, see RecordJob
+	Fields []Field
+	// Checkpoints are the offsets in Data, in ascending order, where printing
+	// can resume after the paper ran out, see WithRollEndResume
+	Checkpoints []int
+	// IdempotencyKey identifies the request of the job, such as an order
+	// number: a job whose key is already queued, or was printed within the
+	// idempotency window, is not enqueued again. See WithIdempotencyWindow.
+	IdempotencyKey string
+
+	// Submitted is the time the job was enqueued
+	Submitted time.Time
+}
+
+// expired reports whether the job outlived its time to live at now
+func (j Job) expired(now time.Time) bool {
+	return j.TTL > 0 && now.Sub(j.Submitted) >= j.TTL
+}
+
+// Queue is a FIFO print queue for a single printer
+type Queue struct {
+	printer escpos.Printer
+
+	defaultTTL    time.Duration
+	retryInterval time.Duration
+	maxBackoff    time.Duration
+	failures      int // consecutive failed attempts of Run
+	onExpired     func(Job)
+	now           func() time.Time
+	coalesceCuts  bool
+	paperCheck    bool
+	rollSlip      bool
+	nextReceipt   func() string
+	store         Store
+	resumeBanner  string
+	verifyStatus  bool
+	keyWindow     time.Duration
+
+	events bus
+
+	mu      sync.Mutex
+	jobs    []Job
+	nextID  int
+	printed int // number of jobs printed
+	// printed jobs with an idempotency key, by key
+	printedKeys map[string]PrintedKey
+	wake        chan struct{}
+
+	// cut removed from the end of the last printed job, sent before the next
+	// job unless it belongs to the same group
+	pendingCut   []byte
+	pendingGroup string
+
+	status   *escpos.Escpos // used to query the paper status
+	paperOut bool
+
+	// job interrupted by the end of the roll, see WithRollEndResume
+	resume *resumePoint
+}
+
+// PrintedKey is the idempotency key of a printed job
+type PrintedKey struct {
+	Key     string
+	JobID   string
+	Printed time.Time
+}
+
+// Option configures a Queue
+type Option func(*Queue)
+
+// WithDefaultTTL sets the time to live of the jobs enqueued without one (default: no expiry)
+func WithDefaultTTL(d time.Duration) Option {
+	return func(q *Queue) {
+		q.defaultTTL = d
+	}
+}
+
+// WithRetryInterval sets the delay between two delivery attempts when the printer fails (default: 5s)
+func WithRetryInterval(d time.Duration) Option {
+	return func(q *Queue) {
+		if d > 0 {
+			q.retryInterval = d
+		}
+	}
+}
+
+// WithBackoff doubles the delay between two delivery attempts after each
+// consecutive failure, from the retry interval up to max, so a printer gone
+// for a while is not polled in a tight loop
+func WithBackoff(max time.Duration) Option {
+	return func(q *Queue) {
+		q.maxBackoff = max
+	}
+}
+
+// WithIdempotencyWindow sets how long the idempotency keys of the printed
+// jobs are remembered (default: 24 hours). The keys are persisted when the
+// store of the queue is a KeyStore, such as DirStore, and restored by Restore;
+// otherwise they are kept in memory and lost on a restart.
+func WithIdempotencyWindow(d time.Duration) Option {
+	return func(q *Queue) {
+		q.keyWindow = d
+	}
+}
+
+// WithExpiredHandler sets the function called with each job expired before it could be printed
+func WithExpiredHandler(fn func(Job)) Option {
+	return func(q *Queue) {
+		q.onExpired = fn
+	}
+}
+
+// WithCutCoalescing removes the final cut of a job when the next queued job
+// has the same GroupKey, so back-to-back jobs of a group come out as a single
+// ticket. The cut is still performed exactly once, after the last job of the
+// group, even when the following jobs expire.
+func WithCutCoalescing() Option {
+	return func(q *Queue) {
+		q.coalesceCuts = true
+	}
+}
+
+// WithClock sets the function returning the current time (default: time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(q *Queue) {
+		if now != nil {
+			q.now = now
+		}
+	}
+}
+
+// WithStore persists the jobs in s until they are printed or expired, so
+// they can be restored with Restore after a restart
+func WithStore(s Store) Option {
+	return func(q *Queue) {
+		q.store = s
+	}
+}
+
+// New creates a queue delivering its jobs to p
+func New(p escpos.Printer, opts ...Option) *Queue {
+	q := &Queue{
+		printer:       p,
+		retryInterval: 5 * time.Second,
+		keyWindow:     24 * time.Hour,
+		now:           time.Now,
+		wake:          make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Enqueue adds a job to the queue and returns its ID. A job with the
+// idempotency key of a job queued or recently printed is ignored, and the ID
+// of that job is returned.
+func (q *Queue) Enqueue(job Job) (string, error) {
+	if len(job.Data) == 0 {
+		return "", fmt.Errorf("job has no data")
+	}
+	if err := validateFields(job); err != nil {
+		return "", fmt.Errorf("invalid job: %w", err)
+	}
+
+	q.mu.Lock()
+	if id, ok := q.duplicate(job.IdempotencyKey); ok {
+		q.mu.Unlock()
+		return id, nil
+	}
+	q.nextID++
+	if job.ID == "" {
+		job.ID = fmt.Sprintf("job-%d", q.nextID)
+	}
+	if job.TTL == 0 {
+		job.TTL = q.defaultTTL
+	}
+	job.Submitted = q.now()
+	if q.store != nil {
+		if err := q.store.Save(job); err != nil {
+			q.mu.Unlock()
+			return "", err
+		}
+	}
+	q.jobs = append(q.jobs, job)
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job.ID, nil
+}
+
+// Len returns the number of jobs waiting in the queue
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.jobs)
+}
+
+// Process expires the stale jobs, then sends the pending jobs to the printer
+// in order. It stops at the first delivery failure, leaving the failed job at
+// the head of the queue.
+func (q *Queue) Process() error {
+	if q.paperCheck && q.Len() > 0 {
+		if err := q.checkPaper(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		job, following, ok := q.next()
+		if !ok {
+			return q.flushCut()
+		}
+
+		var data []byte
+		if q.pendingCut != nil && job.GroupKey != q.pendingGroup {
+			data = append(data, q.pendingCut...)
+		}
+
+		body, checkpoints, err := job.resolve(printContext{time: q.now(), sequence: q.printed + 1, waiting: q.Len() - 1})
+		if err != nil {
+			return fmt.Errorf("failed to resolve the fields of job %s: %w", job.ID, err)
+		}
+
+		var cut []byte
+		if q.coalesceCuts && job.GroupKey != "" && following == job.GroupKey {
+			body, cut = trimCut(body)
+		}
+
+		if q.resumeBanner != "" && len(checkpoints) > 0 {
+			if err := q.printSegments(job, data, body, checkpoints); err != nil {
+				return err
+			}
+		} else if _, err := escpos.WriteFull(q.printer, append(data, body...)); err != nil {
+			return fmt.Errorf("failed to print job %s: %w", job.ID, err)
+		}
+
+		if q.verifyStatus {
+			if err := q.verify(job); err != nil {
+				// The held back cut was sent with the job
+				q.mu.Lock()
+				q.pendingCut = nil
+				q.mu.Unlock()
+				return err
+			}
+		}
+
+		q.mu.Lock()
+		q.jobs = q.jobs[1:]
+		q.printed++
+		var key PrintedKey
+		if job.IdempotencyKey != "" {
+			if q.printedKeys == nil {
+				q.printedKeys = make(map[string]PrintedKey)
+			}
+			key = PrintedKey{Key: job.IdempotencyKey, JobID: job.ID, Printed: q.now()}
+			q.printedKeys[key.Key] = key
+		}
+		q.pendingCut, q.pendingGroup = cut, job.GroupKey
+		q.mu.Unlock()
+
+		q.events.publish(Event{Type: EventJobPrinted, Time: q.now(), Job: job})
+		// The key is saved before the job is removed, so a job printed
+		// again after a crash is still recognized
+		if ks, ok := q.store.(KeyStore); ok && key.Key != "" {
+			if err := ks.SaveKey(key); err != nil {
+				return fmt.Errorf("idempotency key of job %s not persisted: %w", job.ID, err)
+			}
+		}
+		if err := q.forget(job); err != nil {
+			return err
+		}
+	}
+}
+
+// duplicate returns the ID of the job queued or printed within the
+// idempotency window with the key, forgetting the keys printed before the
+// window. Must be called with the lock held.
+func (q *Queue) duplicate(key string) (string, bool) {
+	now := q.now()
+	ks, _ := q.store.(KeyStore)
+	for k, p := range q.printedKeys {
+		if now.Sub(p.Printed) >= q.keyWindow {
+			delete(q.printedKeys, k)
+			if ks != nil {
+				// A store failure only leaves the key on disk
+				ks.DeleteKey(k)
+			}
+		}
+	}
+	if key == "" {
+		return "", false
+	}
+	for _, job := range q.jobs {
+		if job.IdempotencyKey == key {
+			return job.ID, true
+		}
+	}
+	if p, ok := q.printedKeys[key]; ok {
+		return p.JobID, true
+	}
+	return "", false
+}
+
+// forget removes a job printed or expired from the store
+func (q *Queue) forget(job Job) error {
+	if q.store == nil {
+		return nil
+	}
+	if err := q.store.Delete(job.ID); err != nil {
+		return fmt.Errorf("job %s left in the store: %w", job.ID, err)
+	}
+	return nil
+}
+
+// Restore adds the jobs of the store to the queue, such as the jobs left by
+// a previous run, and returns their number. The idempotency keys of the
+// printed jobs are restored too when the store is a KeyStore. Call it before
+// enqueuing jobs.
+func (q *Queue) Restore() (int, error) {
+	if q.store == nil {
+		return 0, fmt.Errorf("the queue has no store")
+	}
+	jobs, err := q.store.Load()
+	if err != nil {
+		return 0, fmt.Errorf("failed to restore jobs: %w", err)
+	}
+	var keys []PrintedKey
+	if ks, ok := q.store.(KeyStore); ok {
+		if keys, err = ks.LoadKeys(); err != nil {
+			return 0, fmt.Errorf("failed to restore idempotency keys: %w", err)
+		}
+	}
+
+	q.mu.Lock()
+	for _, key := range keys {
+		if q.printedKeys == nil {
+			q.printedKeys = make(map[string]PrintedKey)
+		}
+		q.printedKeys[key.Key] = key
+	}
+	for _, job := range jobs {
+		// Keep the generated IDs unique
+		var n int
+		if _, err := fmt.Sscanf(job.ID, "job-%d", &n); err == nil && n > q.nextID {
+			q.nextID = n
+		}
+	}
+	q.jobs = append(q.jobs, jobs...)
+	q.mu.Unlock()
+
+	if len(jobs) > 0 {
+		select {
+		case q.wake <- struct{}{}:
+		default:
+		}
+	}
+	return len(jobs), nil
+}
+
+// flushCut performs the cut held back for a group whose next jobs never came
+func (q *Queue) flushCut() error {
+	if q.pendingCut == nil {
+		return nil
+	}
+	if _, err := escpos.WriteFull(q.printer, q.pendingCut); err != nil {
+		return fmt.Errorf("failed to cut: %w", err)
+	}
+	q.pendingCut = nil
+	return nil
+}
+
+// next expires the stale jobs and returns the job at the head of the queue,
+// with the group key of the job following it
+func (q *Queue) next() (Job, string, bool) {
+	q.mu.Lock()
+	now := q.now()
+	var expired []Job
+	kept := q.jobs[:0]
+	for _, job := range q.jobs {
+		if job.expired(now) {
+			expired = append(expired, job)
+		} else {
+			kept = append(kept, job)
+		}
+	}
+	q.jobs = kept
+	var head Job
+	var following string
+	ok := len(q.jobs) > 0
+	if ok {
+		head = q.jobs[0]
+	}
+	if len(q.jobs) > 1 {
+		following = q.jobs[1].GroupKey
+	}
+	q.mu.Unlock()
+
+	for _, job := range expired {
+		if q.onExpired != nil {
+			q.onExpired(job)
+		}
+		q.events.publish(Event{Type: EventJobExpired, Time: now, Job: job})
+		// The job is gone from the queue, a store failure only leaves it on disk
+		q.forget(job)
+	}
+	return head, following, ok
+}
+
+// trimCut removes the cut command ending data, returning the data and the cut.
+// The reverse feed following a cut with a profile cut offset stays with the cut.
+func trimCut(data []byte) ([]byte, []byte) {
+	n := len(data)
+	if n >= 7 && data[n-3] == esc && data[n-2] == 'K' {
+		if body, cut := trimCut(data[:n-3]); cut != nil {
+			return body, data[len(body):]
+		}
+	}
+	switch {
+	case n >= 4 && data[n-4] == gs && data[n-3] == 'V' && data[n-2] >= 65:
+		// GS V m n
+		return data[:n-4], data[n-4:]
+	case n >= 3 && data[n-3] == gs && data[n-2] == 'V' && (data[n-1]&^0x30) <= 1:
+		// GS V m
+		return data[:n-3], data[n-3:]
+	case n >= 2 && data[n-2] == esc && (data[n-1] == 'i' || data[n-1] == 'm'):
+		// ESC i, ESC m
+		return data[:n-2], data[n-2:]
+	}
+	return data, nil
+}
+
+// Run processes the queue until ctx is done, retrying every retry interval
+// while the printer fails, see WithBackoff. It returns the context error.
+func (q *Queue) Run(ctx context.Context) error {
+	for {
+		var wait <-chan time.Time
+		if err := q.Process(); err != nil {
+			q.failures++
+			wait = time.After(q.retryDelay())
+		} else {
+			q.failures = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-q.wake:
+		case <-wait:
+		}
+	}
+}
+
+// retryDelay returns the delay before the next attempt after the consecutive failures
+func (q *Queue) retryDelay() time.Duration {
+	d := q.retryInterval
+	for i := 1; i < q.failures && d < q.maxBackoff; i++ {
+		d *= 2
+	}
+	if q.maxBackoff > 0 {
+		d = min(d, max(q.maxBackoff, q.retryInterval))
+	}
+	return d
+}
+
+// Record builds the data of a job with the commands written by fn
+func Record(fn func(p *escpos.Escpos) error) ([]byte, error) {
+	buf := &recorder{}
+	p := escpos.New(buf)
+	if err := fn(p); err != nil {
+		return nil, err
+	}
+	if err := p.Print(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// recorder is a printer keeping the data written to it
+type recorder struct {
+	bytes.Buffer
+}
+
+func (r *recorder) Read(p []byte) (int, error) {
+	return 0, errors.New("cannot read the printer status while recording a job")
+}
+
+func (r *recorder) Close() error {
+	return nil
+}
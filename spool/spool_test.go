@@ -0,0 +1,280 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyPrinter is an emulator that can be taken offline
+type flakyPrinter struct {
+	*emulator.Emulator
+	mu      sync.Mutex
+	offline bool
+	writes  int // writes accepted before going offline, 0 for no limit
+}
+
+// failAfter takes the printer offline after n more writes
+func (f *flakyPrinter) failAfter(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.writes = n
+}
+
+func (f *flakyPrinter) setOffline(offline bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.offline = offline
+}
+
+func (f *flakyPrinter) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	if f.writes > 0 {
+		f.writes--
+		f.offline = f.writes == 0
+		f.mu.Unlock()
+		return f.Emulator.Write(p)
+	}
+	offline := f.offline
+	f.mu.Unlock()
+	if offline {
+		return 0, errors.New("printer offline")
+	}
+	return f.Emulator.Write(p)
+}
+
+// fakeClock is a manually advanced clock
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func receipt(t *testing.T, text string) []byte {
+	data, err := Record(func(p *escpos.Escpos) error {
+		_, err := p.Write(text + "\n")
+		return err
+	})
+	require.NoError(t, err)
+	return data
+}
+
+// TestQueueProcess tests printing jobs in submission order
+func TestQueueProcess(t *testing.T) {
+	printer := &flakyPrinter{Emulator: emulator.New()}
+	q := New(printer)
+
+	id, err := q.Enqueue(Job{Data: receipt(t, "first")})
+	assert.NoError(t, err)
+	assert.Equal(t, "job-1", id)
+	_, err = q.Enqueue(Job{ID: "order-42", Data: receipt(t, "second")})
+	assert.NoError(t, err)
+
+	_, err = q.Enqueue(Job{})
+	assert.Error(t, err)
+
+	require.NoError(t, q.Process())
+	assert.Equal(t, 0, q.Len())
+	assert.Equal(t, "first\nsecond\n", printer.Text())
+}
+
+// TestQueueRetry tests that jobs stay queued while the printer is offline
+func TestQueueRetry(t *testing.T) {
+	printer := &flakyPrinter{Emulator: emulator.New()}
+	printer.setOffline(true)
+	q := New(printer)
+
+	_, err := q.Enqueue(Job{Data: receipt(t, "first")})
+	require.NoError(t, err)
+
+	assert.Error(t, q.Process())
+	assert.Equal(t, 1, q.Len())
+
+	printer.setOffline(false)
+	assert.NoError(t, q.Process())
+	assert.Equal(t, 0, q.Len())
+	assert.Equal(t, "first\n", printer.Text())
+}
+
+// TestQueueTTL tests expiring the jobs that could not be printed in time
+func TestQueueTTL(t *testing.T) {
+	printer := &flakyPrinter{Emulator: emulator.New()}
+	printer.setOffline(true)
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	var expired []string
+	q := New(printer,
+		WithClock(clock.Now),
+		WithDefaultTTL(10*time.Minute),
+		WithExpiredHandler(func(j Job) { expired = append(expired, j.ID) }),
+	)
+
+	_, err := q.Enqueue(Job{ID: "stale", Data: receipt(t, "stale")})
+	require.NoError(t, err)
+	_, err = q.Enqueue(Job{ID: "long", Data: receipt(t, "long"), TTL: time.Hour})
+	require.NoError(t, err)
+	assert.Error(t, q.Process())
+
+	// The printer comes back after 15 minutes
+	clock.now = clock.now.Add(15 * time.Minute)
+	printer.setOffline(false)
+
+	assert.NoError(t, q.Process())
+	assert.Equal(t, []string{"stale"}, expired)
+	assert.Equal(t, "long\n", printer.Text())
+}
+
+// TestQueueRun tests that the worker retries until the printer is back
+func TestQueueRun(t *testing.T) {
+	printer := &flakyPrinter{Emulator: emulator.New()}
+	printer.setOffline(true)
+	q := New(printer, WithRetryInterval(10*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- q.Run(ctx) }()
+
+	_, err := q.Enqueue(Job{Data: receipt(t, "hello")})
+	require.NoError(t, err)
+
+	time.Sleep(30 * time.Millisecond)
+	printer.setOffline(false)
+
+	assert.Eventually(t, func() bool { return q.Len() == 0 }, time.Second, 5*time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Equal(t, "hello\n", printer.Text())
+}
+
+// TestQueueCutCoalescing tests printing the jobs of a group as a single ticket
+func TestQueueCutCoalescing(t *testing.T) {
+	ticket := func(text string) []byte {
+		data, err := Record(func(p *escpos.Escpos) error {
+			if _, err := p.Write(text + "\n"); err != nil {
+				return err
+			}
+			_, err := p.Cut()
+			return err
+		})
+		require.NoError(t, err)
+		return data
+	}
+
+	printer := &flakyPrinter{Emulator: emulator.New(emulator.WithColumns(4))}
+	q := New(printer, WithCutCoalescing())
+
+	for _, job := range []Job{
+		{Data: ticket("T1"), GroupKey: "table-1"},
+		{Data: ticket("A1"), GroupKey: "table-1"},
+		{Data: ticket("T2"), GroupKey: "table-2"},
+		{Data: ticket("T3")},
+	} {
+		_, err := q.Enqueue(job)
+		require.NoError(t, err)
+	}
+	require.NoError(t, q.Process())
+	assert.Equal(t, "T1\nA1\n----\nT2\n----\nT3\n----\n", printer.Text())
+}
+
+// TestQueueCutCoalescingExpired tests that the held back cut is performed when the rest of the group expires
+func TestQueueCutCoalescingExpired(t *testing.T) {
+	printer := &flakyPrinter{Emulator: emulator.New(emulator.WithColumns(4))}
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	q := New(printer, WithCutCoalescing(), WithClock(clock.Now))
+
+	data, err := Record(func(p *escpos.Escpos) error {
+		p.Write("T1\n")
+		_, err := p.Cut()
+		return err
+	})
+	require.NoError(t, err)
+
+	_, err = q.Enqueue(Job{Data: data, GroupKey: "g"})
+	require.NoError(t, err)
+	_, err = q.Enqueue(Job{Data: data, GroupKey: "g", TTL: time.Minute})
+	require.NoError(t, err)
+
+	// The printer goes offline after the first job
+	printer.failAfter(1)
+	assert.Error(t, q.Process())
+	assert.Equal(t, "T1\n", printer.Text())
+
+	// The second job expires before the printer is back
+	clock.now = clock.now.Add(2 * time.Minute)
+	printer.setOffline(false)
+	require.NoError(t, q.Process())
+	assert.Equal(t, "T1\n----\n", printer.Text())
+}
+
+// TestTrimCut tests removing the cut ending a job
+func TestTrimCut(t *testing.T) {
+	for _, tc := range []struct {
+		data, body, cut []byte
+	}{
+		{[]byte{'a', gs, 'V', 'A', 0}, []byte{'a'}, []byte{gs, 'V', 'A', 0}},
+		{[]byte{'a', gs, 'V', 1}, []byte{'a'}, []byte{gs, 'V', 1}},
+		{[]byte{'a', esc, 'i'}, []byte{'a'}, []byte{esc, 'i'}},
+		{[]byte{'a', gs, 'V', 'B', 24, esc, 'K', 24}, []byte{'a'}, []byte{gs, 'V', 'B', 24, esc, 'K', 24}},
+		{[]byte{'a', 'b', 'c', 'd', esc, 'K', 24}, []byte{'a', 'b', 'c', 'd', esc, 'K', 24}, nil},
+	} {
+		body, cut := trimCut(tc.data)
+		assert.Equal(t, tc.body, body)
+		assert.Equal(t, tc.cut, cut)
+	}
+}
+
+// TestQueueBackoff tests doubling the retry delay after each consecutive failure
+func TestQueueBackoff(t *testing.T) {
+	q := New(nil, WithRetryInterval(time.Second), WithBackoff(5*time.Second))
+
+	var delays []time.Duration
+	for q.failures = 1; q.failures <= 5; q.failures++ {
+		delays = append(delays, q.retryDelay())
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays)
+
+	q = New(nil, WithRetryInterval(time.Second))
+	q.failures = 4
+	assert.Equal(t, time.Second, q.retryDelay())
+}
+
+// TestQueueIdempotencyKey tests ignoring the jobs of a request already queued or printed
+func TestQueueIdempotencyKey(t *testing.T) {
+	printer := &flakyPrinter{Emulator: emulator.New()}
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	q := New(printer, WithClock(clock.Now), WithIdempotencyWindow(time.Hour))
+
+	id, err := q.Enqueue(Job{Data: receipt(t, "order 42"), IdempotencyKey: "order-42"})
+	require.NoError(t, err)
+
+	// The POS retries after a timeout, while the job is queued
+	retried, err := q.Enqueue(Job{Data: receipt(t, "order 42"), IdempotencyKey: "order-42"})
+	require.NoError(t, err)
+	assert.Equal(t, id, retried)
+	assert.Equal(t, 1, q.Len())
+
+	require.NoError(t, q.Process())
+
+	// and after it was printed
+	retried, err = q.Enqueue(Job{Data: receipt(t, "order 42"), IdempotencyKey: "order-42"})
+	require.NoError(t, err)
+	assert.Equal(t, id, retried)
+	assert.Equal(t, 0, q.Len())
+
+	// The key is forgotten after the window
+	clock.now = clock.now.Add(2 * time.Hour)
+	retried, err = q.Enqueue(Job{Data: receipt(t, "order 42"), IdempotencyKey: "order-42"})
+	require.NoError(t, err)
+	assert.NotEqual(t, id, retried)
+	require.NoError(t, q.Process())
+	assert.Equal(t, "order 42\norder 42\n", printer.Text())
+}
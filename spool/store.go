@@ -0,0 +1,267 @@
+package spool
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Store persists the jobs of a queue so they survive a restart of the process
+type Store interface {
+	// Save stores a job, replacing the job with the same ID
+	Save(job Job) error
+	// Delete removes a job
+	Delete(id string) error
+	// Load returns the stored jobs
+	Load() ([]Job, error)
+}
+
+// KeyStore is a Store also persisting the idempotency keys of the printed
+// jobs, so the requests retried after a restart are still deduplicated
+type KeyStore interface {
+	Store
+	// SaveKey stores a key, replacing the key with the same value
+	SaveKey(key PrintedKey) error
+	// DeleteKey removes a key
+	DeleteKey(key string) error
+	// LoadKeys returns the stored keys
+	LoadKeys() ([]PrintedKey, error)
+}
+
+var _ KeyStore = (*DirStore)(nil)
+
+// Extensions of the job and idempotency key files of a DirStore
+const (
+	jobFileExt = ".job"
+	keyFileExt = ".key"
+)
+
+// DirStore stores each job, and each idempotency key, in a file of a
+// directory, optionally encrypted
+type DirStore struct {
+	dir     string
+	aead    cipher.AEAD
+	nameKey []byte // HMAC key of the file names of an encrypted store
+}
+
+// DirStoreOption configures a DirStore
+type DirStoreOption func(*DirStore) error
+
+// WithEncryptionKey encrypts the job files with AES-GCM, receipts holding
+// personal data. The key must be 16, 24 or 32 bytes long, selecting AES-128,
+// AES-192 or AES-256. The files are named by an HMAC-SHA256 of the job IDs
+// and idempotency keys, which the directory listing does not reveal. Files
+// written with another key cannot be loaded.
+func WithEncryptionKey(key []byte) DirStoreOption {
+	return func(s *DirStore) error {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return fmt.Errorf("invalid encryption key: %w", err)
+		}
+		if s.aead, err = cipher.NewGCM(block); err != nil {
+			return err
+		}
+		// A key of its own for the names, derived from the encryption key
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte("spool file names"))
+		s.nameKey = mac.Sum(nil)
+		return nil
+	}
+}
+
+// NewDirStore creates a store keeping the jobs in dir, created if needed
+func NewDirStore(dir string, opts ...DirStoreOption) (*DirStore, error) {
+	s := &DirStore{dir: dir}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+	return s, nil
+}
+
+// Save writes the job to its file, atomically replacing the previous version
+func (s *DirStore) Save(job Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job %s: %w", job.ID, err)
+	}
+	if err := s.writeFile(s.fileName(job.ID, jobFileExt), data); err != nil {
+		return fmt.Errorf("failed to save job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+// SaveKey writes the idempotency key to its file
+func (s *DirStore) SaveKey(key PrintedKey) error {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode key %s: %w", key.Key, err)
+	}
+	if err := s.writeFile(s.fileName(key.Key, keyFileExt), data); err != nil {
+		return fmt.Errorf("failed to save key %s: %w", key.Key, err)
+	}
+	return nil
+}
+
+// DeleteKey removes the file of an idempotency key
+func (s *DirStore) DeleteKey(key string) error {
+	if err := os.Remove(filepath.Join(s.dir, s.fileName(key, keyFileExt))); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete key %s: %w", key, err)
+	}
+	return nil
+}
+
+// LoadKeys reads the idempotency keys of the directory
+func (s *DirStore) LoadKeys() ([]PrintedKey, error) {
+	names, err := s.files(keyFileExt)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []PrintedKey
+	for _, name := range names {
+		data, err := s.readFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file %s: %w", name, err)
+		}
+		var key PrintedKey
+		if err := json.Unmarshal(data, &key); err != nil {
+			return nil, fmt.Errorf("failed to decode key file %s: %w", name, err)
+		}
+		if s.fileName(key.Key, keyFileExt) != name {
+			return nil, fmt.Errorf("key file %s does not hold its key", name)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// writeFile atomically replaces the file name with data, encrypted when
+// the store is
+func (s *DirStore) writeFile(name string, data []byte) error {
+	if s.aead != nil {
+		data = s.seal(name, data)
+	}
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+}
+
+// readFile reads the file name, decrypted when the store is encrypted
+func (s *DirStore) readFile(name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil || s.aead == nil {
+		return data, err
+	}
+	return s.open(name, data)
+}
+
+// files returns the names of the files of the directory with the extension
+func (s *DirStore) files(ext string) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool directory: %w", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ext) {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Delete removes the file of a job
+func (s *DirStore) Delete(id string) error {
+	if err := os.Remove(filepath.Join(s.dir, s.fileName(id, jobFileExt))); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete job %s: %w", id, err)
+	}
+	return nil
+}
+
+// Load reads the jobs of the directory, in submission order
+func (s *DirStore) Load() ([]Job, error) {
+	names, err := s.files(jobFileExt)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []Job
+	for _, name := range names {
+		data, err := s.readFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job file %s: %w", name, err)
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			return nil, fmt.Errorf("failed to decode job file %s: %w", name, err)
+		}
+		if s.fileName(job.ID, jobFileExt) != name {
+			return nil, fmt.Errorf("job file %s does not hold its job", name)
+		}
+		jobs = append(jobs, job)
+	}
+
+	slices.SortStableFunc(jobs, func(a, b Job) int {
+		return a.Submitted.Compare(b.Submitted)
+	})
+	return jobs, nil
+}
+
+// fileName returns the name of the file of a job ID or an idempotency key:
+// its base64 encoding, or its HMAC-SHA256 in an encrypted store
+func (s *DirStore) fileName(value, ext string) string {
+	if s.nameKey == nil {
+		return base64.RawURLEncoding.EncodeToString([]byte(value)) + ext
+	}
+	mac := hmac.New(sha256.New, s.nameKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil)) + ext
+}
+
+// seal encrypts data, bound to the name of its file so files cannot be
+// swapped
+func (s *DirStore) seal(name string, data []byte) []byte {
+	nonce := make([]byte, s.aead.NonceSize())
+	// crypto/rand.Read never fails
+	rand.Read(nonce)
+	return s.aead.Seal(nonce, nonce, data, []byte(name))
+}
+
+// open decrypts data sealed by seal
+func (s *DirStore) open(name string, data []byte) ([]byte, error) {
+	n := s.aead.NonceSize()
+	if len(data) < n {
+		return nil, errors.New("file too short")
+	}
+	return s.aead.Open(nil, data[:n], data[n:], []byte(name))
+}
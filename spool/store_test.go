@@ -0,0 +1,189 @@
+package spool
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/schawnndev/escpos/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDirStore tests saving, loading and deleting jobs
+func TestDirStore(t *testing.T) {
+	store, err := NewDirStore(t.TempDir())
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	second := Job{ID: "b/2", Data: []byte("second"), Submitted: now.Add(time.Second)}
+	first := Job{ID: "a", Data: []byte("first"), TTL: time.Minute, GroupKey: "g", Submitted: now,
+		Fields: []Field{{Offset: 1, Name: FieldSequence}}}
+	require.NoError(t, store.Save(second))
+	require.NoError(t, store.Save(first))
+
+	jobs, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, first.ID, jobs[0].ID)
+	assert.Equal(t, first.Data, jobs[0].Data)
+	assert.Equal(t, first.Fields, jobs[0].Fields)
+	assert.Equal(t, time.Minute, jobs[0].TTL)
+	assert.True(t, now.Equal(jobs[0].Submitted))
+	assert.Equal(t, second.ID, jobs[1].ID)
+
+	require.NoError(t, store.Delete("a"))
+	require.NoError(t, store.Delete("a"))
+	jobs, err = store.Load()
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
+
+// TestDirStoreEncryption tests that encrypted job files do not reveal their content
+func TestDirStoreEncryption(t *testing.T) {
+	dir := t.TempDir()
+	key := bytes.Repeat([]byte{0x42}, 32)
+	store, err := NewDirStore(dir, WithEncryptionKey(key))
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save(Job{ID: "job-1", Data: []byte("Jane Doe, 12 Main Street")}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.job"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	raw, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "Jane Doe")
+
+	jobs, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, []byte("Jane Doe, 12 Main Street"), jobs[0].Data)
+
+	// Another key cannot read the jobs
+	other, err := NewDirStore(dir, WithEncryptionKey(bytes.Repeat([]byte{0x43}, 32)))
+	require.NoError(t, err)
+	_, err = other.Load()
+	assert.Error(t, err)
+
+	// Nor can the store without a key
+	plain, err := NewDirStore(dir)
+	require.NoError(t, err)
+	_, err = plain.Load()
+	assert.Error(t, err)
+
+	_, err = NewDirStore(dir, WithEncryptionKey([]byte("short")))
+	assert.Error(t, err)
+}
+
+// TestDirStoreEncryptedNames tests that the file names of an encrypted
+// store do not reveal the job IDs and idempotency keys
+func TestDirStoreEncryptedNames(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDirStore(dir, WithEncryptionKey(bytes.Repeat([]byte{0x42}, 32)))
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save(Job{ID: "order-1042", Data: []byte("receipt")}))
+	require.NoError(t, store.SaveKey(PrintedKey{Key: "customer-jane", JobID: "order-1042"}))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		for _, secret := range []string{"order-1042", "customer-jane"} {
+			assert.NotContains(t, entry.Name(), secret)
+			assert.NotContains(t, entry.Name(), base64.RawURLEncoding.EncodeToString([]byte(secret)))
+		}
+	}
+
+	jobs, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "order-1042", jobs[0].ID)
+	keys, err := store.LoadKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "customer-jane", keys[0].Key)
+
+	require.NoError(t, store.Delete("order-1042"))
+	require.NoError(t, store.DeleteKey("customer-jane"))
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// TestQueueStore tests that the jobs not printed survive a restart
+func TestQueueStore(t *testing.T) {
+	store, err := NewDirStore(t.TempDir(), WithEncryptionKey(bytes.Repeat([]byte{1}, 16)))
+	require.NoError(t, err)
+
+	printer := &flakyPrinter{Emulator: emulator.New()}
+	q := New(printer, WithStore(store))
+	_, err = q.Enqueue(Job{Data: receipt(t, "one")})
+	require.NoError(t, err)
+	require.NoError(t, q.Process())
+
+	printer.setOffline(true)
+	_, err = q.Enqueue(Job{Data: receipt(t, "two")})
+	require.NoError(t, err)
+	assert.Error(t, q.Process())
+
+	// The process restarts
+	printer = &flakyPrinter{Emulator: emulator.New()}
+	q = New(printer, WithStore(store))
+	n, err := q.Restore()
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	id, err := q.Enqueue(Job{Data: receipt(t, "three")})
+	require.NoError(t, err)
+	assert.Equal(t, "job-3", id)
+
+	require.NoError(t, q.Process())
+	assert.Equal(t, "two\nthree\n", printer.Text())
+
+	jobs, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	_, err = New(printer).Restore()
+	assert.Error(t, err)
+}
+
+// TestQueueStoreIdempotencyKeys tests that the keys of the printed jobs survive a restart
+func TestQueueStoreIdempotencyKeys(t *testing.T) {
+	store, err := NewDirStore(t.TempDir(), WithEncryptionKey(bytes.Repeat([]byte{1}, 16)))
+	require.NoError(t, err)
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	printer := &flakyPrinter{Emulator: emulator.New()}
+	q := New(printer, WithStore(store), WithClock(clock.Now), WithIdempotencyWindow(time.Hour))
+	id, err := q.Enqueue(Job{Data: receipt(t, "order 42"), IdempotencyKey: "order-42"})
+	require.NoError(t, err)
+	require.NoError(t, q.Process())
+
+	keys, err := store.LoadKeys()
+	require.NoError(t, err)
+	assert.Equal(t, []PrintedKey{{Key: "order-42", JobID: id, Printed: clock.now}}, keys)
+
+	// The process restarts, then the POS retries
+	printer = &flakyPrinter{Emulator: emulator.New()}
+	q = New(printer, WithStore(store), WithClock(clock.Now), WithIdempotencyWindow(time.Hour))
+	_, err = q.Restore()
+	require.NoError(t, err)
+	retried, err := q.Enqueue(Job{Data: receipt(t, "order 42"), IdempotencyKey: "order-42"})
+	require.NoError(t, err)
+	assert.Equal(t, id, retried)
+	assert.Equal(t, 0, q.Len())
+
+	// The key is removed from the store after the window
+	clock.now = clock.now.Add(2 * time.Hour)
+	_, err = q.Enqueue(Job{Data: receipt(t, "order 43")})
+	require.NoError(t, err)
+	keys, err = store.LoadKeys()
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
@@ -0,0 +1,39 @@
+package spool
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/schawnndev/escpos"
+)
+
+// ErrNotConfirmed is returned by Process when the printer reports a problem
+// after a job, which stays in the queue to be printed again
+var ErrNotConfirmed = errors.New("printer did not confirm the job")
+
+// WithStatusVerification makes the queue query the printer status after each
+// job and keep the job at the head of the queue when the printer is offline
+// or reports an error, such as a cover opened or a cutter jammed during the
+// job. The job is then printed again once the printer recovers: a receipt
+// may come out twice, but is never lost.
+func WithStatusVerification() Option {
+	return func(q *Queue) {
+		q.verifyStatus = true
+	}
+}
+
+// verify checks the printer status after a job was sent
+func (q *Queue) verify(job Job) error {
+	if q.status == nil {
+		q.status = escpos.New(q.printer)
+	}
+
+	status, err := q.status.FullStatus()
+	if err != nil {
+		return fmt.Errorf("failed to verify job %s: %w", job.ID, err)
+	}
+	if !status.Online || status.Error {
+		return fmt.Errorf("job %s: %w (%s)", job.ID, ErrNotConfirmed, status)
+	}
+	return nil
+}
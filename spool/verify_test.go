@@ -0,0 +1,31 @@
+package spool
+
+import (
+	"testing"
+
+	"github.com/schawnndev/escpos"
+	"github.com/schawnndev/escpos/emulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQueueStatusVerification tests printing a job again when the printer reports an error after it
+func TestQueueStatusVerification(t *testing.T) {
+	printer := &flakyPrinter{Emulator: emulator.New()}
+	q := New(printer, WithStatusVerification())
+
+	_, err := q.Enqueue(Job{Data: receipt(t, "first")})
+	require.NoError(t, err)
+
+	// The cover is opened during the job
+	printer.SetStatus(escpos.RT_STATUS_ONLINE, 0x12|escpos.RT_MASK_OFFLINE)
+	printer.SetStatus(escpos.RT_STATUS_OFFLINE, 0x12|0x04)
+	assert.ErrorIs(t, q.Process(), ErrNotConfirmed)
+	assert.Equal(t, 1, q.Len())
+
+	printer.SetStatus(escpos.RT_STATUS_ONLINE, 0x16)
+	printer.SetStatus(escpos.RT_STATUS_OFFLINE, 0x12)
+	require.NoError(t, q.Process())
+	assert.Equal(t, 0, q.Len())
+	assert.Equal(t, "first\nfirst\n", printer.Text())
+}
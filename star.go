@@ -0,0 +1,100 @@
+package escpos
+
+// Dialect is the command set understood by a printer
+type Dialect uint8
+
+const (
+	// DialectESCPOS is the Epson ESC/POS command set (default)
+	DialectESCPOS Dialect = iota
+	// DialectStarLine is the Star Line Mode of the Star printers, such as
+	// the TSP100 and TSP650, which ignore several ESC/POS commands. The
+	// justification, bold, size, cut, drawer, barcode and raster image
+	// commands are sent in their Star form; the others are sent unchanged.
+	DialectStarLine
+)
+
+// Star Line Mode barcode types, by GS k barcode type (function A for the
+// symbologies having one)
+var starBarcodeTypes = map[uint8]byte{
+	BarcodeUPCE:         0,
+	BarcodeUPCA:         1,
+	BarcodeEAN8:         2,
+	BarcodeEAN13:        3,
+	BarcodeCode39:       4,
+	BarcodeITF:          5,
+	BarcodeBCode128:     6,
+	BarcodeBCode128Auto: 6,
+	BarcodeBCode93:      7,
+	BarcodeCodabar:      8,
+}
+
+// star reports whether the printer uses the Star Line Mode
+func (e *Escpos) star() bool {
+	return e.profile.Dialect == DialectStarLine
+}
+
+// starJustify returns the ESC GS a command setting the justification
+func starJustify(j Justify) []byte {
+	return []byte{esc, gs, 'a', byte(j)}
+}
+
+// starBold returns the ESC E (on) or ESC F (off) command of the emphasized mode
+func starBold(b bool) []byte {
+	if b {
+		return []byte{esc, 'E'}
+	}
+	return []byte{esc, 'F'}
+}
+
+// starSize returns the ESC i command setting the character expansion, up to 6 times
+func starSize(height, width uint8) []byte {
+	return []byte{esc, 'i', min(height, 6) - 1, min(width, 6) - 1}
+}
+
+// starCut returns the ESC d command feeding the paper to the cutter and
+// cutting it, partially for the ESC/POS partial cut modes
+func starCut(m byte) []byte {
+	if m == 'B' || m == 1 || m == 49 {
+		return []byte{esc, 'd', 3}
+	}
+	return []byte{esc, 'd', 2}
+}
+
+// starDrawer returns the BEL (drawer 1) or SUB (drawer 2) command opening a drawer
+func starDrawer(pin uint8) []byte {
+	if pin == 1 {
+		return []byte{0x1A}
+	}
+	return []byte{0x07}
+}
+
+// starBarcode returns the ESC b command printing a barcode, with the
+// current HRI position, module width and height
+func (e *Escpos) starBarcode(barcodeType uint8, code string) []byte {
+	hri := byte(1) // no human readable text, line feed after the barcode
+	if e.hriPosition != 0 {
+		hri = 2
+	}
+	if barcodeType == BarcodeBCode128 || barcodeType == BarcodeBCode128Auto {
+		code = code128Data(code)
+	}
+	mode := min(max(e.barcodeWidth, 2), 4) - 1
+	cmd := []byte{esc, 'b', starBarcodeTypes[functionA(barcodeType)], hri, mode, e.barcodeHeight}
+	return append(append(cmd, code...), 0x1E)
+}
+
+// starCommand returns the ESC GS S command printing the image with the Star
+// graphics of the Line Mode. The low density modes of GS v 0 are not
+// available, the image is printed at full density.
+func (r rasterImage) starCommand() ([]byte, error) {
+	width, err := LowHigh16(r.widthBytes)
+	if err != nil {
+		return nil, err
+	}
+	height, err := LowHigh16(r.height)
+	if err != nil {
+		return nil, err
+	}
+	cmd := []byte{esc, gs, 'S', 1, width[0], width[1], height[0], height[1], 0}
+	return append(cmd, r.data...), nil
+}
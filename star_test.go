@@ -0,0 +1,85 @@
+package escpos
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStarLineMode tests the commands sent in the Star dialect
+func TestStarLineMode(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(ProfileStarTSP650II)
+
+	step := func(_ int, err error) {
+		require.NoError(t, err)
+	}
+	step(p.SetJustify(JustifyCenter))
+	step(p.SetBold(true))
+	step(p.SetSize(2, 3))
+	step(p.SetBold(false))
+	step(p.EAN13("4006381333931"))
+	step(p.OpenDrawer(1, 2))
+	step(p.PartialCut())
+	step(p.Cut())
+	require.NoError(t, p.Print())
+
+	expected := "\x1b\x1da\x01" +
+		"\x1bE" +
+		"\x1bi\x01\x02" +
+		"\x1bF" +
+		"\x1bb\x03\x01\x02\xa24006381333931\x1e" +
+		"\x1a" +
+		"\x1bd\x03" +
+		"\x1bd\x02"
+	assert.Equal(t, expected, mock.String())
+}
+
+// TestStarLineModeImage tests the raster images and the QR codes printed as images
+func TestStarLineModeImage(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(ProfileStarTSP650II)
+
+	_, err := p.PrintImageWithProcessing(image.NewGray(image.Rect(0, 0, 16, 2)), ImageProcessDither, true, true)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, "\x1b\x1dS\x01\x02\x00\x02\x00\x00\xff\xff\xff\xff", mock.String())
+
+	mock.Reset()
+	_, err = p.QRCode("hello", QRCodeModel2, 3, QRCodeErrorCorrectionLevelL)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Contains(t, mock.String(), "\x1b\x1dS\x01")
+	assert.NotContains(t, mock.String(), "\x1d(k")
+}
+
+// TestStarLineModeBarcodeB tests the function B barcodes in the Star dialect
+func TestStarLineModeBarcodeB(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(ProfileStarTSP650II)
+
+	step := func(_ int, err error) {
+		require.NoError(t, err)
+	}
+	step(p.BarcodeB(BarcodeBCode128, []byte("{BABC-123")))
+	step(p.BarcodeB(BarcodeBCode93, []byte("ABC")))
+	step(p.BarcodeB(BarcodeBEAN8, []byte("9638507")))
+	require.NoError(t, p.Print())
+
+	expected := "\x1bb\x06\x01\x02\xa2ABC-123\x1e" +
+		"\x1bb\x07\x01\x02\xa2ABC\x1e" +
+		"\x1bb\x02\x01\x02\xa29638507\x1e"
+	assert.Equal(t, expected, mock.String())
+
+	// No Star Line Mode form, nor image rendering
+	mock.Reset()
+	_, err := p.BarcodeB(BarcodeBGS1DataBarOmni, []byte("0123456789012"))
+	assert.Error(t, err)
+	require.NoError(t, p.Print())
+	assert.Empty(t, mock.Bytes())
+}
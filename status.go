@@ -0,0 +1,166 @@
+package escpos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DLE EOT status types queried by FullStatus, completing RT_STATUS_ONLINE and RT_STATUS_PAPER
+const (
+	RT_STATUS_OFFLINE byte = 2
+	RT_STATUS_ERROR   byte = 3
+)
+
+// Default time QueryStatus waits for an answer, and interval between two reads
+const (
+	defaultStatusTimeout      = 100 * time.Millisecond
+	defaultStatusPollInterval = 10 * time.Millisecond
+)
+
+// WithStatusTimeout sets how long QueryStatus waits for the answer of the
+// printer (default: 100ms). Slow links, such as Bluetooth, need more.
+func WithStatusTimeout(d time.Duration) Option {
+	return func(e *Escpos) {
+		if d > 0 {
+			e.statusTimeout = d
+		}
+	}
+}
+
+// WithStatusPollInterval sets the interval between two reads while
+// QueryStatus waits for the answer of the printer (default: 10ms)
+func WithStatusPollInterval(d time.Duration) Option {
+	return func(e *Escpos) {
+		if d > 0 {
+			e.statusPollInterval = d
+		}
+	}
+}
+
+// readStatus reads the answer of a status request into buf, polling the
+// printer until it answers or the status timeout expires
+func (e *Escpos) readStatus(buf []byte) (int, error) {
+	deadline := time.Now().Add(e.statusTimeout)
+	for {
+		n, err := e.reader.Read(buf)
+		if n > 0 || err != nil || !time.Now().Before(deadline) {
+			return n, err
+		}
+		time.Sleep(min(e.statusPollInterval, time.Until(deadline)))
+	}
+}
+
+// sendRequest sends the buffered data and a command answered by the printer,
+// the answer being read with readStatus
+func (e *Escpos) sendRequest(cmd []byte) error {
+	if e.reader == nil {
+		return fmt.Errorf("reader not available")
+	}
+	if _, err := e.WriteRaw(cmd); err != nil {
+		return err
+	}
+	return e.flush()
+}
+
+// Bits of the DLE EOT status bytes
+const (
+	rtDrawerPin       byte = 0x04 // printer status: drawer kick-out connector pin 3 high
+	rtFeedButton      byte = 0x40 // printer status: paper feed button pressed
+	rtCoverOpen       byte = 0x04 // offline cause: cover open
+	rtFeeding         byte = 0x08 // offline cause: paper fed with the feed button
+	rtPaperEndStop    byte = 0x20 // offline cause: printing stopped by the paper end
+	rtErrorOccurred   byte = 0x40 // offline cause: error
+	rtRecoverable     byte = 0x04 // error cause: recoverable error
+	rtCutterError     byte = 0x08 // error cause: autocutter error
+	rtUnrecoverable   byte = 0x20 // error cause: unrecoverable error
+	rtAutoRecoverable byte = 0x40 // error cause: automatically recoverable error, such as head overheating
+)
+
+// PrinterStatus is the decoded state of the printer, see FullStatus
+type PrinterStatus struct {
+	Online        bool
+	DrawerPinHigh bool // drawer kick-out connector pin 3 high, usually a closed drawer
+	FeedButton    bool // paper feed button pressed
+
+	CoverOpen    bool
+	Feeding      bool // paper being fed with the feed button
+	PaperEndStop bool // printing stopped by the paper end sensor
+	Error        bool
+
+	RecoverableError     bool // e.g. a cutter jam, see RecoverAndRestart
+	CutterError          bool
+	UnrecoverableError   bool
+	AutoRecoverableError bool // e.g. an overheated head, cleared by the printer itself
+
+	PaperNearEnd bool
+	PaperOut     bool
+}
+
+// String lists the state of the printer, such as "offline, cover open, paper out"
+func (s PrinterStatus) String() string {
+	conditions := []string{"online"}
+	if !s.Online {
+		conditions[0] = "offline"
+	}
+	for _, c := range []struct {
+		set  bool
+		name string
+	}{
+		{s.CoverOpen, "cover open"},
+		{s.Feeding, "feeding"},
+		{s.FeedButton, "feed button pressed"},
+		{s.PaperEndStop, "stopped by paper end"},
+		{s.Error, "error"},
+		{s.RecoverableError, "recoverable error"},
+		{s.CutterError, "cutter error"},
+		{s.UnrecoverableError, "unrecoverable error"},
+		{s.AutoRecoverableError, "auto-recoverable error"},
+		{s.PaperOut, "paper out"},
+		{s.PaperNearEnd && !s.PaperOut, "paper near end"},
+		{s.DrawerPinHigh, "drawer pin high"},
+	} {
+		if c.set {
+			conditions = append(conditions, c.name)
+		}
+	}
+	return strings.Join(conditions, ", ")
+}
+
+// FullStatus queries the four DLE EOT status types (printer, offline cause,
+// error cause and paper sensors) and decodes them
+func (e *Escpos) FullStatus() (PrinterStatus, error) {
+	var statuses [4]byte
+	for i, statusType := range []byte{RT_STATUS_ONLINE, RT_STATUS_OFFLINE, RT_STATUS_ERROR, RT_STATUS_PAPER} {
+		status, err := e.QueryStatus(statusType)
+		if err != nil {
+			return PrinterStatus{}, err
+		}
+		if len(status) == 0 {
+			return PrinterStatus{}, fmt.Errorf("%w: no response to status type %d", ErrTimeout, statusType)
+		}
+		statuses[i] = status[0]
+	}
+	printer, offline, errs, paper := statuses[0], statuses[1], statuses[2], statuses[3]
+
+	status := PrinterStatus{
+		Online:        printer&RT_MASK_OFFLINE == 0,
+		DrawerPinHigh: printer&rtDrawerPin != 0,
+		FeedButton:    printer&rtFeedButton != 0,
+
+		CoverOpen:    offline&rtCoverOpen != 0,
+		Feeding:      offline&rtFeeding != 0,
+		PaperEndStop: offline&rtPaperEndStop != 0,
+		Error:        offline&rtErrorOccurred != 0,
+
+		RecoverableError:     errs&rtRecoverable != 0,
+		CutterError:          errs&rtCutterError != 0,
+		UnrecoverableError:   errs&rtUnrecoverable != 0,
+		AutoRecoverableError: errs&rtAutoRecoverable != 0,
+
+		PaperNearEnd: paper&RT_MASK_NEAREND != 0,
+		PaperOut:     paper&RT_MASK_NOPAPER != 0,
+	}
+	e.reportStatus(status)
+	return status, nil
+}
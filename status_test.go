@@ -0,0 +1,111 @@
+package escpos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusPrinter answers the DLE EOT queries with the status byte of their type
+type statusPrinter struct {
+	MockPrinter
+	status  map[byte]byte
+	pending []byte
+}
+
+func (p *statusPrinter) Write(b []byte) (int, error) {
+	if len(b) == 3 && b[0] == dle && b[1] == 0x04 {
+		if s, ok := p.status[b[2]]; ok {
+			p.pending = []byte{s}
+		}
+	}
+	return p.MockPrinter.Write(b)
+}
+
+func (p *statusPrinter) Read(b []byte) (int, error) {
+	n := copy(b, p.pending)
+	p.pending = p.pending[n:]
+	return n, nil
+}
+
+// TestFullStatus tests decoding the four status types
+func TestFullStatus(t *testing.T) {
+	printer := &statusPrinter{status: map[byte]byte{
+		RT_STATUS_ONLINE:  0x12 | RT_MASK_OFFLINE | rtDrawerPin,
+		RT_STATUS_OFFLINE: 0x12 | rtCoverOpen | rtErrorOccurred,
+		RT_STATUS_ERROR:   0x12 | rtCutterError,
+		RT_STATUS_PAPER:   0x12 | RT_MASK_NEAREND | RT_MASK_NOPAPER,
+	}}
+	p := New(printer)
+
+	status, err := p.FullStatus()
+	require.NoError(t, err)
+	assert.Equal(t, PrinterStatus{
+		DrawerPinHigh: true,
+		CoverOpen:     true,
+		Error:         true,
+		CutterError:   true,
+		PaperNearEnd:  true,
+		PaperOut:      true,
+	}, status)
+	assert.Equal(t, "offline, cover open, error, cutter error, paper out, drawer pin high", status.String())
+
+	expected := []byte{dle, 0x04, 1, dle, 0x04, 2, dle, 0x04, 3, dle, 0x04, 4}
+	assert.Equal(t, expected, printer.Bytes())
+}
+
+// TestFullStatusReady tests the status of a ready printer
+func TestFullStatusReady(t *testing.T) {
+	printer := &statusPrinter{status: map[byte]byte{1: 0x12, 2: 0x12, 3: 0x12, 4: 0x12}}
+	status, err := New(printer).FullStatus()
+	require.NoError(t, err)
+	assert.Equal(t, PrinterStatus{Online: true}, status)
+	assert.Equal(t, "online", status.String())
+
+	// A status type left unanswered
+	delete(printer.status, RT_STATUS_ERROR)
+	_, err = New(printer).FullStatus()
+	assert.ErrorContains(t, err, "status type 3")
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+// slowPrinter answers the status requests after a few empty reads
+type slowPrinter struct {
+	MockPrinter
+	reads int
+}
+
+func (p *slowPrinter) Read(b []byte) (int, error) {
+	p.reads++
+	if p.reads < 3 {
+		return 0, nil
+	}
+	b[0] = 0x16
+	return 1, nil
+}
+
+// TestQueryStatusPolling tests polling the printer until it answers
+func TestQueryStatusPolling(t *testing.T) {
+	printer := &slowPrinter{}
+	p := New(printer, WithStatusTimeout(time.Second), WithStatusPollInterval(time.Millisecond))
+
+	p.Write("pending")
+	status, err := p.QueryStatus(RT_STATUS_ONLINE)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x16}, status)
+	assert.Equal(t, 3, printer.reads)
+	assert.Equal(t, "pending\x10\x04\x01", printer.String())
+}
+
+// TestQueryStatusTimeout tests returning an empty response when the printer does not answer
+func TestQueryStatusTimeout(t *testing.T) {
+	p := New(NewMockPrinter(), WithStatusTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	status, err := p.QueryStatus(RT_STATUS_ONLINE)
+	require.NoError(t, err)
+	assert.Empty(t, status)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
@@ -0,0 +1,38 @@
+package escpos
+
+import "time"
+
+// SetStickyErrors turns the sticky error mode on or off. Once a write to the
+// printer fails in this mode, the following commands send nothing and return
+// the first error, so a job can be built without checking every call and
+// checked once with Print or Err. Turning the mode off clears the error.
+func (e *Escpos) SetStickyErrors(on bool) {
+	e.sticky = on
+	if !on {
+		e.err = nil
+	}
+}
+
+// Err returns the first error met in the sticky error mode, nil if none
+func (e *Escpos) Err() error {
+	return e.err
+}
+
+// fail records err as the first error in the sticky error mode and returns it
+func (e *Escpos) fail(err error) error {
+	if err != nil && e.sticky && e.err == nil {
+		e.err = err
+	}
+	return err
+}
+
+// flush sends the buffered data to the printer
+func (e *Escpos) flush() error {
+	if e.err != nil {
+		return e.err
+	}
+	start := time.Now()
+	err := e.dst.Flush()
+	e.logFlush(start, err)
+	return e.fail(err)
+}
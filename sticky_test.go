@@ -0,0 +1,74 @@
+package escpos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriter accepts limit bytes, then fails every write
+type failingWriter struct {
+	written []byte
+	limit   int
+	err     error
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(w.written)+len(p) > w.limit {
+		return 0, w.err
+	}
+	w.written = append(w.written, p...)
+	return len(p), nil
+}
+
+func (w *failingWriter) Read(p []byte) (int, error) {
+	return 0, errors.New("no data")
+}
+
+func (w *failingWriter) Close() error {
+	return nil
+}
+
+// TestStickyErrors tests that the first failed write stops the job
+func TestStickyErrors(t *testing.T) {
+	w := &failingWriter{limit: 3, err: errors.New("paper jam")}
+	p := New(w)
+	p.SetStickyErrors(true)
+
+	_, err := p.Write("ab")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.NoError(t, p.Err())
+
+	// The flush fails, later calls send nothing and report the first error
+	_, err = p.Write("cdef")
+	require.NoError(t, err)
+	err = p.Print()
+	assert.ErrorIs(t, err, w.err)
+	assert.Equal(t, w.err, p.Err())
+
+	w.limit = 100
+	n, err := p.Write("gh")
+	assert.Equal(t, 0, n)
+	assert.Equal(t, w.err, err)
+	assert.Equal(t, w.err, p.Print())
+	assert.Equal(t, w.err, p.PrintAndCut())
+	assert.Equal(t, []byte("ab"), w.written)
+
+	// Turning the mode off clears the error
+	p.SetStickyErrors(false)
+	assert.NoError(t, p.Err())
+}
+
+// TestStickyErrorsOff tests that no error is kept by default
+func TestStickyErrorsOff(t *testing.T) {
+	w := &failingWriter{limit: 0, err: errors.New("offline")}
+	p := New(w)
+
+	_, err := p.Write("ab")
+	require.NoError(t, err)
+	assert.ErrorIs(t, p.Print(), w.err)
+	assert.NoError(t, p.Err())
+}
@@ -0,0 +1,113 @@
+package escpos
+
+import "fmt"
+
+// normalized returns the style with the unset size multipliers set to 1
+func (s Style) normalized() Style {
+	if s.Width == 0 {
+		s.Width = 1
+	}
+	if s.Height == 0 {
+		s.Height = 1
+	}
+	return s
+}
+
+// ApplyStyle switches from the tracked current style to s, sending only the
+// commands of the attributes that differ (bold, size, underline, reverse,
+// upside down, rotation and justification)
+func (e *Escpos) ApplyStyle(s Style) (int, error) {
+	cur, s := e.Style.normalized(), s.normalized()
+
+	written := 0
+	step := func(n int, err error) error {
+		written += n
+		return err
+	}
+
+	if s.Bold != cur.Bold {
+		if err := step(e.SetBold(s.Bold)); err != nil {
+			return written, err
+		}
+	}
+	if s.Width != cur.Width || s.Height != cur.Height {
+		if err := step(e.SetSize(s.Height, s.Width)); err != nil {
+			return written, err
+		}
+	}
+	if s.Underline != cur.Underline {
+		if err := step(e.SetUnderline(s.Underline)); err != nil {
+			return written, err
+		}
+	}
+	if s.Reverse != cur.Reverse {
+		if err := step(e.SetReverse(s.Reverse)); err != nil {
+			return written, err
+		}
+	}
+	if s.UpsideDown != cur.UpsideDown {
+		if err := step(e.SetUpsideDown(s.UpsideDown)); err != nil {
+			return written, err
+		}
+	}
+	if s.Rotate != cur.Rotate {
+		if err := step(e.SetRotate(s.Rotate)); err != nil {
+			return written, err
+		}
+	}
+	if s.Justify != cur.Justify {
+		if err := step(e.SetJustify(s.Justify)); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// ResetStyle restores the default style, sending the commands of the
+// attributes differing from it
+func (e *Escpos) ResetStyle() (int, error) {
+	return e.ApplyStyle(Style{})
+}
+
+// WriteStyled prints text with the style s, then restores the current style.
+// Only the commands of the attributes differing from the current style are
+// sent, which keeps templates setting a full style per line from flooding
+// slow printers with redundant commands.
+func (e *Escpos) WriteStyled(text string, s Style) (int, error) {
+	prev := e.Style
+
+	written, err := e.ApplyStyle(s)
+	if err != nil {
+		return written, fmt.Errorf("failed to apply style: %w", err)
+	}
+
+	n, err := e.Write(text)
+	written += n
+	if err != nil {
+		return written, err
+	}
+
+	n, err = e.ApplyStyle(prev)
+	written += n
+	if err != nil {
+		return written, fmt.Errorf("failed to restore style: %w", err)
+	}
+	return written, nil
+}
+
+// WithStyle applies the style s, calls fn, then restores the current style,
+// even when fn fails, so headers printed by fn cannot leave bold or reverse
+// on for the rest of the ticket
+func (e *Escpos) WithStyle(s Style, fn func() error) error {
+	prev := e.Style
+
+	if _, err := e.ApplyStyle(s); err != nil {
+		return fmt.Errorf("failed to apply style: %w", err)
+	}
+
+	err := fn()
+	if _, rerr := e.ApplyStyle(prev); rerr != nil && err == nil {
+		err = fmt.Errorf("failed to restore style: %w", rerr)
+	}
+	return err
+}
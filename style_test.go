@@ -0,0 +1,110 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWriteStyled tests that only the changed attributes are sent, then restored
+func TestWriteStyled(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.SetBold(true)
+	assert.NoError(t, err)
+
+	// Bold is already active, only the size and justification change
+	_, err = p.WriteStyled("TOTAL\n", Style{Bold: true, Width: 2, Height: 2, Justify: JustifyRight})
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{esc, 'E', 1}
+	expected = append(expected, gs, '!', 0x11, esc, 'a', byte(JustifyRight))
+	expected = append(expected, []byte("TOTAL\n")...)
+	expected = append(expected, gs, '!', 0x00, esc, 'a', byte(JustifyLeft))
+	assert.Equal(t, expected, mock.Bytes())
+
+	assert.Equal(t, Style{Bold: true, Width: 1, Height: 1}, p.Style)
+}
+
+// TestWriteStyledNoChange tests that an identical style sends no command
+func TestWriteStyledNoChange(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.WriteStyled("plain\n", Style{})
+	assert.NoError(t, err)
+	_, err = p.WriteStyled("plain\n", Style{Width: 1, Height: 1})
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("plain\nplain\n"), mock.Bytes())
+}
+
+// TestWriteStyledDisabled tests that a disabled attribute fails before printing
+func TestWriteStyledDisabled(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetConfig(PrinterConfig{DisableReverse: true})
+
+	_, err := p.WriteStyled("inverted\n", Style{Reverse: true})
+	assert.Error(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+	assert.Empty(t, mock.Bytes())
+}
+
+// TestApplyStyle tests switching styles and resetting to the default one
+func TestApplyStyle(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.ApplyStyle(Style{Underline: UnderlineSingle, Reverse: true, UpsideDown: true})
+	assert.NoError(t, err)
+	_, err = p.ApplyStyle(Style{Underline: UnderlineSingle, Rotate: true})
+	assert.NoError(t, err)
+	assert.Equal(t, Style{Underline: UnderlineSingle, Rotate: true}, p.Style)
+
+	_, err = p.ResetStyle()
+	assert.NoError(t, err)
+	assert.Equal(t, Style{}, p.Style)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{esc, '-', 1, gs, 'B', 1, esc, '{', 1}
+	expected = append(expected, gs, 'B', 0, esc, '{', 0, esc, 'V', 1)
+	expected = append(expected, esc, '-', 0, esc, 'V', 0)
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestWithStyle tests that the style is restored after fn, even when it fails
+func TestWithStyle(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	err := p.WithStyle(Style{Bold: true, Reverse: true}, func() error {
+		_, err := p.Write("HEADER\n")
+		return err
+	})
+	assert.NoError(t, err)
+
+	err = p.WithStyle(Style{Bold: true}, func() error {
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, Style{}, p.Style)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{esc, 'E', 1, gs, 'B', 1}
+	expected = append(expected, []byte("HEADER\n")...)
+	expected = append(expected, esc, 'E', 0, gs, 'B', 0, esc, 'E', 1, esc, 'E', 0)
+	assert.Equal(t, expected, mock.Bytes())
+}
@@ -0,0 +1,176 @@
+package escpos
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Overflow is the handling of the cells longer than their column
+type Overflow uint8
+
+const (
+	// OverflowTruncate cuts the text at the width of the column
+	OverflowTruncate Overflow = iota
+	// OverflowWrap wraps the text on several lines, the row growing to the
+	// height of its tallest cell
+	OverflowWrap
+)
+
+// Column is a column of a Table. Its width is Width characters, or Percent
+// percent of the line when Width is 0; the columns with neither share the
+// rest of the line.
+type Column struct {
+	Width    int
+	Percent  int
+	Align    Justify
+	Overflow Overflow
+}
+
+// Table lays out rows of cells in columns, such as the items of a receipt.
+// Columns are separated by a space and sized for the characters per line of
+// the printer when the table is written.
+type Table struct {
+	Columns []Column
+	rows    [][]string
+}
+
+// NewTable creates a table with the given columns
+func NewTable(columns ...Column) *Table {
+	return &Table{Columns: columns}
+}
+
+// AddRow appends a row, one cell per column; missing cells are left empty
+func (t *Table) AddRow(cells ...string) error {
+	if len(cells) > len(t.Columns) {
+		return fmt.Errorf("row has %d cells for %d columns", len(cells), len(t.Columns))
+	}
+	row := make([]string, len(t.Columns))
+	copy(row, cells)
+	t.rows = append(t.rows, row)
+	return nil
+}
+
+// widths returns the width in characters of each column on a line of cols characters
+func (t *Table) widths(cols int) ([]int, error) {
+	if len(t.Columns) == 0 {
+		return nil, fmt.Errorf("table has no columns")
+	}
+	avail := cols - (len(t.Columns) - 1)
+	widths := make([]int, len(t.Columns))
+	used, flexible := 0, 0
+	for i, c := range t.Columns {
+		switch {
+		case c.Width > 0:
+			widths[i] = c.Width
+		case c.Percent > 0:
+			widths[i] = avail * c.Percent / 100
+		default:
+			flexible++
+		}
+		used += widths[i]
+	}
+	if used > avail {
+		return nil, fmt.Errorf("columns of %d characters do not fit on a line of %d", used, avail)
+	}
+	rest := avail - used
+	for i, c := range t.Columns {
+		if c.Width > 0 || c.Percent > 0 {
+			continue
+		}
+		widths[i] = rest / flexible
+		rest -= widths[i]
+		flexible--
+	}
+	for i, w := range widths {
+		if w < 1 {
+			return nil, fmt.Errorf("column %d is empty on a line of %d characters", i, cols)
+		}
+	}
+	return widths, nil
+}
+
+// WriteTable prints the rows of the table, laid out for the characters per
+// line of the current size
+func (e *Escpos) WriteTable(t *Table) (int, error) {
+	lines, err := t.Lines(e.CharsPerLine())
+	if err != nil {
+		return 0, err
+	}
+	return e.Write(strings.Join(lines, ""))
+}
+
+// Lines returns the rows of the table laid out on lines of cols characters,
+// each line ended by a line feed, for the targets other than Escpos
+func (t *Table) Lines(cols int) ([]string, error) {
+	widths, err := t.widths(cols)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []string
+	for _, row := range t.rows {
+		lines := make([][]string, len(row))
+		height := 1
+		for i, cell := range row {
+			lines[i] = cellLines(cell, widths[i], t.Columns[i].Overflow)
+			height = max(height, len(lines[i]))
+		}
+
+		for l := range height {
+			var b strings.Builder
+			for i := range row {
+				if i > 0 {
+					b.WriteByte(' ')
+				}
+				var text string
+				if l < len(lines[i]) {
+					text = lines[i][l]
+				}
+				b.WriteString(pad(text, widths[i], t.Columns[i].Align))
+			}
+			out = append(out, strings.TrimRight(b.String(), " ")+"\n")
+		}
+	}
+	return out, nil
+}
+
+// WriteKV prints left flush left and right flush right on one line, such as
+// a label and its amount, for the characters per line of the current size.
+// When both do not fit, right is printed on the next line.
+func (e *Escpos) WriteKV(left, right string) (int, error) {
+	return e.Write(LayoutKV(left, right, e.CharsPerLine()))
+}
+
+// LayoutKV returns the text printed by WriteKV on lines of cols characters,
+// for the targets other than Escpos
+func LayoutKV(left, right string, cols int) string {
+	gap := cols - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if gap < 1 {
+		return left + "\n" + pad(right, cols, JustifyRight) + "\n"
+	}
+	return left + strings.Repeat(" ", gap) + right + "\n"
+}
+
+// cellLines returns the lines of a cell in a column of width characters
+func cellLines(text string, width int, overflow Overflow) []string {
+	if overflow == OverflowWrap {
+		return wrapText(text, width)
+	}
+	if r := []rune(text); len(r) > width {
+		text = string(r[:width])
+	}
+	return []string{text}
+}
+
+// pad aligns text in a column of width characters
+func pad(text string, width int, align Justify) string {
+	gap := max(width-utf8.RuneCountInString(text), 0)
+	switch align {
+	case JustifyRight:
+		return strings.Repeat(" ", gap) + text
+	case JustifyCenter:
+		return strings.Repeat(" ", gap/2) + text + strings.Repeat(" ", gap-gap/2)
+	}
+	return text + strings.Repeat(" ", gap)
+}
@@ -0,0 +1,70 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteTable tests the layout of the columns
+func TestWriteTable(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Name: "Test", PrintWidth: 24 * fontAWidth})
+
+	table := NewTable(
+		Column{Width: 3, Align: JustifyRight},
+		Column{Overflow: OverflowWrap},
+		Column{Percent: 25, Align: JustifyRight},
+	)
+	require.NoError(t, table.AddRow("2", "Croissant au beurre", "3.00"))
+	require.NoError(t, table.AddRow("10", "Tea"))
+	assert.Error(t, table.AddRow("1", "2", "3", "4"))
+
+	_, err := p.WriteTable(table)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, "  2 Croissant au    3.00\n"+
+		"    beurre\n"+
+		" 10 Tea\n", mock.String())
+}
+
+// TestWriteTableTruncate tests cells cut at the width of their column
+func TestWriteTableTruncate(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Name: "Test", PrintWidth: 24 * fontAWidth})
+	p.Style.Width = 2
+
+	table := NewTable(Column{Align: JustifyCenter}, Column{Width: 4})
+	require.NoError(t, table.AddRow("ab", "abcdefg"))
+	_, err := p.WriteTable(table)
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, "  ab    abcd\n", mock.String())
+
+	_, err = p.WriteTable(NewTable(Column{Width: 20}))
+	assert.Error(t, err)
+	_, err = p.WriteTable(NewTable())
+	assert.Error(t, err)
+}
+
+// TestWriteKV tests the padding of a label and its value
+func TestWriteKV(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Name: "Test", PrintWidth: 20 * fontAWidth})
+
+	_, err := p.WriteKV("TOTAL", "23.90")
+	require.NoError(t, err)
+	p.Style.Width = 2
+	_, err = p.WriteKV("TOTAL", "9.90")
+	require.NoError(t, err)
+	_, err = p.WriteKV("Discount", "-2.00")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, "TOTAL          23.90\n"+
+		"TOTAL 9.90\n"+
+		"Discount\n     -2.00\n", mock.String())
+}
@@ -0,0 +1,76 @@
+package escpos
+
+// Tear line dimensions and feed distances in millimeters
+const (
+	tearDash      = 2.0
+	tearGap       = 1.5
+	tearThickness = 0.25
+	tearBarOffset = 12.0 // default distance from the print head to the tear bar
+	cutterOffset  = 15.0 // default distance from the print head to the cutter
+)
+
+// TearLine prints a dashed perforation line across the print width, then
+// feeds the line past the tear bar so the ticket can be torn off. It replaces
+// cuts on printers without a cutter, the dash pattern being sized for the
+// resolution of the profile.
+func (e *Escpos) TearLine() (int, error) {
+	width := e.PrintWidth()
+	dash, gap := e.profile.dots(tearDash), e.profile.dots(tearGap)
+	height := max(e.profile.dots(tearThickness), 1)
+
+	img := rasterImage{widthBytes: (width + 7) / 8, width: width, height: height}
+	img.data = make([]byte, img.widthBytes*height)
+	for x := 0; x < width; x++ {
+		if x%(dash+gap) >= dash {
+			continue
+		}
+		for y := 0; y < height; y++ {
+			img.data[y*img.widthBytes+x/8] |= 0x80 >> (x % 8)
+		}
+	}
+
+	written, err := e.printRaster(img)
+	if err != nil {
+		return written, err
+	}
+
+	n, err := e.feed(e.tearBarDistance())
+	return written + n, err
+}
+
+// FeedToCutPosition feeds the last printed line past the cutter, or the
+// tear bar on printers without a cutter, without cutting, so that line is
+// not lost in the gap between the print head and the blade
+func (e *Escpos) FeedToCutPosition() (int, error) {
+	if e.profile.NoCutter {
+		return e.feed(e.tearBarDistance())
+	}
+	distance := e.profile.CutterDistance
+	if distance <= 0 {
+		distance = e.profile.dots(cutterOffset)
+	}
+	return e.feed(distance)
+}
+
+// tearBarDistance returns the distance in dots from the print head to the tear bar
+func (e *Escpos) tearBarDistance() int {
+	if e.profile.TearBarDistance > 0 {
+		return e.profile.TearBarDistance
+	}
+	return e.profile.dots(tearBarOffset)
+}
+
+// feed feeds the paper by dots, with as many ESC J commands as needed
+func (e *Escpos) feed(dots int) (int, error) {
+	written := 0
+	for dots > 0 {
+		n := min(dots, 255)
+		w, err := e.FeedDots(byte(n))
+		written += w
+		if err != nil {
+			return written, err
+		}
+		dots -= n
+	}
+	return written, nil
+}
@@ -0,0 +1,67 @@
+package escpos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTearLine tests the dashed tear line and the feed to the tear bar
+func TestTearLine(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{PrintWidth: 64, TearBarDistance: 300})
+
+	_, err := p.TearLine()
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	// 203 dpi: 16 dot dashes, 12 dot gaps, 2 dot thick
+	row := []byte{0xFF, 0xFF, 0x00, 0x0F, 0xFF, 0xF0, 0x00, 0xFF}
+	expected := []byte{gs, 'v', '0', 0, 8, 0, 2, 0}
+	expected = append(expected, row...)
+	expected = append(expected, row...)
+	expected = append(expected, esc, 'J', 255, esc, 'J', 45)
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestCutWithoutCutter tests cuts replaced by tear lines
+func TestCutWithoutCutter(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{NoCutter: true, DPI: 300})
+
+	_, err := p.Cut()
+	require.NoError(t, err)
+	_, err = p.PartialCut()
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	output := mock.Bytes()
+	assert.False(t, bytes.Contains(output, []byte{gs, 'V'}))
+	// 12mm at 300 dpi
+	assert.Equal(t, 2, bytes.Count(output, []byte{esc, 'J', 142}))
+	assert.Equal(t, 2, bytes.Count(output, []byte{gs, 'v', '0', 0, 72, 0, 3, 0}))
+}
+
+// TestFeedToCutPosition tests feeding to the cutter or the tear bar
+func TestFeedToCutPosition(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	// 15mm at 203 dpi
+	_, err := p.FeedToCutPosition()
+	require.NoError(t, err)
+	p.SetProfile(Profile{CutterDistance: 300})
+	_, err = p.FeedToCutPosition()
+	require.NoError(t, err)
+	p.SetProfile(Profile{NoCutter: true, TearBarDistance: 50})
+	_, err = p.FeedToCutPosition()
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+
+	expected := []byte{esc, 'J', 120, esc, 'J', 255, esc, 'J', 45, esc, 'J', 50}
+	assert.Equal(t, expected, mock.Bytes())
+}
@@ -0,0 +1,105 @@
+package escpos
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// isThaiAbove reports whether r is a Thai vowel or tone mark printed above its consonant
+func isThaiAbove(r rune) bool {
+	return r == 0x0E31 || r >= 0x0E34 && r <= 0x0E37 || r >= 0x0E47 && r <= 0x0E4E
+}
+
+// isThaiBelow reports whether r is a Thai vowel printed below its consonant
+func isThaiBelow(r rune) bool {
+	return r >= 0x0E38 && r <= 0x0E3A
+}
+
+// thaiPasses splits a line of Thai text into the passes printed over each
+// other by three-pass printers: the marks above the consonants, the
+// consonants, and the marks below them. A consonant carrying both a vowel
+// and a tone mark above it needs a second pass for the tone mark.
+func thaiPasses(line string) []string {
+	var base, below []rune
+	var above [][]rune
+	put := func(level []rune, col int, r rune) []rune {
+		for len(level) <= col {
+			level = append(level, ' ')
+		}
+		level[col] = r
+		return level
+	}
+
+	for _, r := range line {
+		col := len(base) - 1
+		switch {
+		case col >= 0 && isThaiAbove(r):
+			i := 0
+			for i < len(above) && col < len(above[i]) && above[i][col] != ' ' {
+				i++
+			}
+			if i == len(above) {
+				above = append(above, nil)
+			}
+			above[i] = put(above[i], col, r)
+		case col >= 0 && isThaiBelow(r):
+			below = put(below, col, r)
+		default:
+			base = append(base, r)
+		}
+	}
+
+	var passes []string
+	for i := len(above) - 1; i >= 0; i-- {
+		passes = append(passes, string(above[i]))
+	}
+	if below != nil {
+		passes = append(passes, string(below))
+	}
+	return append(passes, string(base))
+}
+
+// WriteThai prints Thai text with code page 874 (TIS-620), selected with the
+// Thai code page of the profile, the vendors numbering it differently. Printers composing the vowel and tone marks with
+// their consonants receive the text as is; for the printers printing Thai in
+// three passes (Profile.ThaiThreePass) each line is split into the marks
+// above, the consonants and the marks below, printed over each other. Each
+// line is ended with a line feed, then the code page of the default encoding
+// is restored.
+func (e *Escpos) WriteThai(text string) (int, error) {
+	codepage := e.profile.ThaiCodePage
+	if codepage == 0 {
+		return 0, fmt.Errorf("%s has no Thai code page, see Profile.ThaiCodePage", e.profile.name())
+	}
+	text = strings.TrimSuffix(e.prepareText(text), "\n")
+	if !e.profile.ThaiThreePass {
+		return e.writeInCodePage(text+"\n", charmap.Windows874, codepage)
+	}
+
+	written := 0
+	step := func(n int, err error) error {
+		written += n
+		return err
+	}
+	for _, line := range strings.Split(text, "\n") {
+		passes := thaiPasses(line)
+		if len(passes) > 1 {
+			// The passes before the last one do not feed the paper
+			if err := step(e.SetLineSpacing(0)); err != nil {
+				return written, err
+			}
+			if err := step(e.writeInCodePage(strings.Join(passes[:len(passes)-1], "\n")+"\n", charmap.Windows874, codepage)); err != nil {
+				return written, err
+			}
+			if err := step(e.restoreLineSpacing()); err != nil {
+				return written, err
+			}
+		}
+		if err := step(e.writeInCodePage(passes[len(passes)-1]+"\n", charmap.Windows874, codepage)); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
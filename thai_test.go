@@ -0,0 +1,52 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TestThaiPasses tests the split of the marks above and below the consonants
+func TestThaiPasses(t *testing.T) {
+	assert.Equal(t, []string{"abc"}, thaiPasses("abc"))
+	assert.Equal(t, []string{"ุ", "สข"}, thaiPasses("สุข"))
+	assert.Equal(t, []string{"่", "ี", "ก"}, thaiPasses("กี่"))
+	assert.Equal(t, []string{"่้", "กก"}, thaiPasses("ก่ก้"))
+}
+
+// TestWriteThai tests the text sent to one-pass printers
+func TestWriteThai(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	_, err := p.WriteThai("สวัสดี")
+	assert.ErrorContains(t, err, "no Thai code page")
+
+	p.SetProfile(Profile{ThaiCodePage: 21})
+	_, err = p.WriteThai("สวัสดี")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	text, err := charmap.Windows874.NewEncoder().String("สวัสดี\n")
+	require.NoError(t, err)
+	assert.Equal(t, "\x1bt\x15"+text+"\x1bt\x02", mock.String())
+}
+
+// TestWriteThaiThreePass tests the passes printed over each other
+func TestWriteThaiThreePass(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Name: "Test", ThaiCodePage: 26, ThaiThreePass: true})
+
+	_, err := p.WriteThai("กี่\nab\n")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	enc := charmap.Windows874.NewEncoder()
+	marks, err := enc.String("่\nี\n")
+	require.NoError(t, err)
+	base, err := enc.String("ก\n")
+	require.NoError(t, err)
+	assert.Equal(t, "\x1b3\x00\x1bt\x1a"+marks+"\x1bt\x02\x1b2"+
+		"\x1bt\x1a"+base+"\x1bt\x02"+
+		"ab\n", mock.String())
+}
@@ -0,0 +1,91 @@
+package escpos
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Size of a Font A user-defined character in dots
+const (
+	UserCharacterWidth  = 12
+	UserCharacterHeight = 24
+)
+
+// userCharacterBytes is the number of bytes of a user-defined character column
+const userCharacterBytes = UserCharacterHeight / 8
+
+// DefineUserCharacters defines the Font A characters in the range from-to (ESC &)
+// glyphs holds one glyph per character, as built by UserCharacterFromImage:
+// columns of 3 bytes from left to right, the most significant bit of each
+// byte at the top. Glyphs are at most 12 columns wide; an empty glyph
+// defines a blank character.
+// Call EnableUserCharacters to print the defined characters.
+func (e *Escpos) DefineUserCharacters(from, to byte, glyphs [][]byte) (int, error) {
+	if from < 32 || to > 126 || from > to {
+		return 0, fmt.Errorf("invalid user-defined character range: %d-%d, must be within 32-126", from, to)
+	}
+	if len(glyphs) != int(to-from)+1 {
+		return 0, fmt.Errorf("expected %d glyphs for characters %d-%d, got %d", int(to-from)+1, from, to, len(glyphs))
+	}
+
+	cmd := []byte{esc, '&', userCharacterBytes, from, to}
+	for i, g := range glyphs {
+		if len(g)%userCharacterBytes != 0 || len(g)/userCharacterBytes > UserCharacterWidth {
+			return 0, fmt.Errorf("invalid glyph for character %d: %d bytes, must be up to %d columns of %d bytes", int(from)+i, len(g), UserCharacterWidth, userCharacterBytes)
+		}
+		cmd = append(cmd, byte(len(g)/userCharacterBytes))
+		cmd = append(cmd, g...)
+	}
+	return e.WriteRaw(cmd)
+}
+
+// EnableUserCharacters selects (true) or cancels (false) the user-defined character set (ESC %)
+func (e *Escpos) EnableUserCharacters(b bool) (int, error) {
+	return e.WriteRaw([]byte{esc, '%', boolToByte(b)})
+}
+
+// UserCharacterFromImage converts an image to a user-defined character glyph.
+// The image is cropped to 12x24 dots from its top left corner; dark pixels
+// are printed and the glyph is as wide as the image.
+func UserCharacterFromImage(img image.Image) []byte {
+	b := img.Bounds()
+	width := min(b.Dx(), UserCharacterWidth)
+	height := min(b.Dy(), UserCharacterHeight)
+
+	glyph := make([]byte, width*userCharacterBytes)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			c := img.At(b.Min.X+x, b.Min.Y+y)
+			// Transparent pixels are blank
+			if _, _, _, a := c.RGBA(); a >= 0x8000 && color.GrayModel.Convert(c).(color.Gray).Y < 128 {
+				glyph[x*userCharacterBytes+y/8] |= 0x80 >> (y % 8)
+			}
+		}
+	}
+	return glyph
+}
+
+// UserCharacterFromFace draws r with face and converts it to a user-defined
+// character glyph, the baseline placed 5 dots above the bottom of the cell.
+// The face should be sized to fit a 12x24 cell.
+func UserCharacterFromFace(face font.Face, r rune) ([]byte, error) {
+	if _, ok := face.GlyphAdvance(r); !ok {
+		return nil, fmt.Errorf("the font has no glyph for %q", r)
+	}
+
+	cell := image.NewGray(image.Rect(0, 0, UserCharacterWidth, UserCharacterHeight))
+	draw.Draw(cell, cell.Bounds(), image.White, image.Point{}, draw.Src)
+	d := font.Drawer{
+		Dst:  cell,
+		Src:  image.Black,
+		Face: face,
+		Dot:  fixed.P(0, UserCharacterHeight-5),
+	}
+	d.DrawString(string(r))
+	return UserCharacterFromImage(cell), nil
+}
@@ -0,0 +1,78 @@
+package escpos
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/basicfont"
+)
+
+// TestDefineUserCharacters tests defining and enabling user-defined characters
+func TestDefineUserCharacters(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	glyph := []byte{0xFF, 0x00, 0x01, 0x80, 0x00, 0xFF}
+	_, err := p.DefineUserCharacters('A', 'B', [][]byte{glyph, {}})
+	assert.NoError(t, err)
+	_, err = p.EnableUserCharacters(true)
+	assert.NoError(t, err)
+	_, err = p.EnableUserCharacters(false)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+
+	expected := []byte{
+		esc, '&', 3, 'A', 'B',
+		2, 0xFF, 0x00, 0x01, 0x80, 0x00, 0xFF,
+		0,
+		esc, '%', 1,
+		esc, '%', 0,
+	}
+	assert.Equal(t, expected, mock.Bytes())
+}
+
+// TestDefineUserCharactersInvalid tests the validation of the characters and glyphs
+func TestDefineUserCharactersInvalid(t *testing.T) {
+	p := New(NewMockPrinter())
+
+	_, err := p.DefineUserCharacters(31, 40, make([][]byte, 10))
+	assert.Error(t, err)
+	_, err = p.DefineUserCharacters('B', 'A', nil)
+	assert.Error(t, err)
+	_, err = p.DefineUserCharacters('A', 'C', make([][]byte, 2))
+	assert.Error(t, err)
+	_, err = p.DefineUserCharacters('A', 'A', [][]byte{{0xFF}})
+	assert.Error(t, err)
+	_, err = p.DefineUserCharacters('A', 'A', [][]byte{make([]byte, 13*3)})
+	assert.Error(t, err)
+}
+
+// TestUserCharacterFromImage tests converting an image to a glyph
+func TestUserCharacterFromImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 24))
+	img.Set(0, 0, color.Black)
+	img.Set(0, 23, color.Black)
+	img.Set(1, 9, color.Black)
+	img.Set(1, 10, color.White)
+
+	assert.Equal(t, []byte{0x80, 0x00, 0x01, 0x00, 0x40, 0x00}, UserCharacterFromImage(img))
+
+	// Larger images are cropped
+	assert.Len(t, UserCharacterFromImage(image.NewGray(image.Rect(0, 0, 40, 40))), 36)
+}
+
+// TestUserCharacterFromFace tests drawing a glyph with a font face
+func TestUserCharacterFromFace(t *testing.T) {
+	_, err := UserCharacterFromFace(basicfont.Face7x13, '日')
+	assert.Error(t, err)
+
+	glyph, err := UserCharacterFromFace(basicfont.Face7x13, 'X')
+	require.NoError(t, err)
+	assert.Len(t, glyph, 36)
+	assert.NotEqual(t, make([]byte, 36), glyph)
+}
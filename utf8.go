@@ -0,0 +1,152 @@
+package escpos
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// codePageEncodings maps the ESC t code pages to their character encodings
+var codePageEncodings = map[uint8]encoding.Encoding{
+	CodePagePC437:      charmap.CodePage437,
+	CodePagePC850:      charmap.CodePage850,
+	CodePagePC860:      charmap.CodePage860,
+	CodePagePC863:      charmap.CodePage863,
+	CodePagePC865:      charmap.CodePage865,
+	CodePageISO8859_1:  charmap.ISO8859_1,
+	CodePageWPC1252:    charmap.Windows1252,
+	CodePagePC866:      charmap.CodePage866,
+	CodePagePC852:      charmap.CodePage852,
+	CodePagePC858:      charmap.CodePage858,
+	CodePageISO88596:   charmap.ISO8859_6,
+	CodePageCP864:      CodePage864,
+	CodePageISO8859_15: charmap.ISO8859_15,
+	CodePageISO8859_2:  charmap.ISO8859_2,
+	CodePageCP1250:     charmap.Windows1250,
+	CodePageCP1251:     charmap.Windows1251,
+	CodePageCP1253:     charmap.Windows1253,
+	CodePageCP1254:     charmap.Windows1254,
+	CodePageCP1255:     charmap.Windows1255,
+	CodePageCP1256:     charmap.Windows1256,
+	CodePageCP1257:     charmap.Windows1257,
+	CodePageCP1258:     charmap.Windows1258,
+}
+
+// CodePageEncoding returns the character encoding of an ESC t code page,
+// such as charmap.CodePage850 for CodePagePC850
+func CodePageEncoding(codepage uint8) (encoding.Encoding, bool) {
+	enc, ok := codePageEncodings[codepage]
+	return enc, ok
+}
+
+// utf8CodePages are the code pages tried by WriteUTF8 when the profile does
+// not list the code pages of the printer, the most common ones first
+var utf8CodePages = []uint8{
+	CodePagePC858, CodePageWPC1252, CodePagePC852, CodePageCP1250, CodePagePC866,
+	CodePageCP1251, CodePageCP1253, CodePageCP1254, CodePageCP1257, CodePageCP1255,
+	CodePageCP1258,
+}
+
+// codePageCandidate is a code page WriteUTF8 can switch to
+type codePageCandidate struct {
+	codepage uint8
+	enc      encoding.Encoding
+}
+
+// utf8Candidates returns the code pages tried by WriteUTF8, the default one first
+func (e *Escpos) utf8Candidates() []codePageCandidate {
+	var candidates []codePageCandidate
+	if e.enc != nil {
+		candidates = append(candidates, codePageCandidate{e.codepage, e.enc})
+	}
+	codepages := e.profile.CodePages
+	if codepages == nil {
+		codepages = utf8CodePages
+	}
+	for _, cp := range codepages {
+		if enc, ok := e.profile.codePageEncoding(cp); ok && (e.enc == nil || cp != e.codepage) {
+			candidates = append(candidates, codePageCandidate{cp, enc})
+		}
+	}
+	return candidates
+}
+
+// encodes reports whether enc has the character r
+func encodes(enc encoding.Encoding, r rune) bool {
+	_, err := enc.NewEncoder().String(string(r))
+	return err == nil
+}
+
+// WriteUTF8 prints text mixing several scripts, such as French, Greek and
+// Cyrillic, switching code pages as needed. The text is split in runs, each
+// printed with the code page having the most of its characters, the default
+// encoding first; the code pages tried are those of the profile, or the
+// common ones. Characters found in none are handled by the encoding policy.
+// The code page of the default encoding is restored afterwards.
+func (e *Escpos) WriteUTF8(text string) (int, error) {
+	text = e.prepareText(text)
+	candidates := e.utf8Candidates()
+	if len(candidates) == 0 {
+		return e.WriteRaw([]byte(text))
+	}
+
+	written := 0
+	active := -1
+	for len(text) > 0 {
+		// Pick the code page encoding the longest run from here, ASCII
+		// characters being in all of them
+		best, bestLen := 0, 0
+		if active >= 0 {
+			best = active
+		}
+		for i, c := range candidates {
+			n := encodableRun(text, c.enc)
+			if n > bestLen || (n == bestLen && i == active) {
+				best, bestLen = i, n
+			}
+		}
+		if bestLen == 0 {
+			// No code page has the character, print it with the encoding policy
+			_, size := utf8.DecodeRuneInString(text)
+			bestLen = size
+		}
+
+		run := text[:bestLen]
+		text = text[bestLen:]
+		if best != active && !isASCII(run) {
+			n, err := e.SetCodePage(candidates[best].codepage)
+			written += n
+			if err != nil {
+				return written, err
+			}
+			active = best
+		} else if active < 0 {
+			best = 0
+		}
+		n, err := e.WriteRawWithEncoding([]byte(run), candidates[best].enc)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+
+	if active > 0 && e.enc != nil {
+		n, err := e.SetCodePage(e.codepage)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// encodableRun returns the length in bytes of the longest prefix of text enc has
+func encodableRun(text string, enc encoding.Encoding) int {
+	for i, r := range text {
+		if r >= utf8.RuneSelf && !encodes(enc, r) {
+			return i
+		}
+	}
+	return len(text)
+}
@@ -0,0 +1,35 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteUTF8 tests the code page switches of a text mixing scripts
+func TestWriteUTF8(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+
+	_, err := p.WriteUTF8("Café Ωμέγα, Привет\n")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, "\x1bt\x02Caf\x82 "+
+		"\x1bt\x31\xd9\xec\xdd\xe3\xe1, "+
+		"\x1bt\x11\x8f\xe0\xa8\xa2\xa5\xe2\n"+
+		"\x1bt\x02", mock.String())
+}
+
+// TestWriteUTF8Profile tests the code pages restricted by the profile
+func TestWriteUTF8Profile(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Name: "Test", CodePages: []uint8{CodePageCP1251}})
+
+	_, err := p.WriteUTF8("abc Ω Ж")
+	require.NoError(t, err)
+	require.NoError(t, p.Print())
+	assert.Equal(t, "abc \x1bt\x02\x1a\x1bt\x30 \xc6\x1bt\x02", mock.String())
+	assert.Len(t, p.Warnings(), 1)
+}
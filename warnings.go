@@ -0,0 +1,74 @@
+package escpos
+
+import "fmt"
+
+// WarningKind is the kind of a non-fatal issue met while building a job
+type WarningKind uint8
+
+const (
+	// WarningClamped reports a parameter out of range, adjusted to the nearest valid value
+	WarningClamped WarningKind = iota
+	// WarningReplaced reports a character missing from the encoding, printed
+	// as per the encoding policy
+	WarningReplaced
+	// WarningFallback reports a feature the printer lacks, emulated by the
+	// library, such as a barcode printed as an image
+	WarningFallback
+	// WarningImage reports an image split in bands or wider than the print width
+	WarningImage
+)
+
+var warningKinds = [...]string{
+	WarningClamped:  "clamped",
+	WarningReplaced: "replaced",
+	WarningFallback: "fallback",
+	WarningImage:    "image",
+}
+
+func (k WarningKind) String() string {
+	if int(k) < len(warningKinds) {
+		return warningKinds[k]
+	}
+	return fmt.Sprintf("WarningKind(%d)", k)
+}
+
+// Warning is a non-fatal issue met while building a job, an adjustment made
+// silently on behalf of the caller
+type Warning struct {
+	Kind    WarningKind
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Kind.String() + ": " + w.Message
+}
+
+// Maximum number of warnings kept, the following ones are dropped
+const maxWarnings = 256
+
+// Warnings returns the warnings collected since New or the last call to
+// ClearWarnings, oldest first
+func (e *Escpos) Warnings() []Warning {
+	return append([]Warning(nil), e.warnings...)
+}
+
+// ClearWarnings discards the collected warnings, typically before building a new job
+func (e *Escpos) ClearWarnings() {
+	e.warnings = nil
+}
+
+// warn records a warning
+func (e *Escpos) warn(kind WarningKind, format string, args ...any) {
+	if len(e.warnings) < maxWarnings {
+		e.warnings = append(e.warnings, Warning{Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// clamped limits the value of a parameter to the [lo, hi] range, warning when it is adjusted
+func (e *Escpos) clamped(name string, v, lo, hi uint8) uint8 {
+	c := clamp(v, lo, hi)
+	if c != v {
+		e.warn(WarningClamped, "%s %d out of range %d-%d, using %d", name, v, lo, hi, c)
+	}
+	return c
+}
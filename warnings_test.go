@@ -0,0 +1,62 @@
+package escpos
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// TestWarnings tests collecting the adjustments made while building a job
+func TestWarnings(t *testing.T) {
+	mock := NewMockPrinter()
+	p := New(mock)
+	p.SetProfile(Profile{Name: "Test", NoQRCode: true, UnsupportedBarcodes: []uint8{BarcodeEAN13}, MaxImageHeight: 256, PrintWidth: 384})
+
+	_, err := p.SetSize(0, 9)
+	require.NoError(t, err)
+	_, err = p.WriteRawWithEncoding([]byte("a€"), charmap.CodePage437)
+	require.NoError(t, err)
+	_, err = p.QRCode("hello", QRCodeModel2, 3, QRCodeErrorCorrectionLevelL)
+	require.NoError(t, err)
+	_, err = p.EAN13("4006381333931")
+	require.NoError(t, err)
+
+	// A black image, wider than the print width and taller than the maximum
+	img := image.NewGray(image.Rect(0, 0, 400, 300))
+	_, err = p.PrintImageWithProcessing(img, ImageProcessThreshold, true, true)
+	require.NoError(t, err)
+
+	warnings := p.Warnings()
+	kinds := make([]WarningKind, len(warnings))
+	for i, w := range warnings {
+		kinds[i] = w.Kind
+	}
+	assert.Equal(t, []WarningKind{WarningClamped, WarningClamped, WarningReplaced, WarningFallback, WarningFallback, WarningImage, WarningImage}, kinds)
+	assert.Equal(t, "clamped: width 9 out of range 1-8, using 8", warnings[0].String())
+	assert.Equal(t, "clamped: height 0 out of range 1-8, using 1", warnings[1].String())
+	assert.Contains(t, warnings[3].Message, "Test does not support QR codes")
+	assert.Contains(t, warnings[5].Message, "exceeds the print width of 384 dots")
+
+	p.ClearWarnings()
+	assert.Empty(t, p.Warnings())
+
+	// Valid parameters do not warn
+	_, err = p.SetSize(2, 2)
+	require.NoError(t, err)
+	_, err = p.OpenDrawer(0, 5)
+	require.NoError(t, err)
+	assert.Empty(t, p.Warnings())
+}
+
+// TestWarningsLimit tests that the number of warnings kept is bounded
+func TestWarningsLimit(t *testing.T) {
+	p := New(NewMockPrinter())
+	for range maxWarnings + 10 {
+		_, err := p.Buzzer(0, 0)
+		require.NoError(t, err)
+	}
+	assert.Len(t, p.Warnings(), maxWarnings)
+}
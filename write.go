@@ -0,0 +1,31 @@
+package escpos
+
+import "io"
+
+// WriteFull writes all of p to w, calling Write again after a partial write.
+// Serial and Bluetooth transports may accept only part of a buffer; writing
+// the rest instead of dropping it keeps jobs from being corrupted mid-image.
+// It returns io.ErrShortWrite when w accepts nothing without reporting an error.
+func WriteFull(w io.Writer, p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		n, err := w.Write(p[written:])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		if n == 0 {
+			return written, io.ErrShortWrite
+		}
+	}
+	return written, nil
+}
+
+// fullWriter is a writer retrying partial writes with WriteFull
+type fullWriter struct {
+	w io.Writer
+}
+
+func (f fullWriter) Write(p []byte) (int, error) {
+	return WriteFull(f.w, p)
+}
@@ -0,0 +1,58 @@
+package escpos
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// shortPrinter accepts at most limit bytes per write, like a slow serial link
+type shortPrinter struct {
+	MockPrinter
+	limit int
+}
+
+func (s *shortPrinter) Write(p []byte) (int, error) {
+	if len(p) > s.limit {
+		p = p[:s.limit]
+	}
+	return s.MockPrinter.Write(p)
+}
+
+// TestShortWrites tests that partial writes of the transport are completed
+func TestShortWrites(t *testing.T) {
+	printer := &shortPrinter{limit: 3}
+	p := New(printer)
+
+	data := bytes.Repeat([]byte{0xAA}, 1000)
+	_, err := p.WriteRaw(data)
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.NoError(t, err)
+	assert.Equal(t, data, printer.Bytes())
+}
+
+// TestShortWritesStalled tests that a transport accepting nothing is reported
+func TestShortWritesStalled(t *testing.T) {
+	printer := &shortPrinter{limit: 0}
+	p := New(printer)
+
+	_, err := p.WriteRaw([]byte("hello"))
+	assert.NoError(t, err)
+
+	err = p.Print()
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+}
+
+// TestWriteFull tests writing a buffer in several calls
+func TestWriteFull(t *testing.T) {
+	printer := &shortPrinter{limit: 2}
+
+	n, err := WriteFull(printer, []byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, []byte("hello"), printer.Bytes())
+}